@@ -0,0 +1,207 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package actionlink provides signed, expiring links to act on a release without logging in.
+package actionlink
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultExpiry is the time a Link is valid for when no expiry is set.
+const DefaultExpiry = 24 * time.Hour
+
+// Actions a Link can take.
+const (
+	ActionApprove = "approve" // Approve all actions.
+	ActionSkip    = "skip"    // Skip the release.
+	ActionWebHook = "webhook" // Send the WebHook of Target.
+)
+
+// Path of the action link route (after the route prefix), with the token appended.
+const Path = "/api/v1/action-link/"
+
+var (
+	// ErrInvalid is returned for a token that is malformed, or whose signature doesn't match.
+	ErrInvalid = errors.New("invalid action link")
+	// ErrExpired is returned for a token whose expiry has passed.
+	ErrExpired = errors.New("action link expired")
+	// ErrUsed is returned for a token that has already been used.
+	ErrUsed = errors.New("action link already used")
+)
+
+var (
+	mutex  sync.RWMutex
+	key    []byte        // Key to sign the Links with.
+	expiry time.Duration // Time a Link is valid for.
+
+	usedMutex sync.Mutex
+	used      = map[string]int64{} // Tokens used, with the Unix time they expire at.
+)
+
+// Link to act on a release of a Service.
+type Link struct {
+	ServiceID string `json:"s"`           // ID of the Service.
+	Version   string `json:"v"`           // Version the Link acts on.
+	Action    string `json:"a"`           // ActionApprove/ActionSkip/ActionWebHook.
+	Target    string `json:"t,omitempty"` // ID of the WebHook (ActionWebHook only).
+	Expires   int64  `json:"e"`           // Unix time the Link expires at.
+}
+
+// SetKey sets the `secret` to sign the Links with
+// (empty = a random key, so Links won't survive a restart).
+func SetKey(secret string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if secret == "" {
+		key = randomKey()
+		return
+	}
+	key = []byte(secret)
+}
+
+// SetExpiry sets the time a Link is valid for (0 = DefaultExpiry).
+func SetExpiry(duration time.Duration) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	expiry = duration
+}
+
+// randomKey returns a random 32 byte key.
+func randomKey() []byte {
+	randomKey := make([]byte, 32)
+	_, _ = rand.Read(randomKey)
+	return randomKey
+}
+
+// signingKey returns the key to sign the Links with (generating a random one if unset).
+func signingKey() []byte {
+	mutex.RLock()
+	signingKey := key
+	mutex.RUnlock()
+	if signingKey != nil {
+		return signingKey
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if key == nil {
+		key = randomKey()
+	}
+	return key
+}
+
+// New returns a Link for the `action` on `version` of the Service,
+// that expires after the expiry set.
+func New(serviceID, version, action, target string) Link {
+	mutex.RLock()
+	validFor := expiry
+	mutex.RUnlock()
+	if validFor <= 0 {
+		validFor = DefaultExpiry
+	}
+
+	return Link{
+		ServiceID: serviceID,
+		Version:   version,
+		Action:    action,
+		Target:    target,
+		Expires:   time.Now().Add(validFor).Unix()}
+}
+
+// Token returns the signed token of the Link ('payload.signature', base64url encoded).
+func (l Link) Token() string {
+	payload, _ := json.Marshal(l)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(sign(encoded))
+}
+
+// URL returns the URL of the Link on Argus at `publicURL`.
+func (l Link) URL(publicURL string) string {
+	return strings.TrimSuffix(publicURL, "/") + Path + l.Token()
+}
+
+// sign returns the HMAC-SHA256 of `payload`.
+func sign(payload string) []byte {
+	mac := hmac.New(sha256.New, signingKey())
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// Parse the `token`, verifying its signature, and that it hasn't expired at `now`.
+func Parse(token string, now time.Time) (Link, error) {
+	var link Link
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return link, ErrInvalid
+	}
+	gotSignature, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(gotSignature, sign(encoded)) {
+		return link, ErrInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return link, ErrInvalid
+	}
+	if err := json.Unmarshal(payload, &link); err != nil ||
+		link.ServiceID == "" ||
+		(link.Action != ActionApprove && link.Action != ActionSkip && link.Action != ActionWebHook) {
+		return Link{}, ErrInvalid
+	}
+	if now.Unix() > link.Expires {
+		return link, ErrExpired
+	}
+
+	return link, nil
+}
+
+// Use marks the `token` of `link` as used, so it can't be used again before it expires.
+//
+// Returns ErrUsed if the token has already been used.
+func Use(token string, link Link, now time.Time) error {
+	usedMutex.Lock()
+	defer usedMutex.Unlock()
+
+	// Forget the expired tokens, as Parse rejects them.
+	for usedToken, expires := range used {
+		if now.Unix() > expires {
+			delete(used, usedToken)
+		}
+	}
+
+	if _, alreadyUsed := used[token]; alreadyUsed {
+		return ErrUsed
+	}
+	used[token] = link.Expires
+	return nil
+}
+
+// Release the `token` for use again (e.g. when the action it was used for failed).
+func Release(token string) {
+	usedMutex.Lock()
+	defer usedMutex.Unlock()
+
+	delete(used, token)
+}
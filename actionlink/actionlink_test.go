@@ -0,0 +1,249 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package actionlink
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	// GIVEN an expiry to sign Links with.
+	tests := map[string]struct {
+		expiry time.Duration
+		want   time.Duration
+	}{
+		"default expiry": {
+			want: DefaultExpiry},
+		"custom expiry": {
+			expiry: time.Hour,
+			want:   time.Hour},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// t.Parallel() - Cannot run in parallel since we're using the package-level expiry.
+
+			SetExpiry(tc.expiry)
+			t.Cleanup(func() { SetExpiry(0) })
+
+			// WHEN New is called.
+			got := New("service", "1.2.3", ActionWebHook, "hook")
+
+			// THEN the Link holds the details given.
+			if got.ServiceID != "service" || got.Version != "1.2.3" ||
+				got.Action != ActionWebHook || got.Target != "hook" {
+				t.Errorf("unexpected Link: %+v",
+					got)
+			}
+			// AND it expires after the expiry.
+			wantExpires := time.Now().Add(tc.want).Unix()
+			if got.Expires < wantExpires-1 || got.Expires > wantExpires+1 {
+				t.Errorf("Expires\nwant: ~%d\ngot:  %d",
+					wantExpires, got.Expires)
+			}
+		})
+	}
+}
+
+func TestLink_URL(t *testing.T) {
+	// GIVEN a Link and a public_url.
+	tests := map[string]struct {
+		publicURL string
+	}{
+		"no trailing slash": {
+			publicURL: "https://argus.example.com"},
+		"trailing slash": {
+			publicURL: "https://argus.example.com/"},
+		"sub-path": {
+			publicURL: "https://example.com/argus"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			link := Link{ServiceID: "service", Version: "1.2.3", Action: ActionApprove, Expires: 1}
+
+			// WHEN URL is called.
+			got := link.URL(tc.publicURL)
+
+			// THEN the URL is the token on the action link route of the public_url.
+			want := strings.TrimSuffix(tc.publicURL, "/") + Path + link.Token()
+			if got != want {
+				t.Errorf("want: %q\ngot:  %q",
+					want, got)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	// GIVEN a token.
+	now := time.Now()
+	valid := Link{ServiceID: "service", Version: "1.2.3", Action: ActionSkip, Expires: now.Add(time.Hour).Unix()}
+	tests := map[string]struct {
+		token   func() string
+		want    Link
+		wantErr error
+	}{
+		"valid": {
+			token: valid.Token,
+			want:  valid},
+		"valid webhook": {
+			token: Link{ServiceID: "service", Version: "1.2.3", Action: ActionWebHook, Target: "hook",
+				Expires: now.Add(time.Hour).Unix()}.Token,
+			want: Link{ServiceID: "service", Version: "1.2.3", Action: ActionWebHook, Target: "hook",
+				Expires: now.Add(time.Hour).Unix()}},
+		"expired": {
+			token: Link{ServiceID: "service", Version: "1.2.3", Action: ActionSkip,
+				Expires: now.Add(-time.Second).Unix()}.Token,
+			want: Link{ServiceID: "service", Version: "1.2.3", Action: ActionSkip,
+				Expires: now.Add(-time.Second).Unix()},
+			wantErr: ErrExpired},
+		"unknown action": {
+			token: Link{ServiceID: "service", Version: "1.2.3", Action: "delete",
+				Expires: now.Add(time.Hour).Unix()}.Token,
+			wantErr: ErrInvalid},
+		"no service": {
+			token: Link{Version: "1.2.3", Action: ActionSkip,
+				Expires: now.Add(time.Hour).Unix()}.Token,
+			wantErr: ErrInvalid},
+		"no signature": {
+			token: func() string {
+				token, _, _ := strings.Cut(valid.Token(), ".")
+				return token
+			},
+			wantErr: ErrInvalid},
+		"signature not base64": {
+			token: func() string {
+				token, _, _ := strings.Cut(valid.Token(), ".")
+				return token + ".!"
+			},
+			wantErr: ErrInvalid},
+		"tampered payload": {
+			token: func() string {
+				_, signature, _ := strings.Cut(valid.Token(), ".")
+				tampered := valid
+				tampered.Action = ActionApprove
+				payload, _ := json.Marshal(tampered)
+				return base64.RawURLEncoding.EncodeToString(payload) + "." + signature
+			},
+			wantErr: ErrInvalid},
+		"empty": {
+			token:   func() string { return "" },
+			wantErr: ErrInvalid},
+	}
+
+	SetKey("secret")
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN Parse is called on it.
+			got, err := Parse(tc.token(), now)
+
+			// THEN the error is as expected.
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error\nwant: %v\ngot:  %v",
+					tc.wantErr, err)
+			}
+			// AND the Link is as expected.
+			if got != tc.want {
+				t.Errorf("want: %+v\ngot:  %+v",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSetKey(t *testing.T) {
+	// GIVEN a Link signed with a key.
+	link := Link{ServiceID: "service", Version: "1.2.3", Action: ActionApprove, Expires: time.Now().Add(time.Hour).Unix()}
+	tests := map[string]struct {
+		newKey  string
+		wantErr error
+	}{
+		"same secret": {
+			newKey: "secret"},
+		"different secret": {
+			newKey:  "other",
+			wantErr: ErrInvalid},
+		"random key": {
+			newKey:  "",
+			wantErr: ErrInvalid},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// t.Parallel() - Cannot run in parallel since we're using the package-level key.
+
+			SetKey("secret")
+			token := link.Token()
+
+			// WHEN the key is changed.
+			SetKey(tc.newKey)
+
+			// THEN the Link is only valid with the same key.
+			_, err := Parse(token, time.Now())
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("want: %v\ngot:  %v",
+					tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestUse(t *testing.T) {
+	// GIVEN a Link that hasn't been used.
+	now := time.Now()
+	link := Link{ServiceID: "TestUse", Version: "1.2.3", Action: ActionSkip, Expires: now.Add(time.Hour).Unix()}
+	token := link.Token()
+
+	// WHEN Use is called on it.
+	err := Use(token, link, now)
+
+	// THEN it succeeds the first time.
+	if err != nil {
+		t.Fatalf("first use\nwant: nil\ngot:  %v",
+			err)
+	}
+	// AND fails the second time.
+	if err := Use(token, link, now); !errors.Is(err, ErrUsed) {
+		t.Errorf("second use\nwant: %v\ngot:  %v",
+			ErrUsed, err)
+	}
+	// AND can be used again after being released.
+	Release(token)
+	if err := Use(token, link, now); err != nil {
+		t.Errorf("use after release\nwant: nil\ngot:  %v",
+			err)
+	}
+	// AND is forgotten once expired.
+	Use("other", Link{}, now.Add(2*time.Hour))
+	usedMutex.Lock()
+	_, remembered := used[token]
+	usedMutex.Unlock()
+	if remembered {
+		t.Errorf("expired token %q still remembered",
+			token)
+	}
+}
@@ -19,11 +19,17 @@ On a version change, send notification(s) and/or webhook(s).
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	cfg "github.com/release-argus/Argus/config"
 	"github.com/release-argus/Argus/db"
+	"github.com/release-argus/Argus/outbox"
 	"github.com/release-argus/Argus/testing"
 	"github.com/release-argus/Argus/util"
 	"github.com/release-argus/Argus/web"
@@ -34,6 +40,10 @@ var (
 	jLog             util.JLog
 	configFile       = flag.String("config.file", "config.yml", "Argus configuration file path.")
 	configCheckFlag  = flag.Bool("config.check", false, "Print the fully-parsed config.")
+	dbMigrateOnly    = flag.Bool("db.migrate-only", false, "Apply the database schema migrations and exit.")
+	dbBackupFlag     = flag.String("db.backup", "", "Put the path to write a backup of the database to.")
+	dbExportFlag     = flag.String("db.export", "", "Put the path to export the database to as JSON.")
+	dbImportFlag     = flag.String("db.import", "", "Put the path of a JSON export to import into the database.")
 	testCommandsFlag = flag.String("test.commands", "", "Put the name of the Service to test the `commands` of.")
 	testNotifyFlag   = flag.String("test.notify", "", "Put the name of the Notify service to send a test message.")
 	testServiceFlag  = flag.String("test.service", "", "Put the name of the Service to test the version query.")
@@ -41,7 +51,8 @@ var (
 
 // main loads the config and then calls service.Track to monitor
 // each Service of the config for version changes and acts on
-// them as defined. It also sets up the Web UI and SaveHandler.
+// them as defined. It also sets up the Web UI and SaveHandler,
+// and flushes the state on SIGINT/SIGTERM.
 func main() {
 	flag.Parse()
 	flagset := make(map[string]bool)
@@ -58,6 +69,11 @@ func main() {
 	testing.CommandTest(testCommandsFlag, &config, &jLog)
 	testing.NotifyTest(testNotifyFlag, &config, &jLog)
 	testing.ServiceTest(testServiceFlag, &config, &jLog)
+	// db.*
+	db.MigrateOnly(dbMigrateOnly, &config, &jLog)
+	db.BackupFile(dbBackupFlag, &config, &jLog)
+	db.ExportFile(dbExportFlag, &config, &jLog)
+	db.ImportFile(dbImportFlag, &config, &jLog)
 
 	// Count of active services to monitor (if log level INFO or above).
 	if jLog.Level > 1 {
@@ -84,9 +100,53 @@ func main() {
 	// Setup DB and last known service versions.
 	db.Run(&config, &jLog)
 
+	// Stop on SIGINT/SIGTERM.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Track all targets for changes in version and act on any found changes.
-	go config.Service.Track(&config.Order, &config.OrderMutex)
+	// (cancelled in shutdown once the outbox has stopped, so interrupted deliveries remain in it).
+	trackCtx, cancelTrack := context.WithCancel(context.Background())
+	config.Track(trackCtx)
 
 	// Web server.
-	web.Run(&config, &jLog)
+	webStopped := make(chan struct{})
+	go func() {
+		web.Run(ctx, &config, &jLog)
+		close(webStopped)
+	}()
+
+	<-ctx.Done()
+	shutdown(&config, webStopped, cancelTrack)
+}
+
+// shutdown stops the tracking of the Services (with `cancelTrack`), and waits up to the
+// shutdown timeout for the web server to stop, and the pending config/database changes to be written.
+//
+// Pending deliveries (e.g. delayed WebHooks) are left in the outbox for the next start.
+func shutdown(config *cfg.Config, webStopped <-chan struct{}, cancelTrack context.CancelFunc) {
+	logFrom := util.LogFrom{Primary: "shutdown"}
+	jLog.Info("Shutting down", logFrom, true)
+	deadline := time.Now().Add(config.Settings.ShutdownTimeout())
+
+	// Outbox, before the lookups/actions are cancelled.
+	outbox.Stop()
+	cancelTrack()
+
+	// Web server.
+	select {
+	case <-webStopped:
+	case <-time.After(time.Until(deadline)):
+		jLog.Warn("web server not stopped within the shutdown timeout", logFrom, true)
+	}
+
+	// Config.
+	if err := config.FlushSave(time.Until(deadline)); err != nil {
+		jLog.Error(err, logFrom, true)
+	}
+
+	// Database.
+	if err := db.Shutdown(time.Until(deadline)); err != nil {
+		jLog.Error(err, logFrom, true)
+	}
 }
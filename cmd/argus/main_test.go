@@ -30,6 +30,10 @@ import (
 func resetFlags() {
 	configFile = test.StringPtr("")
 	configCheckFlag = test.BoolPtr(false)
+	dbMigrateOnly = test.BoolPtr(false)
+	dbBackupFlag = test.StringPtr("")
+	dbExportFlag = test.StringPtr("")
+	dbImportFlag = test.StringPtr("")
 	testCommandsFlag = test.StringPtr("")
 	testNotifyFlag = test.StringPtr("")
 	testServiceFlag = test.StringPtr("")
@@ -33,6 +33,12 @@ func (c *Controller) AnnounceCommand(index int) {
 		Failed:       c.Failed.Get(index),
 		NextRunnable: c.NextRunnable(index),
 	}
+	if output := c.Output(index); output != nil {
+		commandSummary[formatted.String()].Output = &apitype.CommandOutput{
+			Stdout:    output.Stdout,
+			Stderr:    output.Stderr,
+			Truncated: output.Truncated}
+	}
 
 	// Command success/fail.
 	var payloadData []byte
@@ -16,19 +16,24 @@
 package command
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
 	"os/exec"
+	"strconv"
 	"time"
 
+	"github.com/release-argus/Argus/history"
+	"github.com/release-argus/Argus/outbox"
 	"github.com/release-argus/Argus/service/status"
 	"github.com/release-argus/Argus/util"
 	"github.com/release-argus/Argus/web/metric"
 )
 
-// Exec will execute every `Command` for the controller.
-func (c *Controller) Exec(logFrom util.LogFrom) error {
+// Exec will execute every `Command` for the controller,
+// killing them if `ctx` is cancelled.
+func (c *Controller) Exec(ctx context.Context, logFrom util.LogFrom) error {
 	if c == nil || c.Command == nil || len(*c.Command) == 0 {
 		return nil
 	}
@@ -36,7 +41,7 @@ func (c *Controller) Exec(logFrom util.LogFrom) error {
 	errChan := make(chan error)
 	for index := range *c.Command {
 		go func(controller *Controller, index int) {
-			errChan <- controller.ExecIndex(logFrom, index)
+			errChan <- controller.DeliverIndex(ctx, logFrom, index)
 		}(c, index)
 
 		// Space out Command starts.
@@ -58,8 +63,59 @@ func (c *Controller) Exec(logFrom util.LogFrom) error {
 	return errors.Join(errs...)
 }
 
-// ExecIndex will execute the `Command` at the given index.
-func (c *Controller) ExecIndex(logFrom util.LogFrom, index int) error {
+// DeliverIndex will execute the `Command` at the given index through the outbox
+// (so a failed run is retried, and only notified of once given up on).
+//
+// Retries are templated with the Service info of this first run,
+// so they can't run for a newer version than the one approved.
+func (c *Controller) DeliverIndex(ctx context.Context, logFrom util.LogFrom, index int) error {
+	serviceInfo := c.serviceInfo()
+	attempt := func() error { return c.AttemptIndex(ctx, logFrom, index, serviceInfo) }
+	return outbox.Deliver(
+		outbox.Entry{
+			Kind:        outbox.KindCommand,
+			ServiceID:   c.serviceID(),
+			Target:      strconv.Itoa(index),
+			ServiceInfo: serviceInfo},
+		outbox.Delivery{
+			Attempt: attempt,
+			Retry:   attempt,
+			Dead:    func(err error) { c.NotifyFailure(ctx, index, serviceInfo, err) }})
+}
+
+// serviceID returns the ID of the Service this Controller is for.
+func (c *Controller) serviceID() string {
+	if c.ServiceStatus == nil {
+		return ""
+	}
+	return util.DereferenceOrDefault(c.ServiceStatus.ServiceID)
+}
+
+// serviceInfo returns the current info of the Service this Controller is for.
+func (c *Controller) serviceInfo() util.ServiceInfo {
+	if c.ServiceStatus == nil {
+		return util.ServiceInfo{}
+	}
+	return c.ServiceStatus.ServiceInfo()
+}
+
+// ExecIndex will execute the `Command` at the given index,
+// notifying of the failure if it fails.
+//
+// If `ctx` is cancelled, the Command is killed and the error of the context returned,
+// without recording a failure.
+func (c *Controller) ExecIndex(ctx context.Context, logFrom util.LogFrom, index int) error {
+	serviceInfo := c.serviceInfo()
+	err := c.AttemptIndex(ctx, logFrom, index, serviceInfo)
+	if err != nil && ctx.Err() == nil {
+		c.NotifyFailure(ctx, index, serviceInfo, err)
+	}
+	return err
+}
+
+// AttemptIndex will execute the `Command` at the given index, templated with `serviceInfo`,
+// without notifying of the failure (for the outbox to retry).
+func (c *Controller) AttemptIndex(ctx context.Context, logFrom util.LogFrom, index int, serviceInfo util.ServiceInfo) error {
 	if index >= len(*c.Command) {
 		return nil
 	}
@@ -67,10 +123,33 @@ func (c *Controller) ExecIndex(logFrom util.LogFrom, index int) error {
 	c.SetExecuting(index, true)
 
 	// Copy Command and apply Jinja templating.
-	command := (*c.Command)[index].ApplyTemplate(c.ServiceStatus)
+	options := c.Options.Get((*c.Command)[index])
+	command := (*c.Command)[index].applyTemplate(serviceInfo, options != nil && options.Shell)
 
 	// Execute.
-	err := command.Exec(logFrom)
+	record := history.Record{
+		Kind:      history.KindCommand,
+		ServiceID: c.serviceID(),
+		Target:    strconv.Itoa(index),
+		Version:   serviceInfo.LatestVersion,
+		Payload:   command.String(),
+		Time:      time.Now()}
+	output, err := command.ExecWithOptions(ctx, logFrom, options, serviceInfo)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr //nolint:wrapcheck
+	}
+	// Container logs are always kept, as they can't be seen on the host.
+	if options != nil && (options.CaptureOutput || options.Container != nil) {
+		c.SetOutput(index, output)
+	}
+	var exitErr *exec.ExitError
+	var containerExitErr *ContainerExitError
+	if errors.As(err, &exitErr) {
+		record.ResponseStatus = exitErr.ExitCode()
+	} else if errors.As(err, &containerExitErr) {
+		record.ResponseStatus = containerExitErr.ExitCode
+	}
+	history.Add(record, err)
 
 	// Set fail/not.
 	failed := err != nil
@@ -82,13 +161,6 @@ func (c *Controller) ExecIndex(logFrom util.LogFrom, index int) error {
 	metricResult := "SUCCESS"
 	if failed {
 		metricResult = "FAIL"
-		//#nosec G104 -- Errors are logged to CL
-		//nolint:errcheck // ^
-		c.Notifiers.Shoutrrr.Send(
-			fmt.Sprintf("Command failed for %q", *c.ServiceStatus.ServiceID),
-			(*c.Command)[index].String()+"\n"+err.Error(),
-			util.ServiceInfo{ID: *c.ServiceStatus.ServiceID},
-			true)
 	}
 	metric.IncPrometheusCounter(metric.CommandResultTotal,
 		(*c.Command)[index].String(),
@@ -99,22 +171,90 @@ func (c *Controller) ExecIndex(logFrom util.LogFrom, index int) error {
 	return err
 }
 
+// NotifyFailure sends the "Command failed" message for the `Command` at the given index
+// (that failed with `err` for `serviceInfo`) to the Notifiers.
+func (c *Controller) NotifyFailure(ctx context.Context, index int, serviceInfo util.ServiceInfo, err error) {
+	if index >= len(*c.Command) {
+		return
+	}
+
+	serviceInfo.Failure = err.Error()
+	//#nosec G104 -- Errors are logged to CLI
+	//nolint:errcheck // ^
+	c.Notifiers.Shoutrrr.Send(
+		ctx,
+		fmt.Sprintf("Command failed for %q", *c.ServiceStatus.ServiceID),
+		(*c.Command)[index].String()+"\n"+err.Error(),
+		serviceInfo,
+		true)
+}
+
 // Exec this Command and return any errors encountered.
-func (c *Command) Exec(logFrom util.LogFrom) error {
+func (c *Command) Exec(ctx context.Context, logFrom util.LogFrom) error {
+	_, err := c.ExecWithOptions(ctx, logFrom, nil, util.ServiceInfo{})
+	return err
+}
+
+// ExecWithOptions executes this Command with the `options` (templating their Env with `serviceInfo`),
+// and returns its output and any errors encountered.
+//
+// The Command is killed if `ctx` is cancelled.
+func (c *Command) ExecWithOptions(
+	ctx context.Context,
+	logFrom util.LogFrom,
+	options *Options,
+	serviceInfo util.ServiceInfo,
+) (*Output, error) {
 	jLog.Info(
 		fmt.Sprintf("Executing '%s'", c),
 		logFrom, true)
-	//#nosec G204 -- Command is user defined.
-	out, err := exec.Command((*c)[0], (*c)[1:]...).Output()
 
+	if timeout := options.GetTimeoutDuration(); timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	name, args := (*c)[0], (*c)[1:]
+	if options != nil && options.Shell {
+		name, args = "sh", []string{"-c", c.String()}
+	}
+	stdout := limitedBuffer{max: options.GetMaxOutput()}
+	stderr := limitedBuffer{max: options.GetMaxOutput()}
+
+	var err error
+	if options != nil && options.Container != nil {
+		err = options.Container.execContainer(ctx,
+			append([]string{name}, args...), options.templatedEnv(serviceInfo), options.Dir,
+			&stdout, &stderr)
+	} else {
+		//#nosec G204 -- Command is user defined.
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Env = options.environ(serviceInfo)
+		if options != nil {
+			cmd.Dir = options.Dir
+		}
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		err = cmd.Run()
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s: %w",
+			options.Timeout, err)
+	}
 	if err != nil {
 		jLog.Error(err, logFrom, true)
 	} else {
-		jLog.Info(string(out), logFrom, string(out) != "")
+		jLog.Info(stdout.String(), logFrom, stdout.buf.Len() != 0)
 	}
 
+	output := &Output{
+		Stdout:    stdout.String(),
+		Stderr:    stderr.String(),
+		Truncated: stdout.truncated || stderr.truncated}
+
 	//nolint:wrapcheck
-	return err
+	return output, err
 }
 
 // ApplyTemplate applies Jinja templating to the Command.
@@ -124,11 +264,21 @@ func (c *Command) ApplyTemplate(serviceStatus *status.Status) Command {
 		return *c
 	}
 
+	return c.applyTemplate(serviceStatus.ServiceInfo(), false)
+}
+
+// applyTemplate applies Jinja templating with `serviceInfo` to the Command.
+//
+// For a `shell` Command, the values are quoted (see util.TemplateShell).
+func (c *Command) applyTemplate(serviceInfo util.ServiceInfo, shell bool) Command {
+	template := util.TemplateString
+	if shell {
+		template = util.TemplateShell
+	}
 	command := Command(make([]string, len(*c)))
 	copy(command, *c)
-	serviceInfo := util.ServiceInfo{LatestVersion: serviceStatus.LatestVersion()}
 	for i, cmd := range command {
-		command[i] = util.TemplateString(cmd, serviceInfo)
+		command[i] = template(cmd, serviceInfo)
 	}
 	return command
 }
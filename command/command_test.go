@@ -17,9 +17,14 @@
 package command
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/release-argus/Argus/service/status"
 	"github.com/release-argus/Argus/test"
@@ -72,6 +77,42 @@ func TestCommand_ApplyTemplate(t *testing.T) {
 	}
 }
 
+func TestCommand_applyTemplate_Shell(t *testing.T) {
+	// GIVEN a shell Command using shell syntax around a template, and an upstream version with shell syntax in it
+	input := Command{"echo {{ version }} > version && echo done"}
+	serviceInfo := util.ServiceInfo{LatestVersion: "1.2.3; touch pwned #"}
+
+	// WHEN applyTemplate is called for a shell Command
+	got := input.applyTemplate(serviceInfo, true)
+
+	// THEN only the templated values are quoted
+	want := Command{"echo '1.2.3; touch pwned #' > version && echo done"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want: %v\ngot:  %v",
+			want, got)
+	}
+	// AND the shell syntax of the Command is run
+	dir := t.TempDir()
+	output, err := got.ExecWithOptions(
+		context.Background(),
+		util.LogFrom{},
+		&Options{Shell: true, Dir: dir},
+		serviceInfo)
+	if err != nil {
+		t.Fatalf("want the command to succeed, got %v (output %+v)",
+			err, output)
+	}
+	version, err := os.ReadFile(filepath.Join(dir, "version"))
+	if err != nil || string(version) != serviceInfo.LatestVersion+"\n" {
+		t.Errorf("want the version %q written, got %q (err=%v)",
+			serviceInfo.LatestVersion, string(version), err)
+	}
+	// AND the templated value isn't run as shell syntax
+	if _, err := os.Stat(filepath.Join(dir, "pwned")); err == nil {
+		t.Error("templated value was run as shell syntax")
+	}
+}
+
 func TestCommand_Exec(t *testing.T) {
 	// GIVEN different Commands to execute
 	tests := map[string]struct {
@@ -94,7 +135,7 @@ func TestCommand_Exec(t *testing.T) {
 			releaseStdout := test.CaptureStdout()
 
 			// WHEN Exec is called on it
-			err := tc.cmd.Exec(util.LogFrom{})
+			err := tc.cmd.Exec(context.Background(), util.LogFrom{})
 
 			// THEN the stdout is expected
 			if util.ErrorToString(err) != util.ErrorToString(tc.err) {
@@ -152,7 +193,7 @@ func TestController_ExecIndex(t *testing.T) {
 			releaseStdout := test.CaptureStdout()
 
 			// WHEN the Command @index is executed
-			err := controller.ExecIndex(util.LogFrom{}, tc.index)
+			err := controller.ExecIndex(context.Background(), util.LogFrom{}, tc.index)
 
 			// THEN the stdout is expected
 			// err
@@ -178,6 +219,49 @@ func TestController_ExecIndex(t *testing.T) {
 	}
 }
 
+func TestController_ExecIndex_Cancelled(t *testing.T) {
+	// GIVEN a Controller with a long running Command, and a context cancelled during it
+	announce := make(chan []byte, 8)
+	controller := Controller{}
+	svcStatus := status.New(
+		&announce, nil, nil,
+		"", "", "", "", "", "")
+	svcStatus.ServiceID = test.StringPtr("service_id")
+	controller.Init(
+		svcStatus,
+		&Slice{
+			{"sleep", "10"}},
+		nil,
+		test.StringPtr("13m"),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	// WHEN the Command is executed
+	start := time.Now()
+	err := controller.ExecIndex(ctx, util.LogFrom{}, 0)
+
+	// THEN the Command is killed
+	if took := time.Since(start); took > 5*time.Second {
+		t.Errorf("Command not killed, took %s",
+			took)
+	}
+	// AND the cancellation is returned
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("want %v, got %v",
+			context.Canceled, err)
+	}
+	// AND no failure is recorded/announced
+	if failed := controller.Failed.Get(0); failed != nil {
+		t.Errorf("want no result recorded, got %t",
+			*failed)
+	}
+	if len(announce) != 0 {
+		t.Errorf("want no announcement, got %d",
+			len(announce))
+	}
+}
+
 func TestController_Exec(t *testing.T) {
 	// GIVEN a Controller
 	tests := map[string]struct {
@@ -219,7 +303,7 @@ func TestController_Exec(t *testing.T) {
 			if tc.nilController {
 				controller = nil
 			}
-			err := controller.Exec(util.LogFrom{})
+			err := controller.Exec(context.Background(), util.LogFrom{})
 
 			// THEN the stdout is expected
 			// err
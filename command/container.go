@@ -0,0 +1,389 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package command provides the cli command functionality for Argus.
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultDockerHost is the Docker Engine API used when DOCKER_HOST is unset.
+const DefaultDockerHost = "unix:///var/run/docker.sock"
+
+// Container to run a Command in (instead of on the host).
+type Container struct {
+	Image      string   `yaml:"image,omitempty" json:"image,omitempty"`             // REQUIRED: Image to run the Command in, e.g. alpine:3.20.
+	Mounts     []string `yaml:"mounts,omitempty" json:"mounts,omitempty"`           // Bind mounts in the 'src:dst[:ro]' format.
+	Network    string   `yaml:"network,omitempty" json:"network,omitempty"`         // Network mode, e.g. none/bridge/host.
+	Memory     string   `yaml:"memory,omitempty" json:"memory,omitempty"`           // Memory limit, e.g. 256m.
+	CPUs       float64  `yaml:"cpus,omitempty" json:"cpus,omitempty"`               // CPU limit, e.g. 0.5.
+	AutoRemove *bool    `yaml:"auto_remove,omitempty" json:"auto_remove,omitempty"` // Remove the container once finished (default = true).
+}
+
+// ContainerExitError is returned when the Command exits non-zero in its Container.
+type ContainerExitError struct {
+	ExitCode int
+}
+
+// Error returns the exit status of the Container.
+func (e *ContainerExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.ExitCode)
+}
+
+// GetAutoRemove returns whether the container should be removed once finished.
+func (c *Container) GetAutoRemove() bool {
+	return c.AutoRemove == nil || *c.AutoRemove
+}
+
+// CheckValues validates the fields of the Container.
+func (c *Container) CheckValues(prefix string) error {
+	if c == nil {
+		return nil
+	}
+
+	var errs []error
+	// image
+	if c.Image == "" {
+		errs = append(errs,
+			fmt.Errorf("%simage: <required> (image to run the command in, e.g. alpine:3.20)",
+				prefix))
+	}
+	// mounts
+	for _, mount := range c.Mounts {
+		parts := strings.Split(mount, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" ||
+			(len(parts) == 3 && parts[2] != "ro" && parts[2] != "rw") {
+			errs = append(errs,
+				fmt.Errorf("%smounts: %q <invalid> (Use the 'src:dst[:ro]' format)",
+					prefix, mount))
+		}
+	}
+	// memory
+	if c.Memory != "" {
+		if _, err := parseMemory(c.Memory); err != nil {
+			errs = append(errs,
+				fmt.Errorf("%smemory: %q <invalid> (e.g. 256m, 1g)",
+					prefix, c.Memory))
+		}
+	}
+	// cpus
+	if c.CPUs < 0 {
+		errs = append(errs,
+			fmt.Errorf("%scpus: %g <invalid> (must be positive)",
+				prefix, c.CPUs))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// parseMemory converts a memory limit, e.g. 256m, into bytes.
+func parseMemory(memory string) (int64, error) {
+	multiplier := int64(1)
+	number := strings.ToLower(memory)
+	switch suffix := number[len(number)-1]; suffix {
+	case 'b':
+		number = number[:len(number)-1]
+	case 'k':
+		multiplier, number = 1<<10, number[:len(number)-1]
+	case 'm':
+		multiplier, number = 1<<20, number[:len(number)-1]
+	case 'g':
+		multiplier, number = 1<<30, number[:len(number)-1]
+	}
+
+	value, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid memory %q", memory)
+	}
+	return value * multiplier, nil
+}
+
+// dockerClient is a client for the Docker Engine API.
+type dockerClient struct {
+	http    *http.Client
+	baseURL string
+}
+
+// newDockerClient returns a client for the Docker Engine API at DOCKER_HOST
+// (unix:// or tcp://, default = DefaultDockerHost).
+func newDockerClient() (*dockerClient, error) {
+	host := os.Getenv("DOCKER_HOST")
+	if host == "" {
+		host = DefaultDockerHost
+	}
+
+	switch {
+	case strings.HasPrefix(host, "unix://"):
+		socket := strings.TrimPrefix(host, "unix://")
+		return &dockerClient{
+			http: &http.Client{
+				Transport: &http.Transport{
+					DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+						var dialer net.Dialer
+						return dialer.DialContext(ctx, "unix", socket)
+					}}},
+			baseURL: "http://docker"}, nil
+	case strings.HasPrefix(host, "tcp://"):
+		return &dockerClient{
+			http:    &http.Client{},
+			baseURL: "http://" + strings.TrimPrefix(host, "tcp://")}, nil
+	}
+	return nil, fmt.Errorf("unsupported DOCKER_HOST %q (use unix:// or tcp://)", host)
+}
+
+// do sends a request to the Docker Engine API, decoding the JSON response into `out` (if not nil).
+func (d *dockerClient) do(ctx context.Context, method, path string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, d.baseURL+path, reqBody)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := d.http.Do(req)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		return &dockerAPIError{
+			StatusCode: resp.StatusCode,
+			Message:    apiErr.Message}
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	//nolint:wrapcheck
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// dockerAPIError is an error response from the Docker Engine API.
+type dockerAPIError struct {
+	StatusCode int
+	Message    string
+}
+
+// Error returns the message of the Docker Engine API error.
+func (e *dockerAPIError) Error() string {
+	return fmt.Sprintf("docker: %s (%d)", e.Message, e.StatusCode)
+}
+
+// execContainer runs the `args` in the Container, with the `env` and working `dir`,
+// writing its logs to `stdout`/`stderr`.
+func (c *Container) execContainer(
+	ctx context.Context,
+	args, env []string,
+	dir string,
+	stdout, stderr io.Writer,
+) error {
+	docker, err := newDockerClient()
+	if err != nil {
+		return err
+	}
+
+	// Create.
+	hostConfig := map[string]any{
+		"Binds":       c.Mounts,
+		"NetworkMode": c.Network}
+	if c.Memory != "" {
+		memory, _ := parseMemory(c.Memory)
+		hostConfig["Memory"] = memory
+	}
+	if c.CPUs != 0 {
+		hostConfig["NanoCpus"] = int64(c.CPUs * 1e9)
+	}
+	createBody := map[string]any{
+		"Image":      c.Image,
+		"Cmd":        args,
+		"Env":        env,
+		"WorkingDir": dir,
+		"HostConfig": hostConfig}
+	var created struct {
+		ID string `json:"Id"`
+	}
+	err = docker.do(ctx, http.MethodPost, "/containers/create", createBody, &created)
+	// Pull the image if it's missing.
+	var apiErr *dockerAPIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		if err = docker.pull(ctx, c.Image); err == nil {
+			err = docker.do(ctx, http.MethodPost, "/containers/create", createBody, &created)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if c.GetAutoRemove() {
+		defer func() {
+			// Remove even if `ctx` has expired.
+			removeCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			_ = docker.do(removeCtx, http.MethodDelete, "/containers/"+created.ID+"?force=1", nil, nil)
+		}()
+	}
+
+	// Start.
+	if err := docker.do(ctx, http.MethodPost, "/containers/"+created.ID+"/start", nil, nil); err != nil {
+		return err
+	}
+
+	// Wait.
+	var waited struct {
+		StatusCode int `json:"StatusCode"`
+	}
+	waitErr := docker.do(ctx, http.MethodPost, "/containers/"+created.ID+"/wait?condition=not-running", nil, &waited)
+	if waitErr != nil && ctx.Err() != nil {
+		killCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_ = docker.do(killCtx, http.MethodPost, "/containers/"+created.ID+"/kill", nil, nil)
+	}
+
+	// Logs.
+	logsCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := docker.logs(logsCtx, created.ID, stdout, stderr); err != nil && waitErr == nil {
+		waitErr = err
+	}
+
+	if waitErr != nil {
+		return waitErr
+	}
+	if waited.StatusCode != 0 {
+		return &ContainerExitError{ExitCode: waited.StatusCode}
+	}
+	return nil
+}
+
+// pull the `image` (default tag = latest).
+//
+// The Docker Engine API streams the progress of the pull as JSON messages after its 200 response,
+// so an error of the pull is in that stream.
+func (d *dockerClient) pull(ctx context.Context, image string) error {
+	name, tag := splitImage(image)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		d.baseURL+"/images/create?fromImage="+url.QueryEscape(name)+"&tag="+url.QueryEscape(tag), nil)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	resp, err := d.http.Do(req)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		return &dockerAPIError{
+			StatusCode: resp.StatusCode,
+			Message:    apiErr.Message}
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var message struct {
+			Error string `json:"error"`
+		}
+		if err := decoder.Decode(&message); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err //nolint:wrapcheck
+		}
+		if message.Error != "" {
+			return fmt.Errorf("docker: pull %q: %s",
+				image, message.Error)
+		}
+	}
+}
+
+// splitImage splits the `image` reference into its name and tag/digest (default = latest),
+// e.g. registry:5000/alpine:3.20 into registry:5000/alpine and 3.20.
+func splitImage(image string) (name, tag string) {
+	if i := strings.LastIndex(image, "@"); i != -1 {
+		return image[:i], image[i+1:]
+	}
+	// A colon before the last '/' is of a registry port.
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, "latest"
+}
+
+// logs of the container `id`, demultiplexed into `stdout` and `stderr`.
+func (d *dockerClient) logs(ctx context.Context, id string, stdout, stderr io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		d.baseURL+"/containers/"+id+"/logs?stdout=1&stderr=1", nil)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	resp, err := d.http.Do(req)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return &dockerAPIError{StatusCode: resp.StatusCode, Message: "failed to get logs"}
+	}
+
+	// Each frame is an 8 byte header ([stream, 0, 0, 0, size (uint32)]), then the payload.
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(resp.Body, header); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err //nolint:wrapcheck
+		}
+		dst := stdout
+		if header[0] == 2 {
+			dst = stderr
+		}
+		if _, err := io.CopyN(dst, resp.Body, int64(binary.BigEndian.Uint32(header[4:]))); err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+}
@@ -0,0 +1,383 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package command
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestContainer_CheckValues(t *testing.T) {
+	// GIVEN a Container
+	tests := map[string]struct {
+		container *Container
+		errRegex  string
+	}{
+		"nil container": {
+			container: nil,
+			errRegex:  `^$`},
+		"valid container": {
+			container: &Container{
+				Image:   "alpine:3.20",
+				Mounts:  []string{"/srv:/srv", "/data:/data:ro"},
+				Network: "none",
+				Memory:  "256m",
+				CPUs:    0.5},
+			errRegex: `^$`},
+		"invalid container": {
+			container: &Container{
+				Mounts: []string{"/srv", "/a:/b:rx"},
+				Memory: "lots",
+				CPUs:   -1},
+			errRegex: test.TrimYAML(`
+				^image: <required>.*
+				mounts: "/srv" <invalid>.*
+				mounts: "/a:/b:rx" <invalid>.*
+				memory: "lots" <invalid>.*
+				cpus: -1 <invalid>.*$`)},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN CheckValues is called
+			err := tc.container.CheckValues("")
+
+			// THEN the expected error is returned
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for:\n%q\nnot:\n%q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestParseMemory(t *testing.T) {
+	// GIVEN a memory limit
+	tests := map[string]struct {
+		memory  string
+		want    int64
+		wantErr bool
+	}{
+		"bytes":      {memory: "512", want: 512},
+		"bytes unit": {memory: "512b", want: 512},
+		"kilobytes":  {memory: "2k", want: 2 << 10},
+		"megabytes":  {memory: "256M", want: 256 << 20},
+		"gigabytes":  {memory: "1g", want: 1 << 30},
+		"zero":       {memory: "0m", wantErr: true},
+		"not number": {memory: "lots", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN parseMemory is called
+			got, err := parseMemory(tc.memory)
+
+			// THEN the expected bytes are returned
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("want err=%t, got %v",
+					tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("want %d, not %d",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+// fakeDocker is a fake Docker Engine API.
+type fakeDocker struct {
+	mutex      sync.Mutex
+	imageFound bool
+	exitCode   int
+	pullError  string
+	created    map[string]any
+	pulled     url.Values
+	removed    bool
+}
+
+func (d *fakeDocker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	switch {
+	case r.URL.Path == "/images/create":
+		d.pulled = r.URL.Query()
+		// The errors of a pull are streamed after the 200 response.
+		w.Write([]byte(`{"status":"Pulling from library/alpine"}` + "\n"))
+		if d.pullError != "" {
+			w.Write([]byte(`{"errorDetail":{"message":"` + d.pullError + `"},"error":"` + d.pullError + `"}` + "\n"))
+			return
+		}
+		d.imageFound = true
+	case r.URL.Path == "/containers/create":
+		if !d.imageFound {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"No such image"}`))
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&d.created)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"Id":"abc"}`))
+	case r.URL.Path == "/containers/abc/start":
+		w.WriteHeader(http.StatusNoContent)
+	case r.URL.Path == "/containers/abc/wait":
+		json.NewEncoder(w).Encode(map[string]int{"StatusCode": d.exitCode})
+	case r.URL.Path == "/containers/abc/logs":
+		for _, frame := range []struct {
+			stream byte
+			data   string
+		}{{1, "hello "}, {2, "oops"}, {1, "world"}} {
+			header := make([]byte, 8)
+			header[0] = frame.stream
+			binary.BigEndian.PutUint32(header[4:], uint32(len(frame.data)))
+			w.Write(header)
+			w.Write([]byte(frame.data))
+		}
+	case r.Method == http.MethodDelete && r.URL.Path == "/containers/abc":
+		d.removed = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestCommand_ExecWithOptions_Container(t *testing.T) {
+	// GIVEN a Command to run in a Container, and a Docker Engine API
+	tests := map[string]struct {
+		imageFound  bool
+		pullError   string
+		exitCode    int
+		autoRemove  *bool
+		wantPulled  bool
+		wantRemoved bool
+		errRegex    string
+	}{
+		"success": {
+			imageFound:  true,
+			wantRemoved: true,
+			errRegex:    `^$`},
+		"pulls missing image": {
+			wantPulled:  true,
+			wantRemoved: true,
+			errRegex:    `^$`},
+		"pull fails": {
+			pullError:  "manifest for alpine:3.20 not found",
+			wantPulled: true,
+			errRegex:   `^docker: pull "alpine:3.20": manifest for alpine:3.20 not found$`},
+		"non-zero exit": {
+			imageFound:  true,
+			exitCode:    3,
+			wantRemoved: true,
+			errRegex:    `^exit status 3$`},
+		"auto_remove disabled": {
+			imageFound: true,
+			autoRemove: test.BoolPtr(false),
+			errRegex:   `^$`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			docker := &fakeDocker{
+				imageFound: tc.imageFound,
+				pullError:  tc.pullError,
+				exitCode:   tc.exitCode}
+			server := httptest.NewServer(docker)
+			t.Cleanup(server.Close)
+			t.Setenv("DOCKER_HOST", "tcp://"+strings.TrimPrefix(server.URL, "http://"))
+			cmd := Command{"echo", "{{ version }}"}
+			options := &Options{
+				Env: map[string]string{
+					"VERSION": "{{ version }}"},
+				Container: &Container{
+					Image:      "alpine:3.20",
+					Mounts:     []string{"/srv:/srv:ro"},
+					Network:    "none",
+					Memory:     "1m",
+					CPUs:       0.5,
+					AutoRemove: tc.autoRemove}}
+
+			// WHEN ExecWithOptions is called
+			output, err := cmd.ExecWithOptions(
+				context.Background(),
+				util.LogFrom{Primary: name},
+				options,
+				util.ServiceInfo{LatestVersion: "1.2.3"})
+
+			// THEN the expected error is returned
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			if tc.pullError != "" {
+				return
+			}
+			// AND the logs are demultiplexed into the Output
+			if output.Stdout != "hello world" || output.Stderr != "oops" {
+				t.Errorf("want stdout=%q, stderr=%q\ngot stdout=%q, stderr=%q",
+					"hello world", "oops", output.Stdout, output.Stderr)
+			}
+			// AND the container was created with the options
+			created := util.ToJSONString(docker.created)
+			for _, want := range []string{
+				`"Image":"alpine:3.20"`,
+				`"Env":["VERSION=1.2.3"]`,
+				`"Binds":["/srv:/srv:ro"]`,
+				`"NetworkMode":"none"`,
+				`"Memory":1048576`,
+				`"NanoCpus":500000000`} {
+				if !strings.Contains(created, want) {
+					t.Errorf("want %s in the create request\ngot %s",
+						want, created)
+				}
+			}
+			// AND the image was only pulled if missing, with its tag
+			if (docker.pulled != nil) != tc.wantPulled {
+				t.Errorf("want pulled=%t, not %t",
+					tc.wantPulled, docker.pulled != nil)
+			}
+			if docker.pulled != nil &&
+				(docker.pulled.Get("fromImage") != "alpine" || docker.pulled.Get("tag") != "3.20") {
+				t.Errorf("want the pull of %q with tag %q, got %v",
+					"alpine", "3.20", docker.pulled)
+			}
+			// AND the container was removed unless auto_remove is disabled
+			if docker.removed != tc.wantRemoved {
+				t.Errorf("want removed=%t, not %t",
+					tc.wantRemoved, docker.removed)
+			}
+		})
+	}
+}
+
+func TestCommand_ExecWithOptions_ContainerExitError(t *testing.T) {
+	// GIVEN a Command that exits non-zero in a Container
+	docker := &fakeDocker{
+		imageFound: true,
+		exitCode:   2}
+	server := httptest.NewServer(docker)
+	t.Cleanup(server.Close)
+	t.Setenv("DOCKER_HOST", "tcp://"+strings.TrimPrefix(server.URL, "http://"))
+
+	// WHEN the error is returned
+	_, err := (&Command{"false"}).ExecWithOptions(
+		context.Background(),
+		util.LogFrom{},
+		&Options{Container: &Container{Image: "alpine"}},
+		util.ServiceInfo{})
+
+	// THEN it is a ContainerExitError with the exit code
+	var exitErr *ContainerExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode != 2 {
+		t.Errorf("want ContainerExitError with code 2, not %#v", err)
+	}
+}
+
+func TestSplitImage(t *testing.T) {
+	// GIVEN a variety of image references
+	tests := map[string]struct {
+		image    string
+		wantName string
+		wantTag  string
+	}{
+		"no tag": {
+			image:    "alpine",
+			wantName: "alpine",
+			wantTag:  "latest"},
+		"tag": {
+			image:    "alpine:3.20",
+			wantName: "alpine",
+			wantTag:  "3.20"},
+		"registry with a port, no tag": {
+			image:    "registry:5000/team/alpine",
+			wantName: "registry:5000/team/alpine",
+			wantTag:  "latest"},
+		"registry with a port, and a tag": {
+			image:    "registry:5000/team/alpine:3.20",
+			wantName: "registry:5000/team/alpine",
+			wantTag:  "3.20"},
+		"digest": {
+			image:    "alpine@sha256:abc123",
+			wantName: "alpine",
+			wantTag:  "sha256:abc123"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN splitImage is called
+			gotName, gotTag := splitImage(tc.image)
+
+			// THEN the name and tag are split out
+			if gotName != tc.wantName || gotTag != tc.wantTag {
+				t.Errorf("want %q, %q\ngot  %q, %q",
+					tc.wantName, tc.wantTag, gotName, gotTag)
+			}
+		})
+	}
+}
+
+func TestNewDockerClient(t *testing.T) {
+	// GIVEN a DOCKER_HOST
+	tests := map[string]struct {
+		host        string
+		wantBaseURL string
+		wantErr     bool
+	}{
+		"default":     {host: "", wantBaseURL: "http://docker"},
+		"unix socket": {host: "unix:///tmp/docker.sock", wantBaseURL: "http://docker"},
+		"tcp":         {host: "tcp://127.0.0.1:2375", wantBaseURL: "http://127.0.0.1:2375"},
+		"unsupported": {host: "ssh://host", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("DOCKER_HOST", tc.host)
+
+			// WHEN newDockerClient is called
+			client, err := newDockerClient()
+
+			// THEN the client points at the DOCKER_HOST
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("want err=%t, got %v",
+					tc.wantErr, err)
+			}
+			if err == nil && client.baseURL != tc.wantBaseURL {
+				t.Errorf("want baseURL %q, not %q",
+					tc.wantBaseURL, client.baseURL)
+			}
+		})
+	}
+}
@@ -50,6 +50,7 @@ func (c *Controller) Init(
 		c.Failed.Init(commandCount)
 	}
 	c.nextRunnable = make([]time.Time, commandCount)
+	c.outputs = make([]*Output, commandCount)
 
 	c.ParentInterval = parentInterval
 
@@ -147,6 +148,30 @@ func (c *Controller) SetNextRunnable(index int, time time.Time) {
 	}
 }
 
+// Output returns the captured output of the latest run of the Command at `index`.
+// If out of range, or not captured, it will return nil.
+func (c *Controller) Output(index int) *Output {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	// out of range.
+	if index >= len(c.outputs) {
+		return nil
+	}
+
+	return c.outputs[index]
+}
+
+// SetOutput will set the captured `output` of the latest run of the Command at `index`.
+func (c *Controller) SetOutput(index int, output *Output) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if index < len(c.outputs) {
+		c.outputs[index] = output
+	}
+}
+
 // SetExecuting will set the time the Command at `index` can be re-run. (longer if `executing`).
 func (c *Controller) SetExecuting(index int, executing bool) {
 	c.mutex.Lock()
@@ -0,0 +1,196 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package command provides the cli command functionality for Argus.
+package command
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/release-argus/Argus/util"
+)
+
+// DefaultMaxOutput is the default number of bytes of stdout/stderr to store from a Command.
+const DefaultMaxOutput = 4096
+
+// OptionsSlice is a mapping of Command (in the 'arg0 arg1' format) to its Options.
+type OptionsSlice map[string]*Options
+
+// Options for running a Command.
+type Options struct {
+	Timeout       string            `yaml:"timeout,omitempty" json:"timeout,omitempty"`               // Time before the Command is killed, e.g. 5m.
+	Env           map[string]string `yaml:"env,omitempty" json:"env,omitempty"`                       // Environment variables to add (templated, and may reference ${ENV_VARS}).
+	Dir           string            `yaml:"dir,omitempty" json:"dir,omitempty"`                       // Working directory to run in.
+	Shell         bool              `yaml:"shell,omitempty" json:"shell,omitempty"`                   // Run the Command through 'sh -c' (with the template values quoted).
+	CaptureOutput bool              `yaml:"capture_output,omitempty" json:"capture_output,omitempty"` // Store the stdout/stderr of the latest run.
+	MaxOutput     int               `yaml:"max_output,omitempty" json:"max_output,omitempty"`         // Bytes of stdout/stderr to store (default = 4096).
+	Container     *Container        `yaml:"container,omitempty" json:"container,omitempty"`           // Run the Command in this Container instead of on the host.
+}
+
+// Output of a Command run.
+type Output struct {
+	Stdout    string // Standard output (up to MaxOutput bytes).
+	Stderr    string // Standard error (up to MaxOutput bytes).
+	Truncated bool   // Whether either was cut short.
+}
+
+// Get the Options of `command`.
+func (s *OptionsSlice) Get(command Command) *Options {
+	if s == nil {
+		return nil
+	}
+
+	return (*s)[command.String()]
+}
+
+// GetTimeoutDuration returns the Timeout as a time.Duration (0 = no timeout).
+func (o *Options) GetTimeoutDuration() time.Duration {
+	if o == nil {
+		return 0
+	}
+
+	timeout, _ := time.ParseDuration(o.Timeout)
+	return timeout
+}
+
+// GetMaxOutput returns the number of bytes of stdout/stderr to store.
+func (o *Options) GetMaxOutput() int {
+	if o == nil || o.MaxOutput == 0 {
+		return DefaultMaxOutput
+	}
+
+	return o.MaxOutput
+}
+
+// environ returns the environment to run the Command in, with the Env
+// templated with `serviceInfo` (nil = inherit the environment of Argus).
+func (o *Options) environ(serviceInfo util.ServiceInfo) []string {
+	if o == nil || len(o.Env) == 0 {
+		return nil
+	}
+
+	return append(os.Environ(), o.templatedEnv(serviceInfo)...)
+}
+
+// templatedEnv returns the Env in the 'KEY=value' format, templated with `serviceInfo`.
+func (o *Options) templatedEnv(serviceInfo util.ServiceInfo) []string {
+	if o == nil || len(o.Env) == 0 {
+		return nil
+	}
+
+	env := make([]string, 0, len(o.Env))
+	for _, key := range util.SortedKeys(o.Env) {
+		value := util.TemplateString(util.EvalEnvVars(o.Env[key]), serviceInfo)
+		env = append(env, key+"="+value)
+	}
+	return env
+}
+
+// CheckValues validates the fields of each Options in the Slice,
+// and that each is for one of the `commands`.
+func (s *OptionsSlice) CheckValues(prefix string, commands []string) error {
+	if s == nil {
+		return nil
+	}
+
+	var errs []error
+	itemPrefix := prefix + "  "
+	for _, key := range util.SortedKeys(*s) {
+		if !util.Contains(commands, key) {
+			errs = append(errs,
+				fmt.Errorf("%s%q: <invalid> (not a command of this service)",
+					prefix, key))
+			continue
+		}
+		util.AppendCheckError(&errs, prefix, fmt.Sprintf("%q", key), (*s)[key].CheckValues(itemPrefix))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// CheckValues validates the fields of the Options.
+func (o *Options) CheckValues(prefix string) error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	// timeout
+	if o.Timeout != "" {
+		if timeout, err := time.ParseDuration(o.Timeout); err != nil || timeout <= 0 {
+			errs = append(errs,
+				fmt.Errorf("%stimeout: %q <invalid> (Use 'AhBmCs' duration format)",
+					prefix, o.Timeout))
+		}
+	}
+	// env
+	for _, key := range util.SortedKeys(o.Env) {
+		if !util.CheckTemplate(o.Env[key]) {
+			errs = append(errs,
+				fmt.Errorf("%senv: %q <invalid> (didn't pass templating)",
+					prefix, key))
+		}
+	}
+	// dir (inside the Container if there is one).
+	if o.Dir != "" && o.Container == nil {
+		if info, err := os.Stat(o.Dir); err != nil || !info.IsDir() {
+			errs = append(errs,
+				fmt.Errorf("%sdir: %q <invalid> (not a directory)",
+					prefix, o.Dir))
+		}
+	}
+	// max_output
+	if o.MaxOutput < 0 {
+		errs = append(errs,
+			fmt.Errorf("%smax_output: %d <invalid> (must be positive)",
+				prefix, o.MaxOutput))
+	}
+	// container
+	util.AppendCheckError(&errs, prefix, "container", o.Container.CheckValues(prefix+"  "))
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// limitedBuffer is a bytes.Buffer that keeps only the first `max` bytes written.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+// Write `p` to the buffer, discarding whatever is beyond the limit.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.buf.Len(); len(p) > room {
+		b.truncated = true
+		b.buf.Write(p[:max(room, 0)])
+	} else {
+		b.buf.Write(p)
+	}
+	return len(p), nil
+}
+
+// String returns the content of the buffer.
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
@@ -0,0 +1,223 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package command
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/release-argus/Argus/service/status"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestOptionsSlice_CheckValues(t *testing.T) {
+	// GIVEN an OptionsSlice and the Commands of its Service
+	commands := []string{"./deploy.sh {{ version }}", "ls"}
+	tests := map[string]struct {
+		slice    *OptionsSlice
+		errRegex string
+	}{
+		"nil slice": {
+			slice:    nil,
+			errRegex: `^$`},
+		"valid options": {
+			slice: &OptionsSlice{
+				"./deploy.sh {{ version }}": {
+					Timeout: "5m",
+					Env: map[string]string{
+						"VERSION": "{{ version }}"},
+					Dir:           os.TempDir(),
+					CaptureOutput: true}},
+			errRegex: `^$`},
+		"unknown command": {
+			slice: &OptionsSlice{
+				"./other.sh": {}},
+			errRegex: `^"./other.sh": <invalid> \(not a command of this service\)$`},
+		"invalid options": {
+			slice: &OptionsSlice{
+				"ls": {
+					Timeout: "5x",
+					Env: map[string]string{
+						"BAD": "{{ version }"},
+					Dir:       "/does/not/exist",
+					MaxOutput: -1}},
+			errRegex: test.TrimYAML(`
+				^"ls":
+					timeout: "5x" <invalid>.*
+					env: "BAD" <invalid>.*
+					dir: "/does/not/exist" <invalid>.*
+					max_output: -1 <invalid>.*$`)},
+		"container": {
+			slice: &OptionsSlice{
+				"ls": {
+					Dir: "/only/in/container",
+					Container: &Container{
+						Memory: "lots"}}},
+			errRegex: test.TrimYAML(`
+				^"ls":
+					container:
+						image: <required>.*
+						memory: "lots" <invalid>.*$`)},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN CheckValues is called
+			err := tc.slice.CheckValues("", commands)
+
+			// THEN the expected error is returned
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for:\n%q\nnot:\n%q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestCommand_ExecWithOptions(t *testing.T) {
+	// GIVEN a Command and Options to run it with
+	t.Setenv("TEST_COMMAND_SECRET", "shazam")
+	dir := t.TempDir()
+	tests := map[string]struct {
+		cmd        Command
+		options    *Options
+		wantStdout string
+		wantStderr string
+		truncated  bool
+		errRegex   string
+	}{
+		"no options": {
+			cmd:        Command{"echo", "hello"},
+			wantStdout: "hello\n",
+			errRegex:   `^$`},
+		"env templated, with env var secrets": {
+			cmd: Command{"sh", "-c", "echo $VERSION $SECRET"},
+			options: &Options{
+				Env: map[string]string{
+					"VERSION": "{{ version }}",
+					"SECRET":  "${TEST_COMMAND_SECRET}"}},
+			wantStdout: "1.2.3 shazam\n",
+			errRegex:   `^$`},
+		"working directory": {
+			cmd: Command{"pwd"},
+			options: &Options{
+				Dir: dir},
+			wantStdout: dir + "\n",
+			errRegex:   `^$`},
+		"shell": {
+			cmd: Command{"echo hello", "&&", "echo world >&2"},
+			options: &Options{
+				Shell: true},
+			wantStdout: "hello\n",
+			wantStderr: "world\n",
+			errRegex:   `^$`},
+		"timeout": {
+			cmd: Command{"sleep", "5"},
+			options: &Options{
+				Timeout: "50ms"},
+			errRegex: `^timed out after 50ms: signal: killed$`},
+		"output truncated": {
+			cmd: Command{"sh", "-c", "echo 1234567890; echo abcdefghij >&2; exit 1"},
+			options: &Options{
+				MaxOutput: 5},
+			wantStdout: "12345",
+			wantStderr: "abcde",
+			truncated:  true,
+			errRegex:   `^exit status 1$`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN ExecWithOptions is called
+			output, err := tc.cmd.ExecWithOptions(
+				context.Background(),
+				util.LogFrom{Primary: name},
+				tc.options,
+				util.ServiceInfo{LatestVersion: "1.2.3"})
+
+			// THEN the expected error is returned
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the output is as expected
+			if output.Stdout != tc.wantStdout {
+				t.Errorf("stdout: want %q, not %q",
+					tc.wantStdout, output.Stdout)
+			}
+			if output.Stderr != tc.wantStderr {
+				t.Errorf("stderr: want %q, not %q",
+					tc.wantStderr, output.Stderr)
+			}
+			if output.Truncated != tc.truncated {
+				t.Errorf("truncated: want %t, not %t",
+					tc.truncated, output.Truncated)
+			}
+		})
+	}
+}
+
+func TestController_ExecIndex_CaptureOutput(t *testing.T) {
+	// GIVEN a Controller with Commands that may capture their output
+	announce := make(chan []byte, 8)
+	svcStatus := status.New(
+		&announce, nil, nil,
+		"", "", "", "", "", "")
+	svcStatus.ServiceID = test.StringPtr("service_id")
+	controller := Controller{}
+	controller.Init(
+		svcStatus,
+		&Slice{
+			{"sh", "-c", "echo out; echo err >&2; exit 1"},
+			{"echo", "not captured"}},
+		nil,
+		test.StringPtr("13m"))
+	controller.Options = &OptionsSlice{
+		"sh -c echo out; echo err >&2; exit 1": {
+			CaptureOutput: true}}
+
+	// WHEN each Command is executed
+	for i := range *controller.Command {
+		controller.ExecIndex(context.Background(), util.LogFrom{}, i)
+	}
+
+	// THEN the output is stored for the Command capturing it
+	got := controller.Output(0)
+	if got == nil || got.Stdout != "out\n" || !strings.Contains(got.Stderr, "err") {
+		t.Errorf("want output stored for the failed Command, got %+v",
+			got)
+	}
+	// AND not for the Command that isn't
+	if got := controller.Output(1); got != nil {
+		t.Errorf("want no output stored, got %+v",
+			got)
+	}
+	// AND out of range is nil
+	if got := controller.Output(2); got != nil {
+		t.Errorf("want nil output out of range, got %+v",
+			got)
+	}
+}
@@ -48,7 +48,9 @@ type Controller struct {
 	mutex        sync.RWMutex         // Mutex for concurrent access.
 	Command      *Slice               `yaml:"-" json:"-"` // command(s) to run (with args).
 	nextRunnable []time.Time          // Time the Commands can next be run (for staggering).
+	outputs      []*Output            // Output of the latest run of each Command (if captured).
 	Failed       *status.FailsCommand `yaml:"-" json:"-"` // Whether the last execution attempt failed.
+	Options      *OptionsSlice        `yaml:"-" json:"-"` // Options to run the Commands with.
 
 	Notifiers      Notifiers      `yaml:"-" json:"-"` // The Notifiers to notify on failures.
 	ServiceStatus  *status.Status `yaml:"-" json:"-"` // Status of the Service (used for templating commands).
@@ -84,6 +86,9 @@ func (c *Controller) CopyFailsFrom(target *Controller) {
 				failed := target.Failed.Get(i)
 				c.Failed.Set(j, *failed)
 				c.nextRunnable[j] = target.nextRunnable[i]
+				if i < len(target.outputs) && j < len(c.outputs) {
+					c.outputs[j] = target.outputs[i]
+				}
 				break
 			}
 		}
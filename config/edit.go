@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/outbox"
 	"github.com/release-argus/Argus/service"
 	"github.com/release-argus/Argus/util"
 )
@@ -44,6 +45,7 @@ func (c *Config) AddService(oldServiceID string, newService *service.Service) er
 	// Whether we need to update the database.
 	changedDB := oldServiceID == "" || c.Service[oldServiceID] == nil ||
 		!c.Service[oldServiceID].Status.SameVersions(&newService.Status)
+	var queuedVersion string
 	// New service.
 	if oldServiceID == "" || c.Service[oldServiceID] == nil {
 		jLog.Info("Adding service", logFrom, true)
@@ -56,6 +58,10 @@ func (c *Config) AddService(oldServiceID string, newService *service.Service) er
 
 		// Targeting an existing service.
 	} else {
+		// Carry over any actions queued for the change window.
+		if queued := c.Service[oldServiceID].QueuedActions(); queued != nil {
+			queuedVersion = queued.Version
+		}
 		// Keeping the same ID.
 		if oldServiceID == newService.ID {
 			jLog.Info("Replacing service", logFrom, true)
@@ -89,7 +95,8 @@ func (c *Config) AddService(oldServiceID string, newService *service.Service) er
 	}
 
 	// Start tracking the service.
-	go c.Service[newService.ID].Track()
+	go c.Service[newService.ID].Track(c.trackContext())
+	newService.RequeueActions(queuedVersion)
 
 	return nil
 }
@@ -159,6 +166,8 @@ func (c *Config) DeleteService(serviceID string) {
 
 	// Remove the service from the config.
 	delete(c.Service, serviceID)
+	// Drop its pending deliveries.
+	outbox.RemoveService(serviceID)
 
 	// Trigger save.
 	*c.HardDefaults.Service.Status.SaveChannel <- true
@@ -58,6 +58,8 @@ func testConfig() Config {
 					nil, &databaseChannel, &saveChannel)}},
 		DatabaseChannel: &databaseChannel,
 		SaveChannel:     &saveChannel,
+		saveStop:        make(chan struct{}),
+		saveStopped:     make(chan struct{}),
 	}
 }
 
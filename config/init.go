@@ -16,11 +16,16 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"os"
 
+	"github.com/release-argus/Argus/actionlink"
 	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/notify/shoutrrr"
+	"github.com/release-argus/Argus/outbox"
 	"github.com/release-argus/Argus/service"
+	"github.com/release-argus/Argus/service/approval"
 	"github.com/release-argus/Argus/service/latest_version/types/github"
 	"github.com/release-argus/Argus/service/status"
 	"github.com/release-argus/Argus/util"
@@ -35,6 +40,7 @@ func LogInit(log *util.JLog) {
 
 	jLog = log
 	service.LogInit(jLog)
+	outbox.LogInit(jLog)
 }
 
 // Init will hand out the appropriate Defaults.X and HardDefaults.X pointer(s).
@@ -48,6 +54,10 @@ func (c *Config) Init(setLog bool) {
 		&c.HardDefaults.Service.LatestVersion.Require.Docker)
 
 	c.Settings.Default()
+	status.SetPublicURL(c.Settings.WebPublicURL())
+	actionlink.SetKey(c.Settings.WebActionLinkSecret())
+	actionlink.SetExpiry(c.Settings.WebActionLinkExpiry())
+	approval.SetIdentities(c.Settings.WebApproverHeader(), c.Settings.WebBasicAuthUser())
 
 	// Options.
 	c.HardDefaults.Service.LatestVersion.Options = &c.HardDefaults.Service.Options
@@ -56,6 +66,7 @@ func (c *Config) Init(setLog bool) {
 	c.Defaults.Service.DeployedVersionLookup.Options = &c.Defaults.Service.Options
 
 	c.HardDefaults.Service.Status.SaveChannel = c.SaveChannel
+	outbox.SetRetrySucceeded(c.outboxRetrySucceeded)
 
 	if setLog {
 		jLog.SetTimestamps(*c.Settings.LogTimestamps())
@@ -75,6 +86,22 @@ func (c *Config) Init(setLog bool) {
 	}
 }
 
+// outboxRetrySucceeded registers the version change when a retry of a WebHook/Command
+// for the LatestVersion of a Service succeeds.
+func (c *Config) outboxRetrySucceeded(entry outbox.Entry) {
+	if entry.Kind == outbox.KindNotify {
+		return
+	}
+
+	c.OrderMutex.RLock()
+	svc := c.Service[entry.ServiceID]
+	c.OrderMutex.RUnlock()
+	if svc == nil || entry.ServiceInfo.LatestVersion != svc.Status.LatestVersion() {
+		return
+	}
+	svc.UpdatedVersion(true)
+}
+
 // Load `file` as Config.
 func (c *Config) Load(file string, flagset *map[string]bool, log *util.JLog) {
 	c.File = file
@@ -104,6 +131,8 @@ func (c *Config) Load(file string, flagset *map[string]bool, log *util.JLog) {
 
 	saveChannel := make(chan bool, 32)
 	c.SaveChannel = &saveChannel
+	c.saveStop = make(chan struct{})
+	c.saveStopped = make(chan struct{})
 
 	for _, svc := range c.Service {
 		svc.Status = *status.New(
@@ -130,3 +159,21 @@ func (c *Config) Load(file string, flagset *map[string]bool, log *util.JLog) {
 	c.Init(log != nil)
 	c.CheckValues()
 }
+
+// Track the Services in the config for changes in version until `ctx` is done.
+func (c *Config) Track(ctx context.Context) {
+	c.OrderMutex.Lock()
+	c.ctx = ctx
+	c.OrderMutex.Unlock()
+	shoutrrr.SetDigestContext(ctx)
+
+	go c.Service.Track(ctx, &c.Order, &c.OrderMutex)
+}
+
+// trackContext returns the context the Services are tracked with.
+func (c *Config) trackContext() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
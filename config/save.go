@@ -28,19 +28,51 @@ import (
 )
 
 // SaveHandler will listen to the `SaveChannel` and save the config (after a delay)
-// when it receives a message.
+// when it receives a message, until FlushSave is called.
 func (c *Config) SaveHandler() {
+	defer close(c.saveStopped)
 	for {
-		<-*c.SaveChannel
-		waitChannelTimeout(c.SaveChannel)
+		select {
+		case <-*c.SaveChannel:
+		case <-c.saveStop:
+			// Save any changes that arrived as we stopped.
+			if len(*c.SaveChannel) != 0 {
+				c.Save()
+			}
+			return
+		}
+
+		stopped := waitChannelTimeout(c.SaveChannel, c.saveStop)
 		c.Save()
+		if stopped {
+			return
+		}
+	}
+}
+
+// FlushSave stops the SaveHandler, saving any pending changes to the config,
+// and waits up to `timeout` for it to finish.
+func (c *Config) FlushSave(timeout time.Duration) error {
+	if c.saveStop == nil {
+		return nil
+	}
+
+	close(c.saveStop)
+	select {
+	case <-c.saveStopped:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("config not saved within %s", timeout)
 	}
 }
 
 // waitChannelTimeout will remove from `channel` and wait 30 seconds.
 //
-// Repeat until the channel is empty at the end of the 30 seconds.
-func waitChannelTimeout(channel *chan bool) {
+// Repeat until the channel is empty at the end of the 30 seconds,
+// or `stop` is closed.
+//
+// Returns whether `stop` was closed.
+func waitChannelTimeout(channel *chan bool, stop <-chan struct{}) bool {
 	for {
 		// Clear queue.
 		for len(*channel) != 0 {
@@ -48,11 +80,15 @@ func waitChannelTimeout(channel *chan bool) {
 		}
 
 		// Sleep 30s.
-		time.Sleep(30 * time.Second)
+		select {
+		case <-stop:
+			return true
+		case <-time.After(30 * time.Second):
+		}
 
 		// End if channel is still empty.
 		if len(*channel) == 0 {
-			break
+			return false
 		}
 	}
 }
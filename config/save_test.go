@@ -18,6 +18,7 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
@@ -51,6 +52,53 @@ func TestConfig_SaveHandler(t *testing.T) {
 		config.File)
 }
 
+func TestConfig_FlushSave(t *testing.T) {
+	// GIVEN a SaveHandler with/without a save pending.
+	tests := map[string]struct {
+		pending   bool
+		wantSaved bool
+	}{
+		"save pending": {
+			pending:   true,
+			wantSaved: true},
+		"no save pending": {
+			pending:   false,
+			wantSaved: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			config := testConfig()
+			config.File = filepath.Join(t.TempDir(), "config.yml")
+			go config.SaveHandler()
+			if tc.pending {
+				*config.SaveChannel <- true
+				time.Sleep(100 * time.Millisecond)
+			}
+
+			// WHEN FlushSave is called.
+			start := time.Now()
+			err := config.FlushSave(time.Second)
+
+			// THEN it returns without waiting for the save delay.
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("FlushSave took %s", elapsed)
+			}
+			// AND the config is only saved if a save was pending.
+			_, statErr := os.Stat(config.File)
+			if saved := statErr == nil; saved != tc.wantSaved {
+				t.Errorf("want saved=%t, got %t",
+					tc.wantSaved, saved)
+			}
+		})
+	}
+}
+
 func TestWaitChannelTimeout(t *testing.T) {
 	// GIVEN a Config.SaveChannel and messages to send/not send
 	tests := map[string]struct {
@@ -87,7 +135,7 @@ func TestWaitChannelTimeout(t *testing.T) {
 			}()
 			time.Sleep(time.Second)
 			start := time.Now().UTC()
-			waitChannelTimeout(config.SaveChannel)
+			waitChannelTimeout(config.SaveChannel, nil)
 
 			// THEN after `TIMEOUT`, it would have tried to Save
 			elapsed := time.Since(start)
@@ -21,6 +21,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/release-argus/Argus/test"
 	"github.com/release-argus/Argus/util"
@@ -840,6 +841,61 @@ func TestSettings_WebBasicAuthUsernameHash(t *testing.T) {
 	}
 }
 
+func TestSettings_WebBasicAuthUser(t *testing.T) {
+	// GIVEN a Settings struct with some values set
+	tests := map[string]struct {
+		want string
+		had  Settings
+	}{
+		"empty": {
+			want: "",
+		},
+		"set in config": {
+			want: "user",
+			had: Settings{
+				SettingsBase: SettingsBase{
+					Web: WebSettings{
+						BasicAuth: &WebSettingsBasicAuth{
+							Username: "user"}}}},
+		},
+		"set in flag": {
+			want: "flag",
+			had: Settings{
+				SettingsBase: SettingsBase{
+					Web: WebSettings{
+						BasicAuth: &WebSettingsBasicAuth{
+							Username: "user"}}},
+				FromFlags: SettingsBase{
+					Web: WebSettings{
+						BasicAuth: &WebSettingsBasicAuth{
+							Username: "flag"}}}},
+		},
+		"only a password": {
+			want: "",
+			had: Settings{
+				SettingsBase: SettingsBase{
+					Web: WebSettings{
+						BasicAuth: &WebSettingsBasicAuth{
+							Password: "pass"}}}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN WebBasicAuthUser is called on it
+			got := tc.had.WebBasicAuthUser()
+
+			// THEN the username is returned
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
 func TestSettings_WebBasicAuthPasswordHash(t *testing.T) {
 	// GIVEN a Settings struct with some values set
 	tests := map[string]struct {
@@ -1273,3 +1329,327 @@ func TestWebSettingsBasicAuth_CheckValues(t *testing.T) {
 		})
 	}
 }
+
+func TestDataSettings_CheckValues(t *testing.T) {
+	// GIVEN a DataSettings struct with a driver/url/history_retention/orphan_retention.
+	tests := map[string]struct {
+		driver, url      string
+		historyRetention string
+		orphanRetention  string
+		errRegex         string
+	}{
+		"empty": {
+			historyRetention: "",
+			errRegex:         `^$`},
+		"valid": {
+			historyRetention: "168h",
+			errRegex:         `^$`},
+		"invalid": {
+			historyRetention: "1w",
+			errRegex:         `^history_retention: "1w" <invalid>`},
+		"valid orphan_retention": {
+			orphanRetention: "0",
+			errRegex:        `^$`},
+		"invalid orphan_retention": {
+			orphanRetention: "30d",
+			errRegex:        `^orphan_retention: "30d" <invalid>`},
+		"sqlite driver": {
+			driver:   "SQLite",
+			errRegex: `^$`},
+		"postgres driver with url": {
+			driver:   "postgres",
+			url:      "postgres://argus@localhost/argus",
+			errRegex: `^$`},
+		"postgres driver without url": {
+			driver:   "postgres",
+			errRegex: `^url: <required>`},
+		"unknown driver": {
+			driver:   "mysql",
+			errRegex: `^driver: "mysql" <invalid>`},
+		"all errs": {
+			driver:           "mysql",
+			historyRetention: "1w",
+			orphanRetention:  "30d",
+			errRegex: `^driver: "mysql" <invalid>.*
+history_retention: "1w" <invalid>.*
+orphan_retention: "30d" <invalid>`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings := DataSettings{
+				Driver:           tc.driver,
+				URL:              tc.url,
+				HistoryRetention: tc.historyRetention,
+				OrphanRetention:  tc.orphanRetention}
+
+			// WHEN CheckValues is called.
+			err := settings.CheckValues("")
+
+			// THEN the error is as expected.
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want error matching %q\ngot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestActionLinkSettings_CheckValues(t *testing.T) {
+	// GIVEN ActionLinkSettings with an expiry.
+	tests := map[string]struct {
+		expiry   string
+		errRegex string
+	}{
+		"empty": {
+			expiry:   "",
+			errRegex: `^$`},
+		"valid": {
+			expiry:   "12h",
+			errRegex: `^$`},
+		"invalid": {
+			expiry:   "1d",
+			errRegex: `^expiry: "1d" <invalid>`},
+		"zero": {
+			expiry:   "0s",
+			errRegex: `^expiry: "0s" <invalid>`},
+		"negative": {
+			expiry:   "-1h",
+			errRegex: `^expiry: "-1h" <invalid>`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings := ActionLinkSettings{Expiry: tc.expiry}
+
+			// WHEN CheckValues is called.
+			err := settings.CheckValues("")
+
+			// THEN the error is as expected.
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want error matching %q\ngot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestShutdownSettings_CheckValues(t *testing.T) {
+	// GIVEN ShutdownSettings with a timeout.
+	tests := map[string]struct {
+		timeout  string
+		errRegex string
+	}{
+		"empty": {
+			timeout:  "",
+			errRegex: `^$`},
+		"valid": {
+			timeout:  "1m",
+			errRegex: `^$`},
+		"zero": {
+			timeout:  "0s",
+			errRegex: `^$`},
+		"invalid": {
+			timeout:  "1d",
+			errRegex: `^timeout: "1d" <invalid>`},
+		"negative": {
+			timeout:  "-1s",
+			errRegex: `^timeout: "-1s" <invalid>`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings := ShutdownSettings{Timeout: tc.timeout}
+
+			// WHEN CheckValues is called.
+			err := settings.CheckValues("")
+
+			// THEN the error is as expected.
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want error matching %q\ngot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestSettings_DataDriverURL(t *testing.T) {
+	// GIVEN Settings with/without a driver and url.
+	tests := map[string]struct {
+		driver, hardDefaultDriver string
+		url                       string
+		env                       map[string]string
+		wantDriver, wantURL       string
+	}{
+		"default": {
+			wantDriver: "sqlite"},
+		"hard default": {
+			hardDefaultDriver: "sqlite",
+			wantDriver:        "sqlite"},
+		"postgres": {
+			hardDefaultDriver: "sqlite",
+			driver:            "Postgres",
+			url:               "postgres://argus@localhost/argus",
+			wantDriver:        "postgres",
+			wantURL:           "postgres://argus@localhost/argus"},
+		"url from env": {
+			driver:     "postgres",
+			url:        "${TEST_SETTINGS_DATA_URL}",
+			env:        map[string]string{"TEST_SETTINGS_DATA_URL": "postgres://env@localhost/argus"},
+			wantDriver: "postgres",
+			wantURL:    "postgres://env@localhost/argus"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			settings := Settings{}
+			settings.HardDefaults.Data.Driver = tc.hardDefaultDriver
+			settings.Data.Driver = tc.driver
+			settings.Data.URL = tc.url
+
+			// WHEN DataDriver and DataURL are called.
+			gotDriver := settings.DataDriver()
+			gotURL := settings.DataURL()
+
+			// THEN they are as expected.
+			if gotDriver != tc.wantDriver {
+				t.Errorf("driver\nwant: %q\ngot:  %q",
+					tc.wantDriver, gotDriver)
+			}
+			if gotURL != tc.wantURL {
+				t.Errorf("url\nwant: %q\ngot:  %q",
+					tc.wantURL, gotURL)
+			}
+		})
+	}
+}
+
+func TestSettings_DataHistoryRetention(t *testing.T) {
+	// GIVEN Settings with/without a history_retention.
+	tests := map[string]struct {
+		historyRetention string
+		want             time.Duration
+	}{
+		"default": {
+			historyRetention: "",
+			want:             720 * time.Hour},
+		"set": {
+			historyRetention: "24h",
+			want:             24 * time.Hour},
+		"forever": {
+			historyRetention: "0",
+			want:             0},
+		"invalid": {
+			historyRetention: "1w",
+			want:             0},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings := Settings{}
+			settings.HardDefaults.Data.HistoryRetention = "720h"
+			settings.Data.HistoryRetention = tc.historyRetention
+
+			// WHEN DataHistoryRetention is called.
+			got := settings.DataHistoryRetention()
+
+			// THEN the retention is as expected.
+			if got != tc.want {
+				t.Errorf("want: %s\ngot:  %s",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSettings_DataOrphanRetention(t *testing.T) {
+	// GIVEN Settings with/without an orphan_retention.
+	tests := map[string]struct {
+		orphanRetention string
+		want            time.Duration
+	}{
+		"default": {
+			orphanRetention: "",
+			want:            720 * time.Hour},
+		"set": {
+			orphanRetention: "24h",
+			want:            24 * time.Hour},
+		"forever": {
+			orphanRetention: "0",
+			want:            0},
+		"invalid": {
+			orphanRetention: "1w",
+			want:            0},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings := Settings{}
+			settings.HardDefaults.Data.OrphanRetention = "720h"
+			settings.Data.OrphanRetention = tc.orphanRetention
+
+			// WHEN DataOrphanRetention is called.
+			got := settings.DataOrphanRetention()
+
+			// THEN the retention is as expected.
+			if got != tc.want {
+				t.Errorf("want: %s\ngot:  %s",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSettings_ShutdownTimeout(t *testing.T) {
+	// GIVEN Settings with/without a shutdown timeout.
+	tests := map[string]struct {
+		timeout string
+		want    time.Duration
+	}{
+		"default": {
+			timeout: "",
+			want:    30 * time.Second},
+		"set": {
+			timeout: "2m",
+			want:    2 * time.Minute},
+		"invalid": {
+			timeout: "1d",
+			want:    0},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings := Settings{}
+			settings.HardDefaults.Shutdown.Timeout = "30s"
+			settings.Shutdown.Timeout = tc.timeout
+
+			// WHEN ShutdownTimeout is called.
+			got := settings.ShutdownTimeout()
+
+			// THEN the timeout is as expected.
+			if got != tc.want {
+				t.Errorf("want: %s\ngot:  %s",
+					tc.want, got)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@
 package config
 
 import (
+	"context"
 	"sync"
 
 	dbtype "github.com/release-argus/Argus/db/types"
@@ -44,4 +45,8 @@ type Config struct {
 
 	DatabaseChannel *chan dbtype.Message `yaml:"-"` // Channel for broadcasts to the Database.
 	SaveChannel     *chan bool           `yaml:"-"` // Channel for triggering a save of the config.
+
+	ctx         context.Context // Context the Services are tracked with (done on shutdown).
+	saveStop    chan struct{}   // Closed to stop the SaveHandler.
+	saveStopped chan struct{}   // Closed once the SaveHandler has stopped.
 }
@@ -28,6 +28,15 @@ func (c *Config) CheckValues() {
 	var errs []error
 	c.Settings.CheckValues()
 
+	// settings.
+	var settingsErrs []error
+	util.AppendCheckError(&settingsErrs, "  ", "data", c.Settings.Data.CheckValues("    "))
+	var webErrs []error
+	util.AppendCheckError(&webErrs, "    ", "action_links", c.Settings.Web.ActionLinks.CheckValues("      "))
+	util.AppendCheckError(&settingsErrs, "  ", "web", errors.Join(webErrs...))
+	util.AppendCheckError(&settingsErrs, "  ", "shutdown", c.Settings.Shutdown.CheckValues("    "))
+	util.AppendCheckError(&errs, "", "settings", errors.Join(settingsErrs...))
+
 	// defaults.
 	util.AppendCheckError(&errs, "", "defaults", c.Defaults.CheckValues("  "))
 	// notify.
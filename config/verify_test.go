@@ -184,7 +184,7 @@ func TestConfig_Print(t *testing.T) {
 		flag  bool
 		lines int
 	}{
-		"flag on":  {flag: true, lines: 190 + len(config.Defaults.Notify)},
+		"flag on":  {flag: true, lines: 202 + len(config.Defaults.Notify)},
 		"flag off": {flag: false},
 	}
 
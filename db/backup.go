@@ -0,0 +1,390 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package db provides database functionality for Argus to keep track of versions found/deployed/approved.
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/release-argus/Argus/config"
+	"github.com/release-argus/Argus/notify/shoutrrr"
+	"github.com/release-argus/Argus/outbox"
+	"github.com/release-argus/Argus/util"
+)
+
+// exportTables are the tables in an export, in the order they're imported.
+var exportTables = []string{"status", "version_event", "history", "approval", "notify_digest", "outbox"}
+
+// exportData is a JSON export of the rows of each table.
+type exportData struct {
+	SchemaVersion int                         `json:"schema_version"` // Schema version of the database exported.
+	Exported      string                      `json:"exported"`       // Time of the export.
+	Tables        map[string][]map[string]any `json:"tables"`         // Rows of each table, keyed by column.
+}
+
+// running is the api of the database Run, for backups/exports/imports while Argus runs.
+var running atomic.Pointer[api]
+
+// errNotRunning is returned by Backup/Export/Import when the database hasn't been Run.
+var errNotRunning = errors.New("database not running")
+
+// Backup takes a consistent copy of the running database, writing it to `path`.
+func Backup(path string) error {
+	api := running.Load()
+	if api == nil {
+		return errNotRunning
+	}
+	return api.backup(path)
+}
+
+// Export writes the rows of each table of the running database to `w` as JSON.
+func Export(w io.Writer) error {
+	api := running.Load()
+	if api == nil {
+		return errNotRunning
+	}
+	return api.export(w)
+}
+
+// Import replaces the tables of the running database with those in the JSON export read from `r`,
+// and restores the status/approvals, pending digests and outbox of the Services from them.
+//
+// Returns the number of rows imported.
+func Import(r io.Reader) (int, error) {
+	api := running.Load()
+	if api == nil {
+		return 0, errNotRunning
+	}
+
+	// Forget the pending digests/deliveries, to restore those in the database once imported.
+	shoutrrr.ClearDigests()
+	outbox.Clear()
+	// Import between writes, so the messages already queued don't overwrite the imported rows.
+	var (
+		count     int
+		importErr error
+	)
+	if err := api.exclusive(func() {
+		count, importErr = api.importJSON(r)
+
+		api.extractServiceStatus()
+		api.extractNotifyDigests()
+		api.extractApprovals()
+		api.extractOutbox()
+	}); err != nil {
+		return 0, err
+	}
+	if importErr != nil {
+		return 0, importErr
+	}
+	return count, nil
+}
+
+// backup takes a consistent copy of the database, writing it to `path`.
+func (api *api) backup(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("backup %q already exists", path)
+	}
+	return api.storage.backup(api.db, path)
+}
+
+// export writes the rows of each table to `w` as JSON.
+func (api *api) export(w io.Writer) error {
+	// Read all tables in one transaction for a consistent export.
+	tx, err := api.db.BeginTx(context.Background(),
+		&sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	//#nosec G104 -- Read-only.
+	//nolint:errcheck // ^
+	defer tx.Rollback()
+
+	export := exportData{
+		Exported: time.Now().UTC().Format(time.RFC3339),
+		Tables:   make(map[string][]map[string]any, len(exportTables))}
+	if err := tx.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_version;").Scan(&export.SchemaVersion); err != nil {
+		return fmt.Errorf("schema_version: %w", err)
+	}
+	for _, table := range exportTables {
+		rows, err := exportTable(tx, table)
+		if err != nil {
+			return fmt.Errorf("export %s: %w", table, err)
+		}
+		export.Tables[table] = rows
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(export)
+}
+
+// exportTable returns the rows of `table`, keyed by column.
+func exportTable(tx *sql.Tx, table string) ([]map[string]any, error) {
+	//#nosec G202 -- table is from exportTables.
+	rows, err := tx.Query("SELECT * FROM " + table + ";")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	result := []map[string]any{}
+	for rows.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			switch value := values[i].(type) {
+			case []byte:
+				row[column] = string(value)
+			case time.Time:
+				// As the text it was stored as.
+				row[column] = value.Format(time.RFC3339Nano)
+			default:
+				row[column] = value
+			}
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// importJSON replaces the tables with those in the JSON export read from `r`, in one transaction.
+//
+// Returns the number of rows imported.
+func (api *api) importJSON(r io.Reader) (int, error) {
+	var export exportData
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := decoder.Decode(&export); err != nil {
+		return 0, fmt.Errorf("invalid export: %w", err)
+	}
+	var unknown []string
+	for table := range export.Tables {
+		if !util.Contains(exportTables, table) {
+			unknown = append(unknown, table)
+		}
+	}
+	if len(unknown) != 0 {
+		return 0, fmt.Errorf("invalid export: unknown tables %s",
+			strings.Join(unknown, ", "))
+	}
+
+	tx, err := api.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	//#nosec G104 -- Rollback after a Commit is a no-op.
+	//nolint:errcheck // ^
+	defer tx.Rollback()
+
+	var schemaVersion int
+	if err := tx.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_version;").Scan(&schemaVersion); err != nil {
+		return 0, fmt.Errorf("schema_version: %w", err)
+	}
+	if export.SchemaVersion > schemaVersion {
+		return 0, fmt.Errorf("export is from schema version %d, but the database is at %d - upgrade Argus to import it",
+			export.SchemaVersion, schemaVersion)
+	}
+
+	count := 0
+	for _, table := range exportTables {
+		rows, ok := export.Tables[table]
+		if !ok {
+			continue
+		}
+		if err := api.importTable(tx, table, rows); err != nil {
+			return 0, fmt.Errorf("import %s: %w", table, err)
+		}
+		count += len(rows)
+	}
+
+	return count, tx.Commit()
+}
+
+// importTable replaces the rows of `table` with `rows`.
+func (api *api) importTable(tx *sql.Tx, table string, rows []map[string]any) error {
+	// Columns of the table, to only insert those that exist.
+	//#nosec G202 -- table is from exportTables.
+	columnRows, err := tx.Query("SELECT * FROM " + table + " WHERE 1 = 0;")
+	if err != nil {
+		return err
+	}
+	columns, err := columnRows.Columns()
+	columnRows.Close()
+	if err != nil {
+		return err
+	}
+
+	//#nosec G202 -- table is from exportTables.
+	if _, err := tx.Exec("DELETE FROM " + table + ";"); err != nil {
+		return err
+	}
+	for i, row := range rows {
+		var (
+			names  []string
+			values []any
+		)
+		for column, value := range row {
+			if !util.Contains(columns, column) {
+				return fmt.Errorf("row %d: unknown column %q",
+					i, column)
+			}
+			names = append(names, api.storage.quote(column))
+			if number, ok := value.(json.Number); ok {
+				value = number.String()
+			}
+			values = append(values, value)
+		}
+		if len(names) == 0 {
+			continue
+		}
+
+		//#nosec G201 -- table is from exportTables, and names are its columns.
+		sqlStmt := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);",
+			table,
+			strings.Join(names, ","),
+			strings.TrimSuffix(strings.Repeat("?,", len(names)), ","))
+		if _, err := tx.Exec(api.storage.rebind(sqlStmt), values...); err != nil {
+			return fmt.Errorf("row %d: %w",
+				i, err)
+		}
+	}
+	return nil
+}
+
+// BackupFile will take a backup of the database to the path in `flag` and exit, if `flag` is set.
+func BackupFile(
+	flag *string,
+	cfg *config.Config,
+	log *util.JLog,
+) {
+	// Only if flag provided.
+	if *flag == "" {
+		return
+	}
+	api := commandAPI(cfg, log)
+	err := api.backup(*flag)
+	api.db.Close()
+	if err != nil {
+		jLog.Fatal(
+			fmt.Sprintf("backup: %s", err),
+			logFrom, true)
+	}
+	jLog.Info(
+		fmt.Sprintf("Database backed up to %q", *flag),
+		logFrom, true)
+	exitCommand()
+}
+
+// ExportFile will export the database as JSON to the path in `flag` and exit, if `flag` is set.
+func ExportFile(
+	flag *string,
+	cfg *config.Config,
+	log *util.JLog,
+) {
+	// Only if flag provided.
+	if *flag == "" {
+		return
+	}
+	api := commandAPI(cfg, log)
+	//#nosec G304 -- Path from the CLI.
+	file, err := os.OpenFile(*flag, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0_600)
+	if err == nil {
+		err = api.export(file)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	api.db.Close()
+	if err != nil {
+		jLog.Fatal(
+			fmt.Sprintf("export: %s", err),
+			logFrom, true)
+	}
+	jLog.Info(
+		fmt.Sprintf("Database exported to %q", *flag),
+		logFrom, true)
+	exitCommand()
+}
+
+// ImportFile will import the JSON export at the path in `flag` into the database and exit, if `flag` is set.
+func ImportFile(
+	flag *string,
+	cfg *config.Config,
+	log *util.JLog,
+) {
+	// Only if flag provided.
+	if *flag == "" {
+		return
+	}
+	api := commandAPI(cfg, log)
+	//#nosec G304 -- Path from the CLI.
+	file, err := os.Open(*flag)
+	var count int
+	if err == nil {
+		count, err = api.importJSON(file)
+		file.Close()
+	}
+	api.db.Close()
+	if err != nil {
+		jLog.Fatal(
+			fmt.Sprintf("import: %s", err),
+			logFrom, true)
+	}
+	jLog.Info(
+		fmt.Sprintf("Imported %d rows from %q", count, *flag),
+		logFrom, true)
+	exitCommand()
+}
+
+// commandAPI returns an api on the (migrated) database of `cfg`, for the db.* CLI flags.
+func commandAPI(cfg *config.Config, log *util.JLog) *api {
+	store := newStorage(&cfg.Settings)
+	if log != nil {
+		LogInit(log, store.String())
+	}
+	api := api{config: cfg, storage: store}
+
+	api.initialise()
+	return &api
+}
+
+// exitCommand exits after a db.* CLI flag has been handled (unless testing).
+func exitCommand() {
+	if !jLog.Testing {
+		os.Exit(0)
+	}
+}
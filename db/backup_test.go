@@ -0,0 +1,336 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package db
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/history"
+	"github.com/release-argus/Argus/outbox"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+// testBackupRows inserts rows into the status, history and approval tables of `tAPI`.
+func testBackupRows(t *testing.T, tAPI *api) {
+	t.Helper()
+	for _, stmt := range []string{
+		`INSERT INTO status (id, latest_version, latest_version_timestamp, approved_version)
+			VALUES ('keep0', '1.2.3', '2025-01-02T03:04:05Z', '1.2.3')`,
+		`INSERT INTO approval (id, approvals)
+			VALUES ('keep0', '[{"approver":"alice"}]')`,
+	} {
+		if _, err := tAPI.db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testHistoryRows(t, tAPI, []history.Record{
+		{ID: "a", Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Kind: history.KindWebHook,
+			ServiceID: "keep0", Target: "wh", Duration: 2 * time.Second}})
+}
+
+// testExport returns the export of `tAPI`, without its time.
+func testExport(t *testing.T, tAPI *api) exportData {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := tAPI.export(&buf); err != nil {
+		t.Fatalf("export: %v",
+			err)
+	}
+	var export exportData
+	if err := json.Unmarshal(buf.Bytes(), &export); err != nil {
+		t.Fatalf("export is not JSON: %v\n%s",
+			err, buf.String())
+	}
+	export.Exported = ""
+	return export
+}
+
+func TestAPI_backup(t *testing.T) {
+	// GIVEN a DB with rows.
+	tAPI := testAPI("TestAPI_backup", "db")
+	t.Cleanup(func() { dbCleanup(tAPI) })
+	tAPI.initialise()
+	testBackupRows(t, tAPI)
+	tests := map[string]struct {
+		exists   bool
+		errRegex string
+	}{
+		"new file": {
+			errRegex: `^$`},
+		"file already exists": {
+			exists:   true,
+			errRegex: `^backup "[^"]+" already exists$`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := strings.ReplaceAll("TestAPI_backup-"+name+".db", " ", "_")
+			t.Cleanup(func() { os.Remove(path) })
+			if tc.exists {
+				os.WriteFile(path, []byte("x"), 0_600)
+			}
+
+			// WHEN backup is called.
+			err := tAPI.backup(path)
+
+			// THEN any error is as expected.
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Fatalf("want error matching %q, got %q",
+					tc.errRegex, e)
+			}
+			if tc.exists {
+				return
+			}
+			// AND the backup has the rows.
+			backup, err := sql.Open("sqlite", path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer backup.Close()
+			if got := queryRow(t, backup, "keep0"); got.LatestVersion() != "1.2.3" {
+				t.Errorf("want latest_version %q in the backup, got %q",
+					"1.2.3", got.LatestVersion())
+			}
+		})
+	}
+}
+
+func TestAPI_exportImport(t *testing.T) {
+	// GIVEN a DB with rows, and an empty DB.
+	from := testAPI("TestAPI_exportImport", "from")
+	t.Cleanup(func() { dbCleanup(from) })
+	from.initialise()
+	testBackupRows(t, from)
+	to := testAPI("TestAPI_exportImport", "to")
+	t.Cleanup(func() { dbCleanup(to) })
+	to.initialise()
+	// that already has a row to be replaced.
+	if _, err := to.db.Exec("INSERT INTO status (id, latest_version) VALUES ('delete0', '0.0.1')"); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN the export of one is imported into the other.
+	var buf bytes.Buffer
+	if err := from.export(&buf); err != nil {
+		t.Fatalf("export: %v",
+			err)
+	}
+	count, err := to.importJSON(&buf)
+
+	// THEN the rows are imported.
+	if err != nil {
+		t.Fatalf("import: %v",
+			err)
+	}
+	if count != 3 {
+		t.Errorf("want 3 rows imported, got %d",
+			count)
+	}
+	// AND both DBs now export the same rows.
+	want, got := testExport(t, from), testExport(t, to)
+	wantJSON, _ := json.Marshal(want)
+	gotJSON, _ := json.Marshal(got)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("want\n%s\ngot\n%s",
+			wantJSON, gotJSON)
+	}
+	// AND the types were kept.
+	if history := got.Tables["history"]; len(history) != 1 || history[0]["duration_ms"] != float64(2000) {
+		t.Errorf("want history duration_ms of 2000, got %v",
+			history)
+	}
+	if status := got.Tables["status"]; len(status) != 1 || status[0]["latest_version_timestamp"] != "2025-01-02T03:04:05Z" {
+		t.Errorf("want status latest_version_timestamp of %q, got %v",
+			"2025-01-02T03:04:05Z", status)
+	}
+}
+
+func TestImport(t *testing.T) {
+	// GIVEN a running DB with writes queued, and pending deliveries in the outbox.
+	from := testAPI("TestImport", "from")
+	t.Cleanup(func() { dbCleanup(from) })
+	from.initialise()
+	testBackupRows(t, from)
+	var export bytes.Buffer
+	if err := from.export(&export); err != nil {
+		t.Fatalf("export: %v",
+			err)
+	}
+	tAPI := testAPI("TestImport", "db")
+	t.Cleanup(func() { dbCleanup(tAPI) })
+	tAPI.shutdown = make(chan struct{})
+	tAPI.stopped = make(chan struct{})
+	tAPI.tasks = make(chan func())
+	tAPI.initialise()
+	*tAPI.config.DatabaseChannel <- dbtype.Message{
+		ServiceID: "keep0",
+		Cells:     []dbtype.Cell{{Column: "latest_version", Value: "0.0.1"}}}
+	outbox.Restore(
+		outbox.Entry{ID: "stale", Kind: outbox.KindWebHook, ServiceID: "keep0", Target: "wh",
+			NextAttempt: time.Now().Add(time.Hour)},
+		outbox.Delivery{Retry: func() error { return nil }})
+	t.Cleanup(outbox.Clear)
+	releaseStdout := test.CaptureStdout()
+	defer releaseStdout()
+	go tAPI.handler()
+	running.Store(tAPI)
+	t.Cleanup(func() {
+		running.Store(nil)
+		close(tAPI.shutdown)
+		<-tAPI.stopped
+	})
+
+	// WHEN an export is imported.
+	count, err := Import(&export)
+
+	// THEN the rows are imported.
+	if err != nil {
+		t.Fatalf("import: %v",
+			err)
+	}
+	if count != 3 {
+		t.Errorf("want 3 rows imported, got %d",
+			count)
+	}
+	// AND the writes queued before the import didn't overwrite them.
+	db, _ := sql.Open("sqlite", tAPI.config.Settings.Data.DatabaseFile)
+	defer db.Close()
+	if got := queryRow(t, db, "keep0").LatestVersion(); got != "1.2.3" {
+		t.Errorf("want latest_version %q imported, got %q",
+			"1.2.3", got)
+	}
+	// AND the status of the Service is restored from them.
+	if got := tAPI.config.Service["keep0"].Status.LatestVersion(); got != "1.2.3" {
+		t.Errorf("want the Service latest_version %q, got %q",
+			"1.2.3", got)
+	}
+	// AND the outbox is replaced with that imported.
+	if list := outbox.List(); len(list) != 0 {
+		t.Errorf("want the outbox emptied, got %+v",
+			list)
+	}
+}
+
+func TestAPI_importJSON(t *testing.T) {
+	// GIVEN exports that can't be imported.
+	tests := map[string]struct {
+		export   string
+		errRegex string
+	}{
+		"invalid JSON": {
+			export:   `{`,
+			errRegex: `^invalid export: `},
+		"unknown table": {
+			export:   `{"tables": {"unknown": []}}`,
+			errRegex: `^invalid export: unknown tables unknown$`},
+		"unknown column": {
+			export:   `{"tables": {"status": [{"id": "keep0", "unknown": "x"}]}}`,
+			errRegex: `^import status: row 0: unknown column "unknown"$`},
+		"newer schema": {
+			export:   `{"schema_version": 9999, "tables": {}}`,
+			errRegex: `^export is from schema version 9999, but the database is at \d+ `},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tAPI := testAPI("TestAPI_importJSON", name)
+			t.Cleanup(func() { dbCleanup(tAPI) })
+			tAPI.initialise()
+			testBackupRows(t, tAPI)
+
+			// WHEN importJSON is called.
+			count, err := tAPI.importJSON(strings.NewReader(tc.export))
+
+			// THEN it errors.
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Fatalf("want error matching %q, got %q",
+					tc.errRegex, e)
+			}
+			if count != 0 {
+				t.Errorf("want 0 rows imported, got %d",
+					count)
+			}
+			// AND the rows were kept.
+			if got := queryRow(t, tAPI.db, "keep0"); got.LatestVersion() != "1.2.3" {
+				t.Errorf("want latest_version %q kept, got %q",
+					"1.2.3", got.LatestVersion())
+			}
+		})
+	}
+}
+
+func TestDatabaseFileCommands(t *testing.T) {
+	// GIVEN a DB with rows, and the db.backup/export/import flags.
+	tAPI := testAPI("TestDatabaseFileCommands", "db")
+	t.Cleanup(func() { dbCleanup(tAPI) })
+	tAPI.initialise()
+	testBackupRows(t, tAPI)
+	tAPI.db.Close()
+	exportFile := "TestDatabaseFileCommands.json"
+	backupFile := "TestDatabaseFileCommands-backup.db"
+	t.Cleanup(func() {
+		os.Remove(exportFile)
+		os.Remove(backupFile)
+	})
+	releaseStdout := test.CaptureStdout()
+	defer releaseStdout()
+
+	// WHEN the flags are unset.
+	BackupFile(test.StringPtr(""), tAPI.config, nil)
+	ExportFile(test.StringPtr(""), tAPI.config, nil)
+	ImportFile(test.StringPtr(""), tAPI.config, nil)
+	// THEN no files are written.
+	for _, path := range []string{exportFile, backupFile} {
+		if _, err := os.Stat(path); err == nil {
+			t.Fatalf("%q written with the flags unset",
+				path)
+		}
+	}
+
+	// WHEN the flags are set.
+	BackupFile(&backupFile, tAPI.config, nil)
+	ExportFile(&exportFile, tAPI.config, nil)
+	// THEN the backup/export are written.
+	for _, path := range []string{exportFile, backupFile} {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("%q not written: %v",
+				path, err)
+		}
+	}
+
+	// WHEN the export is imported into a new DB.
+	to := testAPI("TestDatabaseFileCommands", "to")
+	t.Cleanup(func() { dbCleanup(to) })
+	ImportFile(&exportFile, to.config, nil)
+	// THEN the rows are in it.
+	db, _ := sql.Open("sqlite", to.config.Settings.Data.DatabaseFile)
+	defer db.Close()
+	if got := queryRow(t, db, "keep0"); got.ApprovedVersion() != "1.2.3" {
+		t.Errorf("want approved_version %q imported, got %q",
+			"1.2.3", got.ApprovedVersion())
+	}
+}
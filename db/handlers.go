@@ -18,33 +18,242 @@ package db
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/web/metric"
 )
 
-// handler will listen to the DatabaseChannel and act on
-// incoming messages to the DatabaseChannel.
+const (
+	// historyPruneInterval is how often history older than the retention is removed.
+	historyPruneInterval = time.Hour
+	// maxBatchSize is the most messages written in one transaction.
+	maxBatchSize = 128
+	// maxWriteAttempts is how many times a batch is attempted whilst the database is busy.
+	maxWriteAttempts = 5
+	// writeRetryDelay is the delay before retrying a busy write (doubling with each attempt).
+	writeRetryDelay = 50 * time.Millisecond
+)
+
+// handler will listen to the DatabaseChannel and write
+// the incoming messages to the database in batches.
 func (api *api) handler() {
-	defer api.db.Close()
-	for message := range *api.config.DatabaseChannel {
-		// If the message is to delete a row.
-		if message.Delete {
-			api.deleteRow(message.ServiceID)
-			continue
+	defer func() {
+		api.db.Close()
+		if api.stopped != nil {
+			close(api.stopped)
+		}
+	}()
+	pruneTicker := time.NewTicker(historyPruneInterval)
+	defer pruneTicker.Stop()
+	for {
+		select {
+		case message, ok := <-*api.config.DatabaseChannel:
+			if !ok {
+				return
+			}
+			batch, open := api.collectBatch(message)
+			api.writeBatch(batch)
+			if !open {
+				return
+			}
+		case <-pruneTicker.C:
+			api.pruneHistory()
+		case task := <-api.tasks:
+			open := api.flush()
+			task()
+			if !open {
+				return
+			}
+		case <-api.shutdown:
+			api.flush()
+			return
+		}
+	}
+}
+
+// flush writes the messages already sent to the DatabaseChannel,
+// and returns whether the DatabaseChannel is still open.
+func (api *api) flush() bool {
+	for {
+		batch, open := api.collectBatch()
+		if len(batch) == 0 {
+			return open
+		}
+		api.writeBatch(batch)
+		if !open {
+			return false
+		}
+	}
+}
+
+// exclusive runs `task` on the handler once the messages already sent to the DatabaseChannel are written,
+// so no other writes are made while it runs.
+func (api *api) exclusive(task func()) error {
+	done := make(chan struct{})
+	select {
+	case api.tasks <- func() {
+		defer close(done)
+		task()
+	}:
+	case <-api.stopped:
+		return errNotRunning
+	}
+	<-done
+	return nil
+}
+
+// collectBatch returns the `messages` along with those waiting in the DatabaseChannel (up to maxBatchSize),
+// and whether the DatabaseChannel is still open.
+func (api *api) collectBatch(messages ...dbtype.Message) ([]dbtype.Message, bool) {
+	for len(messages) < maxBatchSize {
+		select {
+		case message, ok := <-*api.config.DatabaseChannel:
+			if !ok {
+				return messages, false
+			}
+			messages = append(messages, message)
+		default:
+			return messages, true
+		}
+	}
+	return messages, true
+}
+
+// writeBatch writes the `batch` of messages in one transaction,
+// falling back to a transaction per message if that fails.
+func (api *api) writeBatch(batch []dbtype.Message) {
+	if len(batch) == 0 {
+		return
+	}
+	start := time.Now()
+	metric.DatabaseQueueLength.Set(float64(len(*api.config.DatabaseChannel)))
+	metric.DatabaseBatchSize.Observe(float64(len(batch)))
+
+	err := api.writeMessages(batch)
+	if err != nil && len(batch) > 1 {
+		// Write the messages individually, so one failing doesn't lose the others.
+		jLog.Warn(
+			fmt.Sprintf("writing %d messages failed, retrying individually - %s",
+				len(batch), err),
+			logFrom, true)
+		for _, message := range batch {
+			if err := api.writeMessages([]dbtype.Message{message}); err != nil {
+				api.writeFailed(message, err)
+			}
+		}
+	} else if err != nil {
+		api.writeFailed(batch[0], err)
+	} else {
+		metric.DatabaseWriteResultTotal.WithLabelValues("SUCCESS").Inc()
+	}
+	metric.DatabaseWriteSeconds.Observe(time.Since(start).Seconds())
+}
+
+// writeFailed logs that the `message` could not be written.
+func (api *api) writeFailed(message dbtype.Message, err error) {
+	table := message.Table
+	if table == "" {
+		table = "status"
+	}
+	jLog.Error(
+		fmt.Sprintf("write %s %q: %s",
+			table, message.ServiceID, err),
+		logFrom, true)
+	metric.DatabaseWriteResultTotal.WithLabelValues("FAIL").Inc()
+}
+
+// writeMessages applies the `messages` in a transaction, retrying whilst the database is busy.
+func (api *api) writeMessages(messages []dbtype.Message) error {
+	delay := writeRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := api.writeTransaction(messages)
+		if err == nil || attempt == maxWriteAttempts || !api.storage.transient(err) {
+			return err
+		}
+
+		metric.DatabaseWriteResultTotal.WithLabelValues("RETRY").Inc()
+		jLog.Verbose(
+			fmt.Sprintf("database busy, retrying in %s - %s",
+				delay, err),
+			logFrom, true)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// writeTransaction applies the `messages` in one transaction.
+func (api *api) writeTransaction(messages []dbtype.Message) error {
+	tx, err := api.db.Begin()
+	if err != nil {
+		return err
+	}
+	//#nosec G104 -- Rollback after a Commit is a no-op.
+	//nolint:errcheck // ^
+	defer tx.Rollback()
+
+	// Run the statements of the messages on the transaction.
+	txAPI := *api
+	txAPI.tx = tx
+	for _, message := range messages {
+		if err := txAPI.handleMessage(message); err != nil {
+			return err
 		}
+	}
+	return tx.Commit()
+}
+
+// handleMessage applies the `message` to its table.
+func (api *api) handleMessage(message dbtype.Message) error {
+	table := message.Table
+	if table == "" {
+		table = "status"
+	}
 
-		// Else, the message is to update a row.
-		api.updateRow(
-			message.ServiceID,
-			message.Cells,
-		)
+	// If the message is to delete a row.
+	if message.Delete {
+		if err := api.deleteTableRow(table, message.ServiceID); err != nil {
+			return err
+		}
+		if table == "status" {
+			return api.deleteVersionEvents(message.ServiceID)
+		}
+		return nil
 	}
+
+	// Keep the version history of the Service.
+	if table == "status" {
+		if err := api.recordVersionEvents(message); err != nil {
+			return err
+		}
+		for _, cell := range message.Cells {
+			if cell.Column == "id" {
+				if err := api.renameVersionEvents(message.ServiceID, cell.Value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Else, the message is to update a row.
+	return api.updateTableRow(
+		table,
+		message.ServiceID,
+		message.Cells,
+	)
 }
 
 // updateRow will update the cells of the serviceID row.
 func (api *api) updateRow(serviceID string, cells []dbtype.Cell) {
+	if err := api.updateTableRow("status", serviceID, cells); err != nil {
+		jLog.Error(err, logFrom, true)
+	}
+}
+
+// updateTableRow will update the cells of the `id` row in `table`.
+func (api *api) updateTableRow(table, id string, cells []dbtype.Cell) error {
 	if len(cells) == 0 {
-		return
+		return nil
 	}
 
 	// The columns to update.
@@ -56,19 +265,18 @@ func (api *api) updateRow(serviceID string, cells []dbtype.Cell) {
 		}
 
 		// `column` = ?
-		setVarsBuilder.WriteString("`")
-		setVarsBuilder.WriteString(cell.Column)
-		setVarsBuilder.WriteString("` = ?")
+		setVarsBuilder.WriteString(api.storage.quote(cell.Column))
+		setVarsBuilder.WriteString(" = ?")
 	}
 
 	// The SQL statement.
-	//#nosec G201 -- setVarsBuilder is built from trusted sources.
-	sqlStmt := fmt.Sprintf("UPDATE status SET %s WHERE id = ?",
-		setVarsBuilder.String())
+	//#nosec G201 -- table and setVarsBuilder are built from trusted sources.
+	sqlStmt := fmt.Sprintf("UPDATE %s SET %s WHERE id = ?",
+		table, setVarsBuilder.String())
 
 	// Get the vars for the SQL statement.
 	params := make([]any, len(cells)+1)
-	params[len(params)-1] = serviceID
+	params[len(params)-1] = id
 	// The values to update with.
 	for i := range cells {
 		params[i] = cells[i].Value
@@ -79,23 +287,20 @@ func (api *api) updateRow(serviceID string, cells []dbtype.Cell) {
 			fmt.Sprintf("%s, %v", sqlStmt, params),
 			logFrom, true)
 	}
-	res, err := api.db.Exec(sqlStmt, params...)
+	res, err := api.exec(sqlStmt, params...)
 	// Query failed.
 	if err != nil {
-		jLog.Error(
-			fmt.Sprintf("updateRow UPDATE: %q %v, %s",
-				sqlStmt, params, err),
-			logFrom, true)
-		return
+		return fmt.Errorf("updateRow UPDATE: %q %v, %w",
+			sqlStmt, params, err)
 	}
 
 	count, _ := res.RowsAffected()
 	// If the row was updated, return.
 	if count != 0 {
-		return
+		return nil
 	}
 
-	// This ServiceID was not in the DB, insert it.
+	// This ID was not in the DB, insert it.
 	// The columns to insert.
 	var columnsBuilder strings.Builder           // `column`,`column`,...
 	var valuesPlaceholderBuilder strings.Builder // ?,?,...
@@ -107,16 +312,15 @@ func (api *api) updateRow(serviceID string, cells []dbtype.Cell) {
 		}
 
 		// `column`
-		columnsBuilder.WriteString("`")
-		columnsBuilder.WriteString(cell.Column)
-		columnsBuilder.WriteString("`")
+		columnsBuilder.WriteString(api.storage.quote(cell.Column))
 		// ?
 		valuesPlaceholderBuilder.WriteString("?")
 	}
 
 	// The SQL statement.
-	sqlStmt = fmt.Sprintf("INSERT INTO status (%s,`id`) VALUES (?,%s)",
-		columnsBuilder.String(), valuesPlaceholderBuilder.String())
+	//#nosec G201 -- table and columnsBuilder are built from trusted sources.
+	sqlStmt = fmt.Sprintf("INSERT INTO %s (%s,%s) VALUES (?,%s)",
+		table, columnsBuilder.String(), api.storage.quote("id"), valuesPlaceholderBuilder.String())
 	// Log the SQL statement.
 	if jLog.IsLevel("DEBUG") {
 		jLog.Debug(
@@ -124,31 +328,38 @@ func (api *api) updateRow(serviceID string, cells []dbtype.Cell) {
 			logFrom, true)
 	}
 
-	// Execute and log any errors.
-	if _, err := api.db.Exec(sqlStmt, params...); err != nil {
-		jLog.Error(
-			fmt.Sprintf("updateRow INSERT: %q %v, %s",
-				sqlStmt, params, err),
-			logFrom, true)
+	// Execute and return any errors.
+	if _, err := api.exec(sqlStmt, params...); err != nil {
+		return fmt.Errorf("updateRow INSERT: %q %v, %w",
+			sqlStmt, params, err)
 	}
+	return nil
 }
 
 // deleteRow will remove the row of a service from the db.
 func (api *api) deleteRow(serviceID string) {
+	if err := api.deleteTableRow("status", serviceID); err != nil {
+		jLog.Error(err, logFrom, true)
+	}
+}
+
+// deleteTableRow will remove the `id` row from `table`.
+func (api *api) deleteTableRow(table, id string) error {
 	// The SQL statement.
-	sqlStmt := "DELETE FROM status WHERE id = ?"
+	//#nosec G201 -- table is from a trusted source.
+	sqlStmt := fmt.Sprintf("DELETE FROM %s WHERE id = ?",
+		table)
 	// Log the SQL statement.
 	if jLog.IsLevel("DEBUG") {
 		jLog.Debug(
-			fmt.Sprintf("%s, %v", sqlStmt, serviceID),
+			fmt.Sprintf("%s, %v", sqlStmt, id),
 			logFrom, true)
 	}
 
-	// Execute and log any errors.
-	if _, err := api.db.Exec(sqlStmt, serviceID); err != nil {
-		jLog.Error(
-			fmt.Sprintf("deleteRow: %q with %q, %s",
-				sqlStmt, serviceID, err),
-			logFrom, true)
+	// Execute and return any errors.
+	if _, err := api.exec(sqlStmt, id); err != nil {
+		return fmt.Errorf("deleteRow: %q with %q, %w",
+			sqlStmt, id, err)
 	}
+	return nil
 }
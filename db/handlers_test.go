@@ -17,13 +17,16 @@
 package db
 
 import (
+	"database/sql"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	dbtype "github.com/release-argus/Argus/db/types"
 	"github.com/release-argus/Argus/test"
 	"github.com/release-argus/Argus/util"
+	"github.com/release-argus/Argus/web/metric"
 )
 
 func TestAPI_UpdateRow(t *testing.T) {
@@ -290,3 +293,212 @@ func TestAPI_Handler(t *testing.T) {
 			cell2.Column, cell2.Value, got, want)
 	}
 }
+
+func TestAPI_Handler_Table(t *testing.T) {
+	// GIVEN a DB.
+	tAPI := testAPI("TestAPI_Handler_Table", "db")
+	t.Cleanup(func() { dbCleanup(tAPI) })
+	tAPI.initialise()
+	go tAPI.handler()
+	queryEntries := func(id string) (entries string) {
+		row := tAPI.db.QueryRow(`SELECT entries FROM notify_digest WHERE id = ?;`, id)
+		//nolint:errcheck // sql.ErrNoRows leaves entries empty.
+		row.Scan(&entries)
+		return
+	}
+
+	// WHEN a message is sent to the DatabaseChannel targeting the notify_digest table.
+	id := "notify0"
+	*tAPI.config.DatabaseChannel <- dbtype.Message{
+		Table:     dbtype.TableNotifyDigest,
+		ServiceID: id,
+		Cells: []dbtype.Cell{
+			{Column: "entries", Value: `[{"service_info":{"id":"keep0"}}]`}},
+	}
+	time.Sleep(250 * time.Millisecond)
+
+	// THEN the row is written to that table.
+	if got := queryEntries(id); got != `[{"service_info":{"id":"keep0"}}]` {
+		t.Errorf("notify_digest row not written, got entries=%q",
+			got)
+	}
+	// AND the status table is untouched.
+	if got := queryRow(t, tAPI.db, id); got.LatestVersion() != "" {
+		t.Errorf("status row unexpectedly written: %#v",
+			got)
+	}
+
+	// WHEN a delete message is sent for that table.
+	*tAPI.config.DatabaseChannel <- dbtype.Message{
+		Table:     dbtype.TableNotifyDigest,
+		ServiceID: id,
+		Delete:    true,
+	}
+	time.Sleep(250 * time.Millisecond)
+
+	// THEN the row is removed.
+	if got := queryEntries(id); got != "" {
+		t.Errorf("notify_digest row not deleted, got entries=%q",
+			got)
+	}
+}
+
+func TestAPI_collectBatch(t *testing.T) {
+	// GIVEN a DatabaseChannel with messages waiting.
+	tests := map[string]struct {
+		waiting  int
+		closed   bool
+		wantLen  int
+		wantOpen bool
+	}{
+		"none waiting": {
+			waiting: 0, wantLen: 1, wantOpen: true},
+		"some waiting": {
+			waiting: 5, wantLen: 6, wantOpen: true},
+		"more than a batch waiting": {
+			waiting: maxBatchSize + 5, wantLen: maxBatchSize, wantOpen: true},
+		"channel closed": {
+			waiting: 2, closed: true, wantLen: 3, wantOpen: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tAPI := api{config: testConfig()}
+			databaseChannel := make(chan dbtype.Message, tc.waiting)
+			tAPI.config.DatabaseChannel = &databaseChannel
+			for i := 0; i < tc.waiting; i++ {
+				databaseChannel <- dbtype.Message{ServiceID: "keep0"}
+			}
+			if tc.closed {
+				close(databaseChannel)
+			}
+
+			// WHEN collectBatch is called with a message.
+			got, open := tAPI.collectBatch(dbtype.Message{ServiceID: "first"})
+
+			// THEN the waiting messages are added to it, up to maxBatchSize.
+			if len(got) != tc.wantLen || got[0].ServiceID != "first" {
+				t.Errorf("want %d messages starting with %q, got %d starting with %q",
+					tc.wantLen, "first", len(got), got[0].ServiceID)
+			}
+			// AND whether the channel is open is returned.
+			if open != tc.wantOpen {
+				t.Errorf("want open=%t, got %t",
+					tc.wantOpen, open)
+			}
+		})
+	}
+}
+
+func TestAPI_writeBatch(t *testing.T) {
+	// GIVEN a batch of messages, one of which can't be written.
+	tAPI := testAPI("TestAPI_writeBatch", "db")
+	t.Cleanup(func() { dbCleanup(tAPI) })
+	tAPI.initialise()
+	batch := []dbtype.Message{
+		{ServiceID: "keep0", Cells: []dbtype.Cell{{Column: "latest_version", Value: "1.0.0"}}},
+		{ServiceID: "keep1", Cells: []dbtype.Cell{{Column: "unknown", Value: "x"}}},
+		{ServiceID: "keep2", Cells: []dbtype.Cell{{Column: "latest_version", Value: "2.0.0"}}},
+	}
+	failsBefore := testutil.ToFloat64(metric.DatabaseWriteResultTotal.WithLabelValues("FAIL"))
+	releaseStdout := test.CaptureStdout()
+
+	// WHEN writeBatch is called.
+	tAPI.writeBatch(batch)
+
+	// THEN the failing message is logged.
+	stdout := releaseStdout()
+	if !util.RegexCheck(`ERROR: [^)]+\), write status "keep1": updateRow UPDATE`, stdout) {
+		t.Errorf("want the failed message logged, got %q",
+			stdout)
+	}
+	// AND counted as a failure.
+	if got := testutil.ToFloat64(metric.DatabaseWriteResultTotal.WithLabelValues("FAIL")) - failsBefore; got != 1 {
+		t.Errorf("want 1 failure counted, got %v",
+			got)
+	}
+	// AND the other messages are written.
+	for id, want := range map[string]string{"keep0": "1.0.0", "keep2": "2.0.0"} {
+		if got := queryRow(t, tAPI.db, id).LatestVersion(); got != want {
+			t.Errorf("want %q latest_version %q, got %q",
+				id, want, got)
+		}
+	}
+}
+
+func TestAPI_writeMessages_Busy(t *testing.T) {
+	// GIVEN the DB is locked by another connection for a short time.
+	tAPI := testAPI("TestAPI_writeMessages_Busy", "db")
+	t.Cleanup(func() { dbCleanup(tAPI) })
+	tAPI.initialise()
+	other, err := sql.Open("sqlite", tAPI.config.Settings.Data.DatabaseFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { other.Close() })
+	other.SetMaxOpenConns(1)
+	if _, err := other.Exec("BEGIN EXCLUSIVE;"); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(2 * writeRetryDelay)
+		other.Exec("COMMIT;")
+	}()
+	retriesBefore := testutil.ToFloat64(metric.DatabaseWriteResultTotal.WithLabelValues("RETRY"))
+	releaseStdout := test.CaptureStdout()
+	defer releaseStdout()
+
+	// WHEN writeMessages is called.
+	err = tAPI.writeMessages([]dbtype.Message{
+		{ServiceID: "keep0", Cells: []dbtype.Cell{{Column: "latest_version", Value: "1.0.0"}}}})
+
+	// THEN the write is retried until the DB is unlocked.
+	if err != nil {
+		t.Fatalf("want the write to succeed after retrying, got %v",
+			err)
+	}
+	if got := testutil.ToFloat64(metric.DatabaseWriteResultTotal.WithLabelValues("RETRY")) - retriesBefore; got == 0 {
+		t.Error("want the retries counted, got none")
+	}
+	if got := queryRow(t, tAPI.db, "keep0").LatestVersion(); got != "1.0.0" {
+		t.Errorf("want latest_version %q, got %q",
+			"1.0.0", got)
+	}
+}
+
+func TestAPI_Handler_Shutdown(t *testing.T) {
+	// GIVEN a handler with messages waiting to be written.
+	tAPI := testAPI("TestAPI_Handler_Shutdown", "db")
+	t.Cleanup(func() { dbCleanup(tAPI) })
+	tAPI.shutdown = make(chan struct{})
+	tAPI.stopped = make(chan struct{})
+	tAPI.initialise()
+	databaseChannel := make(chan dbtype.Message, 8)
+	tAPI.config.DatabaseChannel = &databaseChannel
+	for _, id := range []string{"keep0", "keep1", "keep2"} {
+		databaseChannel <- dbtype.Message{
+			ServiceID: id,
+			Cells:     []dbtype.Cell{{Column: "latest_version", Value: "1.0.0"}}}
+	}
+
+	// WHEN the handler is shut down.
+	close(tAPI.shutdown)
+	go tAPI.handler()
+
+	// THEN it stops once the messages are written.
+	select {
+	case <-tAPI.stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler didn't stop")
+	}
+	db, _ := sql.Open("sqlite", tAPI.config.Settings.Data.DatabaseFile)
+	defer db.Close()
+	for _, id := range []string{"keep0", "keep1", "keep2"} {
+		if got := queryRow(t, db, id).LatestVersion(); got != "1.0.0" {
+			t.Errorf("want %q latest_version %q flushed, got %q",
+				id, "1.0.0", got)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package db provides database functionality for Argus to keep track of versions found/deployed/approved.
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/release-argus/Argus/history"
+)
+
+// queryHistory returns the history Records matching the `filter` (newest first),
+// and the total number of matches.
+func (api *api) queryHistory(filter history.Filter) ([]history.Record, int, error) {
+	// WHERE clause.
+	var conditions []string
+	var params []any
+	for _, condition := range []struct {
+		column, value string
+	}{
+		{"service_id", filter.ServiceID},
+		{"kind", filter.Kind},
+		{"target", filter.Target},
+	} {
+		if condition.value != "" {
+			conditions = append(conditions, condition.column+" = ?")
+			params = append(params, condition.value)
+		}
+	}
+	if filter.Success != nil {
+		if *filter.Success {
+			conditions = append(conditions, "error = ''")
+		} else {
+			conditions = append(conditions, "error != ''")
+		}
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "time >= ?")
+		params = append(params, filter.Since.UTC().Format(history.TimeFormat))
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "time < ?")
+		params = append(params, filter.Until.UTC().Format(history.TimeFormat))
+	}
+	where := ""
+	if len(conditions) != 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Total matches.
+	var total int
+	//#nosec G202 -- where is built from trusted column names.
+	if err := api.queryRow("SELECT COUNT(*) FROM history "+where, params...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("queryHistory count: %w", err)
+	}
+
+	// Page of Records.
+	//#nosec G202 -- where is built from trusted column names.
+	rows, err := api.query(`
+		SELECT
+			id,
+			time,
+			kind,
+			service_id,
+			target,
+			version,
+			payload,
+			response_status,
+			duration_ms,
+			error
+		FROM history `+where+`
+		ORDER BY time DESC, id
+		LIMIT ? OFFSET ?;`,
+		append(params, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("queryHistory: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]history.Record, 0, filter.Limit)
+	for rows.Next() {
+		var (
+			record     history.Record
+			timeStr    string
+			durationMS int64
+		)
+		if err := rows.Scan(
+			&record.ID,
+			&timeStr,
+			&record.Kind,
+			&record.ServiceID,
+			&record.Target,
+			&record.Version,
+			&record.Payload,
+			&record.ResponseStatus,
+			&durationMS,
+			&record.Error,
+		); err != nil {
+			return nil, 0, fmt.Errorf("queryHistory row: %w", err)
+		}
+		record.Time, _ = time.Parse(history.TimeFormat, timeStr)
+		record.Duration = time.Duration(durationMS) * time.Millisecond
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("queryHistory: %w", err)
+	}
+
+	return records, total, nil
+}
+
+// pruneHistory removes the history Records older than the retention setting.
+func (api *api) pruneHistory() {
+	retention := api.config.Settings.DataHistoryRetention()
+	if retention <= 0 {
+		return
+	}
+
+	cutoff := time.Now().UTC().Add(-retention).Format(history.TimeFormat)
+	sqlStmt := "DELETE FROM history WHERE time < ?"
+	if jLog.IsLevel("DEBUG") {
+		jLog.Debug(
+			fmt.Sprintf("%s, %v", sqlStmt, cutoff),
+			logFrom, true)
+	}
+	if _, err := api.exec(sqlStmt, cutoff); err != nil {
+		jLog.Error(
+			fmt.Sprintf("pruneHistory: %q with %q, %s",
+				sqlStmt, cutoff, err),
+			logFrom, true)
+	}
+}
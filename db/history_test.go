@@ -0,0 +1,194 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//go:build unit
+
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/release-argus/Argus/history"
+)
+
+// testHistoryRows inserts history rows into the database of `tAPI`.
+func testHistoryRows(t *testing.T, tAPI *api, records []history.Record) {
+	t.Helper()
+	for _, record := range records {
+		if _, err := tAPI.db.Exec(`
+			INSERT INTO history (id, time, kind, service_id, target, error, duration_ms)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			record.ID,
+			record.Time.UTC().Format(history.TimeFormat),
+			record.Kind,
+			record.ServiceID,
+			record.Target,
+			record.Error,
+			record.Duration.Milliseconds(),
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAPI_queryHistory(t *testing.T) {
+	// GIVEN a DB with history rows.
+	tAPI := testAPI("TestAPI_queryHistory", "db")
+	t.Cleanup(func() { dbCleanup(tAPI) })
+	tAPI.initialise()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	testHistoryRows(t, tAPI, []history.Record{
+		{ID: "a", Time: now.Add(-4 * time.Hour), Kind: history.KindNotify, ServiceID: "svc0", Target: "slack"},
+		{ID: "b", Time: now.Add(-3 * time.Hour), Kind: history.KindWebHook, ServiceID: "svc0", Target: "wh", Error: "fail"},
+		{ID: "c", Time: now.Add(-2 * time.Hour), Kind: history.KindWebHook, ServiceID: "svc1", Target: "wh", Duration: 2 * time.Second},
+		{ID: "d", Time: now.Add(-1 * time.Hour), Kind: history.KindCommand, ServiceID: "svc1", Target: "0", Error: "exit 1"},
+	})
+	success, failure := true, false
+	tests := map[string]struct {
+		filter    history.Filter
+		wantIDs   []string
+		wantTotal int
+	}{
+		"all, newest first": {
+			filter:    history.Filter{Limit: 10},
+			wantIDs:   []string{"d", "c", "b", "a"},
+			wantTotal: 4},
+		"service_id": {
+			filter:    history.Filter{ServiceID: "svc0", Limit: 10},
+			wantIDs:   []string{"b", "a"},
+			wantTotal: 2},
+		"kind and target": {
+			filter:    history.Filter{Kind: history.KindWebHook, Target: "wh", Limit: 10},
+			wantIDs:   []string{"c", "b"},
+			wantTotal: 2},
+		"successes": {
+			filter:    history.Filter{Success: &success, Limit: 10},
+			wantIDs:   []string{"c", "a"},
+			wantTotal: 2},
+		"failures": {
+			filter:    history.Filter{Success: &failure, Limit: 10},
+			wantIDs:   []string{"d", "b"},
+			wantTotal: 2},
+		"since/until": {
+			filter:    history.Filter{Since: now.Add(-3 * time.Hour), Until: now.Add(-time.Hour), Limit: 10},
+			wantIDs:   []string{"c", "b"},
+			wantTotal: 2},
+		"paginated": {
+			filter:    history.Filter{Limit: 2, Offset: 1},
+			wantIDs:   []string{"c", "b"},
+			wantTotal: 4},
+		"no matches": {
+			filter:    history.Filter{ServiceID: "unknown", Limit: 10},
+			wantIDs:   []string{},
+			wantTotal: 0},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN queryHistory is called with a filter.
+			records, total, err := tAPI.queryHistory(tc.filter)
+
+			// THEN the matching records are returned.
+			if err != nil {
+				t.Fatalf("unexpected error: %v",
+					err)
+			}
+			if total != tc.wantTotal {
+				t.Errorf("total\nwant: %d\ngot:  %d",
+					tc.wantTotal, total)
+			}
+			gotIDs := make([]string, len(records))
+			for i := range records {
+				gotIDs[i] = records[i].ID
+			}
+			if len(gotIDs) != len(tc.wantIDs) {
+				t.Fatalf("IDs\nwant: %v\ngot:  %v",
+					tc.wantIDs, gotIDs)
+			}
+			for i := range gotIDs {
+				if gotIDs[i] != tc.wantIDs[i] {
+					t.Fatalf("IDs\nwant: %v\ngot:  %v",
+						tc.wantIDs, gotIDs)
+				}
+			}
+			// AND the times/durations are parsed.
+			for _, record := range records {
+				if record.Time.IsZero() {
+					t.Errorf("%q has a zero time",
+						record.ID)
+				}
+				if record.ID == "c" && record.Duration != 2*time.Second {
+					t.Errorf("%q duration\nwant: %s\ngot:  %s",
+						record.ID, 2*time.Second, record.Duration)
+				}
+			}
+		})
+	}
+}
+
+func TestAPI_pruneHistory(t *testing.T) {
+	// GIVEN a DB with old and new history rows.
+	tests := map[string]struct {
+		retention string
+		wantIDs   []string
+	}{
+		"default retention": {
+			retention: "",
+			wantIDs:   []string{"new"}},
+		"retention of 0 keeps forever": {
+			retention: "0s",
+			wantIDs:   []string{"new", "old"}},
+		"short retention": {
+			retention: "1m",
+			wantIDs:   []string{}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tAPI := testAPI("TestAPI_pruneHistory", name)
+			t.Cleanup(func() { dbCleanup(tAPI) })
+			tAPI.config.Settings.Data.HistoryRetention = tc.retention
+			tAPI.config.Settings.HardDefaults.Data.HistoryRetention = "720h"
+			tAPI.initialise()
+			testHistoryRows(t, tAPI, []history.Record{
+				{ID: "old", Time: time.Now().Add(-31 * 24 * time.Hour)},
+				{ID: "new", Time: time.Now().Add(-time.Hour)},
+			})
+
+			// WHEN pruneHistory is called.
+			tAPI.pruneHistory()
+
+			// THEN only the records within the retention remain.
+			records, _, err := tAPI.queryHistory(history.Filter{Limit: 10})
+			if err != nil {
+				t.Fatalf("unexpected error: %v",
+					err)
+			}
+			if len(records) != len(tc.wantIDs) {
+				t.Fatalf("want %v, got %+v",
+					tc.wantIDs, records)
+			}
+			for i := range records {
+				if records[i].ID != tc.wantIDs[i] {
+					t.Errorf("want %v, got %+v",
+						tc.wantIDs, records)
+				}
+			}
+		})
+	}
+}
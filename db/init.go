@@ -16,22 +16,29 @@
 package db
 
 import (
-	"database/sql"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/release-argus/Argus/config"
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/history"
+	"github.com/release-argus/Argus/notify/shoutrrr"
+	"github.com/release-argus/Argus/outbox"
+	"github.com/release-argus/Argus/service/approval"
 	"github.com/release-argus/Argus/util"
 )
 
 // LogInit for this package.
-func LogInit(log *util.JLog, databaseFile string) {
+func LogInit(log *util.JLog, database string) {
 	// Only set the log if it has not been set (avoid RACE condition).
 	if jLog == nil {
 		jLog = log
-		logFrom = util.LogFrom{Primary: "db", Secondary: databaseFile}
+		logFrom = util.LogFrom{Primary: "db", Secondary: database}
 	}
 }
 
@@ -77,10 +84,16 @@ func checkFile(path string) {
 
 // Run will start the database, initialise it and run the handler for messages in the background.
 func Run(cfg *config.Config, log *util.JLog) {
+	store := newStorage(&cfg.Settings)
 	if log != nil {
-		LogInit(log, cfg.Settings.DataDatabaseFile())
+		LogInit(log, store.String())
 	}
-	api := api{config: cfg}
+	api := api{
+		config:   cfg,
+		storage:  store,
+		shutdown: make(chan struct{}),
+		stopped:  make(chan struct{}),
+		tasks:    make(chan func())}
 
 	api.initialise()
 	runningHandler := false
@@ -90,78 +103,71 @@ func Run(cfg *config.Config, log *util.JLog) {
 		}
 	}()
 
-	if len(api.config.Order) > 0 {
-		api.removeUnknownServices()
-		api.extractServiceStatus()
-	}
+	api.archiveUnknownServices()
+	api.extractServiceStatus()
+	api.extractNotifyDigests()
+	api.extractApprovals()
+	api.extractOutbox()
+	outbox.SetDatabaseChannel(api.config.DatabaseChannel)
+	api.pruneHistory()
+	history.SetDatabaseChannel(api.config.DatabaseChannel)
+	history.SetQuerier(api.queryHistory)
+	history.SetTimelineQuerier(api.queryVersionEvents)
 
 	go api.handler()
 	runningHandler = true
+	running.Store(&api)
 }
 
-func (api *api) initialise() {
-	databaseFile := api.config.Settings.DataDatabaseFile()
-	checkFile(databaseFile)
-	db, err := sql.Open("sqlite", databaseFile)
-	jLog.Fatal(err, logFrom, err != nil)
-
-	// Create the table.
-	sqlStmt := `
-		CREATE TABLE IF NOT EXISTS status (
-			id                         TEXT     NOT NULL PRIMARY KEY,
-			latest_version             TEXT     DEFAULT  '',
-			latest_version_timestamp   DATETIME DEFAULT  (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
-			deployed_version           TEXT     DEFAULT  '',
-			deployed_version_timestamp DATETIME DEFAULT  (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
-			approved_version           TEXT     DEFAULT  ''
-		);`
-	if _, err := db.Exec(sqlStmt); err != nil {
-		jLog.Fatal(err, logFrom, true)
+// Shutdown will write the messages already sent to the DatabaseChannel and close the database,
+// waiting up to `timeout` for them to be written.
+func Shutdown(timeout time.Duration) error {
+	api := running.Swap(nil)
+	if api == nil {
+		return nil
 	}
 
-	updateTable(db)
-
-	api.db = db
+	close(api.shutdown)
+	select {
+	case <-api.stopped:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("database not flushed within %s", timeout)
+	}
 }
 
-// removeUnknownServices will remove rows with an ID not in config.Order.
-func (api *api) removeUnknownServices() {
-	// ? for each service.
-	servicePlaceholders := strings.Repeat("?,", len(api.config.Order))
-	servicePlaceholders = servicePlaceholders[:len(servicePlaceholders)-1]
-
-	// SQL statement to remove unknown services.
-	//#nosec G201 -- servicePlaceholders is safe.
-	sqlStmt := fmt.Sprintf(`
-		DELETE FROM status
-		WHERE id NOT IN (%s);`,
-		servicePlaceholders)
-
-	// Get the vars for the SQL statement.
-	params := make([]interface{}, len(api.config.Order))
-	for i, name := range api.config.Order {
-		params[i] = name
+func (api *api) initialise() {
+	if api.storage == nil {
+		api.storage = newStorage(&api.config.Settings)
 	}
+	db, err := api.storage.open()
+	jLog.Fatal(err, logFrom, err != nil)
 
-	if _, err := api.db.Exec(sqlStmt, params...); err != nil {
+	// Create/update the tables.
+	if err := migrate(db, api.storage); err != nil {
+		db.Close()
 		jLog.Fatal(
-			fmt.Sprintf("removeUnknownServices: %s",
+			fmt.Sprintf("migrate: %s",
 				err),
 			logFrom, true)
 	}
+
+	api.db = db
 }
 
 // extractServiceStatus will query the database and add the data found
 // into the Service.Status inside the config.
 func (api *api) extractServiceStatus() {
-	rows, err := api.db.Query(`
+	rows, err := api.query(`
 		SELECT
 			id,
 			latest_version,
 			latest_version_timestamp,
 			deployed_version,
 			deployed_version_timestamp,
-			approved_version
+			approved_version,
+			queued_version,
+			previous_version
 		FROM status;`)
 	jLog.Fatal(err, logFrom, err != nil)
 	defer rows.Close()
@@ -176,16 +182,24 @@ func (api *api) extractServiceStatus() {
 			dv  string
 			dvt string
 			av  string
+			qv  string
+			pv  string
 		)
-		if err := rows.Scan(&id, &lv, &lvt, &dv, &dvt, &av); err != nil {
+		if err := rows.Scan(&id, &lv, &lvt, &dv, &dvt, &av, &qv, &pv); err != nil {
 			jLog.Fatal(
 				fmt.Sprintf("extractServiceStatus row: %s",
 					err),
 				logFrom, true)
 		}
-		api.config.Service[id].Status.SetLatestVersion(lv, lvt, false)
-		api.config.Service[id].Status.SetDeployedVersion(dv, dvt, false)
-		api.config.Service[id].Status.SetApprovedVersion(av, false)
+		svc := api.config.Service[id]
+		if svc == nil {
+			continue
+		}
+		svc.Status.SetLatestVersion(lv, lvt, false)
+		svc.Status.SetPreviousLatestVersion(pv)
+		svc.Status.SetDeployedVersion(dv, dvt, false)
+		svc.Status.SetApprovedVersion(av, false)
+		svc.Status.SetQueuedVersion(qv, false)
 	}
 	if err := rows.Err(); err != nil {
 		jLog.Fatal(
@@ -195,63 +209,222 @@ func (api *api) extractServiceStatus() {
 	}
 }
 
-// updateTable will update the table for the latest version.
-func updateTable(db *sql.DB) {
-	// Get the type of the *_version columns.
-	var columnType string
-	if err := db.QueryRow("SELECT type FROM pragma_table_info('status') WHERE name = 'latest_version'").Scan(&columnType); err != nil {
+// extractNotifyDigests restores the pending notify digests from the database.
+func (api *api) extractNotifyDigests() {
+	rows, err := api.query(`
+		SELECT
+			id,
+			entries
+		FROM notify_digest;`)
+	jLog.Fatal(err, logFrom, err != nil)
+	defer rows.Close()
+
+	api.config.OrderMutex.RLock()
+	defer api.config.OrderMutex.RUnlock()
+	var unknown []string
+	for rows.Next() {
+		var (
+			id         string
+			entriesStr string
+			entries    []shoutrrr.DigestEntry
+		)
+		if err := rows.Scan(&id, &entriesStr); err != nil {
+			jLog.Fatal(
+				fmt.Sprintf("extractNotifyDigests row: %s",
+					err),
+				logFrom, true)
+		}
+		if err := json.Unmarshal([]byte(entriesStr), &entries); err != nil {
+			jLog.Error(
+				fmt.Sprintf("extractNotifyDigests %q: %s",
+					id, err),
+				logFrom, true)
+			unknown = append(unknown, id)
+			continue
+		}
+
+		// Restore the digest with the Notify of a Service that is in it (and still sends to the same target).
+		notifyID, _, _ := strings.Cut(id, "#")
+		var notify *shoutrrr.Shoutrrr
+		for _, entry := range entries {
+			if svc := api.config.Service[entry.ServiceInfo.ID]; svc != nil &&
+				svc.Notify[notifyID] != nil && svc.Notify[notifyID].DigestKey() == id {
+				notify = svc.Notify[notifyID]
+				break
+			}
+		}
+		if notify == nil {
+			unknown = append(unknown, id)
+			continue
+		}
+		notify.RestoreDigest(entries)
+	}
+	if err := rows.Err(); err != nil {
 		jLog.Fatal(
-			fmt.Sprintf("updateTable: %s",
+			fmt.Sprintf("extractNotifyDigests: %s",
 				err),
 			logFrom, true)
 	}
-	// Update if the column type is not TEXT.
-	if columnType != "TEXT" {
-		jLog.Verbose("Updating column types", logFrom, true)
-		updateColumnTypes(db)
-		jLog.Verbose("Finished updating column types", logFrom, true)
+
+	// Remove the digests that can no longer be sent.
+	for _, id := range unknown {
+		if err := api.deleteTableRow(dbtype.TableNotifyDigest, id); err != nil {
+			jLog.Error(err, logFrom, true)
+		}
 	}
 }
 
-// updateColumnTypes will recreate the table with the correct column types.
-func updateColumnTypes(db *sql.DB) {
-	// Create the new table.
-	sqlStmt := `
-		CREATE TABLE IF NOT EXISTS status_backup (
-			id                         TEXT     NOT NULL PRIMARY KEY,
-			latest_version             TEXT     DEFAULT  '',
-			latest_version_timestamp   DATETIME DEFAULT  (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
-			deployed_version           TEXT     DEFAULT  '',
-			deployed_version_timestamp DATETIME DEFAULT  (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
-			approved_version           TEXT     DEFAULT  ''
-		);`
-	if _, err := db.Exec(sqlStmt); err != nil {
-		jLog.Fatal(
-			fmt.Sprintf("updateColumnTypes - create: %s", err),
-			logFrom, true)
-	}
+// extractApprovals restores the approvals given towards the approval policy of each Service.
+func (api *api) extractApprovals() {
+	rows, err := api.query(`
+		SELECT
+			id,
+			approvals
+		FROM approval;`)
+	jLog.Fatal(err, logFrom, err != nil)
+	defer rows.Close()
+
+	api.config.OrderMutex.RLock()
+	defer api.config.OrderMutex.RUnlock()
+	var unknown []string
+	for rows.Next() {
+		var (
+			id           string
+			approvalsStr string
+			approvals    []approval.Approval
+		)
+		if err := rows.Scan(&id, &approvalsStr); err != nil {
+			jLog.Fatal(
+				fmt.Sprintf("extractApprovals row: %s",
+					err),
+				logFrom, true)
+		}
+		if err := json.Unmarshal([]byte(approvalsStr), &approvals); err != nil {
+			jLog.Error(
+				fmt.Sprintf("extractApprovals %q: %s",
+					id, err),
+				logFrom, true)
+			unknown = append(unknown, id)
+			continue
+		}
 
-	// Copy the data from the old table to the new table.
-	if _, err := db.Exec(`INSERT INTO status_backup SELECT * FROM status;`); err != nil {
+		// Remove the approvals of Services without an approval policy.
+		svc := api.config.Service[id]
+		if svc == nil || svc.Approval == nil {
+			unknown = append(unknown, id)
+			continue
+		}
+		svc.Approval.Restore(approvals)
+	}
+	if err := rows.Err(); err != nil {
 		jLog.Fatal(
-			fmt.Sprintf("updateColumnTypes - copy: %s",
+			fmt.Sprintf("extractApprovals: %s",
 				err),
 			logFrom, true)
 	}
 
-	// Drop the table.
-	if _, err := db.Exec("DROP TABLE status;"); err != nil {
-		jLog.Fatal(
-			fmt.Sprintf("updateColumnTypes - drop: %s",
-				err),
-			logFrom, true)
+	for _, id := range unknown {
+		if err := api.deleteTableRow(dbtype.TableApproval, id); err != nil {
+			jLog.Error(err, logFrom, true)
+		}
 	}
+}
+
+// extractOutbox restores the pending deliveries of the outbox from the database.
+func (api *api) extractOutbox() {
+	rows, err := api.query(`
+		SELECT
+			id,
+			entry
+		FROM outbox;`)
+	jLog.Fatal(err, logFrom, err != nil)
+	defer rows.Close()
 
-	// Rename the new table to the old table.
-	if _, err := db.Exec("ALTER TABLE status_backup RENAME TO status;"); err != nil {
+	api.config.OrderMutex.RLock()
+	defer api.config.OrderMutex.RUnlock()
+	var unknown []string
+	for rows.Next() {
+		var (
+			id       string
+			entryStr string
+			entry    outbox.Entry
+		)
+		if err := rows.Scan(&id, &entryStr); err != nil {
+			jLog.Fatal(
+				fmt.Sprintf("extractOutbox row: %s",
+					err),
+				logFrom, true)
+		}
+		if err := json.Unmarshal([]byte(entryStr), &entry); err != nil {
+			jLog.Error(
+				fmt.Sprintf("extractOutbox %q: %s",
+					id, err),
+				logFrom, true)
+			unknown = append(unknown, id)
+			continue
+		}
+		entry.ID = id
+
+		delivery := api.outboxDelivery(&entry)
+		if delivery == nil {
+			unknown = append(unknown, id)
+			continue
+		}
+		outbox.Restore(entry, *delivery)
+	}
+	if err := rows.Err(); err != nil {
 		jLog.Fatal(
-			fmt.Sprintf("updateColumnTypes - rename: %s",
+			fmt.Sprintf("extractOutbox: %s",
 				err),
 			logFrom, true)
 	}
+
+	// Remove the deliveries that can no longer be made.
+	for _, id := range unknown {
+		if err := api.deleteTableRow(dbtype.TableOutbox, id); err != nil {
+			jLog.Error(err, logFrom, true)
+		}
+	}
+}
+
+// outboxDelivery returns how to retry the outbox `entry`,
+// or nil if its Service/target no longer exists.
+func (api *api) outboxDelivery(entry *outbox.Entry) *outbox.Delivery {
+	svc := api.config.Service[entry.ServiceID]
+	if svc == nil {
+		return nil
+	}
+
+	switch entry.Kind {
+	case outbox.KindNotify:
+		if notify := svc.Notify[entry.Target]; notify != nil {
+			return &outbox.Delivery{
+				Retry: func() error {
+					return notify.Send(svc.Status.Context(), entry.Title, entry.Message, entry.ServiceInfo, false, true)
+				}}
+		}
+	case outbox.KindWebHook:
+		if wh := svc.WebHook[entry.Target]; wh != nil {
+			return &outbox.Delivery{
+				Retry: func() error {
+					return wh.Attempt(svc.Status.Context(), entry.ServiceInfo, false)
+				},
+				Dead: func(error) {
+					wh.NotifyFailure(svc.Status.Context(), entry.ServiceInfo)
+				}}
+		}
+	case outbox.KindCommand:
+		index, err := strconv.Atoi(entry.Target)
+		if controller := svc.CommandController; err == nil && controller != nil &&
+			index >= 0 && index < len(svc.Command) {
+			return &outbox.Delivery{
+				Retry: func() error {
+					return controller.AttemptIndex(svc.Status.Context(), util.LogFrom{Primary: "outbox", Secondary: entry.ServiceID}, index, entry.ServiceInfo)
+				},
+				Dead: func(err error) {
+					controller.NotifyFailure(svc.Status.Context(), index, entry.ServiceInfo, err)
+				}}
+		}
+	}
+	return nil
 }
@@ -18,6 +18,7 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"io/fs"
 	"math/rand"
@@ -26,7 +27,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/release-argus/Argus/command"
 	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/outbox"
+	"github.com/release-argus/Argus/service/approval"
 	"github.com/release-argus/Argus/service/status"
 	"github.com/release-argus/Argus/test"
 	"github.com/release-argus/Argus/util"
@@ -257,118 +261,6 @@ func TestDBQueryService(t *testing.T) {
 	}
 }
 
-func TestAPI_RemoveUnknownServices(t *testing.T) {
-	// GIVEN a DB with many service status'.
-	tests := map[string]struct {
-		databaseDeleted bool
-	}{
-		"DB is not deleted": {
-			databaseDeleted: false},
-		"DB is deleted": {
-			databaseDeleted: true},
-	}
-
-	for name, tc := range tests {
-		t.Run(name, func(t *testing.T) {
-			t.Parallel()
-
-			tAPI := testAPI(name, "TestAPI_RemoveUnknownServices")
-			t.Cleanup(func() { dbCleanup(tAPI) })
-			tAPI.initialise()
-			sqlStmt := `
-				INSERT OR REPLACE INTO status
-					(
-						id,
-						latest_version,
-						latest_version_timestamp,
-						deployed_version,
-						deployed_version_timestamp,
-						approved_version
-					)
-				VALUES`
-			for id, svc := range tAPI.config.Service {
-				sqlStmt += fmt.Sprintf(" (%q, %q, %q, %q, %q, %q),",
-					id,
-					svc.Status.LatestVersion(),
-					svc.Status.LatestVersionTimestamp(),
-					svc.Status.DeployedVersion(),
-					svc.Status.DeployedVersionTimestamp(),
-					svc.Status.ApprovedVersion(),
-				)
-			}
-			if len(tAPI.config.Order) <= 1 {
-				t.Fatalf("Want to test with more services present, have=%d",
-					len(tAPI.config.Order))
-			}
-			util.RemoveIndex(&tAPI.config.Order, 0)
-			_, err := tAPI.db.Exec(sqlStmt[:len(sqlStmt)-1] + ";")
-			if err != nil {
-				t.Fatal(err)
-			}
-			// Catch fatal panics.
-			defer func() {
-				r := recover()
-				// Ignore nil panics.
-				if r == nil {
-					return
-				}
-
-				if !tc.databaseDeleted {
-					t.Fatalf("unexpected panic: %v", r)
-				}
-			}()
-			// Delete the DB file.
-			if tc.databaseDeleted {
-				os.Remove(tAPI.config.Settings.Data.DatabaseFile)
-			}
-
-			// WHEN the unknown Services are removed with removeUnknownServices.
-			tAPI.removeUnknownServices()
-
-			// THEN the app panic'd if the db was deleted.
-			if tc.databaseDeleted {
-				t.Error("Expected a panic")
-				return
-			}
-			// AND the rows of Services not in .All are returned.
-			rows, err := tAPI.db.Query(`
-				SELECT	id,
-						latest_version,
-						latest_version_timestamp,
-						deployed_version,
-						deployed_version_timestamp,
-						approved_version
-				FROM status;`)
-			if err != nil {
-				t.Error(err)
-			}
-			count := 0
-			t.Cleanup(func() { rows.Close() })
-			for rows.Next() {
-				count++
-				var (
-					id  string
-					lv  string
-					lvt string
-					dv  string
-					dvt string
-					av  string
-				)
-				err = rows.Scan(&id, &lv, &lvt, &dv, &dvt, &av)
-				svc := tAPI.config.Service[id]
-				if svc == nil || !util.Contains(tAPI.config.Order, id) {
-					t.Errorf("%q should have been removed from the table",
-						id)
-				}
-			}
-			if count != len(tAPI.config.Order) {
-				t.Errorf("Only %d were left in the table. Expected %d",
-					count, len(tAPI.config.Order))
-			}
-		})
-	}
-}
-
 func TestAPI_Run(t *testing.T) {
 	// GIVEN a DB is running (see TestMain).
 
@@ -432,6 +324,8 @@ func TestAPI_extractServiceStatus(t *testing.T) {
 		wantStatus[index].SetApprovedVersion(fmt.Sprintf("%d.%d.%d",
 			rand.Intn(10), rand.Intn(10), rand.Intn(10)),
 			false)
+		wantStatus[index].SetQueuedVersion(wantStatus[index].LatestVersion(), false)
+		wantStatus[index].SetPreviousLatestVersion("0.0.1")
 
 		*tAPI.config.DatabaseChannel <- dbtype.Message{
 			ServiceID: id,
@@ -441,7 +335,9 @@ func TestAPI_extractServiceStatus(t *testing.T) {
 				{Column: "latest_version_timestamp", Value: wantStatus[index].LatestVersionTimestamp()},
 				{Column: "deployed_version", Value: wantStatus[index].DeployedVersion()},
 				{Column: "deployed_version_timestamp", Value: wantStatus[index].DeployedVersionTimestamp()},
-				{Column: "approved_version", Value: wantStatus[index].ApprovedVersion()}}}
+				{Column: "approved_version", Value: wantStatus[index].ApprovedVersion()},
+				{Column: "queued_version", Value: wantStatus[index].QueuedVersion()},
+				{Column: "previous_version", Value: wantStatus[index].PreviousLatestVersion()}}}
 		// Clear the Status in the Config.
 		svc.Status = *status.New(
 			svc.Status.AnnounceChannel, svc.Status.DatabaseChannel, svc.Status.SaveChannel,
@@ -477,6 +373,17 @@ func TestAPI_extractServiceStatus(t *testing.T) {
 			t.Errorf(errMsg,
 				"approved_version", row.ApprovedVersion(), row, wantStatus[i].String())
 		}
+		// AND the actions queued are restored, to be queued again.
+		svcStatus := &tAPI.config.Service[*wantStatus[i].ServiceID].Status
+		if got := svcStatus.QueuedVersion(); got != wantStatus[i].QueuedVersion() {
+			t.Errorf("QueuedVersion of %q\nwant: %q\ngot:  %q",
+				*wantStatus[i].ServiceID, wantStatus[i].QueuedVersion(), got)
+		}
+		// AND the previous version is restored.
+		if got := svcStatus.PreviousLatestVersion(); got != wantStatus[i].PreviousLatestVersion() {
+			t.Errorf("PreviousLatestVersion of %q\nwant: %q\ngot:  %q",
+				*wantStatus[i].ServiceID, wantStatus[i].PreviousLatestVersion(), got)
+		}
 	}
 }
 
@@ -652,3 +559,114 @@ func Test_UpdateTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestAPI_extractOutbox(t *testing.T) {
+	// GIVEN a DB with outbox rows for deliveries that can/can't be restored.
+	tAPI := testAPI("TestAPI_extractOutbox", "db")
+	t.Cleanup(func() { dbCleanup(tAPI) })
+	tAPI.initialise()
+	svc := tAPI.config.Service["keep0"]
+	svc.Command = command.Slice{{"true"}}
+	svc.CommandController = &command.Controller{}
+	rows := map[string]outbox.Entry{
+		"restore": {
+			Kind: outbox.KindCommand, ServiceID: "keep0", Target: "0",
+			State: outbox.StatePending, NextAttempt: time.Now().Add(time.Hour)},
+		"unknown_service": {
+			Kind: outbox.KindNotify, ServiceID: "unknown", Target: "notify"},
+		"unknown_webhook": {
+			Kind: outbox.KindWebHook, ServiceID: "keep0", Target: "unknown"},
+		"unknown_command": {
+			Kind: outbox.KindCommand, ServiceID: "keep0", Target: "1"},
+	}
+	for id, entry := range rows {
+		data, _ := json.Marshal(entry)
+		if _, err := tAPI.db.Exec("INSERT INTO outbox (id, entry) VALUES (?, ?)", id, string(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := tAPI.db.Exec("INSERT INTO outbox (id, entry) VALUES ('invalid', '{')"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = outbox.Discard("restore") })
+
+	// WHEN extractOutbox is called.
+	tAPI.extractOutbox()
+
+	// THEN the restorable delivery is in the outbox.
+	restored := false
+	for _, entry := range outbox.List() {
+		if entry.ID == "restore" {
+			restored = entry.State == outbox.StatePending
+		}
+	}
+	if !restored {
+		t.Errorf("expected %q to be restored to the outbox, got %+v",
+			"restore", outbox.List())
+	}
+	// AND the others are removed from the DB.
+	var ids []string
+	dbRows, err := tAPI.db.Query("SELECT id FROM outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbRows.Close()
+	for dbRows.Next() {
+		var id string
+		if err := dbRows.Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) != 1 || ids[0] != "restore" {
+		t.Errorf("want only %q left in the outbox table, got %v",
+			"restore", ids)
+	}
+}
+
+func TestAPI_extractApprovals(t *testing.T) {
+	// GIVEN a DB with approval rows for Services with/without an approval policy.
+	tAPI := testAPI("TestAPI_extractApprovals", "db")
+	t.Cleanup(func() { dbCleanup(tAPI) })
+	tAPI.initialise()
+	svc := tAPI.config.Service["keep0"]
+	svc.Approval = &approval.Policy{Required: 2}
+	svc.Approval.Init(&svc.Status)
+	approvals, _ := json.Marshal([]approval.Approval{
+		{Approver: "alice", Version: svc.Status.LatestVersion(), Time: time.Now().UTC()}})
+	for _, id := range []string{"keep0", "keep1", "unknown"} {
+		if _, err := tAPI.db.Exec("INSERT INTO approval (id, approvals) VALUES (?, ?)", id, string(approvals)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := tAPI.db.Exec("INSERT INTO approval (id, approvals) VALUES ('invalid', '[')"); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN extractApprovals is called.
+	tAPI.extractApprovals()
+
+	// THEN the approvals are restored to the Service with the policy.
+	if got := svc.Approval.Approvals(); len(got) != 1 || got[0].Approver != "alice" {
+		t.Errorf("want the approval of %q restored, got %+v",
+			"alice", got)
+	}
+	// AND the others are removed from the DB.
+	var ids []string
+	dbRows, err := tAPI.db.Query("SELECT id FROM approval")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbRows.Close()
+	for dbRows.Next() {
+		var id string
+		if err := dbRows.Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) != 1 || ids[0] != "keep0" {
+		t.Errorf("want only %q left in the approval table, got %v",
+			"keep0", ids)
+	}
+}
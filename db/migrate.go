@@ -0,0 +1,184 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package db provides database functionality for Argus to keep track of versions found/deployed/approved.
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/release-argus/Argus/config"
+	"github.com/release-argus/Argus/util"
+)
+
+// migration is a numbered change to the schema of the database.
+type migration struct {
+	description string                                // Description of the change.
+	up          func(tx *sql.Tx, store storage) error // Apply the change.
+}
+
+// migrations to the schema, applied in order. The version of a migration is its index + 1.
+//
+// Append new migrations to the end, and never edit/reorder those already released.
+var migrations = []migration{
+	{"create status", func(tx *sql.Tx, store storage) error {
+		return store.createStatus(tx)
+	}},
+	{"create notify_digest", execMigration(`
+		CREATE TABLE IF NOT EXISTS notify_digest (
+			id      TEXT NOT NULL PRIMARY KEY,
+			entries TEXT DEFAULT '[]'
+		);`)},
+	{"create outbox", execMigration(`
+		CREATE TABLE IF NOT EXISTS outbox (
+			id    TEXT NOT NULL PRIMARY KEY,
+			entry TEXT DEFAULT '{}'
+		);`)},
+	{"create history", execMigration(`
+		CREATE TABLE IF NOT EXISTS history (
+			id              TEXT    NOT NULL PRIMARY KEY,
+			time            TEXT    DEFAULT  '',
+			kind            TEXT    DEFAULT  '',
+			service_id      TEXT    DEFAULT  '',
+			target          TEXT    DEFAULT  '',
+			version         TEXT    DEFAULT  '',
+			payload         TEXT    DEFAULT  '',
+			response_status INTEGER DEFAULT  0,
+			duration_ms     INTEGER DEFAULT  0,
+			error           TEXT    DEFAULT  ''
+		);
+		CREATE INDEX IF NOT EXISTS history_time ON history (time);`)},
+	{"create approval", execMigration(`
+		CREATE TABLE IF NOT EXISTS approval (
+			id        TEXT NOT NULL PRIMARY KEY,
+			approvals TEXT DEFAULT '[]'
+		);`)},
+	{"create version_event", execMigration(`
+		CREATE TABLE IF NOT EXISTS version_event (
+			id         TEXT NOT NULL PRIMARY KEY,
+			time       TEXT DEFAULT '',
+			service_id TEXT DEFAULT '',
+			type       TEXT DEFAULT '',
+			version    TEXT DEFAULT '',
+			actor      TEXT DEFAULT ''
+		);
+		CREATE INDEX IF NOT EXISTS version_event_service ON version_event (service_id, time);`)},
+	{"add status.archived", execMigration(`
+		ALTER TABLE status ADD COLUMN archived TEXT DEFAULT '';`)},
+	{"add status.queued_version", execMigration(`
+		ALTER TABLE status ADD COLUMN queued_version TEXT DEFAULT '';`)},
+	{"add status.previous_version", execMigration(`
+		ALTER TABLE status ADD COLUMN previous_version TEXT DEFAULT '';`)},
+}
+
+// execMigration returns a migration step that executes `statements`,
+// using types both backends support.
+func execMigration(statements string) func(tx *sql.Tx, store storage) error {
+	return func(tx *sql.Tx, _ storage) error {
+		_, err := tx.Exec(statements)
+		return err
+	}
+}
+
+// schemaVersion returns the version of the latest migration applied to `db`,
+// creating the schema_version table if it doesn't exist.
+func schemaVersion(db *sql.DB) (int, error) {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_version (
+			version     INTEGER NOT NULL PRIMARY KEY,
+			description TEXT    DEFAULT  '',
+			applied     TEXT    DEFAULT  ''
+		);`); err != nil {
+		return 0, fmt.Errorf("create schema_version: %w", err)
+	}
+
+	var version int
+	if err := db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_version;").Scan(&version); err != nil {
+		return 0, fmt.Errorf("query schema_version: %w", err)
+	}
+	return version, nil
+}
+
+// migrate applies the migrations newer than the schema of `db`, each in its own transaction.
+//
+// Returns an error if the schema is newer than the migrations known to this version of Argus.
+func migrate(db *sql.DB, store storage) error {
+	current, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+	latest := len(migrations)
+	if current > latest {
+		return fmt.Errorf("database schema is at version %d, but this version of Argus only knows up to %d - upgrade Argus to use this database",
+			current, latest)
+	}
+
+	for version := current + 1; version <= latest; version++ {
+		step := migrations[version-1]
+		jLog.Verbose(
+			fmt.Sprintf("Applying migration %d - %s",
+				version, step.description),
+			logFrom, true)
+		if err := applyMigration(db, store, version, step); err != nil {
+			return fmt.Errorf("migration %d (%s): %w",
+				version, step.description, err)
+		}
+	}
+	if current != latest {
+		jLog.Info(
+			fmt.Sprintf("Database schema migrated from version %d to %d",
+				current, latest),
+			logFrom, true)
+	}
+	return nil
+}
+
+// applyMigration applies `step` to `db` and records it as `version` in the same transaction.
+func applyMigration(db *sql.DB, store storage, version int, step migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	//#nosec G104 -- Rollback after a Commit is a no-op.
+	//nolint:errcheck // ^
+	defer tx.Rollback()
+
+	if err := step.up(tx, store); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(
+		store.rebind("INSERT INTO schema_version (version, description, applied) VALUES (?, ?, ?);"),
+		version, step.description, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// MigrateOnly will apply the database migrations and exit, if `flag` is set.
+func MigrateOnly(
+	flag *bool,
+	cfg *config.Config,
+	log *util.JLog,
+) {
+	// Only if flag provided.
+	if !*flag {
+		return
+	}
+	api := commandAPI(cfg, log)
+	api.db.Close()
+	jLog.Info("Database migrations complete", logFrom, true)
+	exitCommand()
+}
@@ -0,0 +1,224 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+	_ "modernc.org/sqlite"
+)
+
+// testMigrateDB returns a new SQLite database for `name`, removed on cleanup.
+func testMigrateDB(t *testing.T, name string) (*sql.DB, storage) {
+	t.Helper()
+	databaseFile := strings.ReplaceAll(name+"-migrate.db", " ", "_")
+	store := &sqliteStorage{file: databaseFile}
+	db, err := sql.Open("sqlite", databaseFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		os.Remove(databaseFile)
+	})
+	return db, store
+}
+
+func TestMigrate(t *testing.T) {
+	// GIVEN databases at different schema versions.
+	tests := map[string]struct {
+		setup       func(t *testing.T, db *sql.DB)
+		wantVersion int
+		wantRow     bool
+		errRegex    string
+	}{
+		"new database": {
+			wantVersion: len(migrations)},
+		"database from before versioned migrations": {
+			setup: func(t *testing.T, db *sql.DB) {
+				if _, err := db.Exec(`
+					CREATE TABLE status (
+						id                         STRING   NOT NULL PRIMARY KEY,
+						latest_version             STRING   DEFAULT  '',
+						latest_version_timestamp   DATETIME DEFAULT  (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+						deployed_version           STRING   DEFAULT  '',
+						deployed_version_timestamp DATETIME DEFAULT  (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+						approved_version           STRING   DEFAULT  ''
+					);
+					INSERT INTO status (id, latest_version) VALUES ('keep0', '1.2.3');`); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantVersion: len(migrations),
+			wantRow:     true},
+		"already up to date": {
+			setup: func(t *testing.T, db *sql.DB) {
+				if err := migrate(db, &sqliteStorage{}); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantVersion: len(migrations)},
+		"newer than known": {
+			setup: func(t *testing.T, db *sql.DB) {
+				if _, err := schemaVersion(db); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := db.Exec("INSERT INTO schema_version (version) VALUES (?)", len(migrations)+1); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantVersion: len(migrations) + 1,
+			errRegex:    `^database schema is at version \d+, but this version of Argus only knows up to \d+ `},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// t.Parallel() - Cannot run in parallel since we're using stdout.
+			releaseStdout := test.CaptureStdout()
+			defer releaseStdout()
+
+			db, store := testMigrateDB(t, "TestMigrate-"+name)
+			if tc.setup != nil {
+				tc.setup(t, db)
+			}
+
+			// WHEN migrate is called.
+			err := migrate(db, store)
+
+			// THEN any error is as expected.
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Fatalf("want error matching %q, got %q",
+					tc.errRegex, e)
+			}
+			// AND the schema is at the expected version.
+			version, _ := schemaVersion(db)
+			if version != tc.wantVersion {
+				t.Errorf("want schema version %d, got %d",
+					tc.wantVersion, version)
+			}
+			if err != nil {
+				return
+			}
+			// AND each migration was recorded once.
+			var count int
+			db.QueryRow("SELECT COUNT(*) FROM schema_version").Scan(&count)
+			if count != len(migrations) {
+				t.Errorf("want %d schema_version rows, got %d",
+					len(migrations), count)
+			}
+			// AND the tables exist.
+			for _, table := range []string{"status", "notify_digest", "outbox", "history", "approval", "version_event"} {
+				if _, err := db.Exec("SELECT * FROM " + table); err != nil {
+					t.Errorf("table %q: %v",
+						table, err)
+				}
+			}
+			// AND the status rows were kept.
+			if tc.wantRow {
+				if got := queryRow(t, db, "keep0"); got.LatestVersion() != "1.2.3" {
+					t.Errorf("want latest_version %q kept, got %q",
+						"1.2.3", got.LatestVersion())
+				}
+			}
+		})
+	}
+}
+
+func TestMigrate_Rollback(t *testing.T) {
+	// GIVEN a migration that fails part-way through.
+	db, store := testMigrateDB(t, "TestMigrate_Rollback")
+	known := migrations
+	t.Cleanup(func() { migrations = known })
+	migrations = append(known[:len(known):len(known)], migration{
+		description: "fails",
+		up: func(tx *sql.Tx, _ storage) error {
+			if _, err := tx.Exec("CREATE TABLE partial (id TEXT);"); err != nil {
+				return err
+			}
+			return errors.New("fail")
+		}})
+
+	// WHEN migrate is called.
+	err := migrate(db, store)
+
+	// THEN the error names the failed migration.
+	want := `migration ` + strconv.Itoa(len(migrations)) + ` (fails): fail`
+	if e := util.ErrorToString(err); e != want {
+		t.Fatalf("want error %q, got %q",
+			want, e)
+	}
+	// AND the migrations before it were applied.
+	if version, _ := schemaVersion(db); version != len(known) {
+		t.Errorf("want schema version %d, got %d",
+			len(known), version)
+	}
+	// AND the failed migration was rolled back.
+	if _, err := db.Exec("SELECT * FROM partial"); err == nil {
+		t.Error("want the partial table to have been rolled back")
+	}
+}
+
+func TestMigrateOnly(t *testing.T) {
+	// GIVEN the db.migrate-only flag.
+	tests := map[string]struct {
+		flag      bool
+		wantTable bool
+	}{
+		"flag not set": {
+			flag: false, wantTable: false},
+		"flag set": {
+			flag: true, wantTable: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// t.Parallel() - Cannot run in parallel since we're using stdout.
+			releaseStdout := test.CaptureStdout()
+			defer releaseStdout()
+
+			tAPI := testAPI("TestMigrateOnly", name)
+			t.Cleanup(func() { dbCleanup(tAPI) })
+
+			// WHEN MigrateOnly is called.
+			MigrateOnly(&tc.flag, tAPI.config, nil)
+
+			// THEN the database is only migrated when the flag is set.
+			_, err := os.Stat(tAPI.config.Settings.Data.DatabaseFile)
+			if gotTable := err == nil; gotTable != tc.wantTable {
+				t.Fatalf("want database created=%t, got %t",
+					tc.wantTable, gotTable)
+			}
+			if !tc.wantTable {
+				return
+			}
+			db, _ := sql.Open("sqlite", tAPI.config.Settings.Data.DatabaseFile)
+			defer db.Close()
+			if version, _ := schemaVersion(db); version != len(migrations) {
+				t.Errorf("want schema version %d, got %d",
+					len(migrations), version)
+			}
+		})
+	}
+}
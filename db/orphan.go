@@ -0,0 +1,371 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package db provides database functionality for Argus to keep track of versions found/deployed/approved.
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/notify/shoutrrr"
+	"github.com/release-argus/Argus/outbox"
+	"github.com/release-argus/Argus/service/approval"
+)
+
+var (
+	// ErrOrphanNotFound is returned by RestoreOrphan when there's no archived status with the ID.
+	ErrOrphanNotFound = errors.New("orphan not found")
+	// ErrServiceNotFound is returned by RestoreOrphan when the Service to restore to isn't in the config.
+	ErrServiceNotFound = errors.New("service not found")
+)
+
+// Orphans returns the archived status of the Services no longer in the config, by ID.
+func Orphans() ([]dbtype.Orphan, error) {
+	api := running.Load()
+	if api == nil {
+		return nil, errNotRunning
+	}
+	return api.orphans()
+}
+
+// RestoreOrphan restores the archived status (and version history/approvals) of `id`
+// to the `serviceID` Service (default = `id`), replacing the status that Service has.
+//
+// Restoring to another ID renames the Service in the database, keeping its data.
+//
+// The status/approval writes already queued are made first,
+// so they can't overwrite the restored rows.
+func RestoreOrphan(id, serviceID string) error {
+	api := running.Load()
+	if api == nil {
+		return errNotRunning
+	}
+
+	var restoreErr error
+	if err := api.exclusive(func() {
+		restoreErr = api.restoreOrphan(id, serviceID)
+	}); err != nil {
+		return err
+	}
+	return restoreErr
+}
+
+// archiveUnknownServices will archive the rows with an ID not in config.Order,
+// restore those archived that are back in it, and remove those archived for longer than the retention.
+func (api *api) archiveUnknownServices() {
+	rows, err := api.query(`
+		SELECT
+			id,
+			archived
+		FROM status;`)
+	jLog.Fatal(err, logFrom, err != nil)
+
+	known := make(map[string]bool, len(api.config.Order))
+	for _, id := range api.config.Order {
+		known[id] = true
+	}
+	now := time.Now().UTC()
+	retention := api.config.Settings.DataOrphanRetention()
+	var archive, restore, purge []string
+	for rows.Next() {
+		var id, archived string
+		if err := rows.Scan(&id, &archived); err != nil {
+			rows.Close()
+			jLog.Fatal(
+				fmt.Sprintf("archiveUnknownServices row: %s",
+					err),
+				logFrom, true)
+		}
+
+		switch {
+		case known[id]:
+			if archived != "" {
+				restore = append(restore, id)
+			}
+		case archived == "":
+			archive = append(archive, id)
+		case retention != 0:
+			archivedAt, err := time.Parse(time.RFC3339, archived)
+			if err != nil || now.Sub(archivedAt) > retention {
+				purge = append(purge, id)
+			}
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		jLog.Fatal(
+			fmt.Sprintf("archiveUnknownServices: %s",
+				err),
+			logFrom, true)
+	}
+
+	for _, id := range archive {
+		api.archiveServiceExec("UPDATE status SET archived = ? WHERE id = ?",
+			now.Format(time.RFC3339), id)
+		jLog.Warn(
+			fmt.Sprintf("%q is no longer in the config, archived its status (restore it via /api/v1/orphans)",
+				id),
+			logFrom, true)
+	}
+	for _, id := range restore {
+		api.archiveServiceExec("UPDATE status SET archived = '' WHERE id = ?",
+			id)
+		jLog.Info(
+			fmt.Sprintf("%q is back in the config, restored its archived status",
+				id),
+			logFrom, true)
+	}
+	for _, id := range purge {
+		api.archiveServiceExec("DELETE FROM status WHERE id = ?", id)
+		api.archiveServiceExec("DELETE FROM version_event WHERE service_id = ?", id)
+		api.archiveServiceExec("DELETE FROM approval WHERE id = ?", id)
+		api.archiveServiceExec("DELETE FROM history WHERE service_id = ?", id)
+		api.purgeServiceOutbox(id)
+		api.purgeServiceDigests(id)
+		jLog.Info(
+			fmt.Sprintf("%q was archived for longer than %s, removed its status",
+				id, retention),
+			logFrom, true)
+	}
+}
+
+// archiveServiceExec runs the `sqlStmt` of archiveUnknownServices, exiting on failure.
+func (api *api) archiveServiceExec(sqlStmt string, args ...any) {
+	if _, err := api.exec(sqlStmt, args...); err != nil {
+		jLog.Fatal(
+			fmt.Sprintf("archiveUnknownServices: %q %v, %s",
+				sqlStmt, args, err),
+			logFrom, true)
+	}
+}
+
+// purgeServiceOutbox removes the outbox entries of the `serviceID` Service, exiting on failure.
+func (api *api) purgeServiceOutbox(serviceID string) {
+	rows, err := api.query(`
+		SELECT
+			id,
+			entry
+		FROM outbox;`)
+	jLog.Fatal(err, logFrom, err != nil)
+
+	var remove []string
+	for rows.Next() {
+		var id, entryStr string
+		if err := rows.Scan(&id, &entryStr); err != nil {
+			rows.Close()
+			jLog.Fatal(
+				fmt.Sprintf("archiveUnknownServices outbox row: %s",
+					err),
+				logFrom, true)
+		}
+		var entry outbox.Entry
+		if err := json.Unmarshal([]byte(entryStr), &entry); err == nil && entry.ServiceID == serviceID {
+			remove = append(remove, id)
+		}
+	}
+	rows.Close()
+
+	for _, id := range remove {
+		api.archiveServiceExec("DELETE FROM outbox WHERE id = ?", id)
+	}
+}
+
+// purgeServiceDigests removes the entries of the `serviceID` Service from the pending digests,
+// removing the digests left empty, exiting on failure.
+func (api *api) purgeServiceDigests(serviceID string) {
+	rows, err := api.query(`
+		SELECT
+			id,
+			entries
+		FROM notify_digest;`)
+	jLog.Fatal(err, logFrom, err != nil)
+
+	updates := make(map[string][]shoutrrr.DigestEntry)
+	for rows.Next() {
+		var id, entriesStr string
+		if err := rows.Scan(&id, &entriesStr); err != nil {
+			rows.Close()
+			jLog.Fatal(
+				fmt.Sprintf("archiveUnknownServices notify_digest row: %s",
+					err),
+				logFrom, true)
+		}
+		var entries []shoutrrr.DigestEntry
+		if err := json.Unmarshal([]byte(entriesStr), &entries); err != nil {
+			continue
+		}
+		kept := make([]shoutrrr.DigestEntry, 0, len(entries))
+		for _, entry := range entries {
+			if entry.ServiceInfo.ID != serviceID {
+				kept = append(kept, entry)
+			}
+		}
+		if len(kept) != len(entries) {
+			updates[id] = kept
+		}
+	}
+	rows.Close()
+
+	for id, entries := range updates {
+		if len(entries) == 0 {
+			api.archiveServiceExec("DELETE FROM notify_digest WHERE id = ?", id)
+			continue
+		}
+		entriesStr, _ := json.Marshal(entries)
+		api.archiveServiceExec("UPDATE notify_digest SET entries = ? WHERE id = ?",
+			string(entriesStr), id)
+	}
+}
+
+// orphans returns the archived rows, by ID.
+func (api *api) orphans() ([]dbtype.Orphan, error) {
+	rows, err := api.query(`
+		SELECT
+			id,
+			latest_version,
+			latest_version_timestamp,
+			deployed_version,
+			deployed_version_timestamp,
+			approved_version,
+			archived
+		FROM status
+		WHERE archived <> ''
+		ORDER BY id;`)
+	if err != nil {
+		return nil, fmt.Errorf("orphans: %w", err)
+	}
+	defer rows.Close()
+
+	orphans := []dbtype.Orphan{}
+	for rows.Next() {
+		var (
+			orphan   dbtype.Orphan
+			archived string
+		)
+		if err := rows.Scan(
+			&orphan.ID,
+			&orphan.LatestVersion,
+			&orphan.LatestVersionTimestamp,
+			&orphan.DeployedVersion,
+			&orphan.DeployedVersionTimestamp,
+			&orphan.ApprovedVersion,
+			&archived,
+		); err != nil {
+			return nil, fmt.Errorf("orphans row: %w", err)
+		}
+		orphan.Archived, _ = time.Parse(time.RFC3339, archived)
+		orphans = append(orphans, orphan)
+	}
+	return orphans, rows.Err()
+}
+
+// restoreOrphan restores the archived row of `id` to the `serviceID` Service (default = `id`),
+// in the database and the Service.
+func (api *api) restoreOrphan(id, serviceID string) error {
+	if serviceID == "" {
+		serviceID = id
+	}
+	api.config.OrderMutex.RLock()
+	svc := api.config.Service[serviceID]
+	api.config.OrderMutex.RUnlock()
+	if svc == nil {
+		return fmt.Errorf("%w: %q",
+			ErrServiceNotFound, serviceID)
+	}
+
+	tx, err := api.db.Begin()
+	if err != nil {
+		return err
+	}
+	//#nosec G104 -- Rollback after a Commit is a no-op.
+	//nolint:errcheck // ^
+	defer tx.Rollback()
+
+	var lv, lvt, dv, dvt, av, pv string
+	err = tx.QueryRow(
+		api.storage.rebind(`
+			SELECT
+				latest_version,
+				latest_version_timestamp,
+				deployed_version,
+				deployed_version_timestamp,
+				approved_version,
+				previous_version
+			FROM status
+			WHERE id = ? AND archived <> '';`),
+		id).Scan(&lv, &lvt, &dv, &dvt, &av, &pv)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %q",
+			ErrOrphanNotFound, id)
+	} else if err != nil {
+		return err
+	}
+
+	type statement struct {
+		query string
+		args  []any
+	}
+	var statements []statement
+	if serviceID != id {
+		// Replace the rows of the Service with those of the orphan.
+		statements = []statement{
+			{"DELETE FROM status WHERE id = ?", []any{serviceID}},
+			{"UPDATE status SET id = ? WHERE id = ?", []any{serviceID, id}},
+			{"DELETE FROM approval WHERE id = ?", []any{serviceID}},
+			{"UPDATE approval SET id = ? WHERE id = ?", []any{serviceID, id}},
+			{"UPDATE version_event SET service_id = ? WHERE service_id = ?", []any{serviceID, id}},
+			{"UPDATE history SET service_id = ? WHERE service_id = ?", []any{serviceID, id}}}
+	}
+	statements = append(statements,
+		statement{"UPDATE status SET archived = '' WHERE id = ?", []any{serviceID}})
+	for _, stmt := range statements {
+		if _, err := tx.Exec(api.storage.rebind(stmt.query), stmt.args...); err != nil {
+			return fmt.Errorf("restore %q: %w",
+				id, err)
+		}
+	}
+
+	var approvalsStr string
+	if svc.Approval != nil {
+		err := tx.QueryRow(api.storage.rebind("SELECT approvals FROM approval WHERE id = ?;"), serviceID).Scan(&approvalsStr)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("restore %q approvals: %w",
+				id, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	svc.Status.SetLatestVersion(lv, lvt, false)
+	svc.Status.SetPreviousLatestVersion(pv)
+	svc.Status.SetDeployedVersion(dv, dvt, false)
+	svc.Status.SetApprovedVersion(av, false)
+	if approvalsStr != "" {
+		var approvals []approval.Approval
+		if err := json.Unmarshal([]byte(approvalsStr), &approvals); err == nil {
+			svc.Approval.Restore(approvals)
+		}
+	}
+	jLog.Info(
+		fmt.Sprintf("Restored the archived status of %q to %q",
+			id, serviceID),
+		logFrom, true)
+	return nil
+}
@@ -0,0 +1,425 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/notify/shoutrrr"
+	"github.com/release-argus/Argus/outbox"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+// testOrphanRows inserts status rows (archived at the times given), and a version_event, history row,
+// outbox entry and digest for each (as well as a digest shared by them all).
+func testOrphanRows(t *testing.T, tAPI *api, archived map[string]string) {
+	t.Helper()
+	shared := make([]shoutrrr.DigestEntry, 0, len(archived))
+	for id, archivedAt := range archived {
+		if _, err := tAPI.db.Exec(`
+			INSERT INTO status (id, latest_version, latest_version_timestamp, approved_version, archived)
+			VALUES (?, ?, ?, ?, ?)`,
+			id, "1.2.3", "2025-01-02T03:04:05Z", "1.2.3", archivedAt); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tAPI.db.Exec(`
+			INSERT INTO version_event (id, time, service_id, type, version)
+			VALUES (?, ?, ?, ?, ?)`,
+			"event-"+id, "2025-01-02T03:04:05Z", id, "seen", "1.2.3"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tAPI.db.Exec(`
+			INSERT INTO history (id, time, kind, service_id)
+			VALUES (?, ?, ?, ?)`,
+			"history-"+id, "2025-01-02T03:04:05Z", "webhook", id); err != nil {
+			t.Fatal(err)
+		}
+		entry, _ := json.Marshal(outbox.Entry{Kind: outbox.KindWebHook, ServiceID: id})
+		if _, err := tAPI.db.Exec("INSERT INTO outbox (id, entry) VALUES (?, ?)",
+			"outbox-"+id, string(entry)); err != nil {
+			t.Fatal(err)
+		}
+		digestEntry := shoutrrr.DigestEntry{ServiceInfo: util.ServiceInfo{ID: id}}
+		shared = append(shared, digestEntry)
+		entries, _ := json.Marshal([]shoutrrr.DigestEntry{digestEntry})
+		if _, err := tAPI.db.Exec("INSERT INTO notify_digest (id, entries) VALUES (?, ?)",
+			"digest-"+id, string(entries)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, _ := json.Marshal(shared)
+	if _, err := tAPI.db.Exec("INSERT INTO notify_digest (id, entries) VALUES (?, ?)",
+		"shared", string(entries)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testServiceData returns the services with history rows, outbox entries, and digest entries.
+func testServiceData(t *testing.T, tAPI *api) (history, outboxEntries, digests map[string]bool) {
+	t.Helper()
+	history, outboxEntries, digests = map[string]bool{}, map[string]bool{}, map[string]bool{}
+	rows, err := tAPI.db.Query("SELECT service_id FROM history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var id string
+		rows.Scan(&id)
+		history[id] = true
+	}
+	rows.Close()
+
+	rows, err = tAPI.db.Query("SELECT entry FROM outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var entryStr string
+		rows.Scan(&entryStr)
+		var entry outbox.Entry
+		json.Unmarshal([]byte(entryStr), &entry)
+		outboxEntries[entry.ServiceID] = true
+	}
+	rows.Close()
+
+	rows, err = tAPI.db.Query("SELECT id, entries FROM notify_digest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var id, entriesStr string
+		rows.Scan(&id, &entriesStr)
+		var entries []shoutrrr.DigestEntry
+		json.Unmarshal([]byte(entriesStr), &entries)
+		if len(entries) == 0 {
+			t.Errorf("want empty digest %q removed",
+				id)
+		}
+		for _, entry := range entries {
+			digests[entry.ServiceInfo.ID] = true
+		}
+	}
+	rows.Close()
+	return history, outboxEntries, digests
+}
+
+// testArchived returns the archived column of each status row, and the services with version events.
+func testArchived(t *testing.T, tAPI *api) (map[string]string, map[string]bool) {
+	t.Helper()
+	archived := map[string]string{}
+	rows, err := tAPI.db.Query("SELECT id, archived FROM status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var id, archivedAt string
+		rows.Scan(&id, &archivedAt)
+		archived[id] = archivedAt
+	}
+	rows.Close()
+
+	events := map[string]bool{}
+	rows, err = tAPI.db.Query("SELECT service_id FROM version_event")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var id string
+		rows.Scan(&id)
+		events[id] = true
+	}
+	rows.Close()
+	return archived, events
+}
+
+func TestAPI_archiveUnknownServices(t *testing.T) {
+	now := time.Now().UTC()
+	recent := now.Add(-time.Hour).Format(time.RFC3339)
+	old := now.Add(-48 * time.Hour).Format(time.RFC3339)
+	// GIVEN a DB with rows for services in/not in the config, archived/not.
+	tests := map[string]struct {
+		retention                  string
+		databaseDeleted            bool
+		wantArchived, wantRestored []string
+		wantKept, wantPurged       []string
+	}{
+		"archive, restore and purge": {
+			retention:    "24h",
+			wantArchived: []string{"unknown"},
+			wantRestored: []string{"keep0"},
+			wantKept:     []string{"recent"},
+			wantPurged:   []string{"old"}},
+		"keep forever": {
+			retention:    "0s",
+			wantArchived: []string{"unknown"},
+			wantRestored: []string{"keep0"},
+			wantKept:     []string{"recent", "old"}},
+		"DB is deleted": {
+			databaseDeleted: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tAPI := testAPI(name, "TestAPI_archiveUnknownServices")
+			t.Cleanup(func() { dbCleanup(tAPI) })
+			tAPI.config.Settings.Data.OrphanRetention = tc.retention
+			tAPI.initialise()
+			testOrphanRows(t, tAPI, map[string]string{
+				"keep0":   recent,
+				"keep1":   "",
+				"unknown": "",
+				"recent":  recent,
+				"old":     old})
+			// Catch fatal panics.
+			defer func() {
+				r := recover()
+				// Ignore nil panics.
+				if r == nil {
+					return
+				}
+
+				if !tc.databaseDeleted {
+					t.Fatalf("unexpected panic: %v", r)
+				}
+			}()
+			// Delete the DB file.
+			if tc.databaseDeleted {
+				os.Remove(tAPI.config.Settings.Data.DatabaseFile)
+			}
+
+			// WHEN archiveUnknownServices is called.
+			tAPI.archiveUnknownServices()
+
+			// THEN the app panic'd if the db was deleted.
+			if tc.databaseDeleted {
+				t.Error("Expected a panic")
+				return
+			}
+			archived, events := testArchived(t, tAPI)
+			// AND the services no longer in the config were archived.
+			for _, id := range tc.wantArchived {
+				if archived[id] == "" {
+					t.Errorf("want %q archived, got %q",
+						id, archived[id])
+				}
+			}
+			// AND those back in the config were restored.
+			for _, id := range append(tc.wantRestored, "keep1") {
+				if got, ok := archived[id]; !ok || got != "" {
+					t.Errorf("want %q unarchived, got %q (exists=%t)",
+						id, got, ok)
+				}
+			}
+			// AND those archived within the retention were kept.
+			for _, id := range tc.wantKept {
+				if got := archived[id]; got == "" || !events[id] {
+					t.Errorf("want %q kept archived with its events, got %q (events=%t)",
+						id, got, events[id])
+				}
+			}
+			// AND those archived for longer were removed, with their events.
+			for _, id := range tc.wantPurged {
+				if _, ok := archived[id]; ok || events[id] {
+					t.Errorf("want %q removed with its events, got row=%t, events=%t",
+						id, ok, events[id])
+				}
+			}
+			// AND their history, outbox entries, and digest entries.
+			history, outboxEntries, digests := testServiceData(t, tAPI)
+			for _, id := range tc.wantPurged {
+				if history[id] || outboxEntries[id] || digests[id] {
+					t.Errorf("want the data of %q removed, got history=%t, outbox=%t, digests=%t",
+						id, history[id], outboxEntries[id], digests[id])
+				}
+			}
+			for _, id := range tc.wantKept {
+				if !history[id] || !outboxEntries[id] || !digests[id] {
+					t.Errorf("want the data of %q kept, got history=%t, outbox=%t, digests=%t",
+						id, history[id], outboxEntries[id], digests[id])
+				}
+			}
+		})
+	}
+}
+
+func TestAPI_orphans(t *testing.T) {
+	// GIVEN a DB with archived and unarchived rows.
+	tAPI := testAPI("TestAPI_orphans", "db")
+	t.Cleanup(func() { dbCleanup(tAPI) })
+	tAPI.initialise()
+	archivedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	testOrphanRows(t, tAPI, map[string]string{
+		"keep0": "",
+		"b":     archivedAt.Format(time.RFC3339),
+		"a":     archivedAt.Format(time.RFC3339)})
+
+	// WHEN orphans is called.
+	got, err := tAPI.orphans()
+
+	// THEN the archived rows are returned, by ID.
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].ID != "a" || got[1].ID != "b" {
+		t.Fatalf("want orphans [a b], got %v",
+			got)
+	}
+	if got[0].LatestVersion != "1.2.3" || got[0].ApprovedVersion != "1.2.3" ||
+		!got[0].Archived.Equal(archivedAt) {
+		t.Errorf("unexpected orphan: %+v",
+			got[0])
+	}
+}
+
+func TestAPI_restoreOrphan(t *testing.T) {
+	// GIVEN a DB with an archived row.
+	tests := map[string]struct {
+		id, serviceID string
+		wantService   string
+		errIs         error
+	}{
+		"restore to the same ID": {
+			id:          "keep0",
+			wantService: "keep0"},
+		"restore to a new ID": {
+			id:          "renamed",
+			serviceID:   "keep1",
+			wantService: "keep1"},
+		"orphan not found": {
+			id:        "unknown",
+			serviceID: "keep1",
+			errIs:     ErrOrphanNotFound},
+		"not archived": {
+			id:        "keep2",
+			serviceID: "keep1",
+			errIs:     ErrOrphanNotFound},
+		"service not found": {
+			id:        "renamed",
+			serviceID: "unknown",
+			errIs:     ErrServiceNotFound},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// t.Parallel() - Cannot run in parallel since we're using stdout.
+			releaseStdout := test.CaptureStdout()
+			defer releaseStdout()
+
+			tAPI := testAPI(name, "TestAPI_restoreOrphan")
+			t.Cleanup(func() { dbCleanup(tAPI) })
+			tAPI.initialise()
+			archivedAt := time.Now().UTC().Format(time.RFC3339)
+			testOrphanRows(t, tAPI, map[string]string{
+				"keep0":   archivedAt,
+				"renamed": archivedAt,
+				"keep2":   ""})
+			if _, err := tAPI.db.Exec("INSERT INTO status (id, latest_version) VALUES ('keep1', '0.0.1')"); err != nil {
+				t.Fatal(err)
+			}
+
+			// WHEN restoreOrphan is called.
+			err := tAPI.restoreOrphan(tc.id, tc.serviceID)
+
+			// THEN any error is as expected.
+			if tc.errIs != nil || err != nil {
+				if !errors.Is(err, tc.errIs) {
+					t.Fatalf("want error %v, got %v",
+						tc.errIs, err)
+				}
+				return
+			}
+			// AND the row is restored to the Service.
+			archived, events := testArchived(t, tAPI)
+			if got, ok := archived[tc.wantService]; !ok || got != "" {
+				t.Errorf("want %q unarchived, got %q (exists=%t)",
+					tc.wantService, got, ok)
+			}
+			if !events[tc.wantService] {
+				t.Errorf("want the version events of %q moved to %q",
+					tc.id, tc.wantService)
+			}
+			history, _, _ := testServiceData(t, tAPI)
+			if !history[tc.wantService] {
+				t.Errorf("want the history of %q moved to %q",
+					tc.id, tc.wantService)
+			}
+			if tc.id != tc.wantService {
+				if _, ok := archived[tc.id]; ok || events[tc.id] || history[tc.id] {
+					t.Errorf("want %q renamed to %q, got row=%t, events=%t, history=%t",
+						tc.id, tc.wantService, ok, events[tc.id], history[tc.id])
+				}
+			}
+			got := queryRow(t, tAPI.db, tc.wantService)
+			if got.LatestVersion() != "1.2.3" {
+				t.Errorf("want latest_version %q restored, got %q",
+					"1.2.3", got.LatestVersion())
+			}
+			// AND the status of the Service is updated.
+			svcStatus := &tAPI.config.Service[tc.wantService].Status
+			if svcStatus.LatestVersion() != "1.2.3" || svcStatus.LatestVersionTimestamp() != "2025-01-02T03:04:05Z" {
+				t.Errorf("want the Service status restored, got %s",
+					svcStatus.String())
+			}
+		})
+	}
+}
+
+func TestRestoreOrphan(t *testing.T) {
+	// GIVEN a running DB with an archived row, and a status write of the Service to restore it to queued.
+	tAPI := testAPI("TestRestoreOrphan", "db")
+	t.Cleanup(func() { dbCleanup(tAPI) })
+	tAPI.shutdown = make(chan struct{})
+	tAPI.stopped = make(chan struct{})
+	tAPI.tasks = make(chan func())
+	tAPI.initialise()
+	testOrphanRows(t, tAPI, map[string]string{
+		"renamed": time.Now().UTC().Format(time.RFC3339)})
+	*tAPI.config.DatabaseChannel <- dbtype.Message{
+		ServiceID: "keep1",
+		Cells:     []dbtype.Cell{{Column: "latest_version", Value: "0.0.1"}}}
+	releaseStdout := test.CaptureStdout()
+	defer releaseStdout()
+	go tAPI.handler()
+	running.Store(tAPI)
+	t.Cleanup(func() {
+		running.Store(nil)
+		close(tAPI.shutdown)
+		<-tAPI.stopped
+	})
+
+	// WHEN RestoreOrphan is called.
+	err := RestoreOrphan("renamed", "keep1")
+
+	// THEN the row is restored.
+	if err != nil {
+		t.Fatalf("restore: %v",
+			err)
+	}
+	// AND the queued write was made before it, so doesn't overwrite it.
+	if got := queryRow(t, tAPI.db, "keep1"); got.LatestVersion() != "1.2.3" {
+		t.Errorf("want latest_version %q restored, got %q",
+			"1.2.3", got.LatestVersion())
+	}
+}
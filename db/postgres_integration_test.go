@@ -0,0 +1,171 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration
+
+package db
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/release-argus/Argus/config"
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/history"
+)
+
+// testPostgresAPI returns an api on the PostgreSQL database at ARGUS_TEST_POSTGRES_URL
+// (skipping the test if unset), with its tables emptied.
+func testPostgresAPI(t *testing.T) *api {
+	t.Helper()
+	url := os.Getenv("ARGUS_TEST_POSTGRES_URL")
+	if url == "" {
+		t.Skip("ARGUS_TEST_POSTGRES_URL not set")
+	}
+
+	tAPI := &api{config: testConfig()}
+	tAPI.config.Settings.Data.Driver = config.DataDriverPostgres
+	tAPI.config.Settings.Data.URL = url
+	tAPI.initialise()
+	t.Cleanup(func() { tAPI.db.Close() })
+	for _, table := range []string{"status", "notify_digest", "outbox", "history", "approval", "version_event"} {
+		if _, err := tAPI.db.Exec("DELETE FROM " + table); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tAPI
+}
+
+func TestPostgres_StatusRows(t *testing.T) {
+	// GIVEN a PostgreSQL database.
+	tAPI := testPostgresAPI(t)
+	// Migrating again is a no-op.
+	if err := migrate(tAPI.db, tAPI.storage); err != nil {
+		t.Fatalf("re-migrate: %v",
+			err)
+	}
+
+	// WHEN rows are inserted/updated.
+	tAPI.updateRow("keep0", []dbtype.Cell{
+		{Column: "latest_version", Value: "1.2.3"},
+		{Column: "latest_version_timestamp", Value: "2025-01-01T00:00:00Z"}})
+	tAPI.updateRow("keep0", []dbtype.Cell{
+		{Column: "deployed_version", Value: "1.2.3"},
+		{Column: "deployed_version_timestamp", Value: "2025-01-02T00:00:00Z"},
+		{Column: "approved_version", Value: "1.2.3"}})
+	tAPI.updateRow("delete0", []dbtype.Cell{
+		{Column: "latest_version", Value: "0.0.1"}})
+
+	// THEN they are extracted into the Service Status.
+	tAPI.extractServiceStatus()
+	svcStatus := &tAPI.config.Service["keep0"].Status
+	if svcStatus.LatestVersion() != "1.2.3" ||
+		svcStatus.LatestVersionTimestamp() != "2025-01-01T00:00:00Z" ||
+		svcStatus.DeployedVersion() != "1.2.3" ||
+		svcStatus.DeployedVersionTimestamp() != "2025-01-02T00:00:00Z" {
+		t.Errorf("unexpected status extracted: %s",
+			svcStatus.String())
+	}
+
+	// WHEN a row is deleted.
+	tAPI.deleteRow("delete0")
+
+	// THEN it is removed.
+	var count int
+	if err := tAPI.queryRow("SELECT COUNT(*) FROM status WHERE id = ?", "delete0").Scan(&count); err != nil || count != 0 {
+		t.Errorf("want deleted row, got count=%d (err=%v)",
+			count, err)
+	}
+
+	// WHEN unknown services are archived.
+	tAPI.updateRow("unknown", []dbtype.Cell{
+		{Column: "latest_version", Value: "9.9.9"}})
+	tAPI.archiveUnknownServices()
+
+	// THEN they are orphans.
+	orphans, err := tAPI.orphans()
+	if err != nil || len(orphans) != 1 || orphans[0].ID != "unknown" {
+		t.Fatalf("want the unknown orphan, got %v (err=%v)",
+			orphans, err)
+	}
+
+	// WHEN the orphan is restored to a known service.
+	if err := tAPI.restoreOrphan("unknown", "keep0"); err != nil {
+		t.Fatalf("restoreOrphan: %v",
+			err)
+	}
+
+	// THEN it replaces the status of that service.
+	if svcStatus.LatestVersion() != "9.9.9" {
+		t.Errorf("want latest_version %q restored, got %q",
+			"9.9.9", svcStatus.LatestVersion())
+	}
+	if err := tAPI.queryRow("SELECT COUNT(*) FROM status").Scan(&count); err != nil || count != 1 {
+		t.Errorf("want 1 row, got count=%d (err=%v)",
+			count, err)
+	}
+}
+
+func TestPostgres_History(t *testing.T) {
+	// GIVEN a PostgreSQL database with history and version events.
+	tAPI := testPostgresAPI(t)
+	now := time.Now().UTC()
+	for i, kind := range []string{history.KindNotify, history.KindWebHook} {
+		tAPI.handleMessage(dbtype.Message{
+			Table:     dbtype.TableHistory,
+			ServiceID: kind,
+			Cells: []dbtype.Cell{
+				{Column: "time", Value: now.Add(time.Duration(i) * time.Second).Format(history.TimeFormat)},
+				{Column: "kind", Value: kind},
+				{Column: "service_id", Value: "keep0"},
+				{Column: "response_status", Value: "200"},
+				{Column: "duration_ms", Value: "5"}}})
+	}
+	tAPI.handleMessage(dbtype.Message{
+		ServiceID: "keep0",
+		Cells: []dbtype.Cell{
+			{Column: "latest_version", Value: "2.0.0"},
+			{Column: "deployed_version", Value: "2.0.0"}}})
+
+	// WHEN the history is queried.
+	records, total, err := tAPI.queryHistory(history.Filter{ServiceID: "keep0", Limit: 1})
+
+	// THEN the newest Record is returned, with the total.
+	if err != nil || total != 2 || len(records) != 1 || records[0].Kind != history.KindWebHook {
+		t.Errorf("want newest of 2 records, got %+v, total=%d (err=%v)",
+			records, total, err)
+	}
+
+	// WHEN the version events are queried.
+	events, err := tAPI.queryVersionEvents("keep0")
+
+	// THEN they are in order.
+	if err != nil || len(events) != 2 ||
+		events[0].Type != history.EventSeen || events[1].Type != history.EventDeployed {
+		t.Errorf("want seen then deployed, got %+v (err=%v)",
+			events, err)
+	}
+
+	// WHEN the history is pruned.
+	tAPI.config.Settings.Data.HistoryRetention = "1ns"
+	tAPI.pruneHistory()
+
+	// THEN the old Records are removed.
+	if _, total, _ = tAPI.queryHistory(history.Filter{Limit: 10}); total != 0 {
+		t.Errorf("want 0 records after prune, got %d",
+			total)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package db provides database functionality for Argus to keep track of versions found/deployed/approved.
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqliteStorage keeps the tables in a SQLite database file.
+type sqliteStorage struct {
+	file string // Path to the database file.
+}
+
+// String returns the path to the database file.
+func (s *sqliteStorage) String() string {
+	return s.file
+}
+
+// open the database file, creating it (and its directory) if it doesn't exist.
+func (s *sqliteStorage) open() (*sql.DB, error) {
+	checkFile(s.file)
+	db, err := sql.Open("sqlite", s.file)
+	if err != nil {
+		return nil, err
+	}
+	// History is queried while the handler writes, so share one connection to avoid SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+// createStatus creates the status table, and updates that of older versions.
+func (s *sqliteStorage) createStatus(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS status (
+			id                         TEXT     NOT NULL PRIMARY KEY,
+			latest_version             TEXT     DEFAULT  '',
+			latest_version_timestamp   DATETIME DEFAULT  (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+			deployed_version           TEXT     DEFAULT  '',
+			deployed_version_timestamp DATETIME DEFAULT  (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+			approved_version           TEXT     DEFAULT  ''
+		);`)
+	if err != nil {
+		return err
+	}
+
+	updateTable(tx)
+	return nil
+}
+
+// rebind returns the `query` as is, as SQLite uses `?` placeholders.
+func (s *sqliteStorage) rebind(query string) string {
+	return query
+}
+
+// quote the `identifier` with backticks.
+func (s *sqliteStorage) quote(identifier string) string {
+	return "`" + identifier + "`"
+}
+
+// backup the database to a new file at `path` with VACUUM INTO,
+// which is consistent whilst the database is being written to.
+func (s *sqliteStorage) backup(db *sql.DB, path string) error {
+	_, err := db.Exec("VACUUM INTO ?;", path)
+	return err
+}
+
+// transient returns whether `err` is from the database being busy/locked.
+func (s *sqliteStorage) transient(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	// Primary result code (without the extended code).
+	switch sqliteErr.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return true
+	}
+	return false
+}
+
+// updateTable will update the table for the latest version.
+func updateTable(db sqlExecutor) {
+	// Get the type of the *_version columns.
+	var columnType string
+	if err := db.QueryRow("SELECT type FROM pragma_table_info('status') WHERE name = 'latest_version'").Scan(&columnType); err != nil {
+		jLog.Fatal(
+			fmt.Sprintf("updateTable: %s",
+				err),
+			logFrom, true)
+	}
+	// Update if the column type is not TEXT.
+	if columnType != "TEXT" {
+		jLog.Verbose("Updating column types", logFrom, true)
+		updateColumnTypes(db)
+		jLog.Verbose("Finished updating column types", logFrom, true)
+	}
+}
+
+// updateColumnTypes will recreate the table with the correct column types.
+func updateColumnTypes(db sqlExecutor) {
+	// Create the new table.
+	sqlStmt := `
+		CREATE TABLE IF NOT EXISTS status_backup (
+			id                         TEXT     NOT NULL PRIMARY KEY,
+			latest_version             TEXT     DEFAULT  '',
+			latest_version_timestamp   DATETIME DEFAULT  (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+			deployed_version           TEXT     DEFAULT  '',
+			deployed_version_timestamp DATETIME DEFAULT  (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+			approved_version           TEXT     DEFAULT  ''
+		);`
+	if _, err := db.Exec(sqlStmt); err != nil {
+		jLog.Fatal(
+			fmt.Sprintf("updateColumnTypes - create: %s", err),
+			logFrom, true)
+	}
+
+	// Copy the data from the old table to the new table.
+	if _, err := db.Exec(`INSERT INTO status_backup SELECT * FROM status;`); err != nil {
+		jLog.Fatal(
+			fmt.Sprintf("updateColumnTypes - copy: %s",
+				err),
+			logFrom, true)
+	}
+
+	// Drop the table.
+	if _, err := db.Exec("DROP TABLE status;"); err != nil {
+		jLog.Fatal(
+			fmt.Sprintf("updateColumnTypes - drop: %s",
+				err),
+			logFrom, true)
+	}
+
+	// Rename the new table to the old table.
+	if _, err := db.Exec("ALTER TABLE status_backup RENAME TO status;"); err != nil {
+		jLog.Fatal(
+			fmt.Sprintf("updateColumnTypes - rename: %s",
+				err),
+			logFrom, true)
+	}
+}
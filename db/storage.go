@@ -0,0 +1,80 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package db provides database functionality for Argus to keep track of versions found/deployed/approved.
+package db
+
+import (
+	"database/sql"
+
+	"github.com/release-argus/Argus/config"
+)
+
+// storage is the database backend the tables are kept in.
+//
+// The api writes its queries with `?` placeholders, which the storage rebinds.
+type storage interface {
+	// String returns the database the storage uses (for logging, without secrets).
+	String() string
+	// open the database.
+	open() (*sql.DB, error)
+	// createStatus creates the status table (and updates that of older versions).
+	createStatus(tx *sql.Tx) error
+	// rebind the `?` placeholders of `query` to those of the backend.
+	rebind(query string) string
+	// quote the `identifier` (e.g. a column name).
+	quote(identifier string) string
+	// backup takes a consistent copy of `db`, writing it to `path`.
+	backup(db *sql.DB, path string) error
+	// transient returns whether `err` is temporary (e.g. the database is busy), so the write can be retried.
+	transient(err error) bool
+}
+
+// sqlExecutor is a database, or a transaction on one.
+type sqlExecutor interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// conn returns the transaction of the api if it's in one, else the database.
+func (api *api) conn() sqlExecutor {
+	if api.tx != nil {
+		return api.tx
+	}
+	return api.db
+}
+
+// newStorage returns the storage of the database in the `settings`.
+func newStorage(settings *config.Settings) storage {
+	if settings.DataDriver() == config.DataDriverPostgres {
+		return &postgresStorage{url: settings.DataURL()}
+	}
+	return &sqliteStorage{file: settings.DataDatabaseFile()}
+}
+
+// exec runs the `query` (with `?` placeholders) on the database.
+func (api *api) exec(query string, args ...any) (sql.Result, error) {
+	return api.conn().Exec(api.storage.rebind(query), args...)
+}
+
+// query runs the `query` (with `?` placeholders) on the database, returning its rows.
+func (api *api) query(query string, args ...any) (*sql.Rows, error) {
+	return api.conn().Query(api.storage.rebind(query), args...)
+}
+
+// queryRow runs the `query` (with `?` placeholders) on the database, returning its first row.
+func (api *api) queryRow(query string, args ...any) *sql.Row {
+	return api.conn().QueryRow(api.storage.rebind(query), args...)
+}
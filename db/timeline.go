@@ -0,0 +1,166 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package db provides database functionality for Argus to keep track of versions found/deployed/approved.
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/history"
+	"github.com/release-argus/Argus/util"
+)
+
+// recordVersionEvents adds a VersionEvent for each version the status `message` changes.
+func (api *api) recordVersionEvents(message dbtype.Message) error {
+	// Versions before the message.
+	var latestVersion, deployedVersion, approvedVersion string
+	err := api.queryRow(`
+		SELECT
+			latest_version,
+			deployed_version,
+			approved_version
+		FROM status
+		WHERE id = ?;`,
+		message.ServiceID).Scan(&latestVersion, &deployedVersion, &approvedVersion)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("recordVersionEvents: %q, %w",
+			message.ServiceID, err)
+	}
+
+	var events []history.VersionEvent
+	for _, cell := range message.Cells {
+		event := history.VersionEvent{Version: cell.Value}
+		switch cell.Column {
+		case "latest_version":
+			if cell.Value == latestVersion {
+				continue
+			}
+			event.Type = history.EventSeen
+		case "approved_version":
+			if cell.Value == approvedVersion {
+				continue
+			}
+			event.Type = history.EventApproved
+			if skipped, ok := strings.CutPrefix(cell.Value, "SKIP_"); ok {
+				event.Type = history.EventSkipped
+				event.Version = skipped
+			}
+			event.Actor = message.Actor
+		case "deployed_version":
+			if cell.Value == deployedVersion {
+				continue
+			}
+			event.Type = history.EventDeployed
+		default:
+			continue
+		}
+		// Cleared versions aren't events.
+		if event.Version == "" {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	now := time.Now().UTC()
+	for i, event := range events {
+		id := util.RandAlphaNumericLower(20)
+		// Offset the events of the message by a nanosecond each to keep them in order.
+		eventTime := now.Add(time.Duration(i)).Format(history.TimeFormat)
+		sqlStmt := `
+			INSERT INTO version_event (id, time, service_id, type, version, actor)
+			VALUES (?, ?, ?, ?, ?, ?);`
+		params := []any{id, eventTime, message.ServiceID, event.Type, event.Version, event.Actor}
+		if jLog.IsLevel("DEBUG") {
+			jLog.Debug(
+				fmt.Sprintf("%s, %v", sqlStmt, params),
+				logFrom, true)
+		}
+		if _, err := api.exec(sqlStmt, params...); err != nil {
+			return fmt.Errorf("recordVersionEvents: %q %v, %w",
+				sqlStmt, params, err)
+		}
+	}
+	return nil
+}
+
+// renameVersionEvents moves the VersionEvents of the `oldID` Service to `newID`.
+func (api *api) renameVersionEvents(oldID, newID string) error {
+	sqlStmt := "UPDATE version_event SET service_id = ? WHERE service_id = ?"
+	if _, err := api.exec(sqlStmt, newID, oldID); err != nil {
+		return fmt.Errorf("renameVersionEvents: %q with %q -> %q, %w",
+			sqlStmt, oldID, newID, err)
+	}
+	return nil
+}
+
+// deleteVersionEvents removes the VersionEvents of the `serviceID` Service.
+func (api *api) deleteVersionEvents(serviceID string) error {
+	sqlStmt := "DELETE FROM version_event WHERE service_id = ?"
+	if _, err := api.exec(sqlStmt, serviceID); err != nil {
+		return fmt.Errorf("deleteVersionEvents: %q with %q, %w",
+			sqlStmt, serviceID, err)
+	}
+	return nil
+}
+
+// queryVersionEvents returns the VersionEvents of the `serviceID` Service, oldest first.
+func (api *api) queryVersionEvents(serviceID string) ([]history.VersionEvent, error) {
+	rows, err := api.query(`
+		SELECT
+			id,
+			time,
+			service_id,
+			type,
+			version,
+			actor
+		FROM version_event
+		WHERE service_id = ?
+		ORDER BY time, id;`,
+		serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("queryVersionEvents: %w", err)
+	}
+	defer rows.Close()
+
+	events := []history.VersionEvent{}
+	for rows.Next() {
+		var (
+			event   history.VersionEvent
+			timeStr string
+		)
+		if err := rows.Scan(
+			&event.ID,
+			&timeStr,
+			&event.ServiceID,
+			&event.Type,
+			&event.Version,
+			&event.Actor,
+		); err != nil {
+			return nil, fmt.Errorf("queryVersionEvents row: %w", err)
+		}
+		event.Time, _ = time.Parse(history.TimeFormat, timeStr)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("queryVersionEvents: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,132 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package db
+
+import (
+	"fmt"
+	"testing"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+)
+
+func TestAPI_recordVersionEvents(t *testing.T) {
+	// GIVEN a DB with a status row.
+	tAPI := testAPI("TestAPI_recordVersionEvents", "db")
+	t.Cleanup(func() { dbCleanup(tAPI) })
+	tAPI.initialise()
+	tAPI.handleMessage(dbtype.Message{
+		ServiceID: "svc",
+		Cells: []dbtype.Cell{
+			{Column: "latest_version", Value: "1.0.0"},
+			{Column: "deployed_version", Value: "1.0.0"}}})
+
+	// WHEN status messages change its versions.
+	for _, message := range []dbtype.Message{
+		// unchanged.
+		{ServiceID: "svc", Cells: []dbtype.Cell{
+			{Column: "latest_version", Value: "1.0.0"}}},
+		// new release.
+		{ServiceID: "svc", Cells: []dbtype.Cell{
+			{Column: "latest_version", Value: "1.1.0"},
+			{Column: "latest_version_timestamp", Value: "2025-01-01T00:00:00Z"}}},
+		{ServiceID: "svc", Cells: []dbtype.Cell{
+			{Column: "approved_version", Value: "SKIP_1.1.0"}},
+			Actor: "bob"},
+		{ServiceID: "svc", Cells: []dbtype.Cell{
+			{Column: "latest_version", Value: "1.2.0"}}},
+		{ServiceID: "svc", Cells: []dbtype.Cell{
+			{Column: "approved_version", Value: "1.2.0"}},
+			Actor: "alice"},
+		{ServiceID: "svc", Cells: []dbtype.Cell{
+			{Column: "deployed_version", Value: "1.2.0"}}},
+		// cleared.
+		{ServiceID: "svc", Cells: []dbtype.Cell{
+			{Column: "approved_version", Value: ""}}},
+	} {
+		tAPI.handleMessage(message)
+	}
+
+	// THEN a VersionEvent is recorded for each change, in order.
+	events, err := tAPI.queryVersionEvents("svc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v",
+			err)
+	}
+	want := []string{
+		"seen 1.0.0 ",
+		"deployed 1.0.0 ",
+		"seen 1.1.0 ",
+		"skipped 1.1.0 bob",
+		"seen 1.2.0 ",
+		"approved 1.2.0 alice",
+		"deployed 1.2.0 "}
+	got := make([]string, len(events))
+	for i, event := range events {
+		got[i] = fmt.Sprintf("%s %s %s", event.Type, event.Version, event.Actor)
+		if event.ServiceID != "svc" || len(event.ID) != 20 || event.Time.IsZero() {
+			t.Errorf("unexpected event: %+v",
+				event)
+		}
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("events\nwant: %q\ngot:  %q",
+			want, got)
+	}
+
+	// WHEN the Service is renamed.
+	tAPI.handleMessage(dbtype.Message{
+		ServiceID: "svc",
+		Cells: []dbtype.Cell{
+			{Column: "id", Value: "renamed"}}})
+
+	// THEN its VersionEvents move with it.
+	if events, _ = tAPI.queryVersionEvents("svc"); len(events) != 0 {
+		t.Errorf("want no events for the old ID, got %d",
+			len(events))
+	}
+	if events, _ = tAPI.queryVersionEvents("renamed"); len(events) != len(want) {
+		t.Errorf("want %d events for the new ID, got %d",
+			len(want), len(events))
+	}
+
+	// WHEN the Service is deleted.
+	tAPI.handleMessage(dbtype.Message{
+		ServiceID: "renamed",
+		Delete:    true})
+
+	// THEN its VersionEvents are removed.
+	if events, _ = tAPI.queryVersionEvents("renamed"); len(events) != 0 {
+		t.Errorf("want no events after delete, got %d",
+			len(events))
+	}
+}
+
+func TestAPI_queryVersionEvents_None(t *testing.T) {
+	// GIVEN a DB without any VersionEvents.
+	tAPI := testAPI("TestAPI_queryVersionEvents_None", "db")
+	t.Cleanup(func() { dbCleanup(tAPI) })
+	tAPI.initialise()
+
+	// WHEN queryVersionEvents is called.
+	events, err := tAPI.queryVersionEvents("unknown")
+
+	// THEN an empty (non-nil) slice is returned.
+	if err != nil || events == nil || len(events) != 0 {
+		t.Errorf("want empty events, got %v (err=%v)",
+			events, err)
+	}
+}
@@ -23,8 +23,13 @@ import (
 )
 
 type api struct {
-	config *config.Config
-	db     *sql.DB
+	config   *config.Config
+	db       *sql.DB
+	tx       *sql.Tx       // Transaction the queries run in (only set on the copy of the api writing a batch).
+	storage  storage       // Backend of the db.
+	shutdown chan struct{} // Closed to flush the messages sent and stop the handler.
+	stopped  chan struct{} // Closed once the handler has stopped.
+	tasks    chan func()   // Tasks for the handler to run between writes (see exclusive).
 }
 
 var (
@@ -15,13 +15,37 @@
 // Package types provides types for the Database.
 package types
 
+import "time"
+
 // Message to be used in the channel for messages to the Database.
 //
 //	e.g. update deployed_version/latest_version_timestamp.
 type Message struct {
-	ServiceID string
+	Table     string // Table of the row (default: status).
+	ServiceID string // ID of the row (the Service ID for the status table).
 	Delete    bool
 	Cells     []Cell
+	Actor     string // Who made the change (recorded in the version history of the status table).
+}
+
+// Tables other than status.
+const (
+	TableNotifyDigest = "notify_digest" // Pending digest notifications, keyed by the DigestKey of the Notify.
+	TableOutbox       = "outbox"        // Pending deliveries, keyed by Entry ID.
+	TableHistory      = "history"       // Audit trail of notify/webhook/command attempts, keyed by Record ID.
+	TableApproval     = "approval"      // Approvals given towards the approval policy, keyed by Service ID.
+	TableVersionEvent = "version_event" // Version history of each Service (seen/approved/skipped/deployed), keyed by Event ID.
+)
+
+// Orphan is the status of a Service no longer in the config, archived until restored or its retention passes.
+type Orphan struct {
+	ID                       string
+	LatestVersion            string
+	LatestVersionTimestamp   string
+	DeployedVersion          string
+	DeployedVersionTimestamp string
+	ApprovedVersion          string
+	Archived                 time.Time // When it was found to no longer be in the config.
 }
 
 // Cell to be modified in the Database.
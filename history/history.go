@@ -0,0 +1,147 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package history provides the audit trail of notify/webhook/command attempts, and the version timeline of each Service.
+package history
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/util"
+)
+
+// Kinds of Record.
+const (
+	KindNotify  = "notify"
+	KindWebHook = "webhook"
+	KindCommand = "command"
+)
+
+const (
+	DefaultLimit = 50  // Default number of Records per page.
+	MaxLimit     = 500 // Maximum number of Records per page.
+)
+
+// TimeFormat of the Record times in the database (fixed width, so they sort as strings).
+const TimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// ErrUnavailable is returned when the history can't be queried (no database).
+var ErrUnavailable = errors.New("history unavailable")
+
+var (
+	mutex           sync.RWMutex
+	databaseChannel *chan dbtype.Message // Channel to write the Records to the database.
+	querier         Querier              // Function to query the Records in the database.
+)
+
+// Record of a notify/webhook/command attempt.
+type Record struct {
+	ID             string        `json:"id"`                        // Unique ID of the Record.
+	Time           time.Time     `json:"time"`                      // Time the attempt started.
+	Kind           string        `json:"kind"`                      // KindNotify/KindWebHook/KindCommand.
+	ServiceID      string        `json:"service_id"`                // ID of the Service.
+	Target         string        `json:"target"`                    // ID of the Notify/WebHook, or index of the Command.
+	Version        string        `json:"version,omitempty"`         // Latest version at the time of the attempt.
+	Payload        string        `json:"payload,omitempty"`         // Rendered payload, with secrets redacted.
+	ResponseStatus int           `json:"response_status,omitempty"` // HTTP status code/exit code.
+	Duration       time.Duration `json:"duration"`                  // Duration of the attempt.
+	Error          string        `json:"error,omitempty"`           // Error of the attempt.
+}
+
+// Filter for a history Query.
+type Filter struct {
+	ServiceID string    // Only Records of this Service.
+	Kind      string    // Only Records of this Kind.
+	Target    string    // Only Records of this Target.
+	Success   *bool     // Only successful/failed Records.
+	Since     time.Time // Only Records at/after this time.
+	Until     time.Time // Only Records before this time.
+	Limit     int       // Maximum Records to return.
+	Offset    int       // Records to skip.
+}
+
+// Querier returns the Records matching the Filter (newest first), and the total number of matches.
+type Querier func(filter Filter) ([]Record, int, error)
+
+// SetDatabaseChannel sets the channel the Records are written through.
+//
+// (Recording is disabled when nil).
+func SetDatabaseChannel(channel *chan dbtype.Message) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	databaseChannel = channel
+}
+
+// SetQuerier sets the function to query the Records with.
+func SetQuerier(q Querier) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	querier = q
+}
+
+// Add the `record` to the history, setting its ID and Duration from its Time.
+func Add(record Record, err error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if databaseChannel == nil {
+		return
+	}
+
+	record.ID = util.RandAlphaNumericLower(20)
+	record.Duration = time.Since(record.Time)
+	record.Time = record.Time.UTC()
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	*databaseChannel <- dbtype.Message{
+		Table:     dbtype.TableHistory,
+		ServiceID: record.ID,
+		Cells: []dbtype.Cell{
+			{Column: "time", Value: record.Time.Format(TimeFormat)},
+			{Column: "kind", Value: record.Kind},
+			{Column: "service_id", Value: record.ServiceID},
+			{Column: "target", Value: record.Target},
+			{Column: "version", Value: record.Version},
+			{Column: "payload", Value: record.Payload},
+			{Column: "response_status", Value: strconv.Itoa(record.ResponseStatus)},
+			{Column: "duration_ms", Value: strconv.FormatInt(record.Duration.Milliseconds(), 10)},
+			{Column: "error", Value: record.Error}}}
+}
+
+// Query the Records matching the `filter`.
+func Query(filter Filter) ([]Record, int, error) {
+	mutex.RLock()
+	q := querier
+	mutex.RUnlock()
+	if q == nil {
+		return nil, 0, ErrUnavailable
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultLimit
+	} else if filter.Limit > MaxLimit {
+		filter.Limit = MaxLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return q(filter)
+}
@@ -0,0 +1,146 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//go:build unit
+
+package history
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+)
+
+func TestAdd(t *testing.T) {
+	// GIVEN a DatabaseChannel and a Record of an attempt.
+	channel := make(chan dbtype.Message, 4)
+	SetDatabaseChannel(&channel)
+	t.Cleanup(func() { SetDatabaseChannel(nil) })
+	start := time.Now().Add(-1500 * time.Millisecond)
+	record := Record{
+		Time:           start,
+		Kind:           KindWebHook,
+		ServiceID:      "svc",
+		Target:         "wh",
+		Version:        "1.2.3",
+		Payload:        "{}",
+		ResponseStatus: 500}
+
+	// WHEN Add is called with an error.
+	Add(record, errors.New("fail"))
+
+	// THEN the Record is sent to the database.
+	if got := len(channel); got != 1 {
+		t.Fatalf("want 1 database message, got %d",
+			got)
+	}
+	message := <-channel
+	if message.Table != dbtype.TableHistory || message.Delete || len(message.ServiceID) != 20 {
+		t.Errorf("unexpected message: %+v",
+			message)
+	}
+	want := map[string]string{
+		"time":            start.UTC().Format(TimeFormat),
+		"kind":            KindWebHook,
+		"service_id":      "svc",
+		"target":          "wh",
+		"version":         "1.2.3",
+		"payload":         "{}",
+		"response_status": "500",
+		"error":           "fail"}
+	for _, cell := range message.Cells {
+		if wantValue, ok := want[cell.Column]; ok && cell.Value != wantValue {
+			t.Errorf("%s\nwant: %q\ngot:  %q",
+				cell.Column, wantValue, cell.Value)
+		}
+		// AND the duration is measured from the start.
+		if cell.Column == "duration_ms" && (cell.Value < "1500" || len(cell.Value) != 4) {
+			t.Errorf("duration_ms\nwant: ~1500\ngot:  %q",
+				cell.Value)
+		}
+	}
+}
+
+func TestAdd_Disabled(t *testing.T) {
+	// GIVEN no DatabaseChannel.
+	SetDatabaseChannel(nil)
+
+	// WHEN Add is called.
+	// THEN it doesn't block/panic.
+	Add(Record{Time: time.Now()}, nil)
+}
+
+func TestQuery(t *testing.T) {
+	// GIVEN a Querier and a Filter.
+	tests := map[string]struct {
+		nilQuerier            bool
+		limit, offset         int
+		wantLimit, wantOffset int
+		wantUnavailable       bool
+	}{
+		"no querier": {
+			nilQuerier:      true,
+			wantUnavailable: true},
+		"default limit": {
+			wantLimit: DefaultLimit},
+		"limit within range": {
+			limit: 10, offset: 20,
+			wantLimit: 10, wantOffset: 20},
+		"limit above max": {
+			limit:     MaxLimit + 1,
+			wantLimit: MaxLimit},
+		"negative offset": {
+			limit: 5, offset: -1,
+			wantLimit: 5},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// t.Parallel() - Cannot run in parallel since we're using a global Querier.
+
+			var got Filter
+			if tc.nilQuerier {
+				SetQuerier(nil)
+			} else {
+				SetQuerier(func(filter Filter) ([]Record, int, error) {
+					got = filter
+					return nil, 0, nil
+				})
+			}
+			t.Cleanup(func() { SetQuerier(nil) })
+
+			// WHEN Query is called.
+			_, _, err := Query(Filter{Limit: tc.limit, Offset: tc.offset})
+
+			// THEN the error is as expected.
+			if tc.wantUnavailable {
+				if !errors.Is(err, ErrUnavailable) {
+					t.Fatalf("want %v, got %v",
+						ErrUnavailable, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v",
+					err)
+			}
+			// AND the Filter passed to the Querier is clamped.
+			if got.Limit != tc.wantLimit || got.Offset != tc.wantOffset {
+				t.Errorf("want limit=%d, offset=%d\ngot:  limit=%d, offset=%d",
+					tc.wantLimit, tc.wantOffset, got.Limit, got.Offset)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package history provides the audit trail of notify/webhook/command attempts, and the version timeline of each Service.
+package history
+
+import (
+	"sync"
+	"time"
+)
+
+// Types of VersionEvent.
+const (
+	EventSeen     = "seen"     // Version found as the LatestVersion.
+	EventApproved = "approved" // Version approved.
+	EventSkipped  = "skipped"  // Version skipped.
+	EventDeployed = "deployed" // Version became the DeployedVersion.
+)
+
+var (
+	timelineMutex   sync.RWMutex
+	timelineQuerier TimelineQuerier // Function to query the VersionEvents in the database.
+)
+
+// VersionEvent is a change to the latest/approved/deployed version of a Service.
+type VersionEvent struct {
+	ID        string    `json:"id"`              // Unique ID of the VersionEvent.
+	Time      time.Time `json:"time"`            // Time of the change.
+	ServiceID string    `json:"service_id"`      // ID of the Service.
+	Type      string    `json:"type"`            // EventSeen/EventApproved/EventSkipped/EventDeployed.
+	Version   string    `json:"version"`         // Version the change is for.
+	Actor     string    `json:"actor,omitempty"` // Who approved/skipped the version.
+}
+
+// VersionTimeline is when a version of a Service was first seen/approved/skipped/deployed.
+type VersionTimeline struct {
+	Version    string     // Version of the Service.
+	Seen       *time.Time // First time the version was the LatestVersion.
+	Approved   *time.Time // First time the version was approved.
+	ApprovedBy string     // Who approved the version.
+	Skipped    *time.Time // First time the version was skipped.
+	SkippedBy  string     // Who skipped the version.
+	Deployed   *time.Time // First time the version was deployed.
+}
+
+// LeadTime returns the time from the version being seen to it being deployed
+// (false if it hasn't been both).
+func (t *VersionTimeline) LeadTime() (time.Duration, bool) {
+	if t.Seen == nil || t.Deployed == nil {
+		return 0, false
+	}
+	return t.Deployed.Sub(*t.Seen), true
+}
+
+// TimelineQuerier returns the VersionEvents of a Service, oldest first.
+type TimelineQuerier func(serviceID string) ([]VersionEvent, error)
+
+// SetTimelineQuerier sets the function used to query the VersionEvents.
+func SetTimelineQuerier(q TimelineQuerier) {
+	timelineMutex.Lock()
+	defer timelineMutex.Unlock()
+
+	timelineQuerier = q
+}
+
+// Events returns the VersionEvents of the `serviceID` Service, oldest first.
+func Events(serviceID string) ([]VersionEvent, error) {
+	timelineMutex.RLock()
+	q := timelineQuerier
+	timelineMutex.RUnlock()
+	if q == nil {
+		return nil, ErrUnavailable
+	}
+
+	return q(serviceID)
+}
+
+// Timeline returns the VersionTimeline of each version in the `events` (oldest first),
+// in the order the versions were first mentioned.
+func Timeline(events []VersionEvent) []VersionTimeline {
+	var timeline []VersionTimeline
+	index := make(map[string]int, len(events))
+	for i := range events {
+		event := &events[i]
+		at, ok := index[event.Version]
+		if !ok {
+			at = len(timeline)
+			index[event.Version] = at
+			timeline = append(timeline, VersionTimeline{Version: event.Version})
+		}
+
+		version := &timeline[at]
+		eventTime := event.Time
+		switch event.Type {
+		case EventSeen:
+			if version.Seen == nil {
+				version.Seen = &eventTime
+			}
+		case EventApproved:
+			if version.Approved == nil {
+				version.Approved = &eventTime
+				version.ApprovedBy = event.Actor
+			}
+		case EventSkipped:
+			if version.Skipped == nil {
+				version.Skipped = &eventTime
+				version.SkippedBy = event.Actor
+			}
+		case EventDeployed:
+			if version.Deployed == nil {
+				version.Deployed = &eventTime
+			}
+		}
+	}
+
+	return timeline
+}
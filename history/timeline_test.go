@@ -0,0 +1,104 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package history
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEvents(t *testing.T) {
+	// GIVEN no TimelineQuerier.
+	SetTimelineQuerier(nil)
+	t.Cleanup(func() { SetTimelineQuerier(nil) })
+
+	// WHEN Events is called.
+	_, err := Events("svc")
+
+	// THEN ErrUnavailable is returned.
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("want %v, got %v",
+			ErrUnavailable, err)
+	}
+
+	// GIVEN a TimelineQuerier.
+	var got string
+	SetTimelineQuerier(func(serviceID string) ([]VersionEvent, error) {
+		got = serviceID
+		return []VersionEvent{{ServiceID: serviceID}}, nil
+	})
+
+	// WHEN Events is called.
+	events, err := Events("svc")
+
+	// THEN the VersionEvents of the Service are returned.
+	if err != nil || len(events) != 1 || got != "svc" {
+		t.Errorf("want 1 event for %q, got %v (queried %q, err=%v)",
+			"svc", events, got, err)
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	// GIVEN VersionEvents of a Service.
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return start.Add(time.Duration(hours) * time.Hour) }
+	events := []VersionEvent{
+		{Time: at(0), Type: EventSeen, Version: "1.0.0"},
+		{Time: at(1), Type: EventSeen, Version: "1.1.0"},
+		{Time: at(2), Type: EventSkipped, Version: "1.1.0", Actor: "bob"},
+		{Time: at(3), Type: EventApproved, Version: "1.0.0", Actor: "alice"},
+		{Time: at(4), Type: EventApproved, Version: "1.0.0", Actor: "carol"},
+		{Time: at(5), Type: EventDeployed, Version: "1.0.0"},
+		{Time: at(6), Type: EventDeployed, Version: "0.9.0"},
+	}
+
+	// WHEN Timeline is called.
+	timeline := Timeline(events)
+
+	// THEN each version has the first time of each event, in the order first mentioned.
+	if len(timeline) != 3 {
+		t.Fatalf("want 3 versions, got %d",
+			len(timeline))
+	}
+	v1 := timeline[0]
+	if v1.Version != "1.0.0" ||
+		!v1.Seen.Equal(at(0)) ||
+		!v1.Approved.Equal(at(3)) || v1.ApprovedBy != "alice" ||
+		v1.Skipped != nil ||
+		!v1.Deployed.Equal(at(5)) {
+		t.Errorf("unexpected 1.0.0 timeline: %+v",
+			v1)
+	}
+	// AND the lead time is from seen to deployed.
+	if leadTime, ok := v1.LeadTime(); !ok || leadTime != 5*time.Hour {
+		t.Errorf("1.0.0 lead time\nwant: %s\ngot:  %s (%t)",
+			5*time.Hour, leadTime, ok)
+	}
+	v2 := timeline[1]
+	if v2.Version != "1.1.0" || !v2.Skipped.Equal(at(2)) || v2.SkippedBy != "bob" || v2.Deployed != nil {
+		t.Errorf("unexpected 1.1.0 timeline: %+v",
+			v2)
+	}
+	// AND versions not both seen and deployed have no lead time.
+	for _, version := range timeline[1:] {
+		if _, ok := version.LeadTime(); ok {
+			t.Errorf("%s: want no lead time",
+				version.Version)
+		}
+	}
+}
@@ -0,0 +1,387 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shoutrrr provides the shoutrrr notification service to services.
+package shoutrrr
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/util"
+)
+
+const (
+	defaultDigestTitle   = "Argus - {{ count }} new releases"
+	defaultDigestMessage = "{% for service in services %}" +
+		"{{ service.service_name | default:service.service_id }} - {{ service.version }}\n" +
+		"{% endfor %}"
+)
+
+var (
+	digestsMutex sync.Mutex
+	digests      = map[string]*digest{} // Pending digests, keyed by DigestKey.
+	digestCtx    = context.Background() // Context to send the digests with.
+	persistMutex sync.Mutex             // Lock for the persistQueue.
+	persistQueue []persistMessage       // Database messages waiting to be sent, in order.
+	flushMutex   sync.Mutex             // Lock to send the persistQueue in order.
+)
+
+// persistMessage is a database message waiting to be sent.
+type persistMessage struct {
+	channel *chan dbtype.Message // Channel to send the message to.
+	message dbtype.Message       // Message to send.
+}
+
+// DigestEntry is a new release notification waiting in a digest.
+type DigestEntry struct {
+	ServiceInfo util.ServiceInfo `json:"service_info"` // Context of the release.
+	Queued      time.Time        `json:"queued"`       // Time the entry was added to the digest.
+}
+
+// digest of new release notifications to send as one message.
+type digest struct {
+	key      string                 // DigestKey of the Shoutrrr.
+	shoutrrr *Shoutrrr              // The Shoutrrr to send the digest with.
+	entries  map[string]DigestEntry // Entries, keyed by Service ID.
+	timer    *time.Timer            // Timer to send the digest.
+}
+
+// GetDigestWindow returns the duration to collect notifications for before sending them as one digest.
+func (s *Shoutrrr) GetDigestWindow() time.Duration {
+	window, _ := time.ParseDuration(s.GetOption("digest_window"))
+	return window
+}
+
+// GetDigestSchedule returns the times of day (HH:MM) to send the digest at.
+func (s *Shoutrrr) GetDigestSchedule() []string {
+	schedule := s.GetOption("digest_schedule")
+	if schedule == "" {
+		return nil
+	}
+
+	times := strings.Split(schedule, ",")
+	for i := range times {
+		times[i] = strings.TrimSpace(times[i])
+	}
+	return times
+}
+
+// DigestKey returns the key of the digest of this Shoutrrr - its ID, and a hash of its resolved type, URL and params.
+//
+// (Service notifiers that share an ID, but send to different targets, don't share a digest).
+func (s *Shoutrrr) DigestKey() string {
+	params := make(map[string]struct{}, len(s.Params))
+	for _, level := range []map[string]string{s.Params, s.Main.Params, s.Defaults.Params, s.HardDefaults.Params} {
+		for key := range level {
+			params[key] = struct{}{}
+		}
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00%s", s.GetType(), s.BuildURL())
+	for _, key := range util.SortedKeys(params) {
+		fmt.Fprintf(hash, "\x00%s=%s", key, s.GetParam(key))
+	}
+	return s.ID + "#" + hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// digestEnabled returns whether new release notifications are collected into a digest.
+func (s *Shoutrrr) digestEnabled() bool {
+	return s.GetDigestWindow() > 0 || len(s.GetDigestSchedule()) != 0
+}
+
+// DigestTitle of the Shoutrrr after the contexts are applied and template evaluated.
+func (s *Shoutrrr) DigestTitle(contexts []util.ServiceInfo) string {
+	return util.TemplateDigest(
+		util.FirstNonDefault(s.GetOption("digest_title"), defaultDigestTitle),
+		contexts)
+}
+
+// DigestMessage of the Shoutrrr after the contexts are applied and template evaluated.
+func (s *Shoutrrr) DigestMessage(contexts []util.ServiceInfo) string {
+	return util.TemplateDigest(
+		util.FirstNonDefault(s.GetOption("digest_message"), defaultDigestMessage),
+		contexts)
+}
+
+// nextDigestSend returns the time the digest should be sent, with `queued` being the time of the first entry.
+func (s *Shoutrrr) nextDigestSend(queued time.Time) time.Time {
+	schedule := s.GetDigestSchedule()
+	if len(schedule) == 0 {
+		return queued.Add(s.GetDigestWindow())
+	}
+
+	// Earliest scheduled time after `queued`.
+	var next time.Time
+	for _, timeOfDay := range schedule {
+		t, err := time.ParseInLocation("15:04", timeOfDay, time.Local)
+		if err != nil {
+			continue
+		}
+		at := time.Date(queued.Year(), queued.Month(), queued.Day(),
+			t.Hour(), t.Minute(), 0, 0, time.Local)
+		if !at.After(queued) {
+			at = at.AddDate(0, 0, 1)
+		}
+		if next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+	return next
+}
+
+// addToDigest queues the `serviceInfo` for the next digest of this Shoutrrr.
+func (s *Shoutrrr) addToDigest(serviceInfo util.ServiceInfo) {
+	s.queueDigest(
+		[]DigestEntry{{
+			ServiceInfo: serviceInfo,
+			Queued:      time.Now().UTC()}},
+		true)
+	jLog.Info(
+		fmt.Sprintf("Queued %q for the next %s digest", serviceInfo.LatestVersion, s.ID),
+		util.LogFrom{Primary: s.ID, Secondary: serviceInfo.ID}, true)
+}
+
+// RestoreDigest queues the `entries` for the next digest of this Shoutrrr.
+//
+// (Used to restore the pending digest from the database).
+func (s *Shoutrrr) RestoreDigest(entries []DigestEntry) {
+	s.queueDigest(entries, false)
+}
+
+// SetDigestContext sets the context to send the digests with (done on shutdown).
+//
+// A digest isn't sent with the context of a Service, as that Service may be deleted/reloaded
+// before the digest it queued to is sent.
+func SetDigestContext(ctx context.Context) {
+	digestsMutex.Lock()
+	defer digestsMutex.Unlock()
+
+	digestCtx = ctx
+}
+
+// ClearDigests drops the pending digests, stopping their timers,
+// but leaving them in the database (e.g. to restore those of an import).
+func ClearDigests() {
+	digestsMutex.Lock()
+	defer digestsMutex.Unlock()
+
+	for key, d := range digests {
+		if d.timer != nil {
+			d.timer.Stop()
+		}
+		delete(digests, key)
+	}
+}
+
+// queueDigest adds the `entries` to the digest of this Shoutrrr, creating it if it doesn't exist,
+// and saves the digest to the database if `save`.
+func (s *Shoutrrr) queueDigest(entries []DigestEntry, save bool) {
+	if len(entries) == 0 {
+		return
+	}
+
+	digestsMutex.Lock()
+	key := s.DigestKey()
+	d := digests[key]
+	if d == nil {
+		d = &digest{key: key, entries: make(map[string]DigestEntry, len(entries))}
+		digests[key] = d
+	}
+	d.shoutrrr = s
+	for _, entry := range entries {
+		// Newer releases of a Service replace the older ones.
+		d.entries[entry.ServiceInfo.ID] = entry
+	}
+
+	if d.timer == nil {
+		sendAt := s.nextDigestSend(d.oldestEntry())
+		d.timer = time.AfterFunc(time.Until(sendAt), func() {
+			//#nosec G104 -- Errors are logged to CLI
+			//nolint:errcheck // ^
+			s.sendDigest()
+		})
+	}
+	if save {
+		d.save(s)
+	}
+	digestsMutex.Unlock()
+	flushDigests()
+}
+
+// oldestEntry returns the time the oldest entry of the digest was queued.
+func (d *digest) oldestEntry() time.Time {
+	var oldest time.Time
+	for _, entry := range d.entries {
+		if oldest.IsZero() || entry.Queued.Before(oldest) {
+			oldest = entry.Queued
+		}
+	}
+	return oldest
+}
+
+// sortedEntries returns the entries of the digest in the order they were queued.
+func (d *digest) sortedEntries() []DigestEntry {
+	entries := make([]DigestEntry, 0, len(d.entries))
+	for _, entry := range d.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Queued.Equal(entries[j].Queued) {
+			return entries[i].ServiceInfo.ID < entries[j].ServiceInfo.ID
+		}
+		return entries[i].Queued.Before(entries[j].Queued)
+	})
+	return entries
+}
+
+// save the pending entries of the digest to the database (or remove them if there are none),
+// queued until the next flushDigests.
+func (d *digest) save(s *Shoutrrr) {
+	if s.ServiceStatus == nil || s.ServiceStatus.DatabaseChannel == nil {
+		return
+	}
+
+	message := dbtype.Message{
+		Table:     dbtype.TableNotifyDigest,
+		ServiceID: d.key}
+	if len(d.entries) == 0 {
+		message.Delete = true
+	} else {
+		entries, _ := json.Marshal(d.sortedEntries())
+		message.Cells = []dbtype.Cell{
+			{Column: "entries", Value: string(entries)}}
+	}
+
+	persistMutex.Lock()
+	defer persistMutex.Unlock()
+	persistQueue = append(persistQueue, persistMessage{
+		channel: s.ServiceStatus.DatabaseChannel,
+		message: message})
+}
+
+// flushDigests sends the queued messages to the database, in order.
+//
+// (Called without digestsMutex held, as the DatabaseChannel may block).
+func flushDigests() {
+	flushMutex.Lock()
+	defer flushMutex.Unlock()
+
+	for {
+		persistMutex.Lock()
+		if len(persistQueue) == 0 {
+			persistMutex.Unlock()
+			return
+		}
+		next := persistQueue[0]
+		persistQueue = persistQueue[1:]
+		persistMutex.Unlock()
+
+		*next.channel <- next.message
+	}
+}
+
+// sendDigest sends the pending digest of this Shoutrrr as one message.
+//
+// The persisted digest is only removed once the send succeeds,
+// and on failure, the entries are queued again for the next digest
+// (unless shutting down, when they're left in the database for the next start).
+func (s *Shoutrrr) sendDigest() error {
+	key := s.DigestKey()
+	digestsMutex.Lock()
+	d := digests[key]
+	if d == nil || len(d.entries) == 0 {
+		digestsMutex.Unlock()
+		return nil
+	}
+	delete(digests, key)
+	d.timer = nil
+	entries := d.sortedEntries()
+	ctx := digestCtx
+	digestsMutex.Unlock()
+
+	contexts := make([]util.ServiceInfo, len(entries))
+	for i := range entries {
+		contexts[i] = entries[i].ServiceInfo
+	}
+
+	sender := d.shoutrrr
+	jLog.Info(
+		fmt.Sprintf("Sending digest of %d releases", len(contexts)),
+		util.LogFrom{Primary: sender.ID}, true)
+	err := sender.Send(
+		ctx,
+		sender.DigestTitle(contexts),
+		sender.DigestMessage(contexts),
+		contexts[0],
+		false,
+		false)
+	if err != nil {
+		if ctx.Err() == nil {
+			sender.requeueDigest(entries)
+		}
+		return err //nolint:wrapcheck
+	}
+
+	digestsMutex.Lock()
+	// Nothing queued since, so remove the persisted digest.
+	if digests[key] == nil {
+		d.entries = nil
+		d.save(sender)
+	}
+	digestsMutex.Unlock()
+	flushDigests()
+	return nil
+}
+
+// requeueDigest queues the `entries` of a digest that failed to send for the next digest
+// (keeping any newer releases queued since), and saves the digest to the database.
+func (s *Shoutrrr) requeueDigest(entries []DigestEntry) {
+	digestsMutex.Lock()
+
+	key := s.DigestKey()
+	d := digests[key]
+	if d == nil {
+		d = &digest{key: key, entries: make(map[string]DigestEntry, len(entries))}
+		digests[key] = d
+	}
+	d.shoutrrr = s
+	for _, entry := range entries {
+		if _, newer := d.entries[entry.ServiceInfo.ID]; !newer {
+			d.entries[entry.ServiceInfo.ID] = entry
+		}
+	}
+
+	// Retry at the next send time from now, rather than from the (past) oldest entry.
+	if d.timer == nil {
+		sendAt := s.nextDigestSend(time.Now())
+		d.timer = time.AfterFunc(time.Until(sendAt), func() {
+			//#nosec G104 -- Errors are logged to CLI
+			//nolint:errcheck // ^
+			s.sendDigest()
+		})
+	}
+	d.save(s)
+	digestsMutex.Unlock()
+	flushDigests()
+}
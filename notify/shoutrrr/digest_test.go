@@ -0,0 +1,477 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package shoutrrr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestShoutrrr_DigestEnabled(t *testing.T) {
+	// GIVEN a Shoutrrr with digest options.
+	tests := map[string]struct {
+		options map[string]string
+		want    bool
+	}{
+		"no digest options": {
+			options: map[string]string{},
+			want:    false},
+		"digest_window": {
+			options: map[string]string{
+				"digest_window": "1h"},
+			want: true},
+		"digest_window of 0": {
+			options: map[string]string{
+				"digest_window": "0s"},
+			want: false},
+		"digest_schedule": {
+			options: map[string]string{
+				"digest_schedule": "09:00"},
+			want: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			shoutrrr := testShoutrrr(false, false)
+			shoutrrr.Options = tc.options
+
+			// WHEN digestEnabled is called.
+			got := shoutrrr.digestEnabled()
+
+			// THEN the result is as expected.
+			if got != tc.want {
+				t.Errorf("want: %t\ngot:  %t",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestShoutrrr_GetDigestSchedule(t *testing.T) {
+	// GIVEN a Shoutrrr with a digest_schedule.
+	tests := map[string]struct {
+		schedule string
+		want     []string
+	}{
+		"empty": {
+			schedule: "",
+			want:     nil},
+		"single": {
+			schedule: "09:00",
+			want:     []string{"09:00"}},
+		"multiple with spaces": {
+			schedule: "09:00, 17:30",
+			want:     []string{"09:00", "17:30"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			shoutrrr := testShoutrrr(false, false)
+			shoutrrr.Options["digest_schedule"] = tc.schedule
+
+			// WHEN GetDigestSchedule is called.
+			got := shoutrrr.GetDigestSchedule()
+
+			// THEN the schedule is split as expected.
+			if len(got) != len(tc.want) {
+				t.Fatalf("want: %v\ngot:  %v",
+					tc.want, got)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("want: %v\ngot:  %v",
+						tc.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestShoutrrr_NextDigestSend(t *testing.T) {
+	queued := time.Date(2025, 1, 1, 12, 0, 0, 0, time.Local)
+	// GIVEN a Shoutrrr with a digest_window/digest_schedule.
+	tests := map[string]struct {
+		options map[string]string
+		want    time.Time
+	}{
+		"window": {
+			options: map[string]string{
+				"digest_window": "90m"},
+			want: queued.Add(90 * time.Minute)},
+		"schedule later today": {
+			options: map[string]string{
+				"digest_schedule": "18:00"},
+			want: time.Date(2025, 1, 1, 18, 0, 0, 0, time.Local)},
+		"schedule earlier today sends tomorrow": {
+			options: map[string]string{
+				"digest_schedule": "09:00"},
+			want: time.Date(2025, 1, 2, 9, 0, 0, 0, time.Local)},
+		"schedule at queued time sends tomorrow": {
+			options: map[string]string{
+				"digest_schedule": "12:00"},
+			want: time.Date(2025, 1, 2, 12, 0, 0, 0, time.Local)},
+		"earliest of multiple times": {
+			options: map[string]string{
+				"digest_schedule": "09:00,13:15,18:00"},
+			want: time.Date(2025, 1, 1, 13, 15, 0, 0, time.Local)},
+		"schedule takes precedence over window": {
+			options: map[string]string{
+				"digest_window":   "1m",
+				"digest_schedule": "18:00"},
+			want: time.Date(2025, 1, 1, 18, 0, 0, 0, time.Local)},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			shoutrrr := testShoutrrr(false, false)
+			shoutrrr.Options = tc.options
+
+			// WHEN nextDigestSend is called.
+			got := shoutrrr.nextDigestSend(queued)
+
+			// THEN the send time is as expected.
+			if !got.Equal(tc.want) {
+				t.Errorf("want: %s\ngot:  %s",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestShoutrrr_DigestTitleMessage(t *testing.T) {
+	contexts := []util.ServiceInfo{
+		{ID: "alpha", LatestVersion: "1.0.0"},
+		{ID: "beta", Name: "Beta", LatestVersion: "2.0.0"}}
+	// GIVEN a Shoutrrr with/without digest_title/digest_message.
+	tests := map[string]struct {
+		options                map[string]string
+		wantTitle, wantMessage string
+	}{
+		"defaults": {
+			options:     map[string]string{},
+			wantTitle:   "Argus - 2 new releases",
+			wantMessage: "alpha - 1.0.0\nBeta - 2.0.0\n"},
+		"custom": {
+			options: map[string]string{
+				"digest_title":   "{{ count }} updates",
+				"digest_message": "{% for s in services %}{{ s.service_id }}{% if not forloop.Last %},{% endif %}{% endfor %}"},
+			wantTitle:   "2 updates",
+			wantMessage: "alpha,beta"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			shoutrrr := testShoutrrr(false, false)
+			shoutrrr.Options = tc.options
+
+			// WHEN DigestTitle and DigestMessage are called.
+			gotTitle := shoutrrr.DigestTitle(contexts)
+			gotMessage := shoutrrr.DigestMessage(contexts)
+
+			// THEN the title is as expected.
+			if gotTitle != tc.wantTitle {
+				t.Errorf("title - want: %q\ngot:  %q",
+					tc.wantTitle, gotTitle)
+			}
+			// AND the message is as expected.
+			if gotMessage != tc.wantMessage {
+				t.Errorf("message - want: %q\ngot:  %q",
+					tc.wantMessage, gotMessage)
+			}
+		})
+	}
+}
+
+func TestShoutrrr_DigestKey(t *testing.T) {
+	// GIVEN two Shoutrrrs with the same ID.
+	tests := map[string]struct {
+		changeOther func(s *Shoutrrr)
+		wantSame    bool
+	}{
+		"same target": {
+			changeOther: func(s *Shoutrrr) {},
+			wantSame:    true,
+		},
+		"same target, different options": {
+			changeOther: func(s *Shoutrrr) { s.Options["digest_window"] = "1h" },
+			wantSame:    true,
+		},
+		"different url_fields": {
+			changeOther: func(s *Shoutrrr) { s.URLFields["host"] = "other.release-argus.io" },
+			wantSame:    false,
+		},
+		"different params": {
+			changeOther: func(s *Shoutrrr) { s.Params["title"] = "other" },
+			wantSame:    false,
+		},
+		"different params from main": {
+			changeOther: func(s *Shoutrrr) { s.Main.Params["priority"] = "5" },
+			wantSame:    false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			shoutrrr := testShoutrrr(false, false)
+			other := testShoutrrr(false, false)
+			tc.changeOther(other)
+
+			// WHEN DigestKey is called on both.
+			got := shoutrrr.DigestKey()
+			gotOther := other.DigestKey()
+
+			// THEN they share a digest only when they send to the same target.
+			if (got == gotOther) != tc.wantSame {
+				t.Errorf("want same=%t, got %q and %q",
+					tc.wantSame, got, gotOther)
+			}
+			// AND the key starts with the ID.
+			if !strings.HasPrefix(got, shoutrrr.ID+"#") {
+				t.Errorf("want key prefixed with %q, got %q",
+					shoutrrr.ID+"#", got)
+			}
+		})
+	}
+}
+
+func TestShoutrrr_QueueDigest(t *testing.T) {
+	// GIVEN a Shoutrrr with a digest_window and a DatabaseChannel.
+	shoutrrr := testShoutrrr(false, false)
+	shoutrrr.ID = "TestShoutrrr_QueueDigest"
+	shoutrrr.Options["digest_window"] = "1h"
+	databaseChannel := make(chan dbtype.Message, 4)
+	shoutrrr.ServiceStatus.DatabaseChannel = &databaseChannel
+	t.Cleanup(func() {
+		digestsMutex.Lock()
+		if d := digests[shoutrrr.DigestKey()]; d != nil && d.timer != nil {
+			d.timer.Stop()
+		}
+		delete(digests, shoutrrr.DigestKey())
+		digestsMutex.Unlock()
+	})
+
+	// WHEN entries are restored from the database.
+	queued := time.Now().UTC().Add(-time.Minute)
+	shoutrrr.RestoreDigest([]DigestEntry{
+		{ServiceInfo: util.ServiceInfo{ID: "alpha", LatestVersion: "1.0.0"}, Queued: queued}})
+	// THEN nothing is sent to the database.
+	if got := len(databaseChannel); got != 0 {
+		t.Fatalf("RestoreDigest sent %d messages to the database, want 0",
+			got)
+	}
+
+	// WHEN a newer release of the same Service is added, along with another Service.
+	shoutrrr.addToDigest(util.ServiceInfo{ID: "alpha", LatestVersion: "1.1.0"})
+	shoutrrr.addToDigest(util.ServiceInfo{ID: "beta", LatestVersion: "2.0.0"})
+
+	// THEN the digest holds the latest release of each Service.
+	digestsMutex.Lock()
+	d := digests[shoutrrr.DigestKey()]
+	entries := d.sortedEntries()
+	hasTimer := d.timer != nil
+	digestsMutex.Unlock()
+	if len(entries) != 2 ||
+		entries[0].ServiceInfo.LatestVersion != "1.1.0" ||
+		entries[1].ServiceInfo.LatestVersion != "2.0.0" {
+		t.Errorf("unexpected digest entries: %+v",
+			entries)
+	}
+	// AND a send is scheduled.
+	if !hasTimer {
+		t.Error("expected the digest send to be scheduled")
+	}
+	// AND the digest is saved to the database each time.
+	if got := len(databaseChannel); got != 2 {
+		t.Fatalf("want 2 database messages, got %d",
+			got)
+	}
+	<-databaseChannel
+	msg := <-databaseChannel
+	if msg.Table != dbtype.TableNotifyDigest || msg.ServiceID != shoutrrr.DigestKey() || msg.Delete {
+		t.Errorf("unexpected database message: %+v",
+			msg)
+	}
+	var saved []DigestEntry
+	if err := json.Unmarshal([]byte(msg.Cells[0].Value), &saved); err != nil || len(saved) != 2 {
+		t.Errorf("unexpected saved entries %q (err=%v)",
+			msg.Cells[0].Value, err)
+	}
+}
+
+func TestShoutrrr_QueueDigest_BlockedDatabase(t *testing.T) {
+	// GIVEN a Shoutrrr with a digest_window and a DatabaseChannel that isn't being read.
+	shoutrrr := testShoutrrr(false, false)
+	shoutrrr.ID = "TestShoutrrr_QueueDigest_BlockedDatabase"
+	shoutrrr.Options["digest_window"] = "1h"
+	databaseChannel := make(chan dbtype.Message)
+	shoutrrr.ServiceStatus.DatabaseChannel = &databaseChannel
+	t.Cleanup(func() {
+		// Drain the message of the blocked queue.
+		go func() {
+			for range databaseChannel {
+			}
+		}()
+		time.Sleep(10 * time.Millisecond)
+		digestsMutex.Lock()
+		if d := digests[shoutrrr.DigestKey()]; d != nil && d.timer != nil {
+			d.timer.Stop()
+		}
+		delete(digests, shoutrrr.DigestKey())
+		digestsMutex.Unlock()
+	})
+	go shoutrrr.addToDigest(util.ServiceInfo{ID: "alpha", LatestVersion: "1.0.0"})
+	time.Sleep(10 * time.Millisecond)
+
+	// WHEN the digests are read.
+	read := make(chan int, 1)
+	go func() {
+		digestsMutex.Lock()
+		defer digestsMutex.Unlock()
+		read <- len(digests[shoutrrr.DigestKey()].entries)
+	}()
+
+	// THEN they aren't stalled by the database.
+	select {
+	case got := <-read:
+		if got != 1 {
+			t.Errorf("want 1 entry, got %d",
+				got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("digests stalled by the blocked database")
+	}
+}
+
+func TestShoutrrr_SendDigest_Fail(t *testing.T) {
+	// GIVEN a failing Shoutrrr with a pending digest.
+	shoutrrr := testShoutrrr(true, false)
+	shoutrrr.ID = "TestShoutrrr_SendDigest_Fail"
+	shoutrrr.Options["digest_window"] = "1h"
+	databaseChannel := make(chan dbtype.Message, 4)
+	shoutrrr.ServiceStatus.DatabaseChannel = &databaseChannel
+	t.Cleanup(func() {
+		digestsMutex.Lock()
+		if d := digests[shoutrrr.DigestKey()]; d != nil && d.timer != nil {
+			d.timer.Stop()
+		}
+		delete(digests, shoutrrr.DigestKey())
+		digestsMutex.Unlock()
+	})
+	shoutrrr.addToDigest(util.ServiceInfo{ID: "alpha", LatestVersion: "1.0.0"})
+	<-databaseChannel
+
+	// WHEN the digest is sent.
+	err := shoutrrr.sendDigest()
+
+	// THEN it fails.
+	if err == nil {
+		t.Fatal("expected the digest send to fail")
+	}
+	// AND the entries are queued again for the next digest.
+	digestsMutex.Lock()
+	d := digests[shoutrrr.DigestKey()]
+	var entries []DigestEntry
+	hasTimer := false
+	if d != nil {
+		entries = d.sortedEntries()
+		hasTimer = d.timer != nil
+	}
+	digestsMutex.Unlock()
+	if len(entries) != 1 || entries[0].ServiceInfo.LatestVersion != "1.0.0" {
+		t.Errorf("want the failed entry requeued, got %+v",
+			entries)
+	}
+	if !hasTimer {
+		t.Error("expected the next digest send to be scheduled")
+	}
+	// AND the persisted digest is kept.
+	if got := len(databaseChannel); got != 1 {
+		t.Fatalf("want 1 database message, got %d",
+			got)
+	}
+	if msg := <-databaseChannel; msg.Delete {
+		t.Errorf("persisted digest was deleted on a failed send: %+v",
+			msg)
+	}
+}
+
+func TestShoutrrr_SendDigest_Context(t *testing.T) {
+	// GIVEN a pending digest queued by a Service that's since been deleted,
+	// a digest context that's done shortly (shutdown),
+	// and a Shoutrrr in quiet hours (so the send waits on the context).
+	shoutrrr := testShoutrrr(false, false)
+	shoutrrr.ID = "TestShoutrrr_SendDigest_Context"
+	shoutrrr.Options["digest_window"] = "1h"
+	now := time.Now()
+	shoutrrr.Options["quiet_hours"] = fmt.Sprintf("%s-%s",
+		now.Add(-time.Hour).Format("15:04"), now.Add(time.Hour).Format("15:04"))
+	databaseChannel := make(chan dbtype.Message, 4)
+	shoutrrr.ServiceStatus.DatabaseChannel = &databaseChannel
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	t.Cleanup(func() {
+		cancel()
+		SetDigestContext(context.Background())
+		digestsMutex.Lock()
+		if d := digests[shoutrrr.DigestKey()]; d != nil && d.timer != nil {
+			d.timer.Stop()
+		}
+		delete(digests, shoutrrr.DigestKey())
+		digestsMutex.Unlock()
+	})
+	SetDigestContext(ctx)
+	shoutrrr.addToDigest(util.ServiceInfo{ID: "alpha", LatestVersion: "1.0.0"})
+	<-databaseChannel
+	shoutrrr.ServiceStatus.SetDeleting()
+
+	// WHEN the digest is sent.
+	err := shoutrrr.sendDigest()
+
+	// THEN it's sent with the digest context, not the (cancelled) context of the Service.
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want %v, got %v",
+			context.DeadlineExceeded, err)
+	}
+	// AND once that's done, the persisted digest is left for the next start.
+	digestsMutex.Lock()
+	_, requeued := digests[shoutrrr.DigestKey()]
+	digestsMutex.Unlock()
+	if requeued {
+		t.Error("want the digest left in the database, got it requeued")
+	}
+	if got := len(databaseChannel); got != 0 {
+		t.Errorf("want 0 database messages, got %d",
+			got)
+	}
+}
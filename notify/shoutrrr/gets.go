@@ -102,12 +102,18 @@ func (s *Shoutrrr) GetMaxTries() uint8 {
 
 // Message of the Shoutrrr after the context is applied and template evaluated.
 func (s *Shoutrrr) Message(context util.ServiceInfo) string {
-	return util.TemplateString(s.GetOption("message"), context)
+	return util.TemplateString(s.GetOption("message"), s.templateContext(context))
 }
 
 // Title of the Shoutrrr after the context is applied and template evaluated.
 func (s *Shoutrrr) Title(context util.ServiceInfo) string {
-	return util.TemplateString(s.GetParam("title"), context)
+	return util.TemplateString(s.GetParam("title"), s.templateContext(context))
+}
+
+// templateContext returns the `context` with the notifier_type of this Shoutrrr.
+func (s *Shoutrrr) templateContext(context util.ServiceInfo) util.ServiceInfo {
+	context.NotifierType = s.GetType()
+	return context
 }
 
 // GetType of this Shoutrrr.
@@ -0,0 +1,54 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shoutrrr provides the shoutrrr notification service to services.
+package shoutrrr
+
+import (
+	"github.com/release-argus/Argus/history"
+	"github.com/release-argus/Argus/notify/shoutrrr/types"
+	"github.com/release-argus/Argus/util"
+)
+
+// historyPayload is the payload of a Shoutrrr message recorded in the history.
+type historyPayload struct {
+	Type    string            `json:"type"`
+	Params  map[string]string `json:"params,omitempty"`
+	Message string            `json:"message"`
+}
+
+// historyRecord returns the history.Record of sending `message` with `params` for `serviceInfo`,
+// with the censorable params redacted.
+func (s *Shoutrrr) historyRecord(
+	message string,
+	params map[string]string,
+	serviceInfo util.ServiceInfo,
+) history.Record {
+	redacted := util.CopyMap(params)
+	for _, param := range types.CensorableParams {
+		if redacted[param] != "" {
+			redacted[param] = util.SecretValue
+		}
+	}
+
+	return history.Record{
+		Kind:      history.KindNotify,
+		ServiceID: serviceInfo.ID,
+		Target:    s.ID,
+		Version:   serviceInfo.LatestVersion,
+		Payload: util.ToJSONString(historyPayload{
+			Type:    s.GetType(),
+			Params:  redacted,
+			Message: message})}
+}
@@ -0,0 +1,198 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shoutrrr provides the shoutrrr notification service to services.
+package shoutrrr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxQuietWindows is the most consecutive quiet windows a send will be deferred through.
+const maxQuietWindows = 14
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday}
+
+// quietWindow is a time of day window on certain weekdays where notifications are deferred.
+type quietWindow struct {
+	days  [7]bool // Weekdays the window starts on.
+	start int     // Minute of the day the window starts.
+	end   int     // Minute of the day the window ends (on the next day if <= start).
+}
+
+// parseQuietHours parses `quietHours` of the form
+//
+//	[DAYS ]HH:MM-HH:MM[; [DAYS ]HH:MM-HH:MM...]
+//
+// where DAYS is a comma-separated list of weekdays (mon) or ranges (mon-fri).
+func parseQuietHours(quietHours string) ([]quietWindow, error) {
+	var windows []quietWindow
+	for _, windowStr := range strings.Split(quietHours, ";") {
+		windowStr = strings.TrimSpace(windowStr)
+		if windowStr == "" {
+			continue
+		}
+
+		var window quietWindow
+		fields := strings.Fields(windowStr)
+		switch len(fields) {
+		case 1:
+			for i := range window.days {
+				window.days[i] = true
+			}
+		case 2:
+			if err := window.parseDays(fields[0]); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("%q <invalid> (want '[DAYS ]HH:MM-HH:MM')", windowStr)
+		}
+
+		times := strings.Split(fields[len(fields)-1], "-")
+		if len(times) != 2 {
+			return nil, fmt.Errorf("%q <invalid> (want 'HH:MM-HH:MM')", fields[len(fields)-1])
+		}
+		var err error
+		if window.start, err = parseMinuteOfDay(times[0]); err != nil {
+			return nil, err
+		}
+		if window.end, err = parseMinuteOfDay(times[1]); err != nil {
+			return nil, err
+		}
+
+		windows = append(windows, window)
+	}
+
+	if len(windows) == 0 {
+		return nil, errors.New("no windows given")
+	}
+	return windows, nil
+}
+
+// parseDays sets the weekdays of the window from a comma-separated list of days/ranges.
+func (w *quietWindow) parseDays(days string) error {
+	for _, part := range strings.Split(strings.ToLower(days), ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		fromDay, ok := weekdays[from]
+		if !ok {
+			return fmt.Errorf("%q <invalid> (unknown weekday, use mon/tue/...)", from)
+		}
+		toDay := fromDay
+		if isRange {
+			if toDay, ok = weekdays[to]; !ok {
+				return fmt.Errorf("%q <invalid> (unknown weekday, use mon/tue/...)", to)
+			}
+		}
+
+		// Ranges may wrap, e.g. fri-mon.
+		for day := fromDay; ; day = (day + 1) % 7 {
+			w.days[day] = true
+			if day == toDay {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// parseMinuteOfDay returns the minute of the day of `timeOfDay` (HH:MM).
+func parseMinuteOfDay(timeOfDay string) (int, error) {
+	t, err := time.Parse("15:04", timeOfDay)
+	if err != nil {
+		return 0, fmt.Errorf("%q <invalid> (want 'HH:MM')", timeOfDay)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// activeUntil returns the end of this window if `now` falls within it.
+func (w *quietWindow) activeUntil(now time.Time) (time.Time, bool) {
+	// The window may have started yesterday and run past midnight.
+	for _, daysAgo := range []int{0, 1} {
+		day := now.AddDate(0, 0, -daysAgo)
+		if !w.days[day.Weekday()] {
+			continue
+		}
+
+		start := time.Date(day.Year(), day.Month(), day.Day(), 0, w.start, 0, 0, now.Location())
+		end := time.Date(day.Year(), day.Month(), day.Day(), 0, w.end, 0, 0, now.Location())
+		if w.end <= w.start {
+			end = end.AddDate(0, 0, 1)
+		}
+		if !now.Before(start) && now.Before(end) {
+			return end, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// GetQuietHoursLocation returns the time zone of the quiet_hours (default: local time).
+func (s *Shoutrrr) GetQuietHoursLocation() *time.Location {
+	timezone := s.GetOption("quiet_hours_timezone")
+	if timezone == "" {
+		return time.Local
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Local
+	}
+	return location
+}
+
+// quietHoursAllowFailures returns whether failure alerts are sent during quiet hours.
+func (s *Shoutrrr) quietHoursAllowFailures() bool {
+	allow, _ := strconv.ParseBool(s.GetOption("quiet_hours_allow_failures"))
+	return allow
+}
+
+// quietUntil returns the time the quiet hours active at `now` end,
+// or the zero time if no quiet hours are active.
+func (s *Shoutrrr) quietUntil(now time.Time) time.Time {
+	quietHours := s.GetOption("quiet_hours")
+	if quietHours == "" {
+		return time.Time{}
+	}
+	windows, err := parseQuietHours(quietHours)
+	if err != nil {
+		return time.Time{}
+	}
+
+	now = now.In(s.GetQuietHoursLocation())
+	var until time.Time
+	// Follow windows that start as another ends.
+	for range maxQuietWindows {
+		extended := false
+		for i := range windows {
+			if end, active := windows[i].activeUntil(now); active && end.After(now) {
+				now = end
+				until = end
+				extended = true
+			}
+		}
+		if !extended {
+			break
+		}
+	}
+	return until
+}
@@ -0,0 +1,232 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package shoutrrr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/release-argus/Argus/util"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	// GIVEN a quiet_hours string.
+	tests := map[string]struct {
+		quietHours string
+		want       []quietWindow
+		errRegex   string
+	}{
+		"every day": {
+			quietHours: "22:00-07:00",
+			want: []quietWindow{
+				{days: [7]bool{true, true, true, true, true, true, true}, start: 22 * 60, end: 7 * 60}},
+			errRegex: `^$`},
+		"weekday range": {
+			quietHours: "mon-fri 18:30-09:00",
+			want: []quietWindow{
+				{days: [7]bool{false, true, true, true, true, true, false}, start: 18*60 + 30, end: 9 * 60}},
+			errRegex: `^$`},
+		"wrapping weekday range and list": {
+			quietHours: "fri-sun,Wed 00:00-00:00",
+			want: []quietWindow{
+				{days: [7]bool{true, false, false, true, false, true, true}, start: 0, end: 0}},
+			errRegex: `^$`},
+		"multiple windows": {
+			quietHours: "mon-fri 22:00-07:00; sat,sun 20:00-10:00",
+			want: []quietWindow{
+				{days: [7]bool{false, true, true, true, true, true, false}, start: 22 * 60, end: 7 * 60},
+				{days: [7]bool{true, false, false, false, false, false, true}, start: 20 * 60, end: 10 * 60}},
+			errRegex: `^$`},
+		"unknown weekday": {
+			quietHours: "monday 22:00-07:00",
+			errRegex:   `"monday" <invalid>`},
+		"invalid time": {
+			quietHours: "22:00-25:00",
+			errRegex:   `"25:00" <invalid>`},
+		"missing end time": {
+			quietHours: "22:00",
+			errRegex:   `"22:00" <invalid>`},
+		"too many fields": {
+			quietHours: "mon fri 22:00-07:00",
+			errRegex:   `<invalid> \(want '\[DAYS \]HH:MM-HH:MM'\)`},
+		"no windows": {
+			quietHours: " ; ",
+			errRegex:   `no windows given`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN parseQuietHours is called.
+			got, err := parseQuietHours(tc.quietHours)
+
+			// THEN the error is as expected.
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Fatalf("want error matching %q\ngot: %q",
+					tc.errRegex, e)
+			}
+			// AND the windows are as expected.
+			if len(got) != len(tc.want) {
+				t.Fatalf("want: %+v\ngot:  %+v",
+					tc.want, got)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("window %d\nwant: %+v\ngot:  %+v",
+						i, tc.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestShoutrrr_QuietUntil(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %s", err)
+	}
+	// 2025-01-06 is a Monday.
+	monday := func(hour, minute int) time.Time {
+		return time.Date(2025, 1, 6, hour, minute, 0, 0, time.UTC)
+	}
+	// GIVEN a Shoutrrr with quiet_hours.
+	tests := map[string]struct {
+		options map[string]string
+		now     time.Time
+		want    time.Time
+	}{
+		"no quiet_hours": {
+			options: map[string]string{},
+			now:     monday(3, 0),
+			want:    time.Time{}},
+		"invalid quiet_hours": {
+			options: map[string]string{
+				"quiet_hours": "nope"},
+			now:  monday(3, 0),
+			want: time.Time{}},
+		"outside window": {
+			options: map[string]string{
+				"quiet_hours":          "22:00-07:00",
+				"quiet_hours_timezone": "UTC"},
+			now:  monday(12, 0),
+			want: time.Time{}},
+		"in window after midnight": {
+			options: map[string]string{
+				"quiet_hours":          "22:00-07:00",
+				"quiet_hours_timezone": "UTC"},
+			now:  monday(3, 0),
+			want: monday(7, 0)},
+		"in window before midnight": {
+			options: map[string]string{
+				"quiet_hours":          "22:00-07:00",
+				"quiet_hours_timezone": "UTC"},
+			now:  monday(23, 0),
+			want: monday(7, 0).AddDate(0, 0, 1)},
+		"at window end": {
+			options: map[string]string{
+				"quiet_hours":          "22:00-07:00",
+				"quiet_hours_timezone": "UTC"},
+			now:  monday(7, 0),
+			want: time.Time{}},
+		"window started on a day not listed": {
+			options: map[string]string{
+				"quiet_hours":          "mon-fri 22:00-07:00",
+				"quiet_hours_timezone": "UTC"},
+			now:  monday(3, 0), // Started Sunday.
+			want: time.Time{}},
+		"window started on a listed day": {
+			options: map[string]string{
+				"quiet_hours":          "sun 22:00-07:00",
+				"quiet_hours_timezone": "UTC"},
+			now:  monday(3, 0),
+			want: monday(7, 0)},
+		"consecutive windows": {
+			options: map[string]string{
+				"quiet_hours":          "22:00-07:00; mon 07:00-09:00",
+				"quiet_hours_timezone": "UTC"},
+			now:  monday(3, 0),
+			want: monday(9, 0)},
+		"time zone": {
+			options: map[string]string{
+				"quiet_hours":          "22:00-07:00",
+				"quiet_hours_timezone": "America/New_York"},
+			now:  monday(3, 0), // 22:00 in New York.
+			want: monday(12, 0)},
+		"time zone in summer time": {
+			options: map[string]string{
+				"quiet_hours":          "00:00-08:00",
+				"quiet_hours_timezone": "Europe/London"},
+			now:  time.Date(2025, 7, 1, 6, 0, 0, 0, time.UTC), // 07:00 BST.
+			want: time.Date(2025, 7, 1, 8, 0, 0, 0, london)},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			shoutrrr := testShoutrrr(false, false)
+			shoutrrr.Options = tc.options
+
+			// WHEN quietUntil is called.
+			got := shoutrrr.quietUntil(tc.now)
+
+			// THEN the end of the quiet hours is as expected.
+			if !got.Equal(tc.want) {
+				t.Errorf("want: %s\ngot:  %s",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestShoutrrr_QuietHoursAllowFailures(t *testing.T) {
+	// GIVEN a Shoutrrr with/without quiet_hours_allow_failures.
+	tests := map[string]struct {
+		value string
+		want  bool
+	}{
+		"unset": {
+			value: "",
+			want:  false},
+		"true": {
+			value: "true",
+			want:  true},
+		"false": {
+			value: "false",
+			want:  false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			shoutrrr := testShoutrrr(false, false)
+			shoutrrr.Options["quiet_hours_allow_failures"] = tc.value
+
+			// WHEN quietHoursAllowFailures is called.
+			got := shoutrrr.quietHoursAllowFailures()
+
+			// THEN the result is as expected.
+			if got != tc.want {
+				t.Errorf("want: %t\ngot:  %t",
+					tc.want, got)
+			}
+		})
+	}
+}
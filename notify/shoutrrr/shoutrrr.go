@@ -16,6 +16,7 @@
 package shoutrrr
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -26,6 +27,8 @@ import (
 	shoutrrr_lib "github.com/containrrr/shoutrrr"
 	"github.com/containrrr/shoutrrr/pkg/router"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/release-argus/Argus/history"
+	"github.com/release-argus/Argus/outbox"
 	"github.com/release-argus/Argus/util"
 	"github.com/release-argus/Argus/web/metric"
 )
@@ -33,6 +36,7 @@ import (
 // Send sends a notification with the given title and message to all Shoutrrrs in the Slice.
 // It attempts to send each message up to max_tries times until they succeed or fail.
 func (s *Slice) Send(
+	ctx context.Context,
 	title, message string,
 	serviceInfo util.ServiceInfo,
 	useDelay bool,
@@ -43,9 +47,32 @@ func (s *Slice) Send(
 
 	errChan := make(chan error, len(*s))
 	for _, shoutrrr := range *s {
-		// Send each message up to max_tries amount of times until they don't err.
+		// Queue new release notifications for the digest.
+		if title == "" && message == "" && shoutrrr.digestEnabled() {
+			shoutrrr.addToDigest(serviceInfo)
+			errChan <- nil
+			continue
+		}
+
+		// Send each message up to max_tries amount of times until they don't err,
+		// with the outbox retrying failures.
 		go func(shoutrrr *Shoutrrr) {
-			errChan <- shoutrrr.Send(title, message, serviceInfo, useDelay, true)
+			var sendAt time.Time
+			if useDelay {
+				sendAt = time.Now().UTC().Add(shoutrrr.GetDelayDuration())
+			}
+			errChan <- outbox.Deliver(
+				outbox.Entry{
+					Kind:        outbox.KindNotify,
+					ServiceID:   serviceInfo.ID,
+					Target:      shoutrrr.ID,
+					Title:       title,
+					Message:     message,
+					ServiceInfo: serviceInfo,
+					NextAttempt: sendAt},
+				outbox.Delivery{
+					Attempt: func() error { return shoutrrr.Send(ctx, title, message, serviceInfo, useDelay, true) },
+					Retry:   func() error { return shoutrrr.Send(ctx, title, message, serviceInfo, false, true) }})
 		}(shoutrrr)
 
 		// Space out Shoutrrr send starts.
@@ -68,8 +95,10 @@ func (s *Slice) Send(
 }
 
 // Send sends a notification with the given title and message.
-// It attempts to send the message up to max_tries times until it succeeds.
+// It attempts to send the message up to max_tries times until it succeeds,
+// or `ctx` is cancelled (returning the error of the context).
 func (s *Shoutrrr) Send(
+	ctx context.Context,
 	title, msg string,
 	serviceInfo util.ServiceInfo,
 	useDelay bool,
@@ -81,7 +110,22 @@ func (s *Shoutrrr) Send(
 		// Delay sending the Shoutrrr message by the defined interval.
 		msg := fmt.Sprintf("%s, Sleeping for %s before sending the Shoutrrr message", s.ID, s.GetDelay())
 		jLog.Info(msg, logFrom, s.GetDelay() != "0s")
-		time.Sleep(s.GetDelayDuration())
+		if !util.Sleep(ctx, s.GetDelayDuration()) {
+			return ctx.Err() //nolint:wrapcheck
+		}
+	}
+
+	// Defer the send until the quiet hours end (unless a failure alert allowed through).
+	if serviceInfo.Failure == "" || !s.quietHoursAllowFailures() {
+		if until := s.quietUntil(time.Now()); !until.IsZero() {
+			jLog.Info(
+				fmt.Sprintf("%s, In quiet hours, deferring the Shoutrrr message until %s",
+					s.ID, until.Format(time.RFC3339)),
+				logFrom, true)
+			if !util.Sleep(ctx, time.Until(until)) {
+				return ctx.Err() //nolint:wrapcheck
+			}
+		}
 	}
 
 	sender, message, params, url, err := s.getSender(title, msg, serviceInfo)
@@ -102,10 +146,12 @@ func (s *Shoutrrr) Send(
 		serviceName = ""
 	}
 	return s.send(
+		ctx,
 		sender,
 		message,
 		params,
 		serviceName,
+		s.historyRecord(message, *params, serviceInfo),
 		logFrom)
 }
 
@@ -421,6 +467,7 @@ func (s *Shoutrrr) BuildParams(context util.ServiceInfo) *types.Params {
 	}
 
 	// Apply django templating.
+	context = s.templateContext(context)
 	for key, value := range params {
 		params[key] = util.TemplateString(value, context)
 	}
@@ -429,19 +476,24 @@ func (s *Shoutrrr) BuildParams(context util.ServiceInfo) *types.Params {
 }
 
 // send the message to the Shoutrrr service using the given sender and params.
-// It attempts to send the message up to max_tries times until it succeeds.
+// It attempts to send the message up to max_tries times until it succeeds, recording each attempt in the history.
 func (s *Shoutrrr) send(
+	ctx context.Context,
 	sender *router.ServiceRouter,
 	message string,
 	params *types.Params,
 	serviceName string,
+	record history.Record,
 	logFrom util.LogFrom,
 ) error {
 	combinedErrs := make(map[string]int)
 
 	if err := util.RetryWithBackoff(
+		ctx,
 		func() error {
+			record.Time = time.Now()
 			err := sender.Send(message, params)
+			history.Add(record, errors.Join(err...))
 			if failed := s.parseSend(err, combinedErrs, serviceName, logFrom); failed {
 				return fmt.Errorf("send failed")
 			}
@@ -453,6 +505,8 @@ func (s *Shoutrrr) send(
 		s.ServiceStatus.Deleting,
 	); err == nil {
 		return nil
+	} else if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr //nolint:wrapcheck
 	}
 
 	msg := fmt.Sprintf("failed %d times to send a %s message for %q to %q",
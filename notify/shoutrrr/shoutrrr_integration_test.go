@@ -17,6 +17,7 @@
 package shoutrrr
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
@@ -115,6 +116,7 @@ func TestShoutrrr_Send(t *testing.T) {
 			// WHEN send attempted.
 			msg := strings.ReplaceAll(tc.message, "__name__", name)
 			err := tc.shoutrrr.Send(
+				context.Background(),
 				"test",
 				msg,
 				util.ServiceInfo{ID: *svcStatus.ServiceID},
@@ -207,7 +209,7 @@ func TestSlice_Send(t *testing.T) {
 				ID: name}
 
 			// WHEN Send is called.
-			err := tc.slice.Send("TestSlice_Send", name, serviceInfo, tc.useDelay)
+			err := tc.slice.Send(context.Background(), "TestSlice_Send", name, serviceInfo, tc.useDelay)
 
 			// THEN the expected error state is returned.
 			e := util.ErrorToString(err)
@@ -16,6 +16,7 @@
 package shoutrrr
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
@@ -266,6 +267,52 @@ func (b *Base) checkValuesOptions(prefix string) error {
 			prefix, optionMessage))
 	}
 
+	// Options.DigestWindow.
+	if digestWindow := b.GetOption("digest_window"); digestWindow != "" {
+		if _, err := time.ParseDuration(digestWindow); err != nil {
+			errs = append(errs, fmt.Errorf("%sdigest_window: %q <invalid> (Use 'AhBmCs' duration format)",
+				prefix, digestWindow))
+		}
+	}
+	// Options.DigestSchedule.
+	if digestSchedule := b.GetOption("digest_schedule"); digestSchedule != "" {
+		for _, timeOfDay := range strings.Split(digestSchedule, ",") {
+			if _, err := time.Parse("15:04", strings.TrimSpace(timeOfDay)); err != nil {
+				errs = append(errs, fmt.Errorf("%sdigest_schedule: %q <invalid> (Use comma-separated 'HH:MM' times)",
+					prefix, digestSchedule))
+				break
+			}
+		}
+	}
+	// Options.QuietHours.
+	if quietHours := b.GetOption("quiet_hours"); quietHours != "" {
+		if _, err := parseQuietHours(quietHours); err != nil {
+			errs = append(errs, fmt.Errorf("%squiet_hours: %q <invalid> (%s)",
+				prefix, quietHours, err))
+		}
+	}
+	// Options.QuietHoursTimezone.
+	if timezone := b.GetOption("quiet_hours_timezone"); timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			errs = append(errs, fmt.Errorf("%squiet_hours_timezone: %q <invalid> (Use an IANA time zone, e.g. 'Europe/London')",
+				prefix, timezone))
+		}
+	}
+	// Options.QuietHoursAllowFailures.
+	if allowFailures := b.GetOption("quiet_hours_allow_failures"); allowFailures != "" {
+		if _, err := strconv.ParseBool(allowFailures); err != nil {
+			errs = append(errs, fmt.Errorf("%squiet_hours_allow_failures: %q <invalid> (Use 'true' or 'false')",
+				prefix, allowFailures))
+		}
+	}
+	// Options.DigestTitle/DigestMessage.
+	for _, key := range []string{"digest_title", "digest_message"} {
+		if option := b.GetOption(key); !util.CheckTemplate(option) {
+			errs = append(errs, fmt.Errorf("%s%s: %q <invalid> (didn't pass templating)",
+				prefix, key, option))
+		}
+	}
+
 	if len(errs) == 0 {
 		return nil
 	}
@@ -552,7 +599,7 @@ func (s *Shoutrrr) checkValuesParams(prefix string) error {
 }
 
 // TestSend will test the Shoutrrr by sending a test message.
-func (s *Shoutrrr) TestSend(serviceURL string) error {
+func (s *Shoutrrr) TestSend(ctx context.Context, serviceURL string) error {
 	if s == nil {
 		return errors.New("shoutrrr is nil")
 	}
@@ -585,6 +632,7 @@ func (s *Shoutrrr) TestSend(serviceURL string) error {
 		message, " - "+message)
 
 	return s.Send(
+		ctx,
 		title,
 		message,
 		testServiceInfo,
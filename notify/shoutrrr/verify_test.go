@@ -17,6 +17,7 @@
 package shoutrrr
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"strings"
@@ -163,6 +164,57 @@ func TestBase_checkValuesOptions(t *testing.T) {
 			errRegex: test.TrimYAML(`
 				^max_tries: "-1" <invalid>.*$`),
 		},
+		"valid digest options": {
+			options: map[string]string{
+				"digest_window":   "1h",
+				"digest_schedule": "09:00, 17:30",
+				"digest_title":    "{{ count }} releases",
+				"digest_message":  "{% for service in services %}{{ service.version }}{% endfor %}"},
+			errRegex: `^$`,
+		},
+		"invalid digest_window": {
+			options: map[string]string{
+				"digest_window": "1x"},
+			errRegex: test.TrimYAML(`
+				^digest_window: "1x" <invalid>.*$`),
+		},
+		"invalid digest_schedule": {
+			options: map[string]string{
+				"digest_schedule": "09:00,25:00"},
+			errRegex: test.TrimYAML(`
+				^digest_schedule: "09:00,25:00" <invalid>.*$`),
+		},
+		"valid quiet_hours options": {
+			options: map[string]string{
+				"quiet_hours":                "mon-fri 22:00-07:00; sat,sun 20:00-10:00",
+				"quiet_hours_timezone":       "UTC",
+				"quiet_hours_allow_failures": "true"},
+			errRegex: `^$`,
+		},
+		"invalid quiet_hours": {
+			options: map[string]string{
+				"quiet_hours": "22:00"},
+			errRegex: test.TrimYAML(`
+				^quiet_hours: "22:00" <invalid>.*$`),
+		},
+		"invalid quiet_hours_timezone": {
+			options: map[string]string{
+				"quiet_hours_timezone": "Not/AZone"},
+			errRegex: test.TrimYAML(`
+				^quiet_hours_timezone: "Not/AZone" <invalid>.*$`),
+		},
+		"invalid quiet_hours_allow_failures": {
+			options: map[string]string{
+				"quiet_hours_allow_failures": "sometimes"},
+			errRegex: test.TrimYAML(`
+				^quiet_hours_allow_failures: "sometimes" <invalid>.*$`),
+		},
+		"invalid digest_message template": {
+			options: map[string]string{
+				"digest_message": "{{ count }"},
+			errRegex: test.TrimYAML(`
+				^digest_message: "{{ count }" <invalid>.*$`),
+		},
 	}
 
 	for name, tc := range tests {
@@ -1856,7 +1908,7 @@ func TestShoutrrr_TestSend(t *testing.T) {
 			}
 
 			// WHEN TestSend is called
-			err := shoutrrr.TestSend("https://example.com")
+			err := shoutrrr.TestSend(context.Background(), "https://example.com")
 
 			// THEN it errors when expected
 			if tc.wantErr && err == nil {
@@ -0,0 +1,55 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package outbox
+
+import (
+	"os"
+	"testing"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestMain(m *testing.M) {
+	// initialise jLog
+	jLog = util.NewJLog("DEBUG", false)
+	jLog.Testing = true
+	LogInit(jLog)
+
+	// run other tests
+	exitCode := m.Run()
+
+	// exit
+	os.Exit(exitCode)
+}
+
+// testDatabaseChannel sets a new DatabaseChannel for the outbox and clears the entries.
+func testDatabaseChannel(t *testing.T) chan dbtype.Message {
+	channel := make(chan dbtype.Message, 32)
+	SetDatabaseChannel(&channel)
+	t.Cleanup(func() {
+		entriesMutex.Lock()
+		for id, e := range entries {
+			e.stopTimer()
+			delete(entries, id)
+		}
+		databaseChannel = nil
+		retrySucceeded = nil
+		entriesMutex.Unlock()
+	})
+	return channel
+}
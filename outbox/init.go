@@ -0,0 +1,45 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package outbox provides durable delivery of notifications, webhooks and commands.
+package outbox
+
+import (
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/util"
+)
+
+// LogInit for this package.
+func LogInit(log *util.JLog) {
+	jLog = log
+}
+
+// SetDatabaseChannel sets the channel the outbox is persisted through.
+//
+// (The outbox is disabled when nil).
+func SetDatabaseChannel(channel *chan dbtype.Message) {
+	entriesMutex.Lock()
+	defer entriesMutex.Unlock()
+
+	databaseChannel = channel
+}
+
+// SetRetrySucceeded sets the function called when a retry of an Entry succeeds
+// (e.g. to apply the side effects the first attempt would have on success).
+func SetRetrySucceeded(succeeded func(entry Entry)) {
+	entriesMutex.Lock()
+	defer entriesMutex.Unlock()
+
+	retrySucceeded = succeeded
+}
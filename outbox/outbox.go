@@ -0,0 +1,409 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package outbox provides durable delivery of notifications, webhooks and commands.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/util"
+)
+
+const (
+	MaxAttempts = 10               // Attempts before an Entry is marked dead.
+	baseBackoff = 30 * time.Second // Delay before the first retry.
+	maxBackoff  = 6 * time.Hour    // Maximum delay between retries.
+)
+
+var (
+	ErrNotFound   = errors.New("outbox entry not found")
+	ErrDelivering = errors.New("outbox entry is being delivered")
+)
+
+// Deliver adds the delivery to the outbox and attempts it with `delivery.Attempt`,
+// retrying with `delivery.Retry` in the background with exponential backoff until it succeeds,
+// or MaxAttempts is reached (when `delivery.Dead` is called).
+// A NextAttempt in the future records that the first attempt waits until then (e.g. a delay).
+//
+// Returns the error of the first attempt.
+func Deliver(entry Entry, delivery Delivery) error {
+	entriesMutex.Lock()
+	// Outbox disabled, so the first attempt is the only one.
+	if databaseChannel == nil {
+		entriesMutex.Unlock()
+		err := delivery.Attempt()
+		if err != nil && !errors.Is(err, context.Canceled) && delivery.Dead != nil {
+			delivery.Dead(err)
+		}
+		return err
+	}
+
+	e := &entry
+	e.ID = newID()
+	e.State = StatePending
+	e.Created = time.Now().UTC()
+	if e.NextAttempt.Before(e.Created) {
+		e.NextAttempt = e.Created
+	}
+	e.Attempts = 0
+	e.LastError = ""
+	e.setDelivery(delivery)
+	e.delivering = true
+	entries[e.ID] = e
+	e.save()
+	entriesMutex.Unlock()
+	flush()
+
+	return e.attempt(delivery.Attempt, false)
+}
+
+// Restore an Entry from the database, scheduling its next attempt with `delivery.Retry` if pending.
+func Restore(entry Entry, delivery Delivery) {
+	entriesMutex.Lock()
+	defer entriesMutex.Unlock()
+
+	e := &entry
+	e.setDelivery(delivery)
+	if existing := entries[e.ID]; existing != nil && existing.timer != nil {
+		existing.timer.Stop()
+	}
+	entries[e.ID] = e
+	if e.State != StateDead {
+		e.State = StatePending
+		e.schedule()
+	}
+}
+
+// setDelivery sets the functions to retry, and give up on this Entry with.
+func (e *Entry) setDelivery(delivery Delivery) {
+	e.retry = delivery.Retry
+	if e.retry == nil {
+		e.retry = delivery.Attempt
+	}
+	e.dead = delivery.Dead
+}
+
+// List the Entries in the outbox, oldest first.
+func List() []Entry {
+	entriesMutex.RLock()
+	defer entriesMutex.RUnlock()
+
+	list := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, *e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Created.Equal(list[j].Created) {
+			return list[i].ID < list[j].ID
+		}
+		return list[i].Created.Before(list[j].Created)
+	})
+	return list
+}
+
+// Retry the Entry with this `id` now, reviving it if dead.
+func Retry(id string) error {
+	entriesMutex.Lock()
+	e := entries[id]
+	if e == nil {
+		entriesMutex.Unlock()
+		return ErrNotFound
+	}
+	if e.delivering {
+		entriesMutex.Unlock()
+		return ErrDelivering
+	}
+	if e.State == StateDead {
+		e.State = StatePending
+		e.Attempts = 0
+	}
+	e.stopTimer()
+	e.delivering = true
+	e.NextAttempt = time.Now().UTC()
+	e.save()
+	entriesMutex.Unlock()
+	flush()
+
+	//#nosec G104 -- Errors are logged to CLI
+	//nolint:errcheck // ^
+	go e.attempt(e.retry, true)
+	return nil
+}
+
+// Discard the Entry with this `id` from the outbox.
+func Discard(id string) error {
+	entriesMutex.Lock()
+	e := entries[id]
+	if e == nil {
+		entriesMutex.Unlock()
+		return ErrNotFound
+	}
+	e.stopTimer()
+	delete(entries, id)
+	e.remove()
+	entriesMutex.Unlock()
+	flush()
+
+	return nil
+}
+
+// RemoveService removes the Entries of the Service with this `serviceID` from the outbox.
+func RemoveService(serviceID string) {
+	entriesMutex.Lock()
+	for id, e := range entries {
+		if e.ServiceID == serviceID {
+			e.stopTimer()
+			delete(entries, id)
+			e.remove()
+		}
+	}
+	entriesMutex.Unlock()
+	flush()
+}
+
+// RemovePendingActions removes the pending WebHook/Command Entries of the Service with this `serviceID`
+// from the outbox (e.g. when the version they were for is superseded or skipped).
+func RemovePendingActions(serviceID string) {
+	entriesMutex.Lock()
+	for id, e := range entries {
+		if e.ServiceID == serviceID && e.Kind != KindNotify && e.State == StatePending {
+			e.stopTimer()
+			delete(entries, id)
+			e.remove()
+		}
+	}
+	entriesMutex.Unlock()
+	flush()
+}
+
+// Clear the Entries from the outbox, stopping their scheduled attempts,
+// but leaving them in the database (e.g. to Restore those of an import).
+func Clear() {
+	entriesMutex.Lock()
+	defer entriesMutex.Unlock()
+
+	for id, e := range entries {
+		e.stopTimer()
+		delete(entries, id)
+	}
+}
+
+// Stop the scheduled attempts, and the persisting of the outbox,
+// leaving the pending Entries in the database for the next start.
+func Stop() {
+	entriesMutex.Lock()
+	defer entriesMutex.Unlock()
+
+	for _, e := range entries {
+		e.stopTimer()
+	}
+	databaseChannel = nil
+}
+
+// attempt the delivery of this Entry with `deliver`, and schedule a retry on failure
+// (`retrying` when it's not the first attempt).
+//
+// The database is written to after releasing the lock,
+// so a backed up database writer doesn't stall the other deliveries.
+func (e *Entry) attempt(deliver DeliverFunc, retrying bool) error {
+	err := deliver()
+
+	entriesMutex.Lock()
+	e.delivering = false
+	var dead bool
+
+	switch {
+	// Cancelled.
+	case errors.Is(err, context.Canceled):
+		// By an edit/deletion of the Service, so drop it
+		// (after a Stop for shutdown, it's left in the database for the next start).
+		if databaseChannel != nil && entries[e.ID] == e {
+			delete(entries, e.ID)
+			e.remove()
+		}
+		entriesMutex.Unlock()
+		flush()
+		return err
+
+	// Discarded during the attempt.
+	case entries[e.ID] != e:
+		e.Attempts++
+
+	// SUCCESS!
+	case err == nil:
+		e.Attempts++
+		delete(entries, e.ID)
+		e.remove()
+
+	// FAIL!
+	default:
+		e.Attempts++
+		e.LastError = err.Error()
+		logFrom := util.LogFrom{Primary: "outbox", Secondary: e.ServiceID}
+		if e.Attempts >= MaxAttempts {
+			dead = true
+			e.State = StateDead
+			e.NextAttempt = time.Time{}
+			jLog.Error(
+				fmt.Sprintf("Giving up on %s %q delivery %s after %d attempts",
+					e.Kind, e.Target, e.ID, e.Attempts),
+				logFrom, true)
+		} else {
+			e.NextAttempt = time.Now().UTC().Add(backoff(e.Attempts))
+			// Stopped for shutdown, so leave the retry to the next start.
+			if databaseChannel != nil {
+				e.schedule()
+			}
+			jLog.Verbose(
+				fmt.Sprintf("Retrying %s %q delivery %s at %s (attempt %d/%d)",
+					e.Kind, e.Target, e.ID, e.NextAttempt.Format(time.RFC3339), e.Attempts+1, MaxAttempts),
+				logFrom, true)
+		}
+		e.save()
+	}
+	succeeded := retrySucceeded
+	entry := *e
+	entriesMutex.Unlock()
+	flush()
+
+	switch {
+	case dead && e.dead != nil:
+		e.dead(err)
+	case err == nil && retrying && succeeded != nil:
+		succeeded(entry)
+	}
+	return err
+}
+
+// retry the Entry with this `id` if it is still pending.
+func retry(id string) {
+	entriesMutex.Lock()
+	e := entries[id]
+	if e == nil || e.delivering || e.State != StatePending {
+		entriesMutex.Unlock()
+		return
+	}
+	e.delivering = true
+	e.timer = nil
+	entriesMutex.Unlock()
+
+	//#nosec G104 -- Errors are logged to CLI
+	//nolint:errcheck // ^
+	e.attempt(e.retry, true)
+}
+
+// schedule the next attempt of this Entry at NextAttempt.
+func (e *Entry) schedule() {
+	e.stopTimer()
+	id := e.ID
+	e.timer = time.AfterFunc(time.Until(e.NextAttempt), func() {
+		retry(id)
+	})
+}
+
+// stopTimer stops any scheduled attempt of this Entry.
+func (e *Entry) stopTimer() {
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer = nil
+	}
+}
+
+// save the Entry to the database (queued until the next flush).
+func (e *Entry) save() {
+	if databaseChannel == nil {
+		return
+	}
+
+	data, _ := json.Marshal(e)
+	queuePersist(dbtype.Message{
+		Table:     dbtype.TableOutbox,
+		ServiceID: e.ID,
+		Cells: []dbtype.Cell{
+			{Column: "entry", Value: string(data)}}})
+}
+
+// remove the Entry from the database (queued until the next flush).
+func (e *Entry) remove() {
+	if databaseChannel == nil {
+		return
+	}
+
+	queuePersist(dbtype.Message{
+		Table:     dbtype.TableOutbox,
+		ServiceID: e.ID,
+		Delete:    true})
+}
+
+// queuePersist queues the `message` for the DatabaseChannel.
+//
+// (Called with entriesMutex held, so messages are queued in the order of the changes).
+func queuePersist(message dbtype.Message) {
+	persistMutex.Lock()
+	defer persistMutex.Unlock()
+
+	persistQueue = append(persistQueue, persistMessage{
+		channel: databaseChannel,
+		message: message})
+}
+
+// flush the queued messages to the database, in order.
+//
+// (Called without entriesMutex held, as the DatabaseChannel may block).
+func flush() {
+	flushMutex.Lock()
+	defer flushMutex.Unlock()
+
+	for {
+		persistMutex.Lock()
+		if len(persistQueue) == 0 {
+			persistMutex.Unlock()
+			return
+		}
+		next := persistQueue[0]
+		persistQueue = persistQueue[1:]
+		persistMutex.Unlock()
+
+		*next.channel <- next.message
+	}
+}
+
+// backoff returns the delay before the retry after `attempts` attempts.
+func backoff(attempts int) time.Duration {
+	delay := time.Duration(math.Min(
+		float64(baseBackoff)*math.Pow(2, float64(attempts-1)),
+		float64(maxBackoff)))
+	//#nosec G404 -- jitter does not need cryptographic security.
+	jitter := time.Duration(rand.Int63n(int64(baseBackoff))) // Add randomness to avoid synchronized retries.
+	return delay + jitter
+}
+
+// newID returns a new unique ID for an Entry.
+func newID() string {
+	for {
+		id := util.RandAlphaNumericLower(16)
+		if entries[id] == nil {
+			return id
+		}
+	}
+}
@@ -0,0 +1,632 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+)
+
+func TestDeliver_Disabled(t *testing.T) {
+	// GIVEN the outbox has no DatabaseChannel.
+	SetDatabaseChannel(nil)
+	calls := 0
+
+	// WHEN Deliver is called.
+	var deadErr error
+	err := Deliver(
+		Entry{Kind: KindNotify, ServiceID: "svc", Target: "notify"},
+		Delivery{
+			Attempt: func() error {
+				calls++
+				return errors.New("fail")
+			},
+			Dead: func(err error) { deadErr = err }})
+
+	// THEN the delivery is attempted once.
+	if calls != 1 {
+		t.Errorf("want 1 attempt, got %d",
+			calls)
+	}
+	// AND the error is returned.
+	if err == nil {
+		t.Error("want an error, got nil")
+	}
+	// AND it's given up on straight away.
+	if deadErr == nil || deadErr.Error() != "fail" {
+		t.Errorf("want Dead called with the error, got %v",
+			deadErr)
+	}
+	// AND nothing is added to the outbox.
+	if got := len(List()); got != 0 {
+		t.Errorf("want 0 entries, got %d",
+			got)
+	}
+}
+
+func TestDeliver_Success(t *testing.T) {
+	// GIVEN an outbox with a DatabaseChannel.
+	channel := testDatabaseChannel(t)
+
+	// WHEN Deliver is called with a delivery that succeeds.
+	err := Deliver(
+		Entry{Kind: KindWebHook, ServiceID: "svc", Target: "wh"},
+		Delivery{Attempt: func() error { return nil }})
+
+	// THEN no error is returned.
+	if err != nil {
+		t.Fatalf("want nil, got %v",
+			err)
+	}
+	// AND the outbox is empty.
+	if got := len(List()); got != 0 {
+		t.Errorf("want 0 entries, got %d",
+			got)
+	}
+	// AND the entry was saved, then removed from the database.
+	if got := len(channel); got != 2 {
+		t.Fatalf("want 2 database messages, got %d",
+			got)
+	}
+	saved := <-channel
+	removed := <-channel
+	if saved.Table != dbtype.TableOutbox || saved.Delete {
+		t.Errorf("unexpected save message: %+v",
+			saved)
+	}
+	if removed.Table != dbtype.TableOutbox || !removed.Delete || removed.ServiceID != saved.ServiceID {
+		t.Errorf("unexpected remove message: %+v",
+			removed)
+	}
+}
+
+func TestDeliver_Failure(t *testing.T) {
+	// GIVEN an outbox with a DatabaseChannel.
+	channel := testDatabaseChannel(t)
+
+	// WHEN Deliver is called with a delivery that fails.
+	before := time.Now().UTC()
+	err := Deliver(
+		Entry{Kind: KindNotify, ServiceID: "svc", Target: "notify", Title: "title"},
+		Delivery{Attempt: func() error { return errors.New("unreachable") }})
+
+	// THEN the error is returned.
+	if err == nil {
+		t.Fatal("want an error, got nil")
+	}
+	// AND the entry stays pending in the outbox with a retry scheduled.
+	list := List()
+	if len(list) != 1 {
+		t.Fatalf("want 1 entry, got %d",
+			len(list))
+	}
+	entry := list[0]
+	if entry.State != StatePending || entry.Attempts != 1 || entry.LastError != "unreachable" {
+		t.Errorf("unexpected entry: %+v",
+			entry)
+	}
+	if entry.NextAttempt.Before(before.Add(baseBackoff)) || entry.NextAttempt.After(time.Now().UTC().Add(2*baseBackoff)) {
+		t.Errorf("NextAttempt %s not within backoff of %s",
+			entry.NextAttempt, before)
+	}
+	// AND the failure is saved to the database.
+	var last dbtype.Message
+	for len(channel) != 0 {
+		last = <-channel
+	}
+	var saved Entry
+	if err := json.Unmarshal([]byte(last.Cells[0].Value), &saved); err != nil {
+		t.Fatalf("failed to unmarshal saved entry: %v",
+			err)
+	}
+	if saved.ID != entry.ID || saved.Attempts != 1 || saved.Title != "title" {
+		t.Errorf("unexpected saved entry: %+v",
+			saved)
+	}
+}
+
+func TestDeliver_Shutdown(t *testing.T) {
+	// GIVEN an outbox with a DatabaseChannel.
+	channel := testDatabaseChannel(t)
+
+	// WHEN Deliver is called with a delayed delivery that is interrupted by a shutdown.
+	nextAttempt := time.Now().UTC().Add(time.Hour)
+	err := Deliver(
+		Entry{Kind: KindWebHook, ServiceID: "svc", Target: "wh", NextAttempt: nextAttempt},
+		Delivery{Attempt: func() error {
+			Stop()
+			return context.Canceled
+		}})
+
+	// THEN the cancellation is returned.
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("want %v, got %v",
+			context.Canceled, err)
+	}
+	// AND the entry stays pending in the outbox, without counting the attempt.
+	list := List()
+	if len(list) != 1 {
+		t.Fatalf("want 1 entry, got %d",
+			len(list))
+	}
+	entry := list[0]
+	if entry.State != StatePending || entry.Attempts != 0 || !entry.NextAttempt.Equal(nextAttempt) {
+		t.Errorf("unexpected entry: %+v",
+			entry)
+	}
+	// AND only the save with the delayed NextAttempt reaches the database.
+	if got := len(channel); got != 1 {
+		t.Fatalf("want 1 database message, got %d",
+			got)
+	}
+	var saved Entry
+	if err := json.Unmarshal([]byte((<-channel).Cells[0].Value), &saved); err != nil {
+		t.Fatalf("failed to unmarshal saved entry: %v",
+			err)
+	}
+	if !saved.NextAttempt.Equal(nextAttempt) {
+		t.Errorf("want NextAttempt %s, got %s",
+			nextAttempt, saved.NextAttempt)
+	}
+}
+
+func TestDeliver_FailureAfterStop(t *testing.T) {
+	// GIVEN an outbox with a DatabaseChannel.
+	testDatabaseChannel(t)
+
+	// WHEN Deliver is called with a delivery that fails after a shutdown Stop.
+	err := Deliver(
+		Entry{Kind: KindWebHook, ServiceID: "svc", Target: "wh"},
+		Delivery{Attempt: func() error {
+			Stop()
+			return errors.New("fail")
+		}})
+
+	// THEN the error is returned.
+	if err == nil || err.Error() != "fail" {
+		t.Fatalf("want %q, got %v",
+			"fail", err)
+	}
+	// AND no retry is scheduled.
+	list := List()
+	if len(list) != 1 {
+		t.Fatalf("want 1 entry, got %d",
+			len(list))
+	}
+	entriesMutex.Lock()
+	timer := entries[list[0].ID].timer
+	entriesMutex.Unlock()
+	if timer != nil {
+		t.Errorf("want no retry scheduled after Stop, got one at %s",
+			list[0].NextAttempt)
+	}
+}
+
+func TestDeliver_Cancelled(t *testing.T) {
+	// GIVEN an outbox with a DatabaseChannel.
+	channel := testDatabaseChannel(t)
+
+	// WHEN Deliver is called with a delivery that is cancelled by an edit/deletion of its Service.
+	err := Deliver(
+		Entry{Kind: KindCommand, ServiceID: "svc", Target: "0"},
+		Delivery{Attempt: func() error { return context.Canceled }})
+
+	// THEN the cancellation is returned.
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("want %v, got %v",
+			context.Canceled, err)
+	}
+	// AND the entry is dropped from the outbox.
+	if list := List(); len(list) != 0 {
+		t.Fatalf("want 0 entries, got %d",
+			len(list))
+	}
+	// AND from the database.
+	if got := len(channel); got != 2 {
+		t.Fatalf("want 2 database messages, got %d",
+			got)
+	}
+	<-channel
+	if msg := <-channel; !msg.Delete {
+		t.Errorf("want the entry deleted from the database, got %+v",
+			msg)
+	}
+}
+
+func TestStop(t *testing.T) {
+	// GIVEN an outbox with a pending entry scheduled for retry.
+	channel := testDatabaseChannel(t)
+	calls := 0
+	Restore(
+		Entry{ID: "pending", Kind: KindNotify, ServiceID: "svc", Target: "notify",
+			NextAttempt: time.Now().UTC().Add(50 * time.Millisecond)},
+		Delivery{Retry: func() error {
+			calls++
+			return nil
+		}})
+
+	// WHEN Stop is called.
+	Stop()
+
+	// THEN the scheduled attempt doesn't run.
+	time.Sleep(100 * time.Millisecond)
+	if calls != 0 {
+		t.Errorf("want 0 attempts, got %d",
+			calls)
+	}
+	// AND the entry is left in the outbox.
+	if got := len(List()); got != 1 {
+		t.Errorf("want 1 entry, got %d",
+			got)
+	}
+	// AND nothing more is persisted.
+	Retry("pending")
+	time.Sleep(10 * time.Millisecond)
+	if got := len(channel); got != 0 {
+		t.Errorf("want 0 database messages, got %d",
+			got)
+	}
+}
+
+func TestClear(t *testing.T) {
+	// GIVEN an outbox with a pending entry scheduled for retry.
+	channel := testDatabaseChannel(t)
+	calls := 0
+	Restore(
+		Entry{ID: "pending", Kind: KindNotify, ServiceID: "svc", Target: "notify",
+			NextAttempt: time.Now().UTC().Add(50 * time.Millisecond)},
+		Delivery{Retry: func() error {
+			calls++
+			return nil
+		}})
+
+	// WHEN Clear is called.
+	Clear()
+
+	// THEN the scheduled attempt doesn't run.
+	time.Sleep(100 * time.Millisecond)
+	if calls != 0 {
+		t.Errorf("want 0 attempts, got %d",
+			calls)
+	}
+	// AND the entry is removed from the outbox.
+	if got := len(List()); got != 0 {
+		t.Errorf("want 0 entries, got %d",
+			got)
+	}
+	// AND left in the database.
+	if got := len(channel); got != 0 {
+		t.Errorf("want 0 database messages, got %d",
+			got)
+	}
+}
+
+func TestEntry_Attempt_Dead(t *testing.T) {
+	// GIVEN a pending entry on its last attempt.
+	testDatabaseChannel(t)
+	entry := Entry{ID: "dead", Kind: KindCommand, ServiceID: "svc", Target: "0",
+		State: StatePending, Attempts: MaxAttempts - 1}
+	var deadErr error
+	Restore(entry, Delivery{
+		Retry: func() error { return errors.New("fail") },
+		Dead:  func(err error) { deadErr = err }})
+	entriesMutex.Lock()
+	e := entries["dead"]
+	e.stopTimer()
+	e.delivering = true
+	entriesMutex.Unlock()
+
+	// WHEN the attempt fails.
+	_ = e.attempt(e.retry, true)
+
+	// THEN the entry is dead.
+	list := List()
+	if len(list) != 1 || list[0].State != StateDead || !list[0].NextAttempt.IsZero() {
+		t.Errorf("want a dead entry, got %+v",
+			list)
+	}
+	// AND no retry is scheduled.
+	entriesMutex.RLock()
+	hasTimer := e.timer != nil
+	entriesMutex.RUnlock()
+	if hasTimer {
+		t.Error("dead entries should not be scheduled")
+	}
+	// AND the failure is handled once.
+	if deadErr == nil || deadErr.Error() != "fail" {
+		t.Errorf("want Dead called with the error, got %v",
+			deadErr)
+	}
+}
+
+func TestDeliver_Retry(t *testing.T) {
+	// GIVEN an outbox with a DatabaseChannel, and a handler for successful retries.
+	testDatabaseChannel(t)
+	succeeded := make(chan Entry, 2)
+	SetRetrySucceeded(func(entry Entry) { succeeded <- entry })
+	attempts, retries := 0, 0
+	retried := make(chan struct{}, 1)
+
+	// WHEN Deliver is called with a first attempt that fails.
+	err := Deliver(
+		Entry{Kind: KindWebHook, ServiceID: "svc", Target: "wh"},
+		Delivery{
+			Attempt: func() error {
+				attempts++
+				return errors.New("fail")
+			},
+			Retry: func() error {
+				retries++
+				retried <- struct{}{}
+				return nil
+			},
+			Dead: func(error) { t.Error("Dead called before MaxAttempts") }})
+	if err == nil {
+		t.Fatal("want an error, got nil")
+	}
+	// AND the entry is retried.
+	if err := Retry(List()[0].ID); err != nil {
+		t.Fatalf("want nil, got %v",
+			err)
+	}
+	select {
+	case <-retried:
+	case <-time.After(time.Second):
+		t.Fatal("retry not attempted")
+	}
+
+	// THEN the retry is made with Retry, not Attempt.
+	if attempts != 1 || retries != 1 {
+		t.Errorf("want 1 attempt and 1 retry, got %d and %d",
+			attempts, retries)
+	}
+	// AND the success of the retry is handled.
+	select {
+	case entry := <-succeeded:
+		if entry.Kind != KindWebHook || entry.Target != "wh" {
+			t.Errorf("unexpected entry: %+v",
+				entry)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("retry success not handled")
+	}
+}
+
+func TestDeliver_Success_NotRetry(t *testing.T) {
+	// GIVEN an outbox with a DatabaseChannel, and a handler for successful retries.
+	testDatabaseChannel(t)
+	succeeded := 0
+	SetRetrySucceeded(func(Entry) { succeeded++ })
+
+	// WHEN Deliver is called with a first attempt that succeeds.
+	err := Deliver(
+		Entry{Kind: KindCommand, ServiceID: "svc", Target: "0"},
+		Delivery{Attempt: func() error { return nil }})
+
+	// THEN it succeeds.
+	if err != nil {
+		t.Fatalf("want nil, got %v",
+			err)
+	}
+	// AND the retry success handler isn't called (the caller handles the first attempt).
+	if succeeded != 0 {
+		t.Errorf("want 0 retry success calls, got %d",
+			succeeded)
+	}
+}
+
+func TestDeliver_BlockedDatabase(t *testing.T) {
+	// GIVEN an outbox with a DatabaseChannel that isn't being read.
+	channel := make(chan dbtype.Message)
+	SetDatabaseChannel(&channel)
+	t.Cleanup(func() {
+		// Drain the messages of the blocked delivery.
+		go func() {
+			for range channel {
+			}
+		}()
+		time.Sleep(10 * time.Millisecond)
+		entriesMutex.Lock()
+		for id, e := range entries {
+			e.stopTimer()
+			delete(entries, id)
+		}
+		databaseChannel = nil
+		entriesMutex.Unlock()
+	})
+	go Deliver(
+		Entry{Kind: KindNotify, ServiceID: "svc", Target: "notify"},
+		Delivery{Attempt: func() error { return nil }})
+	time.Sleep(10 * time.Millisecond)
+
+	// WHEN the outbox is listed.
+	listed := make(chan []Entry, 1)
+	go func() { listed <- List() }()
+
+	// THEN it isn't stalled by the database.
+	select {
+	case list := <-listed:
+		if len(list) != 1 {
+			t.Errorf("want 1 entry, got %d",
+				len(list))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("List stalled by the blocked database")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	// GIVEN a dead entry.
+	testDatabaseChannel(t)
+	delivered := make(chan struct{}, 1)
+	Restore(
+		Entry{ID: "retry", State: StateDead, Attempts: MaxAttempts},
+		Delivery{Retry: func() error {
+			delivered <- struct{}{}
+			return nil
+		}})
+
+	// WHEN Retry is called for an unknown ID.
+	err := Retry("unknown")
+	// THEN ErrNotFound is returned.
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("want ErrNotFound, got %v",
+			err)
+	}
+
+	// WHEN Retry is called for the dead entry.
+	if err := Retry("retry"); err != nil {
+		t.Fatalf("want nil, got %v",
+			err)
+	}
+
+	// THEN the delivery is attempted.
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		t.Fatal("delivery not attempted")
+	}
+	// AND the entry is removed on success.
+	deadline := time.Now().Add(time.Second)
+	for len(List()) != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := len(List()); got != 0 {
+		t.Errorf("want 0 entries, got %d",
+			got)
+	}
+}
+
+func TestDiscard(t *testing.T) {
+	// GIVEN a pending entry.
+	channel := testDatabaseChannel(t)
+	Restore(
+		Entry{ID: "discard", State: StatePending, NextAttempt: time.Now().Add(time.Hour)},
+		Delivery{Retry: func() error { return nil }})
+
+	// WHEN Discard is called.
+	err := Discard("discard")
+
+	// THEN no error is returned.
+	if err != nil {
+		t.Fatalf("want nil, got %v",
+			err)
+	}
+	// AND the entry is removed from the outbox.
+	if got := len(List()); got != 0 {
+		t.Errorf("want 0 entries, got %d",
+			got)
+	}
+	// AND from the database.
+	msg := <-channel
+	if !msg.Delete || msg.ServiceID != "discard" {
+		t.Errorf("unexpected database message: %+v",
+			msg)
+	}
+
+	// WHEN Discard is called again.
+	err = Discard("discard")
+	// THEN ErrNotFound is returned.
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("want ErrNotFound, got %v",
+			err)
+	}
+}
+
+func TestRemoveService(t *testing.T) {
+	// GIVEN entries for several Services.
+	testDatabaseChannel(t)
+	for _, e := range []Entry{
+		{ID: "a", ServiceID: "keep", NextAttempt: time.Now().Add(time.Hour)},
+		{ID: "b", ServiceID: "remove", NextAttempt: time.Now().Add(time.Hour)},
+		{ID: "c", ServiceID: "remove", State: StateDead}} {
+		Restore(e, Delivery{Retry: func() error { return nil }})
+	}
+
+	// WHEN RemoveService is called.
+	RemoveService("remove")
+
+	// THEN only the entries of other Services remain.
+	list := List()
+	if len(list) != 1 || list[0].ID != "a" {
+		t.Errorf("want only entry a, got %+v",
+			list)
+	}
+}
+
+func TestRemovePendingActions(t *testing.T) {
+	// GIVEN entries of several kinds/states for a Service.
+	testDatabaseChannel(t)
+	later := time.Now().Add(time.Hour)
+	for _, e := range []Entry{
+		{ID: "notify", Kind: KindNotify, ServiceID: "svc", State: StatePending, NextAttempt: later},
+		{ID: "webhook", Kind: KindWebHook, ServiceID: "svc", State: StatePending, NextAttempt: later},
+		{ID: "command", Kind: KindCommand, ServiceID: "svc", State: StatePending, NextAttempt: later},
+		{ID: "dead", Kind: KindWebHook, ServiceID: "svc", State: StateDead},
+		{ID: "other", Kind: KindWebHook, ServiceID: "other", State: StatePending, NextAttempt: later}} {
+		Restore(e, Delivery{Retry: func() error { return nil }})
+	}
+
+	// WHEN RemovePendingActions is called for the Service.
+	RemovePendingActions("svc")
+
+	// THEN only its pending WebHook/Command entries are removed.
+	var ids []string
+	for _, e := range List() {
+		ids = append(ids, e.ID)
+	}
+	sort.Strings(ids)
+	if want := []string{"dead", "notify", "other"}; strings.Join(ids, ",") != strings.Join(want, ",") {
+		t.Errorf("want entries %v, got %v",
+			want, ids)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	// GIVEN a number of attempts.
+	tests := map[string]struct {
+		attempts int
+		min      time.Duration
+	}{
+		"first retry":  {attempts: 1, min: baseBackoff},
+		"second retry": {attempts: 2, min: 2 * baseBackoff},
+		"fourth retry": {attempts: 4, min: 8 * baseBackoff},
+		"capped":       {attempts: 50, min: maxBackoff},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN backoff is called.
+			got := backoff(tc.attempts)
+
+			// THEN the delay is the exponential backoff plus jitter.
+			if got < tc.min || got >= tc.min+baseBackoff {
+				t.Errorf("want [%s, %s), got %s",
+					tc.min, tc.min+baseBackoff, got)
+			}
+		})
+	}
+}
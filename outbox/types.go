@@ -0,0 +1,89 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package outbox provides durable delivery of notifications, webhooks and commands.
+package outbox
+
+import (
+	"sync"
+	"time"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/util"
+)
+
+var (
+	jLog *util.JLog
+
+	databaseChannel *chan dbtype.Message // Channel to persist the Entries to the database.
+
+	entriesMutex sync.RWMutex
+	entries      = map[string]*Entry{} // Entries in the outbox, keyed by ID.
+
+	retrySucceeded func(entry Entry) // Called when a retry of an Entry succeeds.
+
+	persistMutex sync.Mutex       // Lock for the persistQueue.
+	persistQueue []persistMessage // Database messages waiting to be sent, in order.
+	flushMutex   sync.Mutex       // Lock to send the persistQueue in order.
+)
+
+// Kinds of delivery.
+const (
+	KindNotify  = "notify"
+	KindWebHook = "webhook"
+	KindCommand = "command"
+)
+
+// States of an Entry.
+const (
+	StatePending = "pending" // Awaiting (re)delivery.
+	StateDead    = "dead"    // Given up on after MaxAttempts.
+)
+
+// DeliverFunc attempts a delivery, returning an error on failure.
+type DeliverFunc func() error
+
+// Delivery holds how to attempt an Entry, and how to handle it being given up on.
+type Delivery struct {
+	Attempt DeliverFunc     // First attempt (which may wait out a delay).
+	Retry   DeliverFunc     // Later attempts (without any delay).
+	Dead    func(err error) // Called with the last error when the Entry is given up on (optional).
+}
+
+// persistMessage is a database message waiting to be sent.
+type persistMessage struct {
+	channel *chan dbtype.Message // Channel to send the message to.
+	message dbtype.Message       // Message to send.
+}
+
+// Entry is a delivery in the outbox.
+type Entry struct {
+	ID          string           `json:"id"`                   // Unique ID of the Entry.
+	Kind        string           `json:"kind"`                 // KindNotify/KindWebHook/KindCommand.
+	ServiceID   string           `json:"service_id"`           // ID of the Service the delivery is for.
+	Target      string           `json:"target"`               // ID of the Notify/WebHook, or index of the Command.
+	Title       string           `json:"title,omitempty"`      // Title of the notification.
+	Message     string           `json:"message,omitempty"`    // Message of the notification.
+	ServiceInfo util.ServiceInfo `json:"service_info"`         // Context of the delivery.
+	Attempts    int              `json:"attempts"`             // Number of delivery attempts made.
+	NextAttempt time.Time        `json:"next_attempt"`         // Time of the next delivery attempt.
+	LastError   string           `json:"last_error,omitempty"` // Error of the last delivery attempt.
+	State       string           `json:"state"`                // StatePending/StateDead.
+	Created     time.Time        `json:"created"`              // Time the Entry was added to the outbox.
+
+	retry      DeliverFunc     // Function to retry the delivery.
+	dead       func(err error) // Function to handle the Entry being given up on.
+	delivering bool            // Whether a delivery attempt is in progress.
+	timer      *time.Timer     // Timer for the next delivery attempt.
+}
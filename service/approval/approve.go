@@ -0,0 +1,85 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package approval provides the approval policy a release must satisfy before the actions of a Service run.
+package approval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/util"
+)
+
+// Approve the LatestVersion as `approver`, giving the `reason`,
+// and return whether the Policy is now satisfied.
+//
+// Approving again replaces the earlier approval of the same approver.
+func (p *Policy) Approve(approver, reason string) (bool, error) {
+	approver = strings.TrimSpace(approver)
+	reason = strings.TrimSpace(reason)
+	if approver == "" {
+		return false, errors.New("approver identity required")
+	}
+	if len(p.Approvers) != 0 && !util.Contains(p.Approvers, approver) {
+		return false, fmt.Errorf("%q is not an allowed approver", approver)
+	}
+	if p.RequireReason && reason == "" {
+		return false, errors.New("a reason is required to approve")
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now().UTC()
+	// Drop the approvals that no longer count, and any earlier one of this approver.
+	approvals := p.current(now)
+	p.approvals = make([]Approval, 0, len(approvals)+1)
+	for _, approval := range approvals {
+		if approval.Approver != approver {
+			p.approvals = append(p.approvals, approval)
+		}
+	}
+	p.approvals = append(p.approvals, Approval{
+		Approver: approver,
+		Version:  p.ServiceStatus.LatestVersion(),
+		Reason:   reason,
+		Time:     now})
+	p.save()
+
+	return len(p.approvals) >= p.GetRequired(), nil
+}
+
+// save the approvals to the database (or remove them if there are none).
+func (p *Policy) save() {
+	if p.ServiceStatus == nil || p.ServiceStatus.DatabaseChannel == nil {
+		return
+	}
+
+	message := dbtype.Message{
+		Table:     dbtype.TableApproval,
+		ServiceID: *p.ServiceStatus.ServiceID}
+	if len(p.approvals) == 0 {
+		message.Delete = true
+	} else {
+		approvals, _ := json.Marshal(p.approvals)
+		message.Cells = []dbtype.Cell{
+			{Column: "approvals", Value: string(approvals)}}
+	}
+	*p.ServiceStatus.DatabaseChannel <- message
+}
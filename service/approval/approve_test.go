@@ -0,0 +1,209 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package approval
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/service/status"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+// testPolicy returns the Policy initialised with a Status at `version`.
+func testPolicy(policy *Policy, version string) (*Policy, chan dbtype.Message) {
+	databaseChannel := make(chan dbtype.Message, 16)
+	svcStatus := status.New(
+		nil, &databaseChannel, nil,
+		"", "", "", "", "", "")
+	svcStatus.Init(
+		0, 0, 0,
+		test.StringPtr("svc"), test.StringPtr("svc"),
+		test.StringPtr(""))
+	svcStatus.SetLatestVersion(version, "", false)
+	policy.Init(svcStatus)
+	return policy, databaseChannel
+}
+
+func TestPolicy_Approve(t *testing.T) {
+	// GIVEN a Policy, and the approvals given so far
+	type approve struct {
+		approver, reason string
+	}
+	tests := map[string]struct {
+		policy        *Policy
+		existing      []Approval
+		approvals     []approve
+		wantSatisfied bool
+		wantCount     int
+		errRegex      string
+	}{
+		"default policy needs one approval": {
+			policy:        &Policy{},
+			approvals:     []approve{{approver: "alice"}},
+			wantSatisfied: true,
+			wantCount:     1,
+			errRegex:      `^$`},
+		"needs distinct approvers": {
+			policy:        &Policy{Required: 2},
+			approvals:     []approve{{approver: "alice"}, {approver: "alice"}},
+			wantSatisfied: false,
+			wantCount:     1,
+			errRegex:      `^$`},
+		"satisfied by distinct approvers": {
+			policy:        &Policy{Required: 2},
+			approvals:     []approve{{approver: "alice"}, {approver: "bob"}},
+			wantSatisfied: true,
+			wantCount:     2,
+			errRegex:      `^$`},
+		"no approver identity": {
+			policy:    &Policy{},
+			approvals: []approve{{approver: " "}},
+			errRegex:  `^approver identity required$`},
+		"approver not allowed": {
+			policy:    &Policy{Approvers: []string{"alice"}},
+			approvals: []approve{{approver: "mallory"}},
+			errRegex:  `^"mallory" is not an allowed approver$`},
+		"reason required": {
+			policy:    &Policy{RequireReason: true},
+			approvals: []approve{{approver: "alice"}},
+			errRegex:  `^a reason is required to approve$`},
+		"reason given": {
+			policy:        &Policy{RequireReason: true},
+			approvals:     []approve{{approver: "alice", reason: "change #123"}},
+			wantSatisfied: true,
+			wantCount:     1,
+			errRegex:      `^$`},
+		"approvals of other versions don't count": {
+			policy: &Policy{Required: 2},
+			existing: []Approval{
+				{Approver: "bob", Version: "0.9.0", Time: time.Now().UTC()}},
+			approvals:     []approve{{approver: "alice"}},
+			wantSatisfied: false,
+			wantCount:     1,
+			errRegex:      `^$`},
+		"expired approvals don't count": {
+			policy: &Policy{Required: 2, Expiry: "1h"},
+			existing: []Approval{
+				{Approver: "bob", Version: "1.0.0", Time: time.Now().UTC().Add(-2 * time.Hour)}},
+			approvals:     []approve{{approver: "alice"}},
+			wantSatisfied: false,
+			wantCount:     1,
+			errRegex:      `^$`},
+		"unexpired approvals count": {
+			policy: &Policy{Required: 2, Expiry: "1h"},
+			existing: []Approval{
+				{Approver: "bob", Version: "1.0.0", Time: time.Now().UTC().Add(-time.Minute)}},
+			approvals:     []approve{{approver: "alice"}},
+			wantSatisfied: true,
+			wantCount:     2,
+			errRegex:      `^$`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			policy, databaseChannel := testPolicy(tc.policy, "1.0.0")
+			policy.Restore(tc.existing)
+
+			// WHEN Approve is called for each approval
+			var satisfied bool
+			var err error
+			for _, approval := range tc.approvals {
+				satisfied, err = policy.Approve(approval.approver, approval.reason)
+			}
+
+			// THEN the expected error is returned
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the Policy is satisfied only with enough approvals
+			if satisfied != tc.wantSatisfied || policy.Satisfied() != tc.wantSatisfied {
+				t.Errorf("want satisfied=%t, got %t (Satisfied()=%t)",
+					tc.wantSatisfied, satisfied, policy.Satisfied())
+			}
+			if got := len(policy.Approvals()); got != tc.wantCount {
+				t.Errorf("want %d approvals, not %d",
+					tc.wantCount, got)
+			}
+			// AND the approvals were saved to the database
+			if err != nil {
+				if len(databaseChannel) != 0 {
+					t.Errorf("want no database messages on error, got %d",
+						len(databaseChannel))
+				}
+				return
+			}
+			var msg dbtype.Message
+			for len(databaseChannel) != 0 {
+				msg = <-databaseChannel
+			}
+			var saved []Approval
+			if msg.Table != dbtype.TableApproval || msg.ServiceID != "svc" || len(msg.Cells) != 1 {
+				t.Fatalf("want the approvals saved to the %q table for %q, got %+v",
+					dbtype.TableApproval, "svc", msg)
+			}
+			json.Unmarshal([]byte(msg.Cells[0].Value), &saved)
+			if len(saved) != tc.wantCount {
+				t.Errorf("want %d approvals saved, not %d",
+					tc.wantCount, len(saved))
+			}
+		})
+	}
+}
+
+func TestPolicy_Satisfied(t *testing.T) {
+	// GIVEN a nil Policy
+	var policy *Policy
+
+	// WHEN Satisfied is called
+	got := policy.Satisfied()
+
+	// THEN it is satisfied
+	if !got {
+		t.Error("want a nil Policy to be satisfied")
+	}
+}
+
+func TestPolicy_Summary(t *testing.T) {
+	// GIVEN a Policy with approvals of the LatestVersion and an older version
+	approvedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	policy, _ := testPolicy(&Policy{Required: 2}, "1.0.0")
+	policy.Restore([]Approval{
+		{Approver: "alice", Version: "1.0.0", Reason: "looks good", Time: approvedAt},
+		{Approver: "bob", Version: "0.9.0", Time: approvedAt}})
+
+	// WHEN Summary is called
+	summary := policy.Summary()
+
+	// THEN only the approvals of the LatestVersion are summarised
+	want := `{"required":2,"approvals":[{"approver":"alice","reason":"looks good","time":"2025-01-01T00:00:00Z"}]}`
+	if got := util.ToJSONString(summary); got != want {
+		t.Errorf("want\n%s\ngot\n%s",
+			want, got)
+	}
+	// AND a nil Policy has no summary
+	if got := (*Policy)(nil).Summary(); got != nil {
+		t.Errorf("want nil summary for a nil Policy, got %+v", got)
+	}
+}
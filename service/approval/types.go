@@ -0,0 +1,160 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package approval provides the approval policy a release must satisfy before the actions of a Service run.
+package approval
+
+import (
+	"sync"
+	"time"
+
+	"github.com/release-argus/Argus/service/status"
+	apitype "github.com/release-argus/Argus/web/api/types"
+)
+
+var (
+	identitiesMutex sync.RWMutex
+	approverHeader  string // Header that identifies the approver.
+	basicAuthUser   string // Username of the basic auth (the only identity without an approverHeader).
+)
+
+// SetIdentities sets how the approvers are identified, by the `header` a trusted auth proxy sets,
+// or else by the username of the basic auth (`user`, empty = basic auth off).
+func SetIdentities(header, user string) {
+	identitiesMutex.Lock()
+	defer identitiesMutex.Unlock()
+
+	approverHeader = header
+	basicAuthUser = user
+}
+
+// identities returns the approverHeader and basicAuthUser.
+func identities() (string, string) {
+	identitiesMutex.RLock()
+	defer identitiesMutex.RUnlock()
+
+	return approverHeader, basicAuthUser
+}
+
+// Policy of approvals a release needs before the actions of its Service run.
+type Policy struct {
+	Required      int      `yaml:"required,omitempty" json:"required,omitempty"`             // Number of distinct approvers required (default = 1).
+	Approvers     []string `yaml:"approvers,omitempty" json:"approvers,omitempty"`           // Identities allowed to approve (default = anyone).
+	Expiry        string   `yaml:"expiry,omitempty" json:"expiry,omitempty"`                 // Time an approval counts for, e.g. 24h (default = until the next release).
+	RequireReason bool     `yaml:"require_reason,omitempty" json:"require_reason,omitempty"` // Whether each approval must give a reason.
+
+	mutex     sync.RWMutex // Mutex for concurrent access.
+	approvals []Approval   // Approvals given (for any version).
+
+	ServiceStatus *status.Status `yaml:"-" json:"-"` // Status of the Service (for the LatestVersion and the database).
+}
+
+// Approval of a version.
+type Approval struct {
+	Approver string    `json:"approver"`         // Identity of the approver.
+	Version  string    `json:"version"`          // Version approved.
+	Reason   string    `json:"reason,omitempty"` // Reason given for the approval.
+	Time     time.Time `json:"time"`             // Time of the approval.
+}
+
+// Init the Policy with the Service's Status.
+func (p *Policy) Init(serviceStatus *status.Status) {
+	if p == nil {
+		return
+	}
+
+	p.ServiceStatus = serviceStatus
+}
+
+// GetRequired returns the number of distinct approvers required.
+func (p *Policy) GetRequired() int {
+	if p.Required == 0 {
+		return 1
+	}
+
+	return p.Required
+}
+
+// GetExpiryDuration returns the Expiry as a time.Duration (0 = never expires).
+func (p *Policy) GetExpiryDuration() time.Duration {
+	expiry, _ := time.ParseDuration(p.Expiry)
+	return expiry
+}
+
+// Restore the `approvals` (e.g. from the database).
+func (p *Policy) Restore(approvals []Approval) {
+	if p == nil {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.approvals = approvals
+}
+
+// Approvals returns the approvals that count towards the LatestVersion.
+func (p *Policy) Approvals() []Approval {
+	if p == nil {
+		return nil
+	}
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.current(time.Now().UTC())
+}
+
+// current returns the approvals of the LatestVersion that haven't expired at `now`.
+func (p *Policy) current(now time.Time) []Approval {
+	version := p.ServiceStatus.LatestVersion()
+	expiry := p.GetExpiryDuration()
+
+	approvals := make([]Approval, 0, len(p.approvals))
+	for _, approval := range p.approvals {
+		if approval.Version != version ||
+			(expiry != 0 && now.Sub(approval.Time) > expiry) {
+			continue
+		}
+		approvals = append(approvals, approval)
+	}
+	return approvals
+}
+
+// Satisfied returns whether enough approvals have been given for the LatestVersion.
+func (p *Policy) Satisfied() bool {
+	if p == nil {
+		return true
+	}
+
+	return len(p.Approvals()) >= p.GetRequired()
+}
+
+// Summary returns the API summary of the approvals of the LatestVersion.
+func (p *Policy) Summary() *apitype.ApprovalSummary {
+	if p == nil {
+		return nil
+	}
+
+	approvals := p.Approvals()
+	summary := &apitype.ApprovalSummary{
+		Required:  p.GetRequired(),
+		Approvals: make([]apitype.ApprovalRecord, len(approvals))}
+	for i, approval := range approvals {
+		summary.Approvals[i] = apitype.ApprovalRecord{
+			Approver: approval.Approver,
+			Reason:   approval.Reason,
+			Time:     approval.Time}
+	}
+
+	return summary
+}
@@ -0,0 +1,83 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package approval provides the approval policy a release must satisfy before the actions of a Service run.
+package approval
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/release-argus/Argus/util"
+)
+
+// CheckValues validates the fields of the Policy.
+func (p *Policy) CheckValues(prefix string) error {
+	if p == nil {
+		return nil
+	}
+
+	var errs []error
+	// required
+	if p.Required < 0 {
+		errs = append(errs,
+			fmt.Errorf("%srequired: %d <invalid> (must be positive)",
+				prefix, p.Required))
+	} else if len(p.Approvers) != 0 && p.GetRequired() > len(p.Approvers) {
+		errs = append(errs,
+			fmt.Errorf("%srequired: %d <invalid> (more than the %d approvers allowed)",
+				prefix, p.Required, len(p.Approvers)))
+	}
+	// approvers
+	seen := make(map[string]bool, len(p.Approvers))
+	for _, approver := range p.Approvers {
+		if approver == "" || seen[approver] {
+			errs = append(errs,
+				fmt.Errorf("%sapprovers: %q <invalid> (must be non-empty and unique)",
+					prefix, approver))
+		}
+		seen[approver] = true
+	}
+	// identities
+	header, user := identities()
+	if header == "" {
+		if user == "" {
+			errs = append(errs,
+				fmt.Errorf("%sapprovers: <invalid> (approvers can't be identified, set web.basic_auth, or web.approver_header behind an auth proxy)",
+					prefix))
+		} else if p.GetRequired() > 1 {
+			errs = append(errs,
+				fmt.Errorf("%srequired: %d <invalid> (basic auth has a single user, set web.approver_header behind an auth proxy for more approvers)",
+					prefix, p.Required))
+		} else if len(p.Approvers) != 0 && !util.Contains(p.Approvers, user) {
+			errs = append(errs,
+				fmt.Errorf("%sapprovers: %v <invalid> (doesn't include the basic auth user %q)",
+					prefix, p.Approvers, user))
+		}
+	}
+	// expiry
+	if p.Expiry != "" {
+		if expiry, err := time.ParseDuration(p.Expiry); err != nil || expiry <= 0 {
+			errs = append(errs,
+				fmt.Errorf("%sexpiry: %q <invalid> (Use 'AhBmCs' duration format)",
+					prefix, p.Expiry))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
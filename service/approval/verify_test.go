@@ -0,0 +1,117 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package approval
+
+import (
+	"testing"
+
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestPolicy_CheckValues(t *testing.T) {
+	// GIVEN a Policy
+	tests := map[string]struct {
+		header, user string
+		policy       *Policy
+		errRegex     string
+	}{
+		"nil policy": {
+			header:   "X-Forwarded-User",
+			policy:   nil,
+			errRegex: `^$`},
+		"valid policy": {
+			header: "X-Forwarded-User",
+			policy: &Policy{
+				Required:      2,
+				Approvers:     []string{"alice", "bob"},
+				Expiry:        "24h",
+				RequireReason: true},
+			errRegex: `^$`},
+		"empty policy": {
+			header:   "X-Forwarded-User",
+			policy:   &Policy{},
+			errRegex: `^$`},
+		"negative required": {
+			header: "X-Forwarded-User",
+			policy: &Policy{
+				Required: -1},
+			errRegex: `^required: -1 <invalid>.*$`},
+		"more required than approvers": {
+			header: "X-Forwarded-User",
+			policy: &Policy{
+				Required:  3,
+				Approvers: []string{"alice", "bob"}},
+			errRegex: `^required: 3 <invalid> \(more than the 2 approvers allowed\)$`},
+		"duplicate and empty approvers": {
+			header: "X-Forwarded-User",
+			policy: &Policy{
+				Approvers: []string{"alice", "", "alice"}},
+			errRegex: test.TrimYAML(`
+				^approvers: "" <invalid>.*
+				approvers: "alice" <invalid>.*$`)},
+		"invalid expiry": {
+			header: "X-Forwarded-User",
+			policy: &Policy{
+				Expiry: "a day"},
+			errRegex: `^expiry: "a day" <invalid>.*$`},
+		"no identities": {
+			policy:   &Policy{},
+			errRegex: `^approvers: <invalid> \(approvers can't be identified.*\)$`},
+		"basic auth, one approval": {
+			user: "alice",
+			policy: &Policy{
+				Approvers: []string{"alice", "bob"}},
+			errRegex: `^$`},
+		"basic auth, more than one approval required": {
+			user: "alice",
+			policy: &Policy{
+				Required:  2,
+				Approvers: []string{"alice", "bob"}},
+			errRegex: `^required: 2 <invalid> \(basic auth has a single user.*\)$`},
+		"basic auth user not an approver": {
+			user: "carol",
+			policy: &Policy{
+				Approvers: []string{"alice", "bob"}},
+			errRegex: `^approvers: \[alice bob\] <invalid> \(doesn't include the basic auth user "carol"\)$`},
+		"header and basic auth": {
+			header: "X-Forwarded-User",
+			user:   "carol",
+			policy: &Policy{
+				Required:  2,
+				Approvers: []string{"alice", "bob"}},
+			errRegex: `^$`},
+	}
+	t.Cleanup(func() { SetIdentities("", "") })
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Identities are global, so not parallel.
+			SetIdentities(tc.header, tc.user)
+
+			// WHEN CheckValues is called
+			err := tc.policy.CheckValues("")
+
+			// THEN the expected error is returned
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for:\n%q\nnot:\n%q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
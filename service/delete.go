@@ -22,6 +22,7 @@ import (
 // PrepDelete removes all channels and sets the deleting flag to prepare a service for deletion.
 func (s *Service) PrepDelete(removeFromDB bool) {
 	s.Status.SetDeleting()
+	s.actionQueue.Cancel()
 
 	// Set the channels to nil to prevent the service from triggering further events.
 	s.Status.AnnounceChannel = nil
@@ -33,6 +34,12 @@ func (s *Service) PrepDelete(removeFromDB bool) {
 		*s.HardDefaults.Status.DatabaseChannel <- dbtype.Message{
 			ServiceID: s.ID,
 			Delete:    true}
+		if s.Approval != nil {
+			*s.HardDefaults.Status.DatabaseChannel <- dbtype.Message{
+				Table:     dbtype.TableApproval,
+				ServiceID: s.ID,
+				Delete:    true}
+		}
 	}
 
 	s.deleteMetrics()
@@ -18,7 +18,9 @@ package deployedver
 import (
 	"io"
 	"strings"
+	"time"
 
+	opt "github.com/release-argus/Argus/service/option"
 	"github.com/release-argus/Argus/util"
 )
 
@@ -30,6 +32,39 @@ func (l *Lookup) GetAllowInvalidCerts() bool {
 		l.HardDefaults.AllowInvalidCerts)
 }
 
+// GetSchedule returns the cron schedule of the queries ("" if an interval is used instead),
+// falling back to that of the Service.
+func (l *Lookup) GetSchedule() string {
+	for _, base := range []*Base{&l.Base, &l.Defaults.Base, &l.HardDefaults.Base} {
+		if base.Schedule != "" {
+			return base.Schedule
+		}
+		if base.Interval != "" {
+			return ""
+		}
+	}
+	return l.Options.GetSchedule()
+}
+
+// GetIntervalDuration returns the interval between queries, falling back to that of the Service.
+func (l *Lookup) GetIntervalDuration() time.Duration {
+	d, _ := time.ParseDuration(util.FirstNonDefault(
+		l.Interval,
+		l.Defaults.Interval,
+		l.HardDefaults.Interval,
+		l.Options.GetInterval()))
+	return d
+}
+
+// nextQuery returns when the query after one at `last` is due.
+func (l *Lookup) nextQuery(last time.Time) time.Time {
+	return opt.NextQuery(
+		l.GetSchedule(),
+		l.GetIntervalDuration(),
+		l.Options.GetJitterDuration(),
+		last)
+}
+
 // GetURL will return the URL of the Lookup.
 func (l *Lookup) GetURL() string {
 	return util.EvalEnvVars(l.URL)
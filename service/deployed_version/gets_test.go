@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/release-argus/Argus/test"
 )
@@ -67,6 +68,76 @@ func TestLookup_GetAllowInvalidCerts(t *testing.T) {
 	}
 }
 
+func TestLookup_GetSchedule(t *testing.T) {
+	// GIVEN a Lookup, and a Service with a schedule
+	tests := map[string]struct {
+		rootInterval, rootSchedule, defaultSchedule string
+		want                                        string
+		wantInterval                                time.Duration
+	}{
+		"falls back to the Service": {
+			want:         "0 0 * * *",
+			wantInterval: 10 * time.Minute},
+		"root schedule": {
+			rootSchedule:    "0 * * * *",
+			defaultSchedule: "0 */2 * * *",
+			want:            "0 * * * *",
+			wantInterval:    10 * time.Minute},
+		"default schedule": {
+			defaultSchedule: "0 */2 * * *",
+			want:            "0 */2 * * *",
+			wantInterval:    10 * time.Minute},
+		"root interval takes precedence over the schedules": {
+			rootInterval:    "1h",
+			defaultSchedule: "0 */2 * * *",
+			want:            "",
+			wantInterval:    time.Hour},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookup()
+			lookup.Options.Schedule = "0 0 * * *"
+			lookup.Interval = tc.rootInterval
+			lookup.Schedule = tc.rootSchedule
+			lookup.Defaults.Schedule = tc.defaultSchedule
+
+			// WHEN GetSchedule and GetIntervalDuration are called
+			got := lookup.GetSchedule()
+			gotInterval := lookup.GetIntervalDuration()
+
+			// THEN the functions return the correct results
+			if got != tc.want {
+				t.Errorf("schedule\nwant: %q\ngot:  %q",
+					tc.want, got)
+			}
+			if gotInterval != tc.wantInterval {
+				t.Errorf("interval\nwant: %s\ngot:  %s",
+					tc.wantInterval, gotInterval)
+			}
+		})
+	}
+}
+
+func TestLookup_nextQuery(t *testing.T) {
+	// GIVEN a Lookup with its own interval, and a Service with a different one
+	lookup := testLookup()
+	lookup.Options.Interval = "10m"
+	lookup.Interval = "1h"
+	last := time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC)
+
+	// WHEN nextQuery is called
+	got := lookup.nextQuery(last)
+
+	// THEN the interval of the Lookup is used
+	if want := last.Add(time.Hour); !got.Equal(want) {
+		t.Errorf("want: %s\ngot:  %s",
+			want, got)
+	}
+}
+
 func TestLookup_GetURL(t *testing.T) {
 	// GIVEN a Lookup
 	tests := map[string]struct {
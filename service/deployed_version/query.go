@@ -16,7 +16,7 @@
 package deployedver
 
 import (
-	"crypto/tls"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -29,8 +29,8 @@ import (
 	"github.com/release-argus/Argus/web/metric"
 )
 
-// Track the deployed version (DeployedVersion) of the `parent`.
-func (l *Lookup) Track() {
+// Track the deployed version (DeployedVersion) of the `parent` until `ctx` is done.
+func (l *Lookup) Track(ctx context.Context) {
 	if l == nil {
 		return
 	}
@@ -44,17 +44,31 @@ func (l *Lookup) Track() {
 		}
 
 		// Query the deployed version.
-		deployedVersion, _ := l.Query(true, logFrom)
+		deployedVersion, err := l.Query(ctx, true, logFrom)
+		backoff := &l.Status.Backoffs.DeployedVersion
+		switch {
+		case err == nil:
+			backoff.Reset()
+		case ctx.Err() == nil:
+			backoff.Fail()
+			jLog.Verbose(
+				fmt.Sprintf("%d consecutive deployed_version failures, backing off until %s",
+					backoff.Failures(), backoff.Until().Format(time.RFC3339)),
+				logFrom,
+				true)
+		}
 		// If new release found by ^ query.
 		l.HandleNewVersion(deployedVersion, true)
-		// Sleep interval between queries.
-		time.Sleep(l.Options.GetIntervalDuration())
+		// Sleep until the next query (backing off after failures).
+		if !util.Sleep(ctx, time.Until(backoff.Next(l.nextQuery(time.Now())))) {
+			return
+		}
 	}
 }
 
 // query the deployed version (DeployedVersion) of the Service.
-func (l *Lookup) query(logFrom util.LogFrom) (string, error) {
-	body, err := l.httpRequest(logFrom)
+func (l *Lookup) query(ctx context.Context, logFrom util.LogFrom) (string, error) {
+	body, err := l.httpRequest(ctx, logFrom)
 	if err != nil {
 		return "", err
 	}
@@ -106,9 +120,9 @@ func (l *Lookup) query(logFrom util.LogFrom) (string, error) {
 	return version, nil
 }
 
-// Query the deployed version (DeployedVersion) of the Service.
-func (l *Lookup) Query(metrics bool, logFrom util.LogFrom) (string, error) {
-	version, err := l.query(logFrom)
+// Query the deployed version (DeployedVersion) of the Service (cancelled with `ctx`).
+func (l *Lookup) Query(ctx context.Context, metrics bool, logFrom util.LogFrom) (string, error) {
+	version, err := l.query(ctx, logFrom)
 
 	if metrics {
 		l.queryMetrics(err == nil)
@@ -180,17 +194,18 @@ func (l *Lookup) HandleNewVersion(version string, writeToDB bool) {
 }
 
 // httpRequest sends an HTTP request to the URL and returns the response body.
-func (l *Lookup) httpRequest(logFrom util.LogFrom) ([]byte, error) {
-	// HTTPS insecure skip verify.
-	customTransport := &http.Transport{}
-	if l.GetAllowInvalidCerts() {
-		customTransport = http.DefaultTransport.(*http.Transport).Clone()
-		//#nosec G402 -- explicitly wanted InsecureSkipVerify.
-		customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+func (l *Lookup) httpRequest(ctx context.Context, logFrom util.LogFrom) ([]byte, error) {
+	client, err := util.NewHTTPClient(l.GetAllowInvalidCerts(),
+		&l.HTTPClientOptions,
+		&l.Defaults.HTTPClientOptions,
+		&l.HardDefaults.HTTPClientOptions)
+	if err != nil {
+		jLog.Error(err, logFrom, true)
+		return nil, err //nolint:wrapcheck
 	}
 
 	// Create the request.
-	req, err := http.NewRequest(l.Method, l.GetURL(), l.GetBody())
+	req, err := http.NewRequestWithContext(ctx, l.Method, l.GetURL(), l.GetBody())
 	if err != nil {
 		jLog.Error(err, logFrom, true)
 		return nil, err //nolint:wrapcheck
@@ -206,7 +221,6 @@ func (l *Lookup) httpRequest(logFrom util.LogFrom) ([]byte, error) {
 	}
 
 	// Send the request.
-	client := &http.Client{Transport: customTransport}
 	resp, err := client.Do(req)
 	if err != nil {
 		// Don't crash on invalid certs.
@@ -221,6 +235,10 @@ func (l *Lookup) httpRequest(logFrom util.LogFrom) ([]byte, error) {
 
 	// Ignore non-2XX responses.
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Honour the Retry-After of 429 Too Many Requests/503 Service Unavailable.
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			l.Status.Backoffs.DeployedVersion.SetRetryAfter(util.RetryAfter(resp.Header, time.Now()))
+		}
 		err = fmt.Errorf("non-2XX response code: %d", resp.StatusCode)
 		jLog.Warn(err, logFrom, true)
 		return nil, err
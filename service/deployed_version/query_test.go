@@ -17,6 +17,10 @@
 package deployedver
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -72,7 +76,7 @@ func TestLookup_HTTPRequest(t *testing.T) {
 			lookup.URL = tc.url
 
 			// WHEN httpRequest is called on it.
-			_, err := lookup.httpRequest(util.LogFrom{})
+			_, err := lookup.httpRequest(context.Background(), util.LogFrom{})
 
 			// THEN any err is expected.
 			e := util.ErrorToString(err)
@@ -84,6 +88,60 @@ func TestLookup_HTTPRequest(t *testing.T) {
 	}
 }
 
+func TestLookup_HTTPRequest_RetryAfter(t *testing.T) {
+	// GIVEN a Lookup of a server that is unavailable, asking to retry after a time
+	tests := map[string]struct {
+		statusCode int
+		retryAfter time.Time
+	}{
+		"429 Too Many Requests": {
+			statusCode: http.StatusTooManyRequests,
+			retryAfter: time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"503 Service Unavailable": {
+			statusCode: http.StatusServiceUnavailable,
+			retryAfter: time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"500 Internal Server Error - ignored": {
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "Fri, 01 Jan 2100 00:00:00 GMT")
+				w.WriteHeader(tc.statusCode)
+			}))
+			t.Cleanup(server.Close)
+			lookup := testLookup()
+			lookup.URL = server.URL
+
+			// WHEN httpRequest is called on it.
+			_, err := lookup.httpRequest(context.Background(), util.LogFrom{})
+
+			// THEN it fails.
+			errRegex := fmt.Sprintf(`^non-2XX response code: %d$`, tc.statusCode)
+			if e := util.ErrorToString(err); !util.RegexCheck(errRegex, e) {
+				t.Errorf("want match for %q\nnot: %q",
+					errRegex, e)
+			}
+			// AND the backoff after this failure honours the Retry-After (when asked to).
+			backoff := &lookup.Status.Backoffs.DeployedVersion
+			backoff.Fail()
+			if !tc.retryAfter.IsZero() && !backoff.Until().Equal(tc.retryAfter) {
+				t.Errorf("backoff mismatch\nwant: %s\ngot:  %s",
+					tc.retryAfter, backoff.Until())
+			} else if tc.retryAfter.IsZero() && backoff.Until().After(time.Now().Add(time.Hour)) {
+				t.Errorf("backoff should not have honoured the Retry-After, got %s",
+					backoff.Until())
+			}
+		})
+	}
+}
+
 func TestLookup_Query(t *testing.T) {
 	// GIVEN a Lookup.
 	tests := map[string]struct {
@@ -264,7 +322,7 @@ func TestLookup_Query(t *testing.T) {
 			}
 
 			// WHEN Query is called on it.
-			version, err := dvl.Query(true, util.LogFrom{})
+			version, err := dvl.Query(context.Background(), true, util.LogFrom{})
 
 			// THEN any err is expected.
 			if tc.wantVersion != "" {
@@ -514,7 +572,7 @@ func TestLookup_Track(t *testing.T) {
 
 			// WHEN CheckValues is called on it.
 			go func() {
-				tc.lookup.Track()
+				tc.lookup.Track(context.Background())
 				didFinish <- true
 			}()
 
@@ -16,6 +16,7 @@
 package deployedver
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -24,8 +25,9 @@ import (
 )
 
 // Refresh creates a new Lookup instance (if overrides are provided), and queries the Lookup for the deployed version
-// and returns that version.
+// and returns that version (cancelled with `ctx`).
 func (l *Lookup) Refresh(
+	ctx context.Context,
 	serviceID *string,
 	overrides *string,
 	semanticVersioning *string,
@@ -68,7 +70,7 @@ func (l *Lookup) Refresh(
 	}
 
 	// Query the lookup.
-	version, err := lookup.Query(!usingOverrides, logFrom)
+	version, err := lookup.Query(ctx, !usingOverrides, logFrom)
 	if err != nil {
 		return "", err
 	}
@@ -17,6 +17,7 @@
 package deployedver
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -27,7 +28,7 @@ import (
 
 func TestLookup_Refresh(t *testing.T) {
 	testL := testLookup()
-	testVersion, _ := testL.Query(true, util.LogFrom{Primary: "TestLookup_Refresh"})
+	testVersion, _ := testL.Query(context.Background(), true, util.LogFrom{Primary: "TestLookup_Refresh"})
 	if testVersion == "" {
 		t.Fatalf("test version is empty")
 	}
@@ -133,6 +134,7 @@ func TestLookup_Refresh(t *testing.T) {
 
 			// WHEN we call Refresh
 			got, err := lookup.Refresh(
+				context.Background(),
 				serviceID,
 				tc.args.overrides,
 				tc.args.semanticVersioning)
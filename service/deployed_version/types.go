@@ -31,7 +31,11 @@ var (
 
 // Base is the base struct for the Lookup struct.
 type Base struct {
-	AllowInvalidCerts *bool `yaml:"allow_invalid_certs,omitempty" json:"allow_invalid_certs,omitempty"` // Default - false = Disallows invalid HTTPS certificates.
+	AllowInvalidCerts *bool  `yaml:"allow_invalid_certs,omitempty" json:"allow_invalid_certs,omitempty"` // Default - false = Disallows invalid HTTPS certificates.
+	Interval          string `yaml:"interval,omitempty" json:"interval,omitempty"`                       // AhBmCs between queries (default = the interval/schedule of the Service).
+	Schedule          string `yaml:"schedule,omitempty" json:"schedule,omitempty"`                       // Cron expression of the query times (takes precedence over interval).
+
+	util.HTTPClientOptions `yaml:",inline" json:",inline"` // Timeout, CA bundle and client certificate.
 }
 
 // Defaults are the default values for the Lookup struct.
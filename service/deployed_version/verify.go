@@ -21,9 +21,41 @@ import (
 	"regexp"
 	"strings"
 
+	opt "github.com/release-argus/Argus/service/option"
 	"github.com/release-argus/Argus/util"
 )
 
+// CheckValues validates the fields of the Defaults struct.
+func (ld *Defaults) CheckValues(prefix string) error {
+	if ld == nil {
+		return nil
+	}
+
+	return ld.Base.checkValues(prefix)
+}
+
+// checkValues validates the fields of the Base struct.
+func (b *Base) checkValues(prefix string) error {
+	var errs []error
+	// interval
+	if err := opt.CheckInterval(prefix, "interval", &b.Interval); err != nil {
+		errs = append(errs, err)
+	}
+	// schedule
+	if err := opt.CheckSchedule(prefix, b.Schedule); err != nil {
+		errs = append(errs, err)
+	}
+	// timeout/ca_file/cert_file/key_file
+	if err := b.HTTPClientOptions.CheckValues(prefix); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
 // CheckValues validates the fields of the Lookup struct.
 func (l *Lookup) CheckValues(prefix string) error {
 	if l == nil {
@@ -76,6 +108,11 @@ func (l *Lookup) CheckValues(prefix string) error {
 		l.RegexTemplate = ""
 	}
 
+	// interval/schedule/timeout/ca_file/cert_file/key_file
+	if baseErrs := l.Base.checkValues(prefix); baseErrs != nil {
+		errs = append(errs, baseErrs)
+	}
+
 	if len(errs) == 0 {
 		return nil
 	}
@@ -30,6 +30,8 @@ func TestLookup_CheckValues(t *testing.T) {
 		body                 string
 		json                 string
 		regex, regexTemplate string
+		timeout              string
+		interval, schedule   string
 		defaults             *Defaults
 		errRegex             string
 		nilService           bool
@@ -104,6 +106,42 @@ func TestLookup_CheckValues(t *testing.T) {
 			regexTemplate: "$1.$2.$3",
 			defaults:      &Defaults{},
 		},
+		"timeout - invalid": {
+			errRegex: `^timeout: "10x" <invalid>`,
+			method:   "GET",
+			url:      "https://example.com",
+			timeout:  "10x",
+			defaults: &Defaults{},
+		},
+		"timeout - valid": {
+			errRegex: `^$`,
+			method:   "GET",
+			url:      "https://example.com",
+			timeout:  "10s",
+			defaults: &Defaults{},
+		},
+		"interval - invalid": {
+			errRegex: `^interval: "10x" <invalid>`,
+			method:   "GET",
+			url:      "https://example.com",
+			interval: "10x",
+			defaults: &Defaults{},
+		},
+		"schedule - invalid": {
+			errRegex: `^schedule: "0 25 \* \* \*" <invalid> \(hour: "25" must be 0-23\)$`,
+			method:   "GET",
+			url:      "https://example.com",
+			schedule: "0 25 * * *",
+			defaults: &Defaults{},
+		},
+		"interval and schedule - valid": {
+			errRegex: `^$`,
+			method:   "GET",
+			url:      "https://example.com",
+			interval: "1h",
+			schedule: "0 */6 * * *",
+			defaults: &Defaults{},
+		},
 		"all errs": {
 			errRegex: `url: <required>`,
 			method:   "GET",
@@ -131,6 +169,9 @@ func TestLookup_CheckValues(t *testing.T) {
 			lookup.JSON = tc.json
 			lookup.Regex = tc.regex
 			lookup.RegexTemplate = tc.regexTemplate
+			lookup.Timeout = tc.timeout
+			lookup.Interval = tc.interval
+			lookup.Schedule = tc.schedule
 			lookup.Defaults = nil
 			if tc.defaults != nil {
 				lookup.Defaults = tc.defaults
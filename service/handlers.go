@@ -20,15 +20,19 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/release-argus/Argus/outbox"
 	"github.com/release-argus/Argus/util"
 	"github.com/release-argus/Argus/webhook"
 )
 
 // HandleSkip will set `version` to skipped and announce it to the websocket.
 func (s *Service) HandleSkip() {
+	s.actionQueue.Cancel()
 	// Ignore skips if latest_version is deployed.
 	if s.Status.DeployedVersion() != s.Status.LatestVersion() {
 		s.Status.SetApprovedVersion("SKIP_"+s.Status.LatestVersion(), true)
+		// Drop the retries of the WebHooks/Commands of the skipped version.
+		outbox.RemovePendingActions(s.ID)
 	}
 }
 
@@ -47,7 +51,7 @@ func (s *Service) HandleCommand(command string) {
 	}
 
 	// Send the Command.
-	err = (*s.CommandController).ExecIndex(util.LogFrom{Primary: "Command", Secondary: s.ID}, index)
+	err = (*s.CommandController).ExecIndex(s.Status.Context(), util.LogFrom{Primary: "Command", Secondary: s.ID}, index)
 	if err == nil {
 		s.UpdatedVersion(true)
 	}
@@ -66,7 +70,7 @@ func (s *Service) HandleWebHook(webhookID string) {
 	}
 
 	// Send the WebHook.
-	err := s.WebHook[webhookID].Send(s.ServiceInfo(), false)
+	err := s.WebHook[webhookID].Send(s.Status.Context(), s.ServiceInfo(), false)
 	if err == nil {
 		s.UpdatedVersion(true)
 	}
@@ -75,35 +79,21 @@ func (s *Service) HandleWebHook(webhookID string) {
 // HandleUpdateActions runs all commands and send all WebHooks for this service if auto-approve true.
 // If new releases not auto-approved, then these will
 // only run/send if manually triggered fromUser (via the WebUI).
+// An approval policy always waits for its approvals, even with auto-approve,
+// and auto-approved actions wait for the change window to open.
+//
+// With a Pipeline, its Stages run in order instead (and send their Notify(s) in turn).
 func (s *Service) HandleUpdateActions(writeToDB bool) {
 	serviceInfo := s.ServiceInfo()
 
 	// Send the Notify Message(s).
 	//nolint:errcheck
-	go s.Notify.Send("", "", serviceInfo, true)
+	go s.notifiersOutsidePipeline().Send(s.Status.Context(), "", "", serviceInfo, true)
 
 	//nolint:typecheck
-	if s.WebHook != nil || s.Command != nil {
-		if s.Dashboard.GetAutoApprove() {
-			msg := fmt.Sprintf("Sending WebHooks/Running Commands for %q",
-				s.Status.LatestVersion())
-			jLog.Info(msg, util.LogFrom{Primary: s.ID}, true)
-
-			// Run the Command(s).
-			go func() {
-				err := s.CommandController.Exec(util.LogFrom{Primary: "Command", Secondary: s.ID})
-				if err == nil && len(s.Command) != 0 {
-					s.UpdatedVersion(writeToDB)
-				}
-			}()
-
-			// Send the WebHook(s).
-			go func() {
-				err := s.WebHook.Send(serviceInfo, true)
-				if err == nil && len(s.WebHook) != 0 {
-					s.UpdatedVersion(writeToDB)
-				}
-			}()
+	if s.WebHook != nil || s.Command != nil || s.Pipeline != nil {
+		if s.Dashboard.GetAutoApprove() && s.Approval.Satisfied() {
+			s.runOrQueueActions(serviceInfo, writeToDB)
 		} else {
 			jLog.Info("Waiting for approval on the Web UI", util.LogFrom{Primary: s.ID}, true)
 
@@ -115,12 +105,54 @@ func (s *Service) HandleUpdateActions(writeToDB bool) {
 	}
 }
 
+// runUpdateActions runs all Commands and sends all WebHooks (or runs the Pipeline)
+// for the LatestVersion.
+func (s *Service) runUpdateActions(serviceInfo util.ServiceInfo, writeToDB bool) {
+	// Run the Pipeline.
+	if s.Pipeline != nil {
+		go s.runPipeline(writeToDB)
+		return
+	}
+
+	msg := fmt.Sprintf("Sending WebHooks/Running Commands for %q",
+		s.Status.LatestVersion())
+	jLog.Info(msg, util.LogFrom{Primary: s.ID}, true)
+	ctx := s.Status.Context()
+
+	// Run the Command(s).
+	go func() {
+		err := s.CommandController.Exec(ctx, util.LogFrom{Primary: "Command", Secondary: s.ID})
+		if err == nil && len(s.Command) != 0 {
+			s.UpdatedVersion(writeToDB)
+		}
+	}()
+
+	// Send the WebHook(s).
+	go func() {
+		err := s.WebHook.Send(ctx, serviceInfo, true)
+		if err == nil && len(s.WebHook) != 0 {
+			s.UpdatedVersion(writeToDB)
+		}
+	}()
+}
+
 // HandleFailedActions will re-send all the WebHooks for this service
 // that have either failed, or not sent for this version. Otherwise,
 // if all WebHooks have sent successfully, then they will all resend.
+//
+// With a Pipeline, the Pipeline is re-run from its first Stage.
 func (s *Service) HandleFailedActions() {
+	// Run now, replacing any retries waiting in the outbox.
+	outbox.RemovePendingActions(s.ID)
+
+	if s.Pipeline != nil {
+		s.runPipeline(true)
+		return
+	}
+
 	errChan := make(chan error, len(s.WebHook)+len(s.Command))
 	errored := false
+	ctx := s.Status.Context()
 
 	retryAll := s.shouldRetryAll()
 
@@ -137,7 +169,7 @@ func (s *Service) HandleFailedActions() {
 				}
 				// Send.
 				go func(wh *webhook.WebHook) {
-					err := wh.Send(s.ServiceInfo(), false)
+					err := wh.Send(ctx, s.ServiceInfo(), false)
 					errChan <- err
 				}(wh)
 				// Space out WebHooks.
@@ -161,7 +193,7 @@ func (s *Service) HandleFailedActions() {
 				}
 				// Run.
 				go func(key int) {
-					err := s.CommandController.ExecIndex(logFrom, key)
+					err := s.CommandController.ExecIndex(ctx, logFrom, key)
 					errChan <- err
 				}(key)
 				// Space out Commands.
@@ -220,6 +252,13 @@ func (s *Service) UpdatedVersion(writeToDB bool) {
 	if !s.Status.Fails.Command.AllPassed() {
 		return
 	}
+
+	s.updateVersion(writeToDB)
+}
+
+// updateVersion sets `s.Status.DeployedVersion` to `s.Status.LatestVersion`
+// (or approves it if there's a DeployedVersionLookup) and announces the change.
+func (s *Service) updateVersion(writeToDB bool) {
 	// Do not update DeployedVersion to LatestVersion if we have a deployed lookup check.
 	if s.DeployedVersionLookup != nil {
 		if len(s.Command) != 0 || len(s.WebHook) != 0 {
@@ -227,6 +266,10 @@ func (s *Service) UpdatedVersion(writeToDB bool) {
 			// (only having `deployed_version`, `command` or `webhook` would only use ApprovedVersion to track skips)
 			// They should have all ran/sent successfully at this point.
 			s.UpdateLatestApproved()
+			// Verify they deployed it.
+			if s.Rollback != nil {
+				go s.verifyDeployment(writeToDB)
+			}
 		}
 		return
 	}
@@ -238,7 +281,13 @@ func (s *Service) UpdatedVersion(writeToDB bool) {
 
 // UpdateLatestApproved will check if all WebHook(s) have sent successfully for this Service,
 // set the LatestVersion as approved in the Status, and announce the approval (if not previously).
+//
+// The LatestVersion isn't approved until the approval policy is satisfied.
 func (s *Service) UpdateLatestApproved() {
+	if !s.Approval.Satisfied() {
+		return
+	}
+
 	// Only announce once.
 	lv := s.Status.LatestVersion()
 	if s.Status.ApprovedVersion() != lv {
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/release-argus/Argus/command"
+	"github.com/release-argus/Argus/service/approval"
 	deployedver "github.com/release-argus/Argus/service/deployed_version"
 	"github.com/release-argus/Argus/test"
 	"github.com/release-argus/Argus/webhook"
@@ -973,16 +974,35 @@ func TestService_UpdateLatestApproved(t *testing.T) {
 	tests := map[string]struct {
 		latestVersion        string
 		startApprovedVersion string
+		approval             *approval.Policy
+		approvers            []string
+		wantApproved         bool
 		wantAnnounces        int
 	}{
 		"empty ApprovedVersion does announce": {
 			startApprovedVersion: "",
 			latestVersion:        "1.2.3",
+			wantApproved:         true,
 			wantAnnounces:        1},
 		"same ApprovedVersion doesn't announce": {
 			startApprovedVersion: "1.2.3",
 			latestVersion:        "1.2.3",
+			wantApproved:         true,
 			wantAnnounces:        0},
+		"approval policy not satisfied": {
+			startApprovedVersion: "",
+			latestVersion:        "1.2.3",
+			approval:             &approval.Policy{Required: 2},
+			approvers:            []string{"alice"},
+			wantApproved:         false,
+			wantAnnounces:        0},
+		"approval policy satisfied": {
+			startApprovedVersion: "",
+			latestVersion:        "1.2.3",
+			approval:             &approval.Policy{Required: 2},
+			approvers:            []string{"alice", "bob"},
+			wantApproved:         true,
+			wantAnnounces:        1},
 	}
 
 	for name, tc := range tests {
@@ -993,15 +1013,23 @@ func TestService_UpdateLatestApproved(t *testing.T) {
 
 			svc.Status.SetApprovedVersion(tc.startApprovedVersion, false)
 			svc.Status.SetLatestVersion(tc.latestVersion, "", false)
+			svc.Approval = tc.approval
+			svc.Approval.Init(&svc.Status)
+			for _, approver := range tc.approvers {
+				svc.Approval.Approve(approver, "")
+			}
 
 			// WHEN UpdateLatestApproved is called on it.
-			want := svc.Status.LatestVersion()
+			want := tc.startApprovedVersion
+			if tc.wantApproved {
+				want = svc.Status.LatestVersion()
+			}
 			svc.UpdateLatestApproved()
 
-			// THEN ApprovedVersion becomes LatestVersion.
+			// THEN ApprovedVersion becomes LatestVersion (once the approval policy is satisfied).
 			got := svc.Status.ApprovedVersion()
 			if got != want {
-				t.Errorf("LatestVersion should have changed to %q not %q",
+				t.Errorf("ApprovedVersion should be %q not %q",
 					want, got)
 			}
 			// AND the correct amount of changes are queued in the channel.
@@ -36,13 +36,12 @@ func LogInit(log *util.JLog) {
 
 // ServiceInfo returns info about the service.
 func (s *Service) ServiceInfo() util.ServiceInfo {
-	return util.ServiceInfo{
-		ID:            s.ID,
-		Name:          s.Name,
-		URL:           s.LatestVersion.ServiceURL(true),
-		WebURL:        s.Status.GetWebURL(),
-		LatestVersion: s.Status.LatestVersion(),
-	}
+	serviceInfo := s.Status.ServiceInfo()
+	serviceInfo.ID = s.ID
+	serviceInfo.Name = s.Name
+	serviceInfo.URL = s.LatestVersion.ServiceURL(true)
+
+	return serviceInfo
 }
 
 // IconURL returns the URL Icon for the Service.
@@ -121,6 +120,7 @@ func (s *Service) Init(
 			&s.Command,
 			&s.Notify,
 			s.Options.GetIntervalPointer())
+		s.CommandController.Options = &s.CommandOptions
 	}
 
 	// WebHook.
@@ -132,12 +132,23 @@ func (s *Service) Init(
 		}
 		s.webhookFromDefaults = true
 	}
+	// Rollback (before the WebHooks, as they may share IDs).
+	s.Rollback.Init(
+		&s.Status,
+		rootWebHookConfig, webhookDefaults, webhookHardDefaults,
+		&s.Notify,
+		s.Options.GetIntervalPointer())
 	s.WebHook.Init(
 		&s.Status,
 		rootWebHookConfig, webhookDefaults, webhookHardDefaults,
 		&s.Notify,
 		s.Options.GetIntervalPointer())
 
+	// Pipeline.
+	s.Pipeline.Init(&s.Status)
+	// Approval.
+	s.Approval.Init(&s.Status)
+
 	// LatestVersion.
 	if s.LatestVersion != nil {
 		s.LatestVersion.Init(
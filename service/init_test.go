@@ -18,6 +18,7 @@ package service
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -53,14 +54,17 @@ func TestService_ServiceInfo(t *testing.T) {
 	// When ServiceInfo is called on it.
 	got := svc.ServiceInfo()
 	want := util.ServiceInfo{
-		ID:            id,
-		URL:           url,
-		WebURL:        webURL,
-		LatestVersion: latestVersion,
+		ID:              id,
+		URL:             url,
+		WebURL:          webURL,
+		LatestVersion:   latestVersion,
+		PreviousVersion: "2.2.2",
+		DeployedVersion: "0.0.0",
+		ApprovedVersion: "1.1.1",
 	}
 
 	// THEN we get the correct ServiceInfo.
-	if got != want {
+	if !reflect.DeepEqual(got, want) {
 		t.Errorf("ServiceInfo didn't get the correct data\nwant: %#v\ngot:  %#v",
 			want, got)
 	}
@@ -16,13 +16,14 @@
 package filter
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/release-argus/Argus/util"
 )
 
-// ExecCommand will run Command.
-func (r *Require) ExecCommand(logFrom util.LogFrom) error {
+// ExecCommand will run Command (killing it if `ctx` is cancelled).
+func (r *Require) ExecCommand(ctx context.Context, logFrom util.LogFrom) error {
 	if r == nil || len(r.Command) == 0 {
 		return nil
 	}
@@ -31,7 +32,7 @@ func (r *Require) ExecCommand(logFrom util.LogFrom) error {
 	cmd := r.Command.ApplyTemplate(r.Status)
 
 	// Execute the command.
-	if err := cmd.Exec(logFrom); err != nil {
+	if err := cmd.Exec(ctx, logFrom); err != nil {
 		return fmt.Errorf("command failed: %w", err)
 	}
 	return nil
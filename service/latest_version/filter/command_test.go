@@ -17,6 +17,7 @@
 package filter
 
 import (
+	"context"
 	"testing"
 
 	"github.com/release-argus/Argus/command"
@@ -55,7 +56,7 @@ func TestRequire_ExecCommand(t *testing.T) {
 				test.StringPtr("http://example.com"))
 
 			// WHEN ApplyTemplate is called on the Command
-			err := require.ExecCommand(util.LogFrom{})
+			err := require.ExecCommand(context.Background(), util.LogFrom{})
 
 			// THEN the err is expected
 			e := util.ErrorToString(err)
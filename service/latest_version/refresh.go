@@ -16,6 +16,7 @@
 package latestver
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -23,10 +24,11 @@ import (
 	"github.com/release-argus/Argus/util"
 )
 
-// Refresh the Lookup with the provided overrides.
+// Refresh the Lookup with the provided overrides (cancelled with `ctx`).
 //
 //	Returns: version, announceUpdate, err.
 func Refresh(
+	ctx context.Context,
 	lookup Lookup,
 	overrides *string,
 	semanticVersioning *string, // nil, "true", "false", "null" (unchanged, true, false, default).
@@ -71,7 +73,7 @@ func Refresh(
 
 	hadVersion := lookup.GetStatus().LatestVersion()
 	// Query the lookup.
-	_, err := newLookup.Query(!usingOverrides, logFrom)
+	_, err := newLookup.Query(ctx, !usingOverrides, logFrom)
 	if err != nil {
 		return "", false, err //nolint: wrapcheck
 	}
@@ -17,6 +17,7 @@
 package latestver
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -30,10 +31,10 @@ import (
 
 func TestLookup_Refresh(t *testing.T) {
 	testURL := testLookup("url", false).(*web.Lookup)
-	testURL.Query(true, util.LogFrom{})
+	testURL.Query(context.Background(), true, util.LogFrom{})
 	testVersionURL := testURL.Status.LatestVersion()
 	testGitHub := testLookup("github", false).(*github.Lookup)
-	testGitHub.Query(true, util.LogFrom{})
+	testGitHub.Query(context.Background(), true, util.LogFrom{})
 	testVersionGitHub := testGitHub.Status.LatestVersion()
 
 	type args struct {
@@ -176,6 +177,7 @@ func TestLookup_Refresh(t *testing.T) {
 
 			// WHEN we call Refresh
 			got, gotAnnounce, err := Refresh(
+				context.Background(),
 				tc.previous,
 				tc.args.overrides,
 				tc.args.semanticVersioning)
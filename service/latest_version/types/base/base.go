@@ -16,6 +16,7 @@
 package base
 
 import (
+	"context"
 	"errors"
 	"strings"
 
@@ -154,6 +155,6 @@ func (l *Lookup) Inherit(fromLookup Interface) {
 }
 
 // Query will query the service for the latest version.
-func (l *Lookup) Query(_ bool, _ util.LogFrom) (bool, error) {
+func (l *Lookup) Query(_ context.Context, _ bool, _ util.LogFrom) (bool, error) {
 	return false, errors.New("not implemented")
 }
@@ -18,6 +18,7 @@
 package base
 
 import (
+	"context"
 	"strings"
 	"testing"
 	"time"
@@ -556,7 +557,7 @@ func TestQuery(t *testing.T) {
 			URL:  "https://example.com"}}
 
 	// WHEN Query is called
-	gotBool, gotErr := l.Query(true, util.LogFrom{})
+	gotBool, gotErr := l.Query(context.Background(), true, util.LogFrom{})
 
 	// THEN the function returns false and an error as it is not implemented
 	if gotBool != false {
@@ -16,10 +16,12 @@
 package base
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/release-argus/Argus/service/latest_version/filter"
 	opt "github.com/release-argus/Argus/service/option"
+	"github.com/release-argus/Argus/util"
 )
 
 // Defaults are the default values for a Lookup.
@@ -28,6 +30,8 @@ type Defaults struct {
 	AllowInvalidCerts *bool  `yaml:"allow_invalid_certs,omitempty" json:"allow_invalid_certs,omitempty"` // Default - false = Disallows invalid HTTPS certificates.
 	UsePreRelease     *bool  `yaml:"use_prerelease,omitempty" json:"use_prerelease,omitempty"`           // Whether releases with prerelease tag are considered.
 
+	util.HTTPClientOptions `yaml:",inline" json:",inline"` // Timeout, CA bundle and client certificate (web/github lookups).
+
 	Options *opt.Defaults          `yaml:"-" json:"-"`             // Options for the Lookup.
 	Require filter.RequireDefaults `yaml:"require" json:"require"` // Requirements before release considered valid.
 }
@@ -46,10 +50,20 @@ func (d *Defaults) Default() {
 
 // CheckValues validates the fields of the Defaults struct.
 func (d *Defaults) CheckValues(prefix string) error {
+	var errs []error
+	// timeout/ca_file/cert_file/key_file
+	if httpErrs := d.HTTPClientOptions.CheckValues(prefix); httpErrs != nil {
+		errs = append(errs, httpErrs)
+	}
+	// require
 	if requireErrs := d.Require.CheckValues(prefix + "  "); requireErrs != nil {
-		return fmt.Errorf("%srequire:\n%w",
-			prefix, requireErrs)
+		errs = append(errs,
+			fmt.Errorf("%srequire:\n%w",
+				prefix, requireErrs))
 	}
 
-	return nil
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
 }
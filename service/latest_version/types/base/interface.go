@@ -16,6 +16,8 @@
 package base
 
 import (
+	"context"
+
 	"github.com/release-argus/Argus/service/latest_version/filter"
 	opt "github.com/release-argus/Argus/service/option"
 	"github.com/release-argus/Argus/service/status"
@@ -35,8 +37,8 @@ type Interface interface {
 	// CheckValues validates the fields of the Lookup struct.
 	CheckValues(errPrefix string) (errs error)
 
-	// Query the Lookup for the latest version.
-	Query(metrics bool, logFrom util.LogFrom) (newVersion bool, err error)
+	// Query the Lookup for the latest version (cancelled with `ctx`).
+	Query(ctx context.Context, metrics bool, logFrom util.LogFrom) (newVersion bool, err error)
 
 	// Inherit state values from `fromLookup` if the values should query the same data.
 	Inherit(fromLookup Interface)
@@ -78,7 +78,7 @@ func (l *Lookup) HandleNewVersion(version, releaseDate string, logFrom util.LogF
 	}
 
 	// New version found.
-	l.Status.SetLatestVersion(version, "", true)
+	l.Status.SetLatestVersion(version, releaseDate, true)
 	msg := fmt.Sprintf("New Release - %q", version)
 	jLog.Info(msg, logFrom, true)
 	return true, nil
@@ -200,6 +200,12 @@ func TestLookup_HandleNewVersion(t *testing.T) {
 				t.Errorf("DeployedVersion mismatch\nwant: %q\ngot:  %q",
 					tc.versions.newVersion, lookup.Status.DeployedVersion())
 			}
+			// AND the LatestVersionTimestamp should be the release date of a changed version
+			if tc.versions.initialLatestVersion != tc.versions.newVersion &&
+				lookup.Status.LatestVersionTimestamp() != tc.versions.releaseDate {
+				t.Errorf("LatestVersionTimestamp mismatch\nwant: %q\ngot:  %q",
+					tc.versions.releaseDate, lookup.Status.LatestVersionTimestamp())
+			}
 		})
 	}
 }
@@ -23,12 +23,14 @@ import (
 // Release is the format of a Release on api.github.com/repos/OWNER/REPO/releases.
 type Release struct {
 	URL             string          `json:"url,omitempty"`
+	HTMLURL         string          `json:"html_url,omitempty"`
 	AssetsURL       string          `json:"assets_url,omitempty"`
 	SemanticVersion *semver.Version `json:"-"`
 	TagName         string          `json:"tag_name,omitempty"`
 	Name            string          `json:"name,omitempty"` // Tag name on /tags queries.
 	PreRelease      bool            `json:"prerelease"`
 	PublishedAt     string          `json:"published_at,omitempty"`
+	Body            string          `json:"body,omitempty"`
 	Assets          []Asset         `json:"assets,omitempty"`
 }
 
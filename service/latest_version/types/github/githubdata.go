@@ -16,6 +16,7 @@
 package github
 
 import (
+	"context"
 	"sync"
 
 	"github.com/release-argus/Argus/service/latest_version/types/base"
@@ -45,7 +46,7 @@ func SetEmptyListETag(accessToken string) {
 	lookup.data.SetTagFallback()
 	//#nosec G104 -- Disregard.
 	//nolint:errcheck // ^
-	lookup.httpRequest(util.LogFrom{Primary: "SetEmptyListETag"})
+	lookup.httpRequest(context.Background(), util.LogFrom{Primary: "SetEmptyListETag"})
 
 	setEmptyListETag(lookup.data.ETag())
 }
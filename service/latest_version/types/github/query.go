@@ -17,15 +17,18 @@ package github
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	github_types "github.com/release-argus/Argus/service/latest_version/types/github/api_type"
+	"github.com/release-argus/Argus/service/status"
 	"github.com/release-argus/Argus/util"
 )
 
@@ -34,8 +37,8 @@ import (
 //
 // Parameters:
 //   - metrics: if true, set Prometheus metrics based on the query.
-func (l *Lookup) Query(metrics bool, logFrom util.LogFrom) (bool, error) {
-	newVersion, err := l.query(logFrom, 0)
+func (l *Lookup) Query(ctx context.Context, metrics bool, logFrom util.LogFrom) (bool, error) {
+	newVersion, err := l.query(ctx, logFrom, 0)
 
 	if metrics {
 		l.QueryMetrics(l, err)
@@ -50,14 +53,14 @@ func (l *Lookup) Query(metrics bool, logFrom util.LogFrom) (bool, error) {
 // Parameters:
 //
 //	checkNumber: 0 for first check, 1 for second check (if the first check found a new version).
-func (l *Lookup) query(logFrom util.LogFrom, checkNumber int) (bool, error) {
-	body, err := l.httpRequest(logFrom)
+func (l *Lookup) query(ctx context.Context, logFrom util.LogFrom, checkNumber int) (bool, error) {
+	body, err := l.httpRequest(ctx, logFrom)
 	if err != nil {
 		return false, err
 	}
 
 	// Get the latest version, and its release date from the body.
-	version, releaseDate, err := l.getVersion(body, logFrom)
+	version, releaseDate, release, err := l.getVersion(ctx, body, logFrom)
 	if err != nil {
 		jLog.Error(err, logFrom, true)
 		return false, err
@@ -77,20 +80,21 @@ func (l *Lookup) query(logFrom util.LogFrom, checkNumber int) (bool, error) {
 			}
 		}
 
-		return l.handleNewVersion(checkNumber,
+		return l.handleNewVersion(ctx, checkNumber,
 			version, releaseDate,
+			release,
 			previousLatestVersion,
 			logFrom)
 	}
 
 	l.handleNoVersionChange(checkNumber,
-		version, logFrom)
+		version, release, logFrom)
 	return false, nil
 }
 
 // httpRequest makes a HTTP GET request to the URL of this Lookup, and returns the body retrieved.
-func (l *Lookup) httpRequest(logFrom util.LogFrom) ([]byte, error) {
-	req, err := l.createRequest(logFrom)
+func (l *Lookup) httpRequest(ctx context.Context, logFrom util.LogFrom) ([]byte, error) {
+	req, err := l.createRequest(ctx, logFrom)
 	if err != nil {
 		return nil, err
 	}
@@ -100,12 +104,12 @@ func (l *Lookup) httpRequest(logFrom util.LogFrom) ([]byte, error) {
 		return nil, err
 	}
 
-	return l.handleResponse(resp, body, logFrom)
+	return l.handleResponse(ctx, resp, body, logFrom)
 }
 
 // createRequest returns a HTTP GET request to the URL of this Lookup.
-func (l *Lookup) createRequest(logFrom util.LogFrom) (*http.Request, error) {
-	req, err := http.NewRequest(http.MethodGet, l.url(), nil)
+func (l *Lookup) createRequest(ctx context.Context, logFrom util.LogFrom) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.url(), nil)
 	if err != nil {
 		err = fmt.Errorf("failed creating http request for %q: %w", l.URL, err)
 		jLog.Error(err, logFrom, true)
@@ -130,8 +134,16 @@ func (l *Lookup) createRequest(logFrom util.LogFrom) (*http.Request, error) {
 
 // getResponse will make the request, and return the response, response body, and any errors encountered.
 func (l *Lookup) getResponse(req *http.Request, logFrom util.LogFrom) (*http.Response, []byte, error) {
+	client, err := util.NewHTTPClient(false,
+		&l.HTTPClientOptions,
+		&l.Defaults.HTTPClientOptions,
+		&l.HardDefaults.HTTPClientOptions)
+	if err != nil {
+		jLog.Error(err, logFrom, true)
+		return nil, nil, err //nolint:wrapcheck
+	}
+
 	// Make the request.
-	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
 		jLog.Error(err, logFrom, true)
@@ -154,15 +166,15 @@ func (l *Lookup) getResponse(req *http.Request, logFrom util.LogFrom) (*http.Res
 //   - 304 Not Modified, it flips the tags flag, performs a query on the "/tags" endpoint, and returns that body.
 //   - 401 Unauthorized, 403 Forbidden, and 429 Too Many Requests, it logs the error, and returns a nil body.
 //   - unknown status code, it logs the error, and returns a nil body along with an error.
-func (l *Lookup) handleResponse(resp *http.Response, body []byte, logFrom util.LogFrom) ([]byte, error) {
+func (l *Lookup) handleResponse(ctx context.Context, resp *http.Response, body []byte, logFrom util.LogFrom) ([]byte, error) {
 	switch resp.StatusCode {
 	// 200 - Resource has changed.
 	case http.StatusOK:
-		return l.handleStatusOK(resp, body, logFrom)
+		return l.handleStatusOK(ctx, resp, body, logFrom)
 
 	// 304 - Resource has not changed.
 	case http.StatusNotModified:
-		return l.handleStatusNotModified(logFrom)
+		return l.handleStatusNotModified(ctx, logFrom)
 
 	// 401 - Invalid access token.
 	case http.StatusUnauthorized:
@@ -170,11 +182,11 @@ func (l *Lookup) handleResponse(resp *http.Response, body []byte, logFrom util.L
 
 	// 403 - Rate limit exceeded.
 	case http.StatusForbidden:
-		return l.handleStatusForbidden(body, logFrom)
+		return l.handleStatusForbidden(resp, body, logFrom)
 
 	// 429 - Too many requests.
 	case http.StatusTooManyRequests:
-		return l.handleStatusTooManyRequests(body, logFrom)
+		return l.handleStatusTooManyRequests(resp, body, logFrom)
 	}
 
 	// Unknown status code.
@@ -187,7 +199,7 @@ func (l *Lookup) handleResponse(resp *http.Response, body []byte, logFrom util.L
 // and return any errors from the possible tag fallback request.
 //
 // 200 when the ETag changed.
-func (l *Lookup) handleStatusOK(resp *http.Response, body []byte, logFrom util.LogFrom) ([]byte, error) {
+func (l *Lookup) handleStatusOK(ctx context.Context, resp *http.Response, body []byte, logFrom util.LogFrom) ([]byte, error) {
 	newETag := strings.TrimPrefix(resp.Header.Get("etag"), "W/")
 	l.data.SetETag(newETag)
 
@@ -204,7 +216,7 @@ func (l *Lookup) handleStatusOK(resp *http.Response, body []byte, logFrom util.L
 			jLog.Verbose(
 				fmt.Sprintf("/releases gave %s, trying /tags", body),
 				logFrom, true)
-			body, err := l.httpRequest(logFrom)
+			body, err := l.httpRequest(ctx, logFrom)
 			return body, err
 		}
 
@@ -220,14 +232,14 @@ func (l *Lookup) handleStatusOK(resp *http.Response, body []byte, logFrom util.L
 // and return any errors from the possible tag fallback request.
 //
 // 304 when the ETag is unchanged and the response body is empty.
-func (l *Lookup) handleStatusNotModified(logFrom util.LogFrom) ([]byte, error) {
+func (l *Lookup) handleStatusNotModified(ctx context.Context, logFrom util.LogFrom) ([]byte, error) {
 	// Didn't find any releases before and nothing has changed?
 	if !l.data.hasReleases() {
 		// Flip the fallback flag for next time.
 		l.data.SetTagFallback()
 		if l.data.TagFallback() {
 			jLog.Verbose("no tags found on /releases, trying /tags", logFrom, true)
-			body, err := l.httpRequest(logFrom)
+			body, err := l.httpRequest(ctx, logFrom)
 			return body, err
 		}
 	}
@@ -257,8 +269,8 @@ func (l *Lookup) handleStatusUnauthorized(body []byte, logFrom util.LogFrom) ([]
 
 // handleStatusForbidden will handle a 403 status code response.
 //
-// 403 when the rate limit is exceeded.
-func (l *Lookup) handleStatusForbidden(body []byte, logFrom util.LogFrom) ([]byte, error) {
+// 403 when the rate limit is exceeded (backing off until it resets).
+func (l *Lookup) handleStatusForbidden(resp *http.Response, body []byte, logFrom util.LogFrom) ([]byte, error) {
 	bodyStr := string(body)
 	var err error
 
@@ -267,6 +279,7 @@ func (l *Lookup) handleStatusForbidden(body []byte, logFrom util.LogFrom) ([]byt
 	case strings.Contains(bodyStr, "rate limit"):
 		err = errors.New("rate limit reached for GitHub")
 		jLog.Warn(err, logFrom, true)
+		l.Status.Backoffs.LatestVersion.SetRetryAfter(rateLimitReset(resp.Header, time.Now()))
 
 		// Missing tag_name.
 	case !strings.Contains(bodyStr, `"tag_name"`):
@@ -284,8 +297,10 @@ func (l *Lookup) handleStatusForbidden(body []byte, logFrom util.LogFrom) ([]byt
 
 // handleStatusTooManyRequests handles a 429 status code response.
 //
-// 429 when too many requests made within a short period.
-func (l *Lookup) handleStatusTooManyRequests(body []byte, logFrom util.LogFrom) ([]byte, error) {
+// 429 when too many requests made within a short period (backing off until the rate limit resets).
+func (l *Lookup) handleStatusTooManyRequests(resp *http.Response, body []byte, logFrom util.LogFrom) ([]byte, error) {
+	l.Status.Backoffs.LatestVersion.SetRetryAfter(rateLimitReset(resp.Header, time.Now()))
+
 	var message github_types.Message
 	if err := json.Unmarshal(body, &message); err != nil {
 		err = fmt.Errorf("unmarshal of GitHub API data failed\n%w", err)
@@ -296,9 +311,25 @@ func (l *Lookup) handleStatusTooManyRequests(body []byte, logFrom util.LogFrom)
 	return nil, fmt.Errorf("too many requests made to GitHub - %q", message.Message)
 }
 
+// rateLimitReset returns when to retry after a rate-limited response with `header`,
+// the Retry-After (secondary rate limits), or X-RateLimit-Reset when none remaining (primary rate limit).
+// Zero if neither are given.
+func rateLimitReset(header http.Header, now time.Time) time.Time {
+	if retryAfter := util.RetryAfter(header, now); !retryAfter.IsZero() {
+		return retryAfter
+	}
+
+	if header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return time.Unix(reset, 0).UTC()
+		}
+	}
+	return time.Time{}
+}
+
 // releaseMeetsRequirements verifies that the `release` meets the requirements of the Lookup
 // and returns the version, and its release date if it does.
-func (l *Lookup) releaseMeetsRequirements(release github_types.Release, logFrom util.LogFrom) (string, string, error) {
+func (l *Lookup) releaseMeetsRequirements(ctx context.Context, release github_types.Release, logFrom util.LogFrom) (string, string, error) {
 	version := release.TagName
 	if release.SemanticVersion != nil {
 		version = release.SemanticVersion.String()
@@ -333,7 +364,7 @@ func (l *Lookup) releaseMeetsRequirements(release github_types.Release, logFrom
 	}
 
 	// If the Command didn't return successfully.
-	if err := l.Require.ExecCommand(logFrom); err != nil {
+	if err := l.Require.ExecCommand(ctx, logFrom); err != nil {
 		return "", "", err //nolint: wrapcheck
 	}
 
@@ -364,13 +395,13 @@ func (l *Lookup) releaseMeetsRequirements(release github_types.Release, logFrom
 	return version, releaseDate, nil
 }
 
-// getVersion returns the version, and date of the matching asset/release from `body`
+// getVersion returns the version, date, and release of the matching asset/release from `body`
 // that matches the URLCommands, and Regex requirements.
-func (l *Lookup) getVersion(body []byte, logFrom util.LogFrom) (string, string, error) {
+func (l *Lookup) getVersion(ctx context.Context, body []byte, logFrom util.LogFrom) (string, string, *github_types.Release, error) {
 	// body length = 0 if GitHub ETag unchanged.
 	if len(body) != 0 {
 		if err := l.setReleases(body, logFrom); err != nil {
-			return "", "", fmt.Errorf("release data failed to parse\n%w", err)
+			return "", "", nil, fmt.Errorf("release data failed to parse\n%w", err)
 		}
 	} else {
 		// Recheck this ETag's filteredReleases in case filters/releases changed.
@@ -378,20 +409,20 @@ func (l *Lookup) getVersion(body []byte, logFrom util.LogFrom) (string, string,
 	}
 	filteredReleases := l.filterGitHubReleases(logFrom)
 	if len(filteredReleases) == 0 {
-		return "", "", errors.New("no releases were found matching the url_commands")
+		return "", "", nil, errors.New("no releases were found matching the url_commands")
 	}
 
 	// Check all releases for the one meeting requirements.
 	var firstErr error
-	for _, release := range filteredReleases {
-		if v, rd, err := l.releaseMeetsRequirements(release, logFrom); err == nil {
-			return v, rd, nil
+	for i, release := range filteredReleases {
+		if v, rd, err := l.releaseMeetsRequirements(ctx, release, logFrom); err == nil {
+			return v, rd, &filteredReleases[i], nil
 		} else if firstErr == nil {
 			firstErr = err
 		}
 	}
 
-	return "", "", fmt.Errorf("no releases were found matching the require field(s)\n%w", firstErr)
+	return "", "", nil, fmt.Errorf("no releases were found matching the require field(s)\n%w", firstErr)
 }
 
 // setReleases processes, and stores the provided GitHub releases data.
@@ -408,9 +439,11 @@ func (l *Lookup) setReleases(body []byte, logFrom util.LogFrom) error {
 // handleNewVersion handles the case of a new version find,
 // and re-checks if first run.
 func (l *Lookup) handleNewVersion(
+	ctx context.Context,
 	checkNumber int,
 	version,
-	releaseDate,
+	releaseDate string,
+	release *github_types.Release,
 	latestVersion string,
 	logFrom util.LogFrom,
 ) (bool, error) {
@@ -418,20 +451,47 @@ func (l *Lookup) handleNewVersion(
 	if checkNumber == 0 {
 		msg := fmt.Sprintf("Possibly found a new version (From %q to %q). Checking again", latestVersion, version)
 		jLog.Verbose(msg, logFrom, latestVersion != "")
-		time.Sleep(time.Second)
-		return l.query(logFrom, 1)
+		if !util.Sleep(ctx, time.Second) {
+			return false, ctx.Err() //nolint:wrapcheck
+		}
+		return l.query(ctx, logFrom, 1)
 	}
 
-	return l.HandleNewVersion(version, releaseDate, logFrom) //nolint: wrapcheck
+	newVersion, err := l.HandleNewVersion(version, releaseDate, logFrom)
+	l.setRelease(release)
+	return newVersion, err //nolint: wrapcheck
 }
 
 // handleNoVersionChange handles the case of no version(s) being found.
-func (l *Lookup) handleNoVersionChange(checkNumber int, version string, logFrom util.LogFrom) {
+func (l *Lookup) handleNoVersionChange(
+	checkNumber int,
+	version string,
+	release *github_types.Release,
+	logFrom util.LogFrom,
+) {
 	if checkNumber == 1 {
 		jLog.Verbose(
 			fmt.Sprintf("Staying on %q as that's the latest version in the second check", version),
 			logFrom, true)
 	}
 
+	// Refresh the release details (e.g. edited release notes).
+	l.setRelease(release)
 	l.Status.AnnounceQuery()
 }
+
+// setRelease gives the details of `release` to the Status.
+func (l *Lookup) setRelease(release *github_types.Release) {
+	if release == nil {
+		return
+	}
+
+	assets := make([]string, 0, len(release.Assets))
+	for _, asset := range release.Assets {
+		assets = append(assets, asset.BrowserDownloadURL)
+	}
+	l.Status.SetRelease(status.Release{
+		Notes:  release.Body,
+		URL:    release.HTMLURL,
+		Assets: assets})
+}
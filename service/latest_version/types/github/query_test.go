@@ -18,6 +18,7 @@
 package github
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -29,6 +30,7 @@ import (
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/release-argus/Argus/service/latest_version/filter"
+	"github.com/release-argus/Argus/service/latest_version/types/base"
 	github_types "github.com/release-argus/Argus/service/latest_version/types/github/api_type"
 	"github.com/release-argus/Argus/test"
 	"github.com/release-argus/Argus/util"
@@ -38,7 +40,7 @@ import (
 func TestQuery(t *testing.T) {
 	tLookup := testLookup(false)
 	tLookup.URL = "release-argus/.github"
-	tLookup.Query(false, util.LogFrom{})
+	tLookup.Query(context.Background(), false, util.LogFrom{})
 	emptyReleasesETag := tLookup.data.eTag
 
 	type statusVars struct {
@@ -297,7 +299,7 @@ func TestQuery(t *testing.T) {
 				}
 
 				// WHEN Query is called on it.
-				_, err = lookup.Query(true, util.LogFrom{})
+				_, err = lookup.Query(context.Background(), true, util.LogFrom{})
 
 				// THEN any err is expected.
 				stdout := releaseStdout()
@@ -369,7 +371,7 @@ func TestHTTPRequest(t *testing.T) {
 			}
 
 			// WHEN httpRequest is called on it.
-			_, err := lookup.httpRequest(util.LogFrom{})
+			_, err := lookup.httpRequest(context.Background(), util.LogFrom{})
 
 			// THEN any err is expected.
 			e := util.ErrorToString(err)
@@ -400,6 +402,8 @@ func TestGetResponse_ReadError(t *testing.T) {
 
 	// WHEN getResponse is called on that URL.
 	l := Lookup{}
+	l.Defaults = &base.Defaults{}
+	l.HardDefaults = &base.Defaults{}
 	_, _, err = l.getResponse(req, util.LogFrom{})
 
 	// THEN an error is expected from the read error.
@@ -408,11 +412,38 @@ func TestGetResponse_ReadError(t *testing.T) {
 	}
 }
 
+func TestGetResponse_Timeout(t *testing.T) {
+	// GIVEN a server that responds slower than the timeout of the Lookup.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(func() { server.Close() })
+	req, err := http.NewRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatalf("github.Lookup.getResponse() could not create request: %v", err)
+	}
+	l := Lookup{}
+	l.Defaults = &base.Defaults{}
+	l.HardDefaults = &base.Defaults{}
+	l.Defaults.Timeout = "50ms"
+
+	// WHEN getResponse is called on that URL.
+	_, _, err = l.getResponse(req, util.LogFrom{})
+
+	// THEN the timeout of the Defaults is applied.
+	if err == nil || !strings.Contains(err.Error(), "Client.Timeout") {
+		t.Fatalf("github.Lookup.getResponse() want a timeout error, got %v",
+			err)
+	}
+}
+
 func TestHandleResponse(t *testing.T) {
 	type wants struct {
 		nilBody          bool
 		errRegex         string
 		setEmptyListETag bool
+		retryAfter       time.Time
 	}
 	type conditions struct {
 		hadReleases           bool
@@ -423,6 +454,7 @@ func TestHandleResponse(t *testing.T) {
 	tests := map[string]struct {
 		conditions conditions
 		statusCode int
+		headers    map[string]string
 		body       []byte
 		want       wants
 	}{
@@ -479,6 +511,27 @@ func TestHandleResponse(t *testing.T) {
 				errRegex: `rate limit reached for GitHub`,
 				nilBody:  true},
 		},
+		"403 Forbidden - rate limit, backs off until reset": {
+			statusCode: http.StatusForbidden,
+			headers: map[string]string{
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     "4102444800"},
+			body: []byte(`{"message":"API rate limit exceeded"}`),
+			want: wants{
+				errRegex:   `rate limit reached for GitHub`,
+				nilBody:    true,
+				retryAfter: time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		"403 Forbidden - rate limit, reset ignored when requests remaining": {
+			statusCode: http.StatusForbidden,
+			headers: map[string]string{
+				"X-RateLimit-Remaining": "5",
+				"X-RateLimit-Reset":     "4102444800"},
+			body: []byte(`{"message":"API rate limit exceeded"}`),
+			want: wants{
+				errRegex: `rate limit reached for GitHub`,
+				nilBody:  true},
+		},
 		"403 Forbidden - missing tag_name": {
 			statusCode: http.StatusForbidden,
 			body:       []byte(`{"message":"some other error"}`),
@@ -500,6 +553,16 @@ func TestHandleResponse(t *testing.T) {
 				errRegex: `^too many requests made to GitHub - "something from GitHub"$`,
 				nilBody:  true},
 		},
+		"429 Too Many Requests - backs off until Retry-After": {
+			statusCode: http.StatusTooManyRequests,
+			headers: map[string]string{
+				"Retry-After": "Fri, 01 Jan 2100 00:00:00 GMT"},
+			body: []byte(`{"message":"something from GitHub"}`),
+			want: wants{
+				errRegex:   `^too many requests made to GitHub - "something from GitHub"$`,
+				nilBody:    true,
+				retryAfter: time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		},
 		"429 Too Many Requests - unmarshal fail": {
 			statusCode: http.StatusTooManyRequests,
 			body:       []byte(`{"message":}`),
@@ -533,6 +596,9 @@ func TestHandleResponse(t *testing.T) {
 			}
 			hadETag := name
 			resp.Header.Add("ETag", hadETag)
+			for key, value := range tc.headers {
+				resp.Header.Set(key, value)
+			}
 			if tc.conditions.hadReleases {
 				lookup.data.releases = testBodyObject
 			}
@@ -545,7 +611,7 @@ func TestHandleResponse(t *testing.T) {
 			logFrom := util.LogFrom{Primary: "TestHandleResponse", Secondary: name}
 
 			// WHEN handleResponse is called on it.
-			gotBody, err := lookup.handleResponse(resp, tc.body, logFrom)
+			gotBody, err := lookup.handleResponse(context.Background(), resp, tc.body, logFrom)
 
 			// THEN any err is expected.
 			if tc.want.errRegex == "" && err != nil {
@@ -569,6 +635,16 @@ func TestHandleResponse(t *testing.T) {
 			} else if !tc.want.setEmptyListETag && emptyListETag == hadETag {
 				t.Errorf("github.Lookup.handleResponse() empty list ETag should not have been set")
 			}
+			// AND the backoff after this failure honours the rate limit reset.
+			backoff := &lookup.Status.Backoffs.LatestVersion
+			backoff.Fail()
+			if !tc.want.retryAfter.IsZero() && !backoff.Until().Equal(tc.want.retryAfter) {
+				t.Errorf("github.Lookup.handleResponse() backoff mismatch\nwant: %s\ngot:  %s",
+					tc.want.retryAfter, backoff.Until())
+			} else if tc.want.retryAfter.IsZero() && backoff.Until().After(time.Now().Add(time.Hour)) {
+				t.Errorf("github.Lookup.handleResponse() backoff should not have honoured a reset, got %s",
+					backoff.Until())
+			}
 		})
 	}
 }
@@ -738,7 +814,7 @@ func TestReleaseMeetsRequirements(t *testing.T) {
 			logFrom := util.LogFrom{Primary: "TestReleaseMeetsRequirements", Secondary: name}
 
 			// WHEN releaseMeetsRequirements is called on it.
-			version, releaseDate, err := lookup.releaseMeetsRequirements(testRelease, logFrom)
+			version, releaseDate, err := lookup.releaseMeetsRequirements(context.Background(), testRelease, logFrom)
 
 			// THEN the version is as expected.
 			if version != tc.want.version {
@@ -857,7 +933,7 @@ func TestGetVersion(t *testing.T) {
 			}
 
 			// WHEN getVersion is called on it.
-			version, releaseDate, err := lookup.getVersion(testBody, logFrom)
+			version, releaseDate, release, err := lookup.getVersion(context.Background(), testBody, logFrom)
 
 			// THEN any err is expected.
 			e := util.ErrorToString(err)
@@ -878,6 +954,11 @@ func TestGetVersion(t *testing.T) {
 				t.Errorf("github.Lookup.getVersion() release date mismatch\nwant: %q\ngot:  %q",
 					tc.want.releaseDate, releaseDate)
 			}
+			// AND the release returned is the one matched.
+			if release == nil || release.PublishedAt != tc.want.releaseDate {
+				t.Errorf("github.Lookup.getVersion() release mismatch\nwant: release published at %q\ngot:  %v",
+					tc.want.releaseDate, release)
+			}
 		})
 	}
 }
@@ -1032,7 +1113,7 @@ func TestQueryGitHubETag(t *testing.T) {
 					lookup.Require = &filter.Require{}
 				}
 
-				_, err := lookup.Query(true, util.LogFrom{})
+				_, err := lookup.Query(context.Background(), true, util.LogFrom{})
 				if err != nil {
 					errs = append(errs, err)
 				}
@@ -1090,7 +1171,7 @@ func TestHandleNoVersionChange(t *testing.T) {
 			lookup := testLookup(false)
 
 			// WHEN handleNoVersionChange is called on it.
-			lookup.handleNoVersionChange(tc.checkNumber, tc.version, util.LogFrom{})
+			lookup.handleNoVersionChange(tc.checkNumber, tc.version, nil, util.LogFrom{})
 
 			// THEN a message is printed when expected.
 			stdout := releaseStdout()
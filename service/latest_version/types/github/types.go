@@ -37,6 +37,8 @@ type Lookup struct {
 	AccessToken   string `yaml:"access_token,omitempty" json:"access_token,omitempty"`     // GitHub access token to use.
 	UsePreRelease *bool  `yaml:"use_prerelease,omitempty" json:"use_prerelease,omitempty"` // Whether releases with the prerelease tag should be considered.
 
+	util.HTTPClientOptions `yaml:",inline" json:",inline"` // Timeout, CA bundle and client certificate.
+
 	data Data // GitHub Conditional Request vars / Releases.
 }
 
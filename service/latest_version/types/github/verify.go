@@ -34,6 +34,10 @@ func (l *Lookup) CheckValues(prefix string) error {
 		l.URL = strings.Join(parts[len(parts)-2:], "/")
 	}
 
+	if httpErrs := l.HTTPClientOptions.CheckValues(prefix); httpErrs != nil {
+		errs = append(errs, httpErrs)
+	}
+
 	if baseErrs := l.Lookup.CheckValues(prefix); baseErrs != nil {
 		errs = append(errs, baseErrs)
 	}
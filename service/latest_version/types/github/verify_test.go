@@ -30,6 +30,7 @@ func TestLookup_CheckValues(t *testing.T) {
 	// GIVEN a Lookup
 	type args struct {
 		url         *string
+		timeout     string
 		require     *filter.Require
 		urlCommands *filter.URLCommandSlice
 	}
@@ -52,6 +53,11 @@ func TestLookup_CheckValues(t *testing.T) {
 			args: args{
 				url: test.StringPtr("https://github.com/release-argus/Argus")},
 		},
+		"invalid timeout": {
+			errRegex: `^timeout: "foo" <invalid>.*$`,
+			args: args{
+				timeout: "foo"},
+		},
 		"invalid require": {
 			errRegex: test.TrimYAML(`
 				^require:
@@ -90,6 +96,7 @@ func TestLookup_CheckValues(t *testing.T) {
 			if tc.args.url != nil {
 				lookup.URL = *tc.args.url
 			}
+			lookup.Timeout = tc.args.timeout
 			if tc.args.require != nil {
 				lookup.Require = tc.args.require
 			}
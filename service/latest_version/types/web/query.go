@@ -16,12 +16,13 @@
 package web
 
 import (
-	"crypto/tls"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/release-argus/Argus/util"
 )
@@ -32,8 +33,8 @@ import (
 // Parameters:
 //
 //	metrics: if true, set Prometheus metrics based on the query.
-func (l *Lookup) Query(metrics bool, logFrom util.LogFrom) (bool, error) {
-	isNewVersion, err := l.query(logFrom)
+func (l *Lookup) Query(ctx context.Context, metrics bool, logFrom util.LogFrom) (bool, error) {
+	isNewVersion, err := l.query(ctx, logFrom)
 
 	if metrics {
 		l.QueryMetrics(l, err)
@@ -44,13 +45,13 @@ func (l *Lookup) Query(metrics bool, logFrom util.LogFrom) (bool, error) {
 
 // Query queries the source,
 // and returns whether a new release was found, and updates LatestVersion if so.
-func (l *Lookup) query(logFrom util.LogFrom) (bool, error) {
-	body, err := l.httpRequest(logFrom)
+func (l *Lookup) query(ctx context.Context, logFrom util.LogFrom) (bool, error) {
+	body, err := l.httpRequest(ctx, logFrom)
 	if err != nil {
 		return false, err
 	}
 
-	version, err := l.getVersion(string(body), logFrom)
+	version, err := l.getVersion(ctx, string(body), logFrom)
 	if err != nil {
 		jLog.Error(err, logFrom, true)
 		return false, err
@@ -78,16 +79,17 @@ func (l *Lookup) query(logFrom util.LogFrom) (bool, error) {
 }
 
 // httpRequest makes a HTTP GET request to the URL, and returns the body.
-func (l *Lookup) httpRequest(logFrom util.LogFrom) ([]byte, error) {
-	customTransport := &http.Transport{}
-	// HTTPS insecure skip verify.
-	if l.allowInvalidCerts() {
-		customTransport = http.DefaultTransport.(*http.Transport).Clone()
-		//#nosec G402 -- explicitly wanted InsecureSkipVerify
-		customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+func (l *Lookup) httpRequest(ctx context.Context, logFrom util.LogFrom) ([]byte, error) {
+	client, err := util.NewHTTPClient(l.allowInvalidCerts(),
+		&l.HTTPClientOptions,
+		&l.Defaults.HTTPClientOptions,
+		&l.HardDefaults.HTTPClientOptions)
+	if err != nil {
+		jLog.Error(err, logFrom, true)
+		return nil, err //nolint:wrapcheck
 	}
 
-	req, err := http.NewRequest(http.MethodGet, l.URL, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.URL, nil)
 	if err != nil {
 		err = fmt.Errorf("failed creating http request for %q: %w",
 			l.URL, err)
@@ -98,7 +100,6 @@ func (l *Lookup) httpRequest(logFrom util.LogFrom) ([]byte, error) {
 	// Set headers.
 	req.Header.Set("Connection", "close")
 
-	client := &http.Client{Transport: customTransport}
 	resp, err := client.Do(req)
 	if err != nil {
 		// Don't crash on invalid certs.
@@ -113,13 +114,20 @@ func (l *Lookup) httpRequest(logFrom util.LogFrom) ([]byte, error) {
 
 	// Read the response body.
 	defer resp.Body.Close()
+	// 429 Too Many Requests/503 Service Unavailable, honour the Retry-After.
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		l.Status.Backoffs.LatestVersion.SetRetryAfter(util.RetryAfter(resp.Header, time.Now()))
+		err = fmt.Errorf("non-2XX response code: %d", resp.StatusCode)
+		jLog.Warn(err, logFrom, true)
+		return nil, err
+	}
 	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20)) // Limit to 10 MB.
 	jLog.Error(err, logFrom, err != nil)
 	return body, err //nolint: wrapcheck
 }
 
 // getVersion returns the latest version from `body` that matches the URLCommands, and Regex requirements.
-func (l *Lookup) getVersion(body string, logFrom util.LogFrom) (string, error) {
+func (l *Lookup) getVersion(ctx context.Context, body string, logFrom util.LogFrom) (string, error) {
 	filteredVersions, err := l.URLCommands.GetVersions(body, logFrom)
 	if err != nil {
 		return "", fmt.Errorf("no releases were found matching the url_commands\n%w", err)
@@ -131,7 +139,7 @@ func (l *Lookup) getVersion(body string, logFrom util.LogFrom) (string, error) {
 	// Check all releases for the one meeting the requirements.
 	var firstErr error
 	for _, version := range filteredVersions {
-		if err := l.versionMeetsRequirements(version, body, logFrom); err == nil {
+		if err := l.versionMeetsRequirements(ctx, version, body, logFrom); err == nil {
 			return version, nil
 		} else if firstErr == nil {
 			firstErr = err
@@ -142,7 +150,7 @@ func (l *Lookup) getVersion(body string, logFrom util.LogFrom) (string, error) {
 }
 
 // versionMeetsRequirements checks whether `version` meets the requirements of the Lookup.
-func (l *Lookup) versionMeetsRequirements(version, body string, logFrom util.LogFrom) error {
+func (l *Lookup) versionMeetsRequirements(ctx context.Context, version, body string, logFrom util.LogFrom) error {
 	// No `Require` filters.
 	if l.Require == nil {
 		return nil
@@ -160,7 +168,7 @@ func (l *Lookup) versionMeetsRequirements(version, body string, logFrom util.Log
 	}
 
 	// If the Command didn't return successfully.
-	if err := l.Require.ExecCommand(logFrom); err != nil {
+	if err := l.Require.ExecCommand(ctx, logFrom); err != nil {
 		return err //nolint: wrapcheck
 	}
 
@@ -18,6 +18,9 @@
 package web
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -62,7 +65,7 @@ func TestHTTPRequest(t *testing.T) {
 			}
 
 			// WHEN httpRequest is called on it.
-			_, err := lookup.httpRequest(util.LogFrom{})
+			_, err := lookup.httpRequest(context.Background(), util.LogFrom{})
 
 			// THEN any err is expected.
 			e := util.ErrorToString(err)
@@ -74,6 +77,63 @@ func TestHTTPRequest(t *testing.T) {
 	}
 }
 
+func TestHTTPRequest_RetryAfter(t *testing.T) {
+	// GIVEN a Lookup of a server that is unavailable, asking to retry after a time.
+	tests := map[string]struct {
+		statusCode int
+		errRegex   string
+		retryAfter time.Time
+	}{
+		"429 Too Many Requests": {
+			statusCode: http.StatusTooManyRequests,
+			errRegex:   `^non-2XX response code: 429$`,
+			retryAfter: time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"503 Service Unavailable": {
+			statusCode: http.StatusServiceUnavailable,
+			errRegex:   `^non-2XX response code: 503$`,
+			retryAfter: time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"200 OK - ignored": {
+			statusCode: http.StatusOK,
+			errRegex:   `^$`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "Fri, 01 Jan 2100 00:00:00 GMT")
+				w.WriteHeader(tc.statusCode)
+			}))
+			t.Cleanup(server.Close)
+			lookup := testLookup(false)
+			lookup.URL = server.URL
+
+			// WHEN httpRequest is called on it.
+			_, err := lookup.httpRequest(context.Background(), util.LogFrom{})
+
+			// THEN any err is expected.
+			if e := util.ErrorToString(err); !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the backoff after a failure honours the Retry-After (when asked to).
+			backoff := &lookup.Status.Backoffs.LatestVersion
+			backoff.Fail()
+			if !tc.retryAfter.IsZero() && !backoff.Until().Equal(tc.retryAfter) {
+				t.Errorf("backoff mismatch\nwant: %s\ngot:  %s",
+					tc.retryAfter, backoff.Until())
+			} else if tc.retryAfter.IsZero() && backoff.Until().After(time.Now().Add(time.Hour)) {
+				t.Errorf("backoff should not have honoured the Retry-After, got %s",
+					backoff.Until())
+			}
+		})
+	}
+}
+
 func TestGetVersion(t *testing.T) {
 	// GIVEN a Lookup and a Body to filter.
 	body := `
@@ -273,7 +333,7 @@ func TestGetVersion(t *testing.T) {
 
 			// WHEN getVersion is called on it.
 			version, err := lookup.getVersion(
-				testBody, util.LogFrom{})
+				context.Background(), testBody, util.LogFrom{})
 
 			// THEN any err is expected.
 			e := util.ErrorToString(err)
@@ -292,7 +352,7 @@ func TestGetVersion(t *testing.T) {
 
 func TestQuery(t *testing.T) {
 	testLookupVersions := testLookup(false)
-	testLookupVersions.query(util.LogFrom{})
+	testLookupVersions.query(context.Background(), util.LogFrom{})
 
 	type statusVars struct {
 		latestVersion, latestVersionWant string
@@ -562,7 +622,7 @@ func TestQuery(t *testing.T) {
 
 				// WHEN Query is called on it.
 				var newVersion bool
-				newVersion, err = lookup.Query(true, util.LogFrom{})
+				newVersion, err = lookup.Query(context.Background(), true, util.LogFrom{})
 
 				// THEN any err is expected.
 				stdout := releaseStdout()
@@ -35,6 +35,8 @@ type Lookup struct {
 	base.Lookup `yaml:",inline" json:",inline"` // Base struct for a Lookup.
 
 	AllowInvalidCerts *bool `yaml:"allow_invalid_certs,omitempty" json:"allow_invalid_certs,omitempty"` // Allow invalid SSL certificates.
+
+	util.HTTPClientOptions `yaml:",inline" json:",inline"` // Timeout, CA bundle and client certificate.
 }
 
 // New returns a new Lookup from a string in a given format (json/yaml).
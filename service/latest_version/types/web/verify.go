@@ -29,6 +29,10 @@ func (l *Lookup) CheckValues(prefix string) error {
 				prefix))
 	}
 
+	if httpErrs := l.HTTPClientOptions.CheckValues(prefix); httpErrs != nil {
+		errs = append(errs, httpErrs)
+	}
+
 	if baseErrs := l.Lookup.CheckValues(prefix); baseErrs != nil {
 		errs = append(errs, baseErrs)
 	}
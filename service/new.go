@@ -17,15 +17,21 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 
+	"github.com/release-argus/Argus/command"
 	"github.com/release-argus/Argus/notify/shoutrrr"
 	shoutrrr_types "github.com/release-argus/Argus/notify/shoutrrr/types"
+	"github.com/release-argus/Argus/service/approval"
 	deployedver "github.com/release-argus/Argus/service/deployed_version"
 	latestver "github.com/release-argus/Argus/service/latest_version"
 	"github.com/release-argus/Argus/service/latest_version/types/github"
+	"github.com/release-argus/Argus/service/pipeline"
+	"github.com/release-argus/Argus/service/rollback"
+	"github.com/release-argus/Argus/service/window"
 	"github.com/release-argus/Argus/util"
 	"github.com/release-argus/Argus/webhook"
 )
@@ -57,6 +63,11 @@ type oldSecretRefs struct {
 	DeployedVersionLookup dvSecretRef               `json:"deployed_version,omitempty"`
 	Notify                map[string]oldStringIndex `json:"notify,omitempty"`
 	WebHook               map[string]whSecretRef    `json:"webhook,omitempty"`
+	CommandOptions        json.RawMessage           `json:"command_options,omitempty"` // nil = keep the CommandOptions of the old Service.
+	Pipeline              json.RawMessage           `json:"pipeline,omitempty"`        // nil = keep the Pipeline of the old Service.
+	Rollback              json.RawMessage           `json:"rollback,omitempty"`        // nil = keep the Rollback of the old Service.
+	Approval              json.RawMessage           `json:"approval,omitempty"`        // nil = keep the Approval policy of the old Service.
+	ChangeWindow          json.RawMessage           `json:"change_window,omitempty"`   // nil = keep the ChangeWindow of the old Service.
 }
 
 // FromPayload creates a new/edited Service from a payload.
@@ -118,6 +129,38 @@ func FromPayload(
 	newService.Status.DatabaseChannel = serviceHardDefaults.Status.DatabaseChannel
 	newService.Status.SaveChannel = serviceHardDefaults.Status.SaveChannel
 
+	// CommandOptions aren't in the Web UI form, so keep the old ones unless given.
+	if secretRefs.CommandOptions == nil && oldService != nil {
+		newService.CommandOptions = oldService.CommandOptions
+	}
+	// Pipeline isn't in the Web UI form, so keep the old one unless given.
+	if secretRefs.Pipeline == nil && oldService != nil && oldService.Pipeline != nil {
+		newService.Pipeline = &pipeline.Pipeline{
+			Stages: oldService.Pipeline.Stages}
+	}
+	// Rollback isn't in the Web UI form, so keep the old one unless given.
+	if secretRefs.Rollback == nil && oldService != nil && oldService.Rollback != nil {
+		newService.Rollback = &rollback.Rollback{
+			Timeout:  oldService.Rollback.Timeout,
+			Interval: oldService.Rollback.Interval,
+			Command:  oldService.Rollback.Command,
+			WebHook:  oldService.Rollback.WebHook}
+	}
+	// Approval isn't in the Web UI form, so keep the old policy unless given.
+	if secretRefs.Approval == nil && oldService != nil && oldService.Approval != nil {
+		newService.Approval = &approval.Policy{
+			Required:      oldService.Approval.Required,
+			Approvers:     oldService.Approval.Approvers,
+			Expiry:        oldService.Approval.Expiry,
+			RequireReason: oldService.Approval.RequireReason}
+	}
+	// ChangeWindow isn't in the Web UI form, so keep the old one unless given.
+	if secretRefs.ChangeWindow == nil && oldService != nil && oldService.ChangeWindow != nil {
+		newService.ChangeWindow = &window.Schedule{
+			Windows: oldService.ChangeWindow.Windows,
+			Freezes: oldService.ChangeWindow.Freezes}
+	}
+
 	removeDefaults(oldService, newService, serviceDefaults)
 	newService.Init(
 		serviceDefaults, serviceHardDefaults,
@@ -280,6 +323,23 @@ func (s *Service) giveSecretsWebHook(oldWebHooks webhook.Slice, secretRefs map[s
 	}
 }
 
+// giveSecretsCommandOptions from the `oldCommandOptions` (of the same Command).
+func (s *Service) giveSecretsCommandOptions(oldCommandOptions command.OptionsSlice) {
+	for key, options := range s.CommandOptions {
+		oldOptions := oldCommandOptions[key]
+		if options == nil || oldOptions == nil {
+			continue
+		}
+
+		// env
+		for name, value := range options.Env {
+			if value == util.SecretValue {
+				options.Env[name] = oldOptions.Env[name]
+			}
+		}
+	}
+}
+
 // giveSecrets replaces `SecretValue` in this Service with the corresponding value from oldService,
 // using secretRefs to locate secrets in maps/lists.
 func (s *Service) giveSecrets(oldService *Service, secretRefs oldSecretRefs) {
@@ -296,13 +356,17 @@ func (s *Service) giveSecrets(oldService *Service, secretRefs oldSecretRefs) {
 	// WebHook.
 	s.giveSecretsWebHook(oldService.WebHook, secretRefs.WebHook)
 	// Command.
+	s.giveSecretsCommandOptions(oldService.CommandOptions)
 	s.CommandController.CopyFailsFrom(oldService.CommandController)
 
 	// Keep LatestVersion if the LatestVersion Lookup is unchanged.
 	if s.LatestVersion.IsEqual(s.LatestVersion, oldService.LatestVersion) {
 		s.Status.SetApprovedVersion(oldService.Status.ApprovedVersion(), false)
 		s.Status.SetLatestVersion(oldService.Status.LatestVersion(), oldService.Status.LatestVersionTimestamp(), false)
+		s.Status.SetPreviousLatestVersion(oldService.Status.PreviousLatestVersion())
 		s.Status.SetLastQueried(oldService.Status.LastQueried())
+		// Keep the approvals of that LatestVersion.
+		s.Approval.Restore(oldService.Approval.Approvals())
 	}
 	// Keep DeployedVersion if the DeployedVersionLookup is unchanged.
 	if s.DeployedVersionLookup.IsEqual(oldService.DeployedVersionLookup) &&
@@ -311,8 +375,9 @@ func (s *Service) giveSecrets(oldService *Service, secretRefs oldSecretRefs) {
 	}
 }
 
-// CheckFetches verifies that, if set, the LatestVersion and DeployedVersion can be retrieved.
-func (s *Service) CheckFetches() error {
+// CheckFetches verifies that, if set, the LatestVersion and DeployedVersion can be retrieved
+// (giving up if `ctx` is cancelled).
+func (s *Service) CheckFetches(ctx context.Context) error {
 	// Don't check if the Service is inactive.
 	if !s.Options.GetActive() {
 		return nil
@@ -338,6 +403,7 @@ func (s *Service) CheckFetches() error {
 		s.Status.SetDeployedVersion("", "", false)
 
 		if _, err := s.LatestVersion.Query(
+			ctx,
 			false,
 			logFrom); err != nil {
 			return fmt.Errorf("latest_version - %w", err)
@@ -348,6 +414,7 @@ func (s *Service) CheckFetches() error {
 	// Fetch deployed version.
 	if s.DeployedVersionLookup != nil {
 		version, err := s.DeployedVersionLookup.Query(
+			ctx,
 			false,
 			logFrom)
 		if err != nil {
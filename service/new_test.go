@@ -18,6 +18,7 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net/http"
 	"strings"
@@ -2117,6 +2118,49 @@ func TestService_GiveSecretsWebHook(t *testing.T) {
 	}
 }
 
+func TestService_GiveSecretsCommandOptions(t *testing.T) {
+	// GIVEN a Service with CommandOptions that may reference secrets of the old Service
+	tests := map[string]struct {
+		env, oldEnv map[string]string
+		want        map[string]string
+	}{
+		"no secrets referenced": {
+			env:    map[string]string{"TOKEN": "new"},
+			oldEnv: map[string]string{"TOKEN": "old"},
+			want:   map[string]string{"TOKEN": "new"}},
+		"secret referenced": {
+			env:    map[string]string{"TOKEN": util.SecretValue, "OTHER": "x"},
+			oldEnv: map[string]string{"TOKEN": "old"},
+			want:   map[string]string{"TOKEN": "old", "OTHER": "x"}},
+		"secret referenced that didn't exist": {
+			env:    map[string]string{"TOKEN": util.SecretValue},
+			oldEnv: map[string]string{},
+			want:   map[string]string{"TOKEN": ""}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := &Service{
+				CommandOptions: command.OptionsSlice{
+					"ls": {Env: tc.env}}}
+			oldCommandOptions := command.OptionsSlice{
+				"ls": {Env: tc.oldEnv}}
+
+			// WHEN giveSecretsCommandOptions is called
+			svc.giveSecretsCommandOptions(oldCommandOptions)
+
+			// THEN the secrets are taken from the old Service
+			got := svc.CommandOptions["ls"].Env
+			if util.ToJSONString(got) != util.ToJSONString(tc.want) {
+				t.Errorf("want %v, not %v",
+					tc.want, got)
+			}
+		})
+	}
+}
+
 func TestService_GiveSecrets(t *testing.T) {
 	type statusTests struct {
 		oldLatestVersion, expectedLatestVersion                       string
@@ -3273,9 +3317,9 @@ func TestService_GiveSecrets(t *testing.T) {
 func TestService_CheckFetches(t *testing.T) {
 	// GIVEN a Service
 	testLV := testLatestVersion(t, "url", false)
-	testLV.Query(false, util.LogFrom{})
+	testLV.Query(context.Background(), false, util.LogFrom{})
 	testDVL := testDeployedVersionLookup(t, false)
-	v, _ := testDVL.Query(false, util.LogFrom{})
+	v, _ := testDVL.Query(context.Background(), false, util.LogFrom{})
 	testDVL.Status.SetDeployedVersion(v, "", false)
 	tests := map[string]struct {
 		svc                                       *Service
@@ -3353,7 +3397,7 @@ func TestService_CheckFetches(t *testing.T) {
 			tc.svc.Status.SetDeployedVersion(tc.startDeployedVersion, "", false)
 
 			// WHEN we call CheckFetches
-			err := tc.svc.CheckFetches()
+			err := tc.svc.CheckFetches(context.Background())
 
 			// THEN we get the err we expect
 			e := util.ErrorToString(err)
@@ -16,6 +16,7 @@
 package option
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
@@ -26,6 +27,8 @@ import (
 // Base is the base struct for Options.
 type Base struct {
 	Interval           string `yaml:"interval,omitempty" json:"interval,omitempty"`                       // AhBmCs = Sleep A hours, B minutes, and C seconds between queries.
+	Schedule           string `yaml:"schedule,omitempty" json:"schedule,omitempty"`                       // Cron expression of the query times, e.g. "0 */6 * * *" (takes precedence over interval).
+	Jitter             string `yaml:"jitter,omitempty" json:"jitter,omitempty"`                           // AhBmCs = Delay each query by up to A hours, B minutes, and C seconds at random.
 	SemanticVersioning *bool  `yaml:"semantic_versioning,omitempty" json:"semantic_versioning,omitempty"` // Default - true = Version has to follow semantic versioning (https://semver.org/), and be greater than the previous to trigger anything.
 }
 
@@ -90,6 +93,8 @@ func (o *Options) Copy() *Options {
 	return &Options{
 		Base: Base{
 			Interval:           o.Interval,
+			Schedule:           o.Schedule,
+			Jitter:             o.Jitter,
 			SemanticVersioning: util.CopyPointer(o.SemanticVersioning)},
 		Active:       util.CopyPointer(o.Active),
 		Defaults:     o.Defaults,
@@ -117,6 +122,40 @@ func (o *Options) GetInterval() string {
 		o.HardDefaults.Interval)
 }
 
+// GetSchedule returns the cron schedule of the queries ("" if an interval is used instead).
+//
+// A schedule/interval of the Service takes precedence over those of the Defaults.
+func (o *Options) GetSchedule() string {
+	for _, base := range []*Base{&o.Base, &o.Defaults.Base, &o.HardDefaults.Base} {
+		if base.Schedule != "" {
+			return base.Schedule
+		}
+		if base.Interval != "" {
+			return ""
+		}
+	}
+	return ""
+}
+
+// GetJitter returns the maximum random delay added to each query.
+func (o *Options) GetJitter() string {
+	return util.FirstNonDefault(
+		o.Jitter,
+		o.Defaults.Jitter,
+		o.HardDefaults.Jitter)
+}
+
+// GetJitterDuration returns the maximum random delay added to each query.
+func (o *Options) GetJitterDuration() time.Duration {
+	d, _ := time.ParseDuration(o.GetJitter())
+	return d
+}
+
+// NextQuery returns when the query after one at `last` is due (see NextQuery).
+func (o *Options) NextQuery(last time.Time) time.Time {
+	return NextQuery(o.GetSchedule(), o.GetIntervalDuration(), o.GetJitterDuration(), last)
+}
+
 // GetSemanticVersioning returns whether the Service uses Semantic Versioning.
 func (o *Options) GetSemanticVersioning() bool {
 	return *util.FirstNonNilPtr(
@@ -144,17 +183,58 @@ func (o *Options) GetIntervalDuration() time.Duration {
 
 // CheckValues validates the fields of the Base struct.
 func (b *Base) CheckValues(prefix string) error {
+	var errs []error
 	// Interval
-	if b.Interval != "" {
-		// Treat integers as seconds by default.
-		if _, err := strconv.Atoi(b.Interval); err == nil {
-			b.Interval += "s"
-		}
-		if _, err := time.ParseDuration(b.Interval); err != nil {
-			return fmt.Errorf("%sinterval: %q <invalid> (Use 'AhBmCs' duration format)",
-				prefix, b.Interval)
-		}
+	if err := CheckInterval(prefix, "interval", &b.Interval); err != nil {
+		errs = append(errs, err)
+	}
+	// Schedule
+	if err := CheckSchedule(prefix, b.Schedule); err != nil {
+		errs = append(errs, err)
+	}
+	// Jitter
+	if err := CheckInterval(prefix, "jitter", &b.Jitter); err != nil {
+		errs = append(errs, err)
 	}
 
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// CheckInterval validates the `interval` duration of `key` (treating integers as seconds).
+func CheckInterval(prefix, key string, interval *string) error {
+	if *interval == "" {
+		return nil
+	}
+
+	// Treat integers as seconds by default.
+	if _, err := strconv.Atoi(*interval); err == nil {
+		*interval += "s"
+	}
+	if d, err := time.ParseDuration(*interval); err != nil || d < 0 {
+		return fmt.Errorf("%s%s: %q <invalid> (Use 'AhBmCs' duration format)",
+			prefix, key, *interval)
+	}
+	return nil
+}
+
+// CheckSchedule validates the cron `schedule`.
+func CheckSchedule(prefix, schedule string) error {
+	if schedule == "" {
+		return nil
+	}
+
+	parsed, err := ParseSchedule(schedule)
+	if err != nil {
+		return fmt.Errorf("%sschedule: %q <invalid> (%w)",
+			prefix, schedule, err)
+	}
+	// e.g. '0 0 31 feb *'.
+	if parsed.Next(time.Now()).IsZero() {
+		return fmt.Errorf("%sschedule: %q <invalid> (never matches)",
+			prefix, schedule)
+	}
 	return nil
 }
@@ -108,6 +108,142 @@ func TestOptions_GetInterval(t *testing.T) {
 	}
 }
 
+func TestOptions_GetSchedule(t *testing.T) {
+	// GIVEN Options with schedules/intervals at different levels
+	type level struct {
+		schedule, interval string
+	}
+	tests := map[string]struct {
+		root, defaults, hardDefaults level
+		want                         string
+	}{
+		"no schedule": {
+			hardDefaults: level{interval: "10m"},
+			want:         ""},
+		"root schedule": {
+			root:         level{schedule: "0 * * * *"},
+			defaults:     level{schedule: "0 0 * * *"},
+			hardDefaults: level{interval: "10m"},
+			want:         "0 * * * *"},
+		"root interval takes precedence over a default schedule": {
+			root:         level{interval: "1h"},
+			defaults:     level{schedule: "0 0 * * *"},
+			hardDefaults: level{interval: "10m"},
+			want:         ""},
+		"default schedule": {
+			defaults:     level{schedule: "0 0 * * *"},
+			hardDefaults: level{interval: "10m"},
+			want:         "0 0 * * *"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			options := testOptions()
+			options.Schedule, options.Interval = tc.root.schedule, tc.root.interval
+			options.Defaults.Schedule, options.Defaults.Interval = tc.defaults.schedule, tc.defaults.interval
+			options.HardDefaults.Schedule, options.HardDefaults.Interval = tc.hardDefaults.schedule, tc.hardDefaults.interval
+
+			// WHEN GetSchedule is called
+			got := options.GetSchedule()
+
+			// THEN the function returns the correct result
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestOptions_GetJitter(t *testing.T) {
+	// GIVEN Options
+	tests := map[string]struct {
+		rootValue, defaultValue, hardDefaultValue string
+		want                                      string
+		wantDuration                              time.Duration
+	}{
+		"root overrides all": {
+			want:             "10s",
+			wantDuration:     10 * time.Second,
+			rootValue:        "10s",
+			defaultValue:     "1m10s",
+			hardDefaultValue: "1m10s",
+		},
+		"default overrides hardDefault": {
+			want:             "1m10s",
+			wantDuration:     70 * time.Second,
+			defaultValue:     "1m10s",
+			hardDefaultValue: "10s",
+		},
+		"none": {
+			want:         "",
+			wantDuration: 0,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			options := testOptions()
+			options.Jitter = tc.rootValue
+			options.Defaults.Jitter = tc.defaultValue
+			options.HardDefaults.Jitter = tc.hardDefaultValue
+
+			// WHEN GetJitter and GetJitterDuration are called
+			got := options.GetJitter()
+			gotDuration := options.GetJitterDuration()
+
+			// THEN the functions return the correct results
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+			if gotDuration != tc.wantDuration {
+				t.Errorf("want: %s\ngot:  %s",
+					tc.wantDuration, gotDuration)
+			}
+		})
+	}
+}
+
+func TestOptions_NextQuery(t *testing.T) {
+	// GIVEN Options with an interval, or a schedule
+	last := time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC)
+	tests := map[string]struct {
+		interval, schedule string
+		want               time.Time
+	}{
+		"interval": {
+			interval: "1h",
+			want:     last.Add(time.Hour)},
+		"schedule": {
+			schedule: "0 */6 * * *",
+			want:     time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			options := testOptions()
+			options.Interval = tc.interval
+			options.Schedule = tc.schedule
+
+			// WHEN NextQuery is called
+			got := options.NextQuery(last)
+
+			// THEN the next query is due at the expected time
+			if !got.Equal(tc.want) {
+				t.Errorf("want: %s\ngot:  %s",
+					tc.want, got)
+			}
+		})
+	}
+}
+
 func TestOptions_GetSemanticVersioning(t *testing.T) {
 	// GIVEN Options
 	tests := map[string]struct {
@@ -214,6 +350,7 @@ func TestOptions_CheckValues(t *testing.T) {
 	tests := map[string]struct {
 		options      *Options
 		wantInterval string
+		wantJitter   string
 		errRegex     string
 	}{
 		"valid options": {
@@ -228,6 +365,46 @@ func TestOptions_CheckValues(t *testing.T) {
 				test.BoolPtr(false), "10x", test.BoolPtr(false),
 				nil, nil),
 		},
+		"valid schedule and jitter": {
+			errRegex: `^$`,
+			options: &Options{
+				Base: Base{
+					Schedule: "CRON_TZ=Europe/London 0 */6 * * mon-fri",
+					Jitter:   "5m"}},
+		},
+		"invalid schedule": {
+			errRegex: `^schedule: "0 \*/6 \* \*" <invalid> \(want 5 fields`,
+			options: &Options{
+				Base: Base{
+					Schedule: "0 */6 * *"}},
+		},
+		"schedule that never matches": {
+			errRegex: `^schedule: "0 0 31 feb \*" <invalid> \(never matches\)$`,
+			options: &Options{
+				Base: Base{
+					Schedule: "0 0 31 feb *"}},
+		},
+		"invalid jitter": {
+			errRegex: `^jitter: "-5m" <invalid>`,
+			options: &Options{
+				Base: Base{
+					Jitter: "-5m"}},
+		},
+		"all invalid": {
+			errRegex: `^interval: .*\nschedule: .*\njitter: .*$`,
+			options: &Options{
+				Base: Base{
+					Interval: "10x",
+					Schedule: "@sometimes",
+					Jitter:   "5x"}},
+		},
+		"seconds get appended to pure decimal jitter": {
+			errRegex:   `^$`,
+			wantJitter: "10s",
+			options: &Options{
+				Base: Base{
+					Jitter: "10"}},
+		},
 		"seconds get appended to pure decimal interval": {
 			errRegex:     `^$`,
 			wantInterval: "10s",
@@ -250,6 +427,15 @@ func TestOptions_CheckValues(t *testing.T) {
 				t.Fatalf("want match for %q\nnot: %q",
 					tc.errRegex, e)
 			}
+			// AND integer durations are converted to seconds
+			if tc.wantInterval != "" && tc.options.Interval != tc.wantInterval {
+				t.Errorf("interval\nwant: %q\ngot:  %q",
+					tc.wantInterval, tc.options.Interval)
+			}
+			if tc.wantJitter != "" && tc.options.Jitter != tc.wantJitter {
+				t.Errorf("jitter\nwant: %q\ngot:  %q",
+					tc.wantJitter, tc.options.Jitter)
+			}
 		})
 	}
 }
@@ -0,0 +1,226 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package option provides options for a service.
+package option
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule of the times a cron expression matches.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64 // Bitsets of the values matched.
+	domAny, dowAny                bool   // Whether the day of the month/week starts with '*'.
+	location                      *time.Location
+}
+
+// scheduleField is the range, and names of the values of a field of a cron expression.
+type scheduleField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	scheduleFields = [5]scheduleField{
+		{name: "minute", min: 0, max: 59},
+		{name: "hour", min: 0, max: 23},
+		{name: "day of month", min: 1, max: 31},
+		{name: "month", min: 1, max: 12, names: map[string]int{
+			"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+			"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}},
+		// 7 is also Sunday.
+		{name: "day of week", min: 0, max: 7, names: map[string]int{
+			"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}},
+	}
+	scheduleDescriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *"}
+)
+
+// ParseSchedule parses the cron `expression` ("minute hour day-of-month month day-of-week",
+// or a descriptor like @daily), optionally prefixed with "CRON_TZ=<IANA timezone> " (default = UTC).
+func ParseSchedule(expression string) (*Schedule, error) {
+	schedule := &Schedule{location: time.UTC}
+
+	expression = strings.TrimSpace(expression)
+	// Timezone.
+	for _, prefix := range []string{"CRON_TZ=", "TZ="} {
+		if rest, ok := strings.CutPrefix(expression, prefix); ok {
+			timezone, rest, _ := strings.Cut(rest, " ")
+			location, err := time.LoadLocation(timezone)
+			if err != nil {
+				return nil, fmt.Errorf("unknown IANA timezone %q, e.g. Europe/London", timezone)
+			}
+			schedule.location = location
+			expression = strings.TrimSpace(rest)
+			break
+		}
+	}
+	// Descriptor.
+	if strings.HasPrefix(expression, "@") {
+		descriptor, ok := scheduleDescriptors[strings.ToLower(expression)]
+		if !ok {
+			return nil, fmt.Errorf("unknown descriptor %q, use @yearly/@monthly/@weekly/@daily/@hourly",
+				expression)
+		}
+		expression = descriptor
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != len(scheduleFields) {
+		return nil, fmt.Errorf("want 5 fields (minute hour day-of-month month day-of-week), got %d",
+			len(fields))
+	}
+	bitsets := [5]*uint64{&schedule.minute, &schedule.hour, &schedule.dom, &schedule.month, &schedule.dow}
+	for i, field := range fields {
+		bits, err := scheduleFields[i].parse(field)
+		if err != nil {
+			return nil, err
+		}
+		*bitsets[i] = bits
+	}
+	// Sunday is 0 or 7.
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+	schedule.domAny = strings.HasPrefix(fields[2], "*")
+	schedule.dowAny = strings.HasPrefix(fields[4], "*")
+
+	return schedule, nil
+}
+
+// parse the `value` of this field into a bitset of the values it matches.
+//
+// A value is a comma separated list of '*', 'N' or 'N-M', each optionally with a '/step'.
+func (f scheduleField) parse(value string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step < 1 {
+				return 0, fmt.Errorf("%s: step %q must be a positive integer",
+					f.name, stepPart)
+			}
+		}
+
+		var start, end int
+		switch {
+		case rangePart == "*":
+			start, end = f.min, f.max
+		default:
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = f.value(from); err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				if end, err = f.value(to); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// 'N/step' runs from N to the max.
+				end = f.max
+			}
+			if end < start {
+				return 0, fmt.Errorf("%s: range %q must not end before it starts",
+					f.name, rangePart)
+			}
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << i
+		}
+	}
+	return bits, nil
+}
+
+// value returns the integer value of `value` (a number, or name) in this field.
+func (f scheduleField) value(value string) (int, error) {
+	if named, ok := f.names[strings.ToLower(value)]; ok {
+		return named, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < f.min || number > f.max {
+		return 0, fmt.Errorf("%s: %q must be %d-%d",
+			f.name, value, f.min, f.max)
+	}
+	return number, nil
+}
+
+// Next returns the first time after `after` that the Schedule matches (zero if none within 5 years).
+func (s *Schedule) Next(after time.Time) time.Time {
+	t := after.In(s.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		year, month, day := t.Date()
+		switch {
+		case s.month&(1<<uint(month)) == 0:
+			t = time.Date(year, month+1, 1, 0, 0, 0, 0, s.location)
+		case !s.dayMatches(t):
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, s.location)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(year, month, day, t.Hour()+1, 0, 0, 0, s.location)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches returns whether the day of `t` matches the day of the month and week of the Schedule
+// (either, when both are restricted).
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// NextQuery returns when the query after one at `last` is due - the next time of the cron `schedule`
+// (if set and it matches, otherwise `interval` after `last`), plus up to `jitter` at random.
+func NextQuery(schedule string, interval, jitter time.Duration, last time.Time) time.Time {
+	next := last.Add(interval)
+	if schedule != "" {
+		if parsed, err := ParseSchedule(schedule); err == nil {
+			if scheduled := parsed.Next(last); !scheduled.IsZero() {
+				next = scheduled
+			}
+		}
+	}
+
+	if jitter > 0 {
+		//#nosec G404 -- jitter does not need cryptographic security.
+		next = next.Add(time.Duration(rand.Int63n(int64(jitter))))
+	}
+	return next
+}
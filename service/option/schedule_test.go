@@ -0,0 +1,197 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package option
+
+import (
+	"testing"
+	"time"
+
+	"github.com/release-argus/Argus/util"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// GIVEN cron expressions
+	tests := map[string]struct {
+		expression string
+		errRegex   string
+	}{
+		"every minute": {
+			expression: "* * * * *",
+			errRegex:   `^$`},
+		"lists, ranges and steps": {
+			expression: "0,30 */6 1-15/2 jan-jun mon-fri",
+			errRegex:   `^$`},
+		"descriptor": {
+			expression: "@daily",
+			errRegex:   `^$`},
+		"timezone": {
+			expression: "CRON_TZ=Europe/London 0 9 * * *",
+			errRegex:   `^$`},
+		"unknown timezone": {
+			expression: "CRON_TZ=Somewhere/Else 0 9 * * *",
+			errRegex:   `^unknown IANA timezone "Somewhere/Else"`},
+		"unknown descriptor": {
+			expression: "@sometimes",
+			errRegex:   `^unknown descriptor "@sometimes"`},
+		"too few fields": {
+			expression: "0 9 * *",
+			errRegex:   `^want 5 fields .*, got 4$`},
+		"value out of range": {
+			expression: "60 * * * *",
+			errRegex:   `^minute: "60" must be 0-59$`},
+		"unknown name": {
+			expression: "0 0 * * funday",
+			errRegex:   `^day of week: "funday" must be 0-7$`},
+		"invalid step": {
+			expression: "*/0 * * * *",
+			errRegex:   `^minute: step "0" must be a positive integer$`},
+		"backwards range": {
+			expression: "0 20-10 * * *",
+			errRegex:   `^hour: range "20-10" must not end before it starts$`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN ParseSchedule is called
+			_, err := ParseSchedule(tc.expression)
+
+			// THEN it errors when expected
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	// GIVEN a Schedule, and a time (Wednesday 1st January 2025 10:30:15 UTC)
+	after := time.Date(2025, 1, 1, 10, 30, 15, 0, time.UTC)
+	london, _ := time.LoadLocation("Europe/London")
+	tests := map[string]struct {
+		expression string
+		want       time.Time
+	}{
+		"every minute": {
+			expression: "* * * * *",
+			want:       time.Date(2025, 1, 1, 10, 31, 0, 0, time.UTC)},
+		"every 6 hours": {
+			expression: "0 */6 * * *",
+			want:       time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)},
+		"next day": {
+			expression: "0 9 * * *",
+			want:       time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)},
+		"day of the week": {
+			expression: "0 0 * * sat",
+			want:       time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC)},
+		"sunday as 7": {
+			expression: "0 0 * * 7",
+			want:       time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)},
+		"day of the month or week": {
+			expression: "0 0 10 * fri",
+			want:       time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)},
+		"day of the month and every other day of the week": {
+			expression: "0 0 10 * */2",
+			want:       time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC)},
+		"next year": {
+			expression: "@yearly",
+			want:       time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		"timezone": {
+			expression: "CRON_TZ=Europe/London 0 9 1 7 *",
+			want:       time.Date(2025, 7, 1, 9, 0, 0, 0, london)},
+		"never": {
+			expression: "0 0 31 feb *",
+			want:       time.Time{}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := ParseSchedule(tc.expression)
+			if err != nil {
+				t.Fatalf("failed to parse %q: %v",
+					tc.expression, err)
+			}
+
+			// WHEN Next is called
+			got := schedule.Next(after)
+
+			// THEN the next matching time is returned
+			if !got.Equal(tc.want) {
+				t.Errorf("want: %s\ngot:  %s",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestNextQuery(t *testing.T) {
+	// GIVEN the time of the last query
+	last := time.Date(2025, 1, 1, 10, 30, 15, 0, time.UTC)
+	tests := map[string]struct {
+		schedule string
+		interval time.Duration
+		jitter   time.Duration
+		min, max time.Time
+	}{
+		"interval": {
+			interval: time.Hour,
+			min:      last.Add(time.Hour),
+			max:      last.Add(time.Hour)},
+		"schedule takes precedence": {
+			schedule: "0 11 * * *",
+			interval: time.Hour,
+			min:      time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC),
+			max:      time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC)},
+		"invalid schedule falls back to the interval": {
+			schedule: "invalid",
+			interval: time.Hour,
+			min:      last.Add(time.Hour),
+			max:      last.Add(time.Hour)},
+		"schedule that never matches falls back to the interval": {
+			schedule: "0 0 31 feb *",
+			interval: time.Hour,
+			min:      last.Add(time.Hour),
+			max:      last.Add(time.Hour)},
+		"jitter": {
+			interval: time.Hour,
+			jitter:   time.Minute,
+			min:      last.Add(time.Hour),
+			max:      last.Add(time.Hour + time.Minute)},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			for range 10 {
+				// WHEN NextQuery is called
+				got := NextQuery(tc.schedule, tc.interval, tc.jitter, last)
+
+				// THEN the next query is due within the expected range
+				if got.Before(tc.min) || got.After(tc.max) {
+					t.Fatalf("want %s-%s\ngot:  %s",
+						tc.min, tc.max, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package service provides the service functionality for Argus.
+package service
+
+import (
+	"fmt"
+
+	"github.com/release-argus/Argus/notify/shoutrrr"
+	"github.com/release-argus/Argus/util"
+)
+
+// pipelineActions runs the actions of a Service's Pipeline.
+type pipelineActions struct {
+	service     *Service
+	serviceInfo util.ServiceInfo
+}
+
+// RunCommand runs the Command of the Service whose String is `command`.
+//
+// Steps run directly, rather than through the outbox, as a failure runs the on_failure branch
+// instead of being retried in the background.
+func (a *pipelineActions) RunCommand(command string) error {
+	for index, cmd := range a.service.Command {
+		if cmd.String() == command {
+			return a.service.CommandController.ExecIndex(
+				a.service.Status.Context(),
+				util.LogFrom{Primary: "Command", Secondary: a.service.ID},
+				index)
+		}
+	}
+	return fmt.Errorf("command %q not found", command)
+}
+
+// SendWebHook sends the WebHook of the Service with this `id` (directly, like RunCommand).
+func (a *pipelineActions) SendWebHook(id string) error {
+	wh := a.service.WebHook[id]
+	if wh == nil {
+		return fmt.Errorf("webhook %q not found", id)
+	}
+
+	//nolint:wrapcheck
+	return wh.Send(a.service.Status.Context(), a.serviceInfo, true)
+}
+
+// SendNotify sends the new release message with the Notify of the Service with this `id`.
+func (a *pipelineActions) SendNotify(id string) error {
+	notify := a.service.Notify[id]
+	if notify == nil {
+		return fmt.Errorf("notify %q not found", id)
+	}
+
+	notifiers := shoutrrr.Slice{id: notify}
+	return notifiers.Send(a.service.Status.Context(), "", "", a.serviceInfo, true)
+}
+
+// runPipeline runs the Pipeline of the Service,
+// registering the version change if every Stage it ran succeeded
+// (actions on branches that didn't run needn't have passed).
+func (s *Service) runPipeline(writeToDB bool) {
+	logFrom := util.LogFrom{Primary: "Pipeline", Secondary: s.ID}
+	jLog.Info(
+		fmt.Sprintf("Running the pipeline for %q", s.Status.LatestVersion()),
+		logFrom, true)
+
+	err := s.Pipeline.Run(&pipelineActions{
+		service:     s,
+		serviceInfo: s.ServiceInfo()})
+	if err != nil {
+		jLog.Error(err, logFrom, true)
+		return
+	}
+
+	if s.Status.DeployedVersion() != s.Status.LatestVersion() {
+		s.updateVersion(writeToDB)
+	}
+}
+
+// notifiersOutsidePipeline returns the Notifiers that aren't sent by the Pipeline.
+func (s *Service) notifiersOutsidePipeline() *shoutrrr.Slice {
+	if s.Pipeline == nil {
+		return &s.Notify
+	}
+
+	notifiers := make(shoutrrr.Slice, len(s.Notify))
+	for id, notify := range s.Notify {
+		if !s.Pipeline.References("notify", id) {
+			notifiers[id] = notify
+		}
+	}
+	return &notifiers
+}
+
+// commandStrings returns the String of every Command of the Service.
+func (s *Service) commandStrings() []string {
+	commands := make([]string, len(s.Command))
+	for i := range s.Command {
+		commands[i] = s.Command[i].String()
+	}
+	return commands
+}
@@ -0,0 +1,41 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pipeline provides ordered stages of actions to run on approval of a release.
+package pipeline
+
+import (
+	"encoding/json"
+
+	"github.com/release-argus/Argus/util"
+	apitype "github.com/release-argus/Argus/web/api/types"
+)
+
+// announce the progress of the Pipeline to the `ServiceStatus.AnnounceChannel`
+// (Broadcast to all WebSocket clients).
+func (p *Pipeline) announce() {
+	if p.ServiceStatus == nil {
+		return
+	}
+
+	payloadData, _ := json.Marshal(apitype.WebSocketMessage{
+		Page:    "APPROVALS",
+		Type:    "PIPELINE",
+		SubType: "EVENT",
+		ServiceData: &apitype.ServiceSummary{
+			ID: util.DereferenceOrDefault(p.ServiceStatus.ServiceID)},
+		PipelineData: p.Summary()})
+
+	p.ServiceStatus.SendAnnounce(&payloadData)
+}
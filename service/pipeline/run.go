@@ -0,0 +1,160 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pipeline provides ordered stages of actions to run on approval of a release.
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Run the Pipeline from its first Stage, following the on_success/on_failure branches
+// and announcing the progress of each Stage.
+//
+// Returns the errors of every Stage that failed.
+func (p *Pipeline) Run(actions Actions) error {
+	if p == nil || len(p.Stages) == 0 {
+		return nil
+	}
+	if !p.start() {
+		return errors.New("pipeline is already running")
+	}
+	defer p.finish()
+
+	var errs []error
+	for index := 0; index < len(p.Stages); {
+		stage := p.Stages[index]
+		p.setState(index, StatusRunning, nil)
+
+		err := stage.run(actions)
+		next := stage.OnSuccess
+		if err == nil {
+			p.setState(index, StatusSuccess, nil)
+		} else {
+			p.setState(index, StatusFailed, err)
+			errs = append(errs, fmt.Errorf("stage %q failed: %w", stage.Name, err))
+			next = stage.OnFailure
+			if next == "" {
+				next = End
+			}
+		}
+
+		index = p.nextIndex(index, next)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// run every action of the Stage concurrently, returning their errors.
+func (s *Stage) run(actions Actions) error {
+	type action func(string) error
+	var (
+		runs []action
+		ids  []string
+	)
+	for _, command := range s.Command {
+		runs, ids = append(runs, actions.RunCommand), append(ids, command)
+	}
+	for _, webhookID := range s.WebHook {
+		runs, ids = append(runs, actions.SendWebHook), append(ids, webhookID)
+	}
+	for _, notifyID := range s.Notify {
+		runs, ids = append(runs, actions.SendNotify), append(ids, notifyID)
+	}
+
+	errChan := make(chan error, len(runs))
+	for i := range runs {
+		go func(run action, id string) {
+			errChan <- run(id)
+		}(runs[i], ids[i])
+	}
+
+	var errs []error
+	for range runs {
+		if err := <-errChan; err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// nextIndex returns the index of the `next` Stage after the Stage at `index`.
+//
+// "" = the following Stage, End = past the last Stage.
+func (p *Pipeline) nextIndex(index int, next string) int {
+	switch next {
+	case "":
+		return index + 1
+	case End:
+		return len(p.Stages)
+	}
+
+	for i, stage := range p.Stages {
+		if stage.Name == next {
+			return i
+		}
+	}
+	return len(p.Stages)
+}
+
+// start the Pipeline, resetting the progress of every Stage.
+//
+// Returns false if it is already running.
+func (p *Pipeline) start() bool {
+	p.mutex.Lock()
+	if p.running {
+		p.mutex.Unlock()
+		return false
+	}
+	p.running = true
+	p.resetState()
+	p.mutex.Unlock()
+
+	p.announce()
+	return true
+}
+
+// finish the Pipeline, marking the Stages that didn't run as skipped.
+func (p *Pipeline) finish() {
+	p.mutex.Lock()
+	p.running = false
+	for i := range p.state {
+		if p.state[i].status == StatusPending {
+			p.state[i].status = StatusSkipped
+		}
+	}
+	p.mutex.Unlock()
+
+	p.announce()
+}
+
+// setState of the Stage at `index` and announce it.
+func (p *Pipeline) setState(index int, status string, err error) {
+	p.mutex.Lock()
+	p.state[index] = stageState{status: status}
+	if err != nil {
+		p.state[index].err = err.Error()
+	}
+	p.mutex.Unlock()
+
+	p.announce()
+}
@@ -0,0 +1,282 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/service/status"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+	apitype "github.com/release-argus/Argus/web/api/types"
+)
+
+// testActions records the actions run, failing those in `fail`.
+type testActions struct {
+	mutex sync.Mutex
+	fail  []string
+	ran   []string
+	wait  chan struct{}
+}
+
+func (a *testActions) do(id string) error {
+	if a.wait != nil {
+		<-a.wait
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.ran = append(a.ran, id)
+	if util.Contains(a.fail, id) {
+		return errors.New(id + " failed")
+	}
+	return nil
+}
+func (a *testActions) RunCommand(command string) error { return a.do("command:" + command) }
+func (a *testActions) SendWebHook(id string) error     { return a.do("webhook:" + id) }
+func (a *testActions) SendNotify(id string) error      { return a.do("notify:" + id) }
+
+func testPipeline() *Pipeline {
+	return &Pipeline{
+		Stages: []*Stage{
+			{Name: "backup", Command: []string{"./backup.sh"}},
+			{Name: "deploy", WebHook: []string{"deploy"}, OnFailure: "rollback"},
+			{Name: "smoke_test", Command: []string{"./smoke.sh"}, OnFailure: "rollback"},
+			{Name: "notify", Notify: []string{"done"}, OnSuccess: End},
+			{Name: "rollback", Command: []string{"./rollback.sh"}, Notify: []string{"failed"}}}}
+}
+
+func TestPipeline_Run(t *testing.T) {
+	// GIVEN a Pipeline and actions that may fail
+	tests := map[string]struct {
+		pipeline   *Pipeline
+		fail       []string
+		wantRan    []string
+		wantStatus []string
+		errRegex   string
+	}{
+		"nil pipeline": {
+			pipeline: nil,
+			errRegex: `^$`},
+		"all succeed": {
+			pipeline: testPipeline(),
+			wantRan: []string{
+				"command:./backup.sh", "webhook:deploy", "command:./smoke.sh", "notify:done"},
+			wantStatus: []string{
+				StatusSuccess, StatusSuccess, StatusSuccess, StatusSuccess, StatusSkipped},
+			errRegex: `^$`},
+		"first stage fails, defaults to end": {
+			pipeline: testPipeline(),
+			fail:     []string{"command:./backup.sh"},
+			wantRan: []string{
+				"command:./backup.sh"},
+			wantStatus: []string{
+				StatusFailed, StatusSkipped, StatusSkipped, StatusSkipped, StatusSkipped},
+			errRegex: `^stage "backup" failed: command:./backup.sh failed$`},
+		"on_failure branch": {
+			pipeline: testPipeline(),
+			fail:     []string{"command:./smoke.sh"},
+			wantRan: []string{
+				"command:./backup.sh", "webhook:deploy", "command:./smoke.sh", "command:./rollback.sh", "notify:failed"},
+			wantStatus: []string{
+				StatusSuccess, StatusSuccess, StatusFailed, StatusSkipped, StatusSuccess},
+			errRegex: `^stage "smoke_test" failed: command:./smoke.sh failed$`},
+		"on_failure branch fails too": {
+			pipeline: testPipeline(),
+			fail:     []string{"webhook:deploy", "notify:failed"},
+			wantRan: []string{
+				"command:./backup.sh", "webhook:deploy", "command:./rollback.sh", "notify:failed"},
+			wantStatus: []string{
+				StatusSuccess, StatusFailed, StatusSkipped, StatusSkipped, StatusFailed},
+			errRegex: `^stage "deploy" failed: webhook:deploy failed\nstage "rollback" failed: notify:failed failed$`},
+		"on_success jumps ahead": {
+			pipeline: &Pipeline{
+				Stages: []*Stage{
+					{Name: "a", Command: []string{"a"}, OnSuccess: "c"},
+					{Name: "b", Command: []string{"b"}},
+					{Name: "c", Command: []string{"c"}}}},
+			wantRan: []string{
+				"command:a", "command:c"},
+			wantStatus: []string{
+				StatusSuccess, StatusSkipped, StatusSuccess},
+			errRegex: `^$`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actions := &testActions{fail: tc.fail}
+
+			// WHEN Run is called
+			err := tc.pipeline.Run(actions)
+
+			// THEN the expected error is returned
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the expected actions ran (the rollback stage runs its actions concurrently)
+			got := strings.Join(actions.ran, ",")
+			if len(actions.ran) != len(tc.wantRan) {
+				t.Fatalf("want ran %v\ngot %v",
+					tc.wantRan, actions.ran)
+			}
+			for _, want := range tc.wantRan {
+				if !util.Contains(actions.ran, want) {
+					t.Errorf("want %q to have run\ngot %s",
+						want, got)
+				}
+			}
+			if tc.pipeline == nil {
+				return
+			}
+			// AND each Stage has the expected status
+			summary := tc.pipeline.Summary()
+			for i, want := range tc.wantStatus {
+				if summary.Stages[i].Status != want {
+					t.Errorf("stage %q: want status %q, not %q",
+						summary.Stages[i].Name, want, summary.Stages[i].Status)
+				}
+			}
+			// AND the Pipeline is no longer running
+			if summary.Running {
+				t.Error("want Running=false after the run")
+			}
+		})
+	}
+}
+
+func TestPipeline_Run_AlreadyRunning(t *testing.T) {
+	// GIVEN a Pipeline that is running
+	pipeline := testPipeline()
+	actions := &testActions{wait: make(chan struct{})}
+	done := make(chan error)
+	go func() {
+		done <- pipeline.Run(actions)
+	}()
+	for !pipeline.Running() {
+		time.Sleep(time.Millisecond)
+	}
+
+	// WHEN Run is called again
+	err := pipeline.Run(&testActions{})
+
+	// THEN it errors
+	if err == nil || err.Error() != "pipeline is already running" {
+		t.Errorf("want 'already running' error, not %v", err)
+	}
+	// AND the first run continues
+	close(actions.wait)
+	if err := <-done; err != nil {
+		t.Errorf("first run errored: %v", err)
+	}
+}
+
+func TestPipeline_Announce(t *testing.T) {
+	// GIVEN a Pipeline with a Status that has an AnnounceChannel
+	announceChannel := make(chan []byte, 20)
+	databaseChannel := make(chan dbtype.Message, 5)
+	svcStatus := status.New(
+		&announceChannel, &databaseChannel, nil,
+		"", "", "", "", "", "")
+	svcStatus.Init(
+		0, 0, 0,
+		test.StringPtr("svc"), test.StringPtr("svc"),
+		test.StringPtr(""))
+	pipeline := &Pipeline{
+		Stages: []*Stage{
+			{Name: "only", Command: []string{"foo"}}}}
+	pipeline.Init(svcStatus)
+
+	// WHEN it is Run
+	pipeline.Run(&testActions{})
+
+	// THEN the start, each Stage change and the finish are announced
+	wantStatuses := []string{StatusPending, StatusRunning, StatusSuccess, StatusSuccess}
+	if len(announceChannel) != len(wantStatuses) {
+		t.Fatalf("want %d announcements, not %d",
+			len(wantStatuses), len(announceChannel))
+	}
+	for i, want := range wantStatuses {
+		var msg apitype.WebSocketMessage
+		json.Unmarshal(<-announceChannel, &msg)
+		if msg.Type != "PIPELINE" || msg.ServiceData.ID != "svc" {
+			t.Errorf("announcement %d: want type PIPELINE for svc, not %q for %q",
+				i, msg.Type, msg.ServiceData.ID)
+		}
+		if got := msg.PipelineData.Stages[0].Status; got != want {
+			t.Errorf("announcement %d: want status %q, not %q",
+				i, want, got)
+		}
+	}
+}
+
+func TestPipeline_References(t *testing.T) {
+	// GIVEN a Pipeline
+	pipeline := testPipeline()
+	tests := map[string]struct {
+		pipeline *Pipeline
+		kind, id string
+		want     bool
+	}{
+		"nil pipeline": {
+			kind: "command", id: "./backup.sh",
+			want: false},
+		"command referenced": {
+			pipeline: pipeline,
+			kind:     "command", id: "./backup.sh",
+			want: true},
+		"command not referenced": {
+			pipeline: pipeline,
+			kind:     "command", id: "./other.sh",
+			want: false},
+		"webhook referenced": {
+			pipeline: pipeline,
+			kind:     "webhook", id: "deploy",
+			want: true},
+		"notify referenced": {
+			pipeline: pipeline,
+			kind:     "notify", id: "failed",
+			want: true},
+		"id of a different kind": {
+			pipeline: pipeline,
+			kind:     "notify", id: "deploy",
+			want: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN References is called
+			got := tc.pipeline.References(tc.kind, tc.id)
+
+			// THEN the expected result is returned
+			if got != tc.want {
+				t.Errorf("want %t, not %t",
+					tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pipeline provides ordered stages of actions to run on approval of a release.
+package pipeline
+
+import (
+	"sync"
+
+	"github.com/release-argus/Argus/service/status"
+	apitype "github.com/release-argus/Argus/web/api/types"
+)
+
+// End is the `on_success`/`on_failure` target that ends the Pipeline.
+const End = "end"
+
+// Statuses of a Stage.
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// Actions runs the actions referenced by a Stage.
+type Actions interface {
+	RunCommand(command string) error // Run the Command, e.g. "./backup.sh --full".
+	SendWebHook(id string) error     // Send the WebHook with this ID.
+	SendNotify(id string) error      // Send the Notify with this ID.
+}
+
+// Stage is a set of actions that run concurrently, and the Stage to move to once they finish.
+type Stage struct {
+	Name      string   `yaml:"name,omitempty" json:"name,omitempty"`             // Unique name of the Stage.
+	Command   []string `yaml:"command,omitempty" json:"command,omitempty"`       // Commands to run, e.g. "./backup.sh --full".
+	WebHook   []string `yaml:"webhook,omitempty" json:"webhook,omitempty"`       // IDs of the WebHooks to send.
+	Notify    []string `yaml:"notify,omitempty" json:"notify,omitempty"`         // IDs of the Notifiers to send.
+	OnSuccess string   `yaml:"on_success,omitempty" json:"on_success,omitempty"` // Stage to run if every action succeeds (default = the next Stage).
+	OnFailure string   `yaml:"on_failure,omitempty" json:"on_failure,omitempty"` // Stage to run if any action fails (default = end).
+}
+
+// stageState is the progress of a Stage in the latest run.
+type stageState struct {
+	status string
+	err    string
+}
+
+// Pipeline is an ordered list of Stages to run on approval of a release.
+type Pipeline struct {
+	Stages []*Stage `yaml:"stages,omitempty" json:"stages,omitempty"` // Stages to run, in order.
+
+	mutex   sync.RWMutex // Mutex for concurrent access.
+	running bool         // Whether the Pipeline is running.
+	state   []stageState // Progress of each Stage in the latest run.
+
+	ServiceStatus *status.Status `yaml:"-" json:"-"` // Status of the Service (for announcements).
+}
+
+// Init the Pipeline with the Service's Status.
+func (p *Pipeline) Init(serviceStatus *status.Status) {
+	if p == nil {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.ServiceStatus = serviceStatus
+	p.resetState()
+}
+
+// resetState sets every Stage to pending.
+func (p *Pipeline) resetState() {
+	p.state = make([]stageState, len(p.Stages))
+	for i := range p.state {
+		p.state[i].status = StatusPending
+	}
+}
+
+// Running returns whether the Pipeline is running.
+func (p *Pipeline) Running() bool {
+	if p == nil {
+		return false
+	}
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.running
+}
+
+// References returns whether the Pipeline references the action `id` of `kind` ("command"/"webhook"/"notify").
+func (p *Pipeline) References(kind, id string) bool {
+	if p == nil {
+		return false
+	}
+
+	for _, stage := range p.Stages {
+		if stage == nil {
+			continue
+		}
+		var ids []string
+		switch kind {
+		case "command":
+			ids = stage.Command
+		case "webhook":
+			ids = stage.WebHook
+		case "notify":
+			ids = stage.Notify
+		}
+		for _, stageID := range ids {
+			if stageID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Summary returns the API summary of the Pipeline's latest run.
+func (p *Pipeline) Summary() *apitype.PipelineSummary {
+	if p == nil {
+		return nil
+	}
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	summary := &apitype.PipelineSummary{
+		Running: p.running,
+		Stages:  make([]apitype.PipelineStageSummary, len(p.Stages))}
+	for i, stage := range p.Stages {
+		summary.Stages[i].Status = StatusPending
+		if stage != nil {
+			summary.Stages[i].Name = stage.Name
+		}
+		if i < len(p.state) {
+			summary.Stages[i].Status = p.state[i].status
+			summary.Stages[i].Error = p.state[i].err
+		}
+	}
+
+	return summary
+}
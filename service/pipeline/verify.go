@@ -0,0 +1,158 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pipeline provides ordered stages of actions to run on approval of a release.
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/release-argus/Argus/util"
+)
+
+// CheckValues validates the fields of the Pipeline against the
+// `commands`, `webhooks` and `notifies` of its Service.
+//
+// Every Command and WebHook of the Service must be in a Stage,
+// and branches may only jump forward (so every run ends).
+func (p *Pipeline) CheckValues(prefix string, commands, webhooks, notifies []string) error {
+	if p == nil {
+		return nil
+	}
+
+	if len(p.Stages) == 0 {
+		return fmt.Errorf("%sstages: <required> (at least one stage)",
+			prefix)
+	}
+
+	var errs []error
+	// Index of each Stage name.
+	stageIndex := make(map[string]int, len(p.Stages))
+	for i, stage := range p.Stages {
+		if stage == nil {
+			continue
+		}
+		if _, exists := stageIndex[stage.Name]; !exists {
+			stageIndex[stage.Name] = i
+		}
+	}
+
+	var stageErrs []error
+	itemPrefix := prefix + "    "
+	for i, stage := range p.Stages {
+		if stage == nil {
+			util.AppendCheckError(&stageErrs, prefix+"  ", fmt.Sprintf("- item_%d", i),
+				fmt.Errorf("%sname: <required>", itemPrefix))
+			continue
+		}
+		util.AppendCheckError(&stageErrs, prefix+"  ", fmt.Sprintf("- item_%d", i),
+			stage.checkValues(itemPrefix, i, stageIndex, commands, webhooks, notifies))
+	}
+	util.AppendCheckError(&errs, prefix, "stages", errors.Join(stageErrs...))
+
+	// Every Command/WebHook must run in the Pipeline.
+	for _, command := range commands {
+		if !p.References("command", command) {
+			errs = append(errs,
+				fmt.Errorf("%scommand: %q <required> (every command must be in a stage)",
+					prefix, command))
+		}
+	}
+	for _, webhookID := range webhooks {
+		if !p.References("webhook", webhookID) {
+			errs = append(errs,
+				fmt.Errorf("%swebhook: %q <required> (every webhook must be in a stage)",
+					prefix, webhookID))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// checkValues validates the fields of the Stage at `index`.
+func (s *Stage) checkValues(
+	prefix string,
+	index int,
+	stageIndex map[string]int,
+	commands, webhooks, notifies []string,
+) error {
+	var errs []error
+	// name
+	switch {
+	case s.Name == "":
+		errs = append(errs,
+			fmt.Errorf("%sname: <required>",
+				prefix))
+	case s.Name == End:
+		errs = append(errs,
+			fmt.Errorf("%sname: %q <invalid> (reserved for ending the pipeline)",
+				prefix, s.Name))
+	case stageIndex[s.Name] != index:
+		errs = append(errs,
+			fmt.Errorf("%sname: %q <invalid> (must be unique)",
+				prefix, s.Name))
+	}
+
+	// command/webhook/notify
+	if len(s.Command) == 0 && len(s.WebHook) == 0 && len(s.Notify) == 0 {
+		errs = append(errs,
+			fmt.Errorf("%scommand/webhook/notify: <required> (at least one action)",
+				prefix))
+	}
+	for _, command := range s.Command {
+		if !util.Contains(commands, command) {
+			errs = append(errs,
+				fmt.Errorf("%scommand: %q <invalid> (not a command of this service)",
+					prefix, command))
+		}
+	}
+	for _, webhookID := range s.WebHook {
+		if !util.Contains(webhooks, webhookID) {
+			errs = append(errs,
+				fmt.Errorf("%swebhook: %q <invalid> (not a webhook of this service)",
+					prefix, webhookID))
+		}
+	}
+	for _, notifyID := range s.Notify {
+		if !util.Contains(notifies, notifyID) {
+			errs = append(errs,
+				fmt.Errorf("%snotify: %q <invalid> (not a notify of this service)",
+					prefix, notifyID))
+		}
+	}
+
+	// on_success/on_failure
+	for _, branch := range []struct{ key, target string }{
+		{key: "on_success", target: s.OnSuccess},
+		{key: "on_failure", target: s.OnFailure},
+	} {
+		if branch.target == "" || branch.target == End {
+			continue
+		}
+		if targetIndex, exists := stageIndex[branch.target]; !exists || targetIndex <= index {
+			errs = append(errs,
+				fmt.Errorf("%s%s: %q <invalid> (must be %q or the name of a later stage)",
+					prefix, branch.key, branch.target, End))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,147 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestPipeline_CheckValues(t *testing.T) {
+	// GIVEN a Pipeline, and the actions of its Service
+	commands := []string{"./backup.sh", "./smoke.sh", "./rollback.sh"}
+	webhooks := []string{"deploy"}
+	notifies := []string{"done", "failed", "other"}
+	tests := map[string]struct {
+		pipeline *Pipeline
+		commands []string
+		webhooks []string
+		errRegex string
+	}{
+		"nil pipeline": {
+			pipeline: nil,
+			errRegex: `^$`},
+		"valid pipeline": {
+			pipeline: testPipeline(),
+			errRegex: `^$`},
+		"no stages": {
+			pipeline: &Pipeline{},
+			errRegex: `^stages: <required>`},
+		"nil stage": {
+			pipeline: &Pipeline{
+				Stages: []*Stage{
+					nil}},
+			commands: []string{},
+			webhooks: []string{},
+			errRegex: test.TrimYAML(`
+				^stages:
+					- item_0:
+						name: <required>$`)},
+		"stage without name or actions": {
+			pipeline: &Pipeline{
+				Stages: []*Stage{
+					{}}},
+			commands: []string{},
+			webhooks: []string{},
+			errRegex: test.TrimYAML(`
+				^stages:
+					- item_0:
+						name: <required>
+						command/webhook/notify: <required>.*$`)},
+		"duplicate and reserved names": {
+			pipeline: &Pipeline{
+				Stages: []*Stage{
+					{Name: "a", Notify: []string{"done"}},
+					{Name: "a", Notify: []string{"done"}},
+					{Name: End, Notify: []string{"done"}}}},
+			commands: []string{},
+			webhooks: []string{},
+			errRegex: test.TrimYAML(`
+				^stages:
+					- item_1:
+						name: "a" <invalid> \(must be unique\)
+					- item_2:
+						name: "end" <invalid>.*$`)},
+		"unknown actions": {
+			pipeline: &Pipeline{
+				Stages: []*Stage{
+					{Name: "a", Command: []string{"foo"}, WebHook: []string{"bar"}, Notify: []string{"baz"}}}},
+			commands: []string{},
+			webhooks: []string{"deploy"},
+			errRegex: test.TrimYAML(`
+				^stages:
+					- item_0:
+						command: "foo" <invalid>.*
+						webhook: "bar" <invalid>.*
+						notify: "baz" <invalid>.*
+				webhook: "deploy" <required>.*$`)},
+		"branch to an earlier stage": {
+			pipeline: &Pipeline{
+				Stages: []*Stage{
+					{Name: "a", Notify: []string{"done"}},
+					{Name: "b", Notify: []string{"done"}, OnSuccess: "a", OnFailure: "b"}}},
+			commands: []string{},
+			webhooks: []string{},
+			errRegex: test.TrimYAML(`
+				^stages:
+					- item_1:
+						on_success: "a" <invalid>.*
+						on_failure: "b" <invalid>.*$`)},
+		"branch to an unknown stage": {
+			pipeline: &Pipeline{
+				Stages: []*Stage{
+					{Name: "a", WebHook: []string{"deploy"}, OnFailure: "unknown"}}},
+			commands: []string{},
+			errRegex: test.TrimYAML(`
+				^stages:
+					- item_0:
+						on_failure: "unknown" <invalid>.*$`)},
+		"command not in a stage": {
+			pipeline: &Pipeline{
+				Stages: []*Stage{
+					{Name: "a", WebHook: []string{"deploy"}}}},
+			commands: []string{"./backup.sh"},
+			errRegex: `^command: "./backup.sh" <required> \(every command must be in a stage\)$`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			serviceCommands := commands
+			if tc.commands != nil {
+				serviceCommands = tc.commands
+			}
+			serviceWebHooks := webhooks
+			if tc.webhooks != nil {
+				serviceWebHooks = tc.webhooks
+			}
+
+			// WHEN CheckValues is called
+			err := tc.pipeline.CheckValues("", serviceCommands, serviceWebHooks, notifies)
+
+			// THEN the expected error is returned
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for:\n%q\nnot:\n%q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
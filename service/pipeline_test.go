@@ -0,0 +1,200 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package service
+
+import (
+	"testing"
+
+	"github.com/release-argus/Argus/command"
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/notify/shoutrrr"
+	"github.com/release-argus/Argus/outbox"
+	"github.com/release-argus/Argus/service/pipeline"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestService_runPipeline(t *testing.T) {
+	// GIVEN a Service with Commands in a Pipeline
+	tests := map[string]struct {
+		stages                []*pipeline.Stage
+		wantRan               []bool // per Command.
+		deployedBecomesLatest bool
+	}{
+		"every stage passes": {
+			stages: []*pipeline.Stage{
+				{Name: "first", Command: []string{"true"}},
+				{Name: "second", Command: []string{"ls"}, OnSuccess: pipeline.End},
+				{Name: "on_fail", Command: []string{"false"}}},
+			wantRan:               []bool{true, true, false},
+			deployedBecomesLatest: true},
+		"stage fails, running the on_failure branch": {
+			stages: []*pipeline.Stage{
+				{Name: "first", Command: []string{"false"}, OnFailure: "on_fail"},
+				{Name: "second", Command: []string{"ls"}},
+				{Name: "on_fail", Command: []string{"true"}}},
+			wantRan:               []bool{true, false, true},
+			deployedBecomesLatest: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := testService(t, name, "url")
+			svc.DeployedVersionLookup = nil
+			announceChannel := make(chan []byte, 50)
+			svc.Status.SetAnnounceChannel(&announceChannel)
+			svc.Command = command.Slice{{"true"}, {"ls"}, {"false"}}
+			svc.Status.Init(
+				0, len(svc.Command), 0,
+				&svc.ID, nil,
+				&svc.Dashboard.WebURL)
+			svc.CommandController = &command.Controller{}
+			svc.CommandController.Init(
+				&svc.Status,
+				&svc.Command,
+				nil,
+				&svc.Options.Interval)
+			svc.Pipeline = &pipeline.Pipeline{Stages: tc.stages}
+			svc.Pipeline.Init(&svc.Status)
+			want := svc.Status.LatestVersion()
+
+			// WHEN runPipeline is called
+			svc.runPipeline(true)
+
+			// THEN only the Commands of the Stages reached ran
+			for i, wantRan := range tc.wantRan {
+				ran := !svc.CommandController.NextRunnable(i).IsZero()
+				if ran != wantRan {
+					t.Errorf("Command %q: want ran=%t, not %t",
+						svc.Command[i].String(), wantRan, ran)
+				}
+			}
+			// AND the DeployedVersion becomes the LatestVersion only when every Stage reached passed
+			got := svc.Status.DeployedVersion()
+			if (got == want) != tc.deployedBecomesLatest {
+				t.Errorf("want DeployedVersion to become LatestVersion=%t, got %q (latest=%q)",
+					tc.deployedBecomesLatest, got, want)
+			}
+		})
+	}
+}
+
+func TestPipelineActions_RunCommand_NoRetry(t *testing.T) {
+	// GIVEN the outbox is enabled
+	databaseChannel := make(chan dbtype.Message, 10)
+	outbox.SetDatabaseChannel(&databaseChannel)
+	// AND a Service with a failing Command
+	svc := testService(t, "TestPipelineActions_RunCommand_NoRetry", "url")
+	svc.Command = command.Slice{{"false"}}
+	svc.Status.Init(
+		0, len(svc.Command), 0,
+		&svc.ID, nil,
+		&svc.Dashboard.WebURL)
+	svc.CommandController = &command.Controller{}
+	svc.CommandController.Init(
+		&svc.Status,
+		&svc.Command,
+		nil,
+		&svc.Options.Interval)
+	t.Cleanup(func() {
+		outbox.RemoveService(svc.ID)
+		outbox.Stop()
+	})
+	actions := &pipelineActions{service: svc, serviceInfo: svc.ServiceInfo()}
+
+	// WHEN the Command is run as a pipeline step
+	err := actions.RunCommand("false")
+
+	// THEN it fails
+	if err == nil {
+		t.Fatal("want the Command to fail")
+	}
+	// AND it isn't left in the outbox to retry
+	for _, entry := range outbox.List() {
+		if entry.ServiceID == svc.ID {
+			t.Errorf("pipeline step added to the outbox: %+v",
+				entry)
+		}
+	}
+}
+
+func TestPipelineActions_NotFound(t *testing.T) {
+	// GIVEN a Service without the actions referenced
+	svc := testService(t, "TestPipelineActions_NotFound", "url")
+	actions := &pipelineActions{service: svc}
+
+	// WHEN each action is run
+	errs := map[string]error{
+		"command": actions.RunCommand("foo"),
+		"webhook": actions.SendWebHook("foo"),
+		"notify":  actions.SendNotify("foo")}
+
+	// THEN they each error
+	for kind, err := range errs {
+		want := kind + ` "foo" not found`
+		if e := util.ErrorToString(err); e != want {
+			t.Errorf("%s: want %q, not %q",
+				kind, want, e)
+		}
+	}
+}
+
+func TestService_notifiersOutsidePipeline(t *testing.T) {
+	// GIVEN a Service with Notifiers, and maybe a Pipeline
+	tests := map[string]struct {
+		pipeline *pipeline.Pipeline
+		want     []string
+	}{
+		"no pipeline": {
+			want: []string{"a", "b", "c"}},
+		"pipeline sends some": {
+			pipeline: &pipeline.Pipeline{
+				Stages: []*pipeline.Stage{
+					{Name: "x", Notify: []string{"b"}}}},
+			want: []string{"a", "c"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := &Service{
+				Notify: shoutrrr.Slice{
+					"a": &shoutrrr.Shoutrrr{},
+					"b": &shoutrrr.Shoutrrr{},
+					"c": &shoutrrr.Shoutrrr{}},
+				Pipeline: tc.pipeline}
+
+			// WHEN notifiersOutsidePipeline is called
+			got := svc.notifiersOutsidePipeline()
+
+			// THEN the Notifiers not in the Pipeline are returned
+			gotKeys := util.SortedKeys(*got)
+			if len(gotKeys) != len(tc.want) {
+				t.Fatalf("want %v, not %v",
+					tc.want, gotKeys)
+			}
+			for i := range tc.want {
+				if gotKeys[i] != tc.want[i] {
+					t.Errorf("want %v, not %v",
+						tc.want, gotKeys)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package service provides the service functionality for Argus.
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/release-argus/Argus/service/rollback"
+	"github.com/release-argus/Argus/util"
+)
+
+// verifyDeployment waits for the DeployedVersionLookup to report the LatestVersion,
+// and if it doesn't within the Rollback Timeout, runs the Rollback (templated with
+// the version deployed before the actions) and sends the failure to the Notifiers.
+func (s *Service) verifyDeployment(writeToDB bool) {
+	version := s.Status.LatestVersion()
+	rollbackVersion := s.Status.DeployedVersion()
+	logFrom := util.LogFrom{Primary: "Rollback", Secondary: s.ID}
+	ctx := s.Status.Context()
+
+	err := s.Rollback.Verify(
+		ctx,
+		version,
+		func() (string, error) {
+			return s.DeployedVersionLookup.Query(ctx, false, logFrom)
+		})
+	if errors.Is(err, rollback.ErrVerifying) || ctx.Err() != nil {
+		return
+	}
+	if err == nil {
+		jLog.Info(
+			fmt.Sprintf("Verified %q is deployed", version),
+			logFrom, true)
+		s.DeployedVersionLookup.HandleNewVersion(version, writeToDB)
+		return
+	}
+
+	// Roll back.
+	jLog.Error(
+		fmt.Errorf("failed to verify %q is deployed, rolling back to %q: %w",
+			version, rollbackVersion, err),
+		logFrom, true)
+	s.Status.SetRollbackVersion(rollbackVersion)
+	message := fmt.Sprintf("Rolled back to %q as %s", rollbackVersion, err)
+	if rollbackErr := s.Rollback.Run(ctx, logFrom); rollbackErr != nil {
+		jLog.Error(rollbackErr, logFrom, true)
+		message = fmt.Sprintf("Failed to roll back to %q as %s:\n%s",
+			rollbackVersion, err, rollbackErr)
+	}
+
+	// Alert.
+	serviceInfo := s.ServiceInfo()
+	serviceInfo.Failure = message
+	//#nosec G104 -- Errors are logged to CLI
+	//nolint:errcheck // ^
+	s.Notify.Send(
+		ctx,
+		fmt.Sprintf("Deployment of %q failed verification for %q", version, s.ID),
+		message,
+		serviceInfo,
+		false)
+}
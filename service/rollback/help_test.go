@@ -0,0 +1,38 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package rollback
+
+import (
+	"os"
+	"testing"
+
+	"github.com/release-argus/Argus/command"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestMain(m *testing.M) {
+	// initialise jLog
+	jLog := util.NewJLog("DEBUG", false)
+	jLog.Testing = true
+	command.LogInit(jLog)
+
+	// run other tests
+	exitCode := m.Run()
+
+	// exit
+	os.Exit(exitCode)
+}
@@ -0,0 +1,110 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rollback provides the verification of a deployment after the actions of a Service,
+// and the rollback of it if the deployed version doesn't follow.
+package rollback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/release-argus/Argus/util"
+	"github.com/release-argus/Argus/webhook"
+)
+
+// ErrVerifying is returned by Verify when `version` is already being verified.
+var ErrVerifying = errors.New("already verifying this version")
+
+// Verify queries the deployed version with `query` every Interval
+// until it reports `version`, returning an error if it doesn't within the Timeout.
+//
+// Cancelling `ctx` ends the verification early (e.g. when the Service is deleted),
+// returning the error of the context.
+func (r *Rollback) Verify(ctx context.Context, version string, query func() (string, error)) error {
+	r.mutex.Lock()
+	if r.verifying == version {
+		r.mutex.Unlock()
+		return ErrVerifying
+	}
+	r.verifying = version
+	r.mutex.Unlock()
+	defer func() {
+		r.mutex.Lock()
+		r.verifying = ""
+		r.mutex.Unlock()
+	}()
+
+	timeout := r.GetTimeoutDuration()
+	deadline := time.Now().Add(timeout)
+	var (
+		deployedVersion string
+		err             error
+	)
+	for {
+		deployedVersion, err = query()
+		if err == nil && deployedVersion == version {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			break
+		}
+		if !util.Sleep(ctx, min(r.GetIntervalDuration(), time.Until(deadline))) {
+			return ctx.Err() //nolint:wrapcheck
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("deployed_version not %q after %s: %w",
+			version, timeout, err)
+	}
+	return fmt.Errorf("deployed_version %q not %q after %s",
+		deployedVersion, version, timeout)
+}
+
+// Run the rollback Commands, and send the rollback WebHooks (cancelled with `ctx`).
+func (r *Rollback) Run(ctx context.Context, logFrom util.LogFrom) error {
+	if r == nil {
+		return nil
+	}
+
+	errChan := make(chan error, len(r.Command)+len(r.WebHook))
+	// Run the Command(s) in order.
+	go func() {
+		for _, cmd := range r.Command {
+			command := cmd.ApplyTemplate(r.ServiceStatus)
+			errChan <- command.Exec(ctx, logFrom)
+		}
+	}()
+	// Send the WebHook(s).
+	var serviceInfo util.ServiceInfo
+	if r.ServiceStatus != nil {
+		serviceInfo = r.ServiceStatus.ServiceInfo()
+	}
+	for _, wh := range r.WebHook {
+		go func(wh *webhook.WebHook) {
+			errChan <- wh.Send(ctx, serviceInfo, false)
+		}(wh)
+	}
+
+	var errs []error
+	for range len(r.Command) + len(r.WebHook) {
+		if err := <-errChan; err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
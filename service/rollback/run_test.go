@@ -0,0 +1,203 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package rollback
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/release-argus/Argus/command"
+	"github.com/release-argus/Argus/service/status"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestRollback_Verify(t *testing.T) {
+	// GIVEN a Rollback and a deployed_version query
+	tests := map[string]struct {
+		versions []string // deployed version returned by each query (last repeats).
+		queryErr error
+		cancel   bool
+		errRegex string
+		minTries int32
+		maxTries int32
+	}{
+		"already deployed": {
+			versions: []string{"2.0.0"},
+			errRegex: `^$`,
+			minTries: 1, maxTries: 1},
+		"deployed after a few queries": {
+			versions: []string{"1.0.0", "1.0.0", "2.0.0"},
+			errRegex: `^$`,
+			minTries: 3, maxTries: 3},
+		"never deployed": {
+			versions: []string{"1.0.0"},
+			errRegex: `^deployed_version "1.0.0" not "2.0.0" after 100ms$`,
+			minTries: 2, maxTries: 20},
+		"query errors": {
+			queryErr: errors.New("connection refused"),
+			errRegex: `^deployed_version not "2.0.0" after 100ms: connection refused$`,
+			minTries: 2, maxTries: 20},
+		"stopped": {
+			versions: []string{"1.0.0"},
+			cancel:   true,
+			errRegex: `^context canceled$`,
+			minTries: 1, maxTries: 1},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rollback := &Rollback{
+				Timeout:  "100ms",
+				Interval: "10ms"}
+			var tries atomic.Int32
+			query := func() (string, error) {
+				try := int(tries.Add(1))
+				if tc.queryErr != nil {
+					return "", tc.queryErr
+				}
+				return tc.versions[min(try, len(tc.versions))-1], nil
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if tc.cancel {
+				cancel()
+			}
+			defer cancel()
+
+			// WHEN Verify is called
+			err := rollback.Verify(ctx, "2.0.0", query)
+
+			// THEN the expected error is returned
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the deployed version was queried until verified, the timeout, or cancelled
+			if got := tries.Load(); got < tc.minTries || got > tc.maxTries {
+				t.Errorf("want %d-%d queries, not %d",
+					tc.minTries, tc.maxTries, got)
+			}
+		})
+	}
+}
+
+func TestRollback_Verify_AlreadyVerifying(t *testing.T) {
+	// GIVEN a Rollback verifying a version
+	rollback := &Rollback{
+		Timeout:  "1s",
+		Interval: "10ms"}
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- rollback.Verify(context.Background(), "2.0.0",
+			func() (string, error) {
+				<-release
+				return "2.0.0", nil
+			})
+	}()
+	for {
+		rollback.mutex.Lock()
+		verifying := rollback.verifying
+		rollback.mutex.Unlock()
+		if verifying != "" {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// WHEN Verify is called for that version again
+	err := rollback.Verify(context.Background(), "2.0.0", nil)
+
+	// THEN it returns ErrVerifying
+	if !errors.Is(err, ErrVerifying) {
+		t.Errorf("want ErrVerifying, not %v", err)
+	}
+	// AND the first verification continues
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("first verification errored: %v", err)
+	}
+}
+
+func TestRollback_Run(t *testing.T) {
+	// GIVEN a Rollback with Commands templated with the rollback_version
+	tests := map[string]struct {
+		command  command.Slice
+		errRegex string
+	}{
+		"nil rollback": {
+			errRegex: `^$`},
+		"command succeeds": {
+			command: command.Slice{
+				{"touch", "{{ rollback_version }}"}},
+			errRegex: `^$`},
+		"command fails": {
+			command: command.Slice{
+				{"touch", "{{ rollback_version }}"},
+				{"false"}},
+			errRegex: `^exit status 1$`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var rollback *Rollback
+			dir := t.TempDir()
+			if tc.command != nil {
+				svcStatus := status.New(
+					nil, nil, nil,
+					"", "", "", "", "", "")
+				svcStatus.SetRollbackVersion(filepath.Join(dir, "1.0.0"))
+				rollback = &Rollback{
+					Timeout: "1s",
+					Command: tc.command}
+				rollback.Init(
+					svcStatus,
+					nil, nil, nil,
+					nil,
+					nil)
+			}
+
+			// WHEN Run is called
+			err := rollback.Run(context.Background(), util.LogFrom{Primary: name})
+
+			// THEN the expected error is returned
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the Commands ran with the rollback_version
+			if tc.command == nil {
+				return
+			}
+			if _, err := os.Stat(filepath.Join(dir, "1.0.0")); err != nil {
+				t.Errorf("want the command templated with the rollback_version to have run: %v",
+					err)
+			}
+		})
+	}
+}
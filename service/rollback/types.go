@@ -0,0 +1,91 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rollback provides the verification of a deployment after the actions of a Service,
+// and the rollback of it if the deployed version doesn't follow.
+package rollback
+
+import (
+	"sync"
+	"time"
+
+	"github.com/release-argus/Argus/command"
+	"github.com/release-argus/Argus/notify/shoutrrr"
+	"github.com/release-argus/Argus/service/status"
+	"github.com/release-argus/Argus/webhook"
+)
+
+// DefaultInterval is the time between deployed_version queries whilst verifying.
+const DefaultInterval = "10s"
+
+// Rollback verifies the deployed_version reports the approved version
+// within a Timeout of the actions finishing, and rolls back if it doesn't.
+type Rollback struct {
+	Timeout  string        `yaml:"timeout,omitempty" json:"timeout,omitempty"`   // Time for deployed_version to report the approved version, e.g. 10m.
+	Interval string        `yaml:"interval,omitempty" json:"interval,omitempty"` // Time between deployed_version queries whilst verifying (default = 10s).
+	Command  command.Slice `yaml:"command,omitempty" json:"command,omitempty"`   // Commands to roll back with.
+	WebHook  webhook.Slice `yaml:"webhook,omitempty" json:"webhook,omitempty"`   // WebHooks to roll back with.
+
+	mutex     sync.Mutex          // Mutex for concurrent access.
+	verifying string              // Version being verified.
+	fails     status.FailsWebHook // Whether each WebHook failed (kept out of the Service's Fails).
+
+	ServiceStatus *status.Status `yaml:"-" json:"-"` // Status of the Service (for templating).
+}
+
+// Init the Rollback WebHooks with the Service's Status and WebHook defaults.
+//
+// The Rollback WebHooks track their fails apart from those of the Service,
+// so they don't block the next version being marked as deployed
+// (call before the Service's WebHooks are initialised, as they may share IDs).
+func (r *Rollback) Init(
+	serviceStatus *status.Status,
+	mains *webhook.SliceDefaults,
+	defaults, hardDefaults *webhook.Defaults,
+	shoutrrrNotifiers *shoutrrr.Slice,
+	parentInterval *string,
+) {
+	if r == nil {
+		return
+	}
+
+	r.ServiceStatus = serviceStatus
+	r.fails.Init(len(r.WebHook))
+	r.WebHook.Init(
+		serviceStatus,
+		mains, defaults, hardDefaults,
+		shoutrrrNotifiers,
+		parentInterval)
+	for id, wh := range r.WebHook {
+		serviceStatus.Fails.WebHook.Delete(id)
+		wh.Failed = &r.fails
+		wh.Failed.Set(id, nil)
+	}
+}
+
+// GetTimeoutDuration returns the Timeout as a time.Duration.
+func (r *Rollback) GetTimeoutDuration() time.Duration {
+	timeout, _ := time.ParseDuration(r.Timeout)
+	return timeout
+}
+
+// GetIntervalDuration returns the Interval as a time.Duration (default = DefaultInterval).
+func (r *Rollback) GetIntervalDuration() time.Duration {
+	interval := r.Interval
+	if interval == "" {
+		interval = DefaultInterval
+	}
+	duration, _ := time.ParseDuration(interval)
+	return duration
+}
@@ -0,0 +1,59 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rollback provides the verification of a deployment after the actions of a Service,
+// and the rollback of it if the deployed version doesn't follow.
+package rollback
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/release-argus/Argus/util"
+)
+
+// CheckValues validates the fields of the Rollback.
+func (r *Rollback) CheckValues(prefix string) error {
+	if r == nil {
+		return nil
+	}
+
+	var errs []error
+	// timeout
+	if r.Timeout == "" {
+		errs = append(errs,
+			fmt.Errorf("%stimeout: <required> (time to wait for deployed_version to report the new version, e.g. 10m)",
+				prefix))
+	} else if timeout, err := time.ParseDuration(r.Timeout); err != nil || timeout <= 0 {
+		errs = append(errs,
+			fmt.Errorf("%stimeout: %q <invalid> (Use 'AhBmCs' duration format)",
+				prefix, r.Timeout))
+	}
+	// interval
+	if r.Interval != "" {
+		if interval, err := time.ParseDuration(r.Interval); err != nil || interval <= 0 {
+			errs = append(errs,
+				fmt.Errorf("%sinterval: %q <invalid> (Use 'AhBmCs' duration format)",
+					prefix, r.Interval))
+		}
+	}
+	util.AppendCheckError(&errs, prefix, "command", r.Command.CheckValues(prefix+"  "))
+	util.AppendCheckError(&errs, prefix, "webhook", r.WebHook.CheckValues(prefix+"  "))
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
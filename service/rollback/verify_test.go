@@ -0,0 +1,84 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package rollback
+
+import (
+	"testing"
+
+	"github.com/release-argus/Argus/command"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestRollback_CheckValues(t *testing.T) {
+	// GIVEN a Rollback
+	tests := map[string]struct {
+		rollback *Rollback
+		errRegex string
+	}{
+		"nil rollback": {
+			rollback: nil,
+			errRegex: `^$`},
+		"valid rollback": {
+			rollback: &Rollback{
+				Timeout:  "10m",
+				Interval: "30s",
+				Command: command.Slice{
+					{"./rollback.sh", "{{ rollback_version }}"}}},
+			errRegex: `^$`},
+		"no timeout": {
+			rollback: &Rollback{},
+			errRegex: `^timeout: <required>.*$`},
+		"invalid timeout": {
+			rollback: &Rollback{
+				Timeout: "ten minutes"},
+			errRegex: `^timeout: "ten minutes" <invalid>.*$`},
+		"negative timeout": {
+			rollback: &Rollback{
+				Timeout: "-1m"},
+			errRegex: `^timeout: "-1m" <invalid>.*$`},
+		"invalid interval": {
+			rollback: &Rollback{
+				Timeout:  "10m",
+				Interval: "0s"},
+			errRegex: `^interval: "0s" <invalid>.*$`},
+		"invalid command": {
+			rollback: &Rollback{
+				Timeout: "10m",
+				Command: command.Slice{
+					{"./rollback.sh", "{{ rollback_version }"}}},
+			errRegex: test.TrimYAML(`
+				^command:
+					item_0: .* <invalid> \(didn't pass templating\)$`)},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN CheckValues is called
+			err := tc.rollback.CheckValues("")
+
+			// THEN the expected error is returned
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for:\n%q\nnot:\n%q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
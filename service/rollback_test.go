@@ -0,0 +1,95 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/release-argus/Argus/command"
+	"github.com/release-argus/Argus/service/rollback"
+)
+
+func TestService_verifyDeployment(t *testing.T) {
+	// GIVEN a Service with a Rollback, and a deployed_version that may follow the actions
+	tests := map[string]struct {
+		deployed       string
+		wantDeployed   string
+		wantRolledBack bool
+	}{
+		"deployed version follows": {
+			deployed:       "2.2.2",
+			wantDeployed:   "2.2.2",
+			wantRolledBack: false},
+		"deployed version doesn't follow": {
+			deployed:       "0.0.0",
+			wantDeployed:   "0.0.0",
+			wantRolledBack: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"version":%q}`, tc.deployed)
+			}))
+			t.Cleanup(server.Close)
+			dir := t.TempDir()
+
+			svc := testService(t, name, "url")
+			announceChannel := make(chan []byte, 50)
+			svc.Status.SetAnnounceChannel(&announceChannel)
+			svc.DeployedVersionLookup.URL = server.URL
+			svc.Rollback = &rollback.Rollback{
+				Timeout:  "200ms",
+				Interval: "10ms",
+				Command: command.Slice{
+					{"touch", filepath.Join(dir, "{{ rollback_version }}")}}}
+			svc.Rollback.Init(
+				&svc.Status,
+				nil, nil, nil,
+				&svc.Notify,
+				&svc.Options.Interval)
+
+			// WHEN verifyDeployment is called after the actions for the LatestVersion
+			svc.verifyDeployment(false)
+
+			// THEN the DeployedVersion is as expected
+			if got := svc.Status.DeployedVersion(); got != tc.wantDeployed {
+				t.Errorf("want DeployedVersion %q, not %q",
+					tc.wantDeployed, got)
+			}
+			// AND the Rollback ran with the previous DeployedVersion only when it didn't follow
+			_, err := os.Stat(filepath.Join(dir, "0.0.0"))
+			if rolledBack := err == nil; rolledBack != tc.wantRolledBack {
+				t.Errorf("want rolled back=%t, not %t",
+					tc.wantRolledBack, rolledBack)
+			}
+			if tc.wantRolledBack {
+				if got := svc.Status.RollbackVersion(); got != "0.0.0" {
+					t.Errorf("want RollbackVersion %q, not %q",
+						"0.0.0", got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package status provides the status functionality to keep track of the approved/deployed/latest versions of a Service.
+package status
+
+import (
+	"sync"
+	"time"
+
+	"github.com/release-argus/Argus/web/metric"
+)
+
+var (
+	backoffBase = time.Minute   // Backoff after the first failure (doubled on each consecutive failure).
+	backoffMax  = 2 * time.Hour // Cap of the backoff.
+)
+
+// Backoffs keeps track of the backoff of the latest/deployed version lookups.
+type Backoffs struct {
+	LatestVersion   Backoff `yaml:"-" json:"-"` // LatestVersion lookup backoff.
+	DeployedVersion Backoff `yaml:"-" json:"-"` // DeployedVersion lookup backoff.
+}
+
+// Backoff of the queries of a lookup after consecutive failures.
+type Backoff struct {
+	mutex      sync.RWMutex // Mutex for concurrent access.
+	serviceID  *string      // ID of the Service (for metrics).
+	lookupType string       // Type of lookup, 'latest_version'/'deployed_version' (for metrics).
+	failures   uint         // Count of consecutive failed queries.
+	retryAfter time.Time    // Time the source asked not to be queried before (Retry-After/rate-limit reset).
+	until      time.Time    // Time to not query before.
+}
+
+// Init the Backoff.
+func (b *Backoff) Init(serviceID *string, lookupType string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.serviceID = serviceID
+	b.lookupType = lookupType
+}
+
+// Failures returns the count of consecutive failed queries.
+func (b *Backoff) Failures() uint {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.failures
+}
+
+// Until returns the time to not query before (zero if not backing off).
+func (b *Backoff) Until() time.Time {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.until
+}
+
+// String returns the UTC timestamp the Backoff is until,
+// (empty if not currently backing off).
+func (b *Backoff) String() string {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if !b.until.After(time.Now()) {
+		return ""
+	}
+	return b.until.UTC().Format(time.RFC3339)
+}
+
+// SetRetryAfter records a time the source asked not to be queried before,
+// applied on the next Fail.
+func (b *Backoff) SetRetryAfter(t time.Time) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if t.After(b.retryAfter) {
+		b.retryAfter = t
+	}
+}
+
+// Fail records a failed query, and backs off the next query by
+// backoffBase, doubled for each consecutive failure (up to backoffMax),
+// or until the time the source asked to retry after (if later).
+func (b *Backoff) Fail() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures++
+	delay := backoffMax
+	if shift := b.failures - 1; shift < 32 && backoffBase<<shift < backoffMax {
+		delay = backoffBase << shift
+	}
+	now := time.Now().UTC()
+	b.until = now.Add(delay)
+	if b.retryAfter.After(b.until) {
+		b.until = b.retryAfter
+	}
+	b.retryAfter = time.Time{}
+
+	b.setMetric(b.until.Sub(now))
+}
+
+// Reset the Backoff after a successful query.
+func (b *Backoff) Reset() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.failures == 0 && b.until.IsZero() {
+		return
+	}
+	b.failures = 0
+	b.retryAfter = time.Time{}
+	b.until = time.Time{}
+
+	b.setMetric(0)
+}
+
+// Next returns when the next query is due,
+// `scheduled`, or the end of the backoff (if later).
+func (b *Backoff) Next(scheduled time.Time) time.Time {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if b.until.After(scheduled) {
+		return b.until
+	}
+	return scheduled
+}
+
+// setMetric sets the Prometheus metric of the backoff to `delay`.
+func (b *Backoff) setMetric(delay time.Duration) {
+	if b.serviceID == nil {
+		return
+	}
+
+	metric.SetPrometheusGauge(metric.QueryBackoffSeconds,
+		*b.serviceID, b.lookupType,
+		delay.Seconds())
+}
+
+// DeleteMetrics of the Backoff.
+func (b *Backoff) DeleteMetrics() {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if b.serviceID == nil {
+		return
+	}
+
+	metric.DeletePrometheusGauge(metric.QueryBackoffSeconds,
+		*b.serviceID, b.lookupType)
+}
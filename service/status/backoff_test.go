@@ -0,0 +1,216 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package status
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/web/metric"
+)
+
+func TestBackoff_Fail(t *testing.T) {
+	// GIVEN a Backoff that has failed a number of times
+	tests := map[string]struct {
+		failures   int
+		retryAfter time.Duration
+		want       time.Duration
+	}{
+		"1 failure": {
+			failures: 1,
+			want:     backoffBase,
+		},
+		"2 failures": {
+			failures: 2,
+			want:     2 * backoffBase,
+		},
+		"4 failures": {
+			failures: 4,
+			want:     8 * backoffBase,
+		},
+		"capped": {
+			failures: 10,
+			want:     backoffMax,
+		},
+		"many failures don't overflow": {
+			failures: 100,
+			want:     backoffMax,
+		},
+		"retry after later than the backoff": {
+			failures:   1,
+			retryAfter: time.Hour,
+			want:       time.Hour,
+		},
+		"retry after earlier than the backoff": {
+			failures:   3,
+			retryAfter: time.Second,
+			want:       4 * backoffBase,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var backoff Backoff
+			backoff.Init(test.StringPtr(name), "latest_version")
+			t.Cleanup(func() { backoff.DeleteMetrics() })
+
+			// WHEN Fail is called `failures` times
+			for i := 0; i < tc.failures; i++ {
+				if i == tc.failures-1 && tc.retryAfter != 0 {
+					backoff.SetRetryAfter(time.Now().Add(tc.retryAfter))
+				}
+				backoff.Fail()
+			}
+
+			// THEN the failures are counted
+			if got := backoff.Failures(); got != uint(tc.failures) {
+				t.Errorf("Failures() - want: %d, got: %d",
+					tc.failures, got)
+			}
+			// AND the next query is backed off by the expected amount
+			got := time.Until(backoff.Until())
+			if got > tc.want || got < tc.want-time.Second {
+				t.Errorf("Until() - want: ~%s, got: %s",
+					tc.want, got)
+			}
+			// AND the metric holds the backoff
+			gotMetric := testutil.ToFloat64(metric.QueryBackoffSeconds.WithLabelValues(name, "latest_version"))
+			if gotMetric > tc.want.Seconds() || gotMetric < (tc.want-time.Second).Seconds() {
+				t.Errorf("metric - want: ~%f, got: %f",
+					tc.want.Seconds(), gotMetric)
+			}
+		})
+	}
+}
+
+func TestBackoff_Reset(t *testing.T) {
+	// GIVEN a Backoff that has failed
+	var backoff Backoff
+	id := "TestBackoff_Reset"
+	backoff.Init(&id, "deployed_version")
+	t.Cleanup(func() { backoff.DeleteMetrics() })
+	backoff.SetRetryAfter(time.Now().Add(time.Hour))
+	backoff.Fail()
+	backoff.SetRetryAfter(time.Now().Add(time.Hour))
+
+	// WHEN Reset is called
+	backoff.Reset()
+
+	// THEN the failures are cleared
+	if got := backoff.Failures(); got != 0 {
+		t.Errorf("Failures() - want: 0, got: %d",
+			got)
+	}
+	// AND it's no longer backing off
+	if got := backoff.Until(); !got.IsZero() {
+		t.Errorf("Until() - want: zero, got: %s",
+			got)
+	}
+	// AND the metric is reset
+	if got := testutil.ToFloat64(metric.QueryBackoffSeconds.WithLabelValues(id, "deployed_version")); got != 0 {
+		t.Errorf("metric - want: 0, got: %f",
+			got)
+	}
+	// AND the pending retry after is cleared
+	backoff.Fail()
+	if got := time.Until(backoff.Until()); got > backoffBase {
+		t.Errorf("Until() after Fail - want: <=%s, got: %s",
+			backoffBase, got)
+	}
+}
+
+func TestBackoff_Next(t *testing.T) {
+	now := time.Now()
+	// GIVEN a Backoff, and a scheduled time for the next query
+	tests := map[string]struct {
+		until, scheduled time.Time
+		want             time.Time
+	}{
+		"not backing off": {
+			scheduled: now.Add(time.Minute),
+			want:      now.Add(time.Minute),
+		},
+		"backoff ends before the schedule": {
+			until:     now.Add(time.Second),
+			scheduled: now.Add(time.Minute),
+			want:      now.Add(time.Minute),
+		},
+		"backoff ends after the schedule": {
+			until:     now.Add(time.Hour),
+			scheduled: now.Add(time.Minute),
+			want:      now.Add(time.Hour),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			backoff := Backoff{until: tc.until}
+
+			// WHEN Next is called
+			got := backoff.Next(tc.scheduled)
+
+			// THEN the later of the schedule and backoff is returned
+			if !got.Equal(tc.want) {
+				t.Errorf("want: %s, got: %s",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestBackoff_String(t *testing.T) {
+	// GIVEN a Backoff
+	tests := map[string]struct {
+		until time.Time
+		want  string
+	}{
+		"not backing off": {
+			want: "",
+		},
+		"backoff ended": {
+			until: time.Now().Add(-time.Minute),
+			want:  "",
+		},
+		"backing off": {
+			until: time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC),
+			want:  "2100-01-01T00:00:00Z",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			backoff := Backoff{until: tc.until}
+
+			// WHEN String is called
+			got := backoff.String()
+
+			// THEN the timestamp of the current backoff is returned
+			if got != tc.want {
+				t.Errorf("want: %q, got: %q",
+					tc.want, got)
+			}
+		})
+	}
+}
@@ -60,6 +60,22 @@ func (f *failsBase) Set(index string, state *bool) {
 	f.fails[index] = state
 }
 
+// Delete the fail state of this index.
+func (f *failsBase) Delete(index string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	delete(f.fails, index)
+}
+
+// Keys returns the indexes, sorted.
+func (f *failsBase) Keys() []string {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	return util.SortedKeys(f.fails)
+}
+
 // AllPassed returns whether all the indexes have passed (fail=false).
 func (f *failsBase) AllPassed() bool {
 	f.mutex.RLock()
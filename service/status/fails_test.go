@@ -315,6 +315,26 @@ func TestFailsBase_Reset(t *testing.T) {
 	}
 }
 
+func TestFailsBase_Delete(t *testing.T) {
+	// GIVEN a Fails with a failed index
+	var fails FailsWebHook
+	fails.Init(2)
+	fails.Set("a", test.BoolPtr(true))
+	fails.Set("b", test.BoolPtr(false))
+
+	// WHEN we call Delete on the failed index
+	fails.Delete("a")
+
+	// THEN that index is removed
+	if got := fails.Length(); got != 1 {
+		t.Errorf("want length 1, not %d", got)
+	}
+	// AND the rest have passed
+	if !fails.AllPassed() {
+		t.Error("want AllPassed=true after deleting the only fail")
+	}
+}
+
 func TestFailsBase_Length(t *testing.T) {
 	// GIVEN a Fails
 	tests := map[string]struct {
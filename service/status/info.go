@@ -0,0 +1,209 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package status provides the status functionality to keep track of the approved/deployed/latest versions of a Service.
+package status
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/release-argus/Argus/actionlink"
+	"github.com/release-argus/Argus/util"
+)
+
+var (
+	publicURLMutex sync.RWMutex
+	publicURL      string
+)
+
+// SetPublicURL sets the URL that Argus is reachable at, used to build links for templates.
+func SetPublicURL(url string) {
+	publicURLMutex.Lock()
+	defer publicURLMutex.Unlock()
+
+	publicURL = strings.TrimRight(url, "/")
+}
+
+// getPublicURL returns the URL that Argus is reachable at.
+func getPublicURL() string {
+	publicURLMutex.RLock()
+	defer publicURLMutex.RUnlock()
+
+	return publicURL
+}
+
+// Release holds the details of a release.
+type Release struct {
+	Notes  string   // Release notes.
+	URL    string   // URL of the release.
+	Assets []string // Download URLs of the release assets.
+}
+
+// Release returns the details of the LatestVersion release.
+func (s *Status) Release() Release {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.release
+}
+
+// SetRelease sets the details of the LatestVersion release.
+func (s *Status) SetRelease(release Release) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.release = release
+}
+
+// WebHookOutputs returns the values captured from the response of WebHook `webhookID`.
+func (s *Status) WebHookOutputs(webhookID string) map[string]string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return util.CopyMap(s.webhookOutputs[webhookID])
+}
+
+// SetWebHookOutputs sets the values captured from the response of WebHook `webhookID`.
+func (s *Status) SetWebHookOutputs(webhookID string, outputs map[string]string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(outputs) == 0 {
+		delete(s.webhookOutputs, webhookID)
+		return
+	}
+	if s.webhookOutputs == nil {
+		s.webhookOutputs = make(map[string]map[string]string, 1)
+	}
+	s.webhookOutputs[webhookID] = util.CopyMap(outputs)
+}
+
+// RollbackVersion returns the DeployedVersion before the actions of the ApprovedVersion ran.
+func (s *Status) RollbackVersion() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.rollbackVersion
+}
+
+// SetRollbackVersion sets the version to roll back to if the actions of the ApprovedVersion fail verification.
+func (s *Status) SetRollbackVersion(version string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.rollbackVersion = version
+}
+
+// PreviousLatestVersion returns the LatestVersion before the current one.
+func (s *Status) PreviousLatestVersion() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.previousLatestVersion
+}
+
+// SetPreviousLatestVersion sets the LatestVersion before the current one
+// (e.g. to restore it from the database after SetLatestVersion).
+func (s *Status) SetPreviousLatestVersion(version string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.previousLatestVersion = version
+}
+
+// ServiceInfo returns the template context of the Status.
+func (s *Status) ServiceInfo() util.ServiceInfo {
+	webURL := s.GetWebURL()
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var approvalURL, approveURL, skipURL string
+	var webhookURLs map[string]string
+	if url := getPublicURL(); url != "" {
+		approvalURL = url + "/approvals"
+		// Signed links to act on the LatestVersion.
+		if serviceID := util.DereferenceOrDefault(s.ServiceID); serviceID != "" && s.latestVersion != "" {
+			approveURL = actionlink.New(serviceID, s.latestVersion, actionlink.ActionApprove, "").URL(url)
+			skipURL = actionlink.New(serviceID, s.latestVersion, actionlink.ActionSkip, "").URL(url)
+			if webhookIDs := s.Fails.WebHook.Keys(); len(webhookIDs) != 0 {
+				webhookURLs = make(map[string]string, len(webhookIDs))
+				for _, id := range webhookIDs {
+					webhookURLs[id] = actionlink.New(serviceID, s.latestVersion, actionlink.ActionWebHook, id).URL(url)
+				}
+			}
+		}
+	}
+
+	return util.ServiceInfo{
+		ID:              util.DereferenceOrDefault(s.ServiceID),
+		Name:            util.DereferenceOrDefault(s.ServiceName),
+		WebURL:          webURL,
+		LatestVersion:   s.latestVersion,
+		PreviousVersion: s.previousLatestVersion,
+		DeployedVersion: s.deployedVersion,
+		ApprovedVersion: s.approvedVersion,
+		RollbackVersion: s.rollbackVersion,
+		UpdateType:      updateType(s.deployedVersion, s.latestVersion),
+		ReleaseDate:     s.latestVersionTimestamp,
+		ReleaseNotes:    s.release.Notes,
+		ReleaseURL:      s.release.URL,
+		ReleaseAssets:   s.release.Assets,
+		ApprovalURL:     approvalURL,
+		ApproveURL:      approveURL,
+		SkipURL:         skipURL,
+		WebHookURLs:     webhookURLs,
+		WebHookOutputs:  s.webhookOutputsCopy()}
+}
+
+// webhookOutputsCopy returns a copy of the WebHook outputs (nil if there are none).
+//
+// (Caller must hold the mutex).
+func (s *Status) webhookOutputsCopy() map[string]map[string]string {
+	if len(s.webhookOutputs) == 0 {
+		return nil
+	}
+	outputs := make(map[string]map[string]string, len(s.webhookOutputs))
+	for id, values := range s.webhookOutputs {
+		outputs[id] = util.CopyMap(values)
+	}
+	return outputs
+}
+
+// updateType returns the type of update from `from` to `to`
+// ("major", "minor", "patch" or "prerelease"),
+// or "" if either isn't a semantic version, or `to` isn't newer.
+func updateType(from, to string) string {
+	fromSV, err := semver.NewVersion(from)
+	if err != nil {
+		return ""
+	}
+	toSV, err := semver.NewVersion(to)
+	if err != nil || !toSV.GreaterThan(fromSV) {
+		return ""
+	}
+
+	switch {
+	case toSV.Major() != fromSV.Major():
+		return "major"
+	case toSV.Minor() != fromSV.Minor():
+		return "minor"
+	case toSV.Patch() != fromSV.Patch():
+		return "patch"
+	default:
+		return "prerelease"
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package status
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/release-argus/Argus/actionlink"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestStatus_ServiceInfo(t *testing.T) {
+	// GIVEN a Status with release details.
+	tests := map[string]struct {
+		publicURL string
+		webhooks  []string
+		want      util.ServiceInfo
+		wantLinks bool
+		wantHooks []string
+	}{
+		"no public_url": {
+			want: util.ServiceInfo{
+				ID:              "test-service",
+				Name:            "test-service",
+				WebURL:          "https://example.com",
+				LatestVersion:   "2.2.2",
+				PreviousVersion: "",
+				DeployedVersion: "0.0.0",
+				ApprovedVersion: "1.1.1",
+				UpdateType:      "major",
+				ReleaseDate:     "2002-02-02T02:02:02Z",
+				ReleaseNotes:    "notes",
+				ReleaseURL:      "https://example.com/release",
+				ReleaseAssets:   []string{"https://example.com/asset"}}},
+		"public_url": {
+			publicURL: "https://argus.example.com/",
+			want: util.ServiceInfo{
+				ID:              "test-service",
+				Name:            "test-service",
+				WebURL:          "https://example.com",
+				LatestVersion:   "2.2.2",
+				PreviousVersion: "",
+				DeployedVersion: "0.0.0",
+				ApprovedVersion: "1.1.1",
+				UpdateType:      "major",
+				ReleaseDate:     "2002-02-02T02:02:02Z",
+				ReleaseNotes:    "notes",
+				ReleaseURL:      "https://example.com/release",
+				ReleaseAssets:   []string{"https://example.com/asset"},
+				ApprovalURL:     "https://argus.example.com/approvals"},
+			wantLinks: true},
+		"public_url with webhooks": {
+			publicURL: "https://argus.example.com",
+			webhooks:  []string{"hook_a", "hook_b"},
+			want: util.ServiceInfo{
+				ID:              "test-service",
+				Name:            "test-service",
+				WebURL:          "https://example.com",
+				LatestVersion:   "2.2.2",
+				PreviousVersion: "",
+				DeployedVersion: "0.0.0",
+				ApprovedVersion: "1.1.1",
+				UpdateType:      "major",
+				ReleaseDate:     "2002-02-02T02:02:02Z",
+				ReleaseNotes:    "notes",
+				ReleaseURL:      "https://example.com/release",
+				ReleaseAssets:   []string{"https://example.com/asset"},
+				ApprovalURL:     "https://argus.example.com/approvals"},
+			wantLinks: true,
+			wantHooks: []string{"hook_a", "hook_b"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// t.Parallel() - Cannot run in parallel since we're using the package-level publicURL.
+
+			status := testStatus()
+			status.SetRelease(Release{
+				Notes:  "notes",
+				URL:    "https://example.com/release",
+				Assets: []string{"https://example.com/asset"}})
+			for _, id := range tc.webhooks {
+				status.Fails.WebHook.Set(id, nil)
+			}
+			SetPublicURL(tc.publicURL)
+			t.Cleanup(func() { SetPublicURL("") })
+
+			// WHEN ServiceInfo is called on it.
+			got := status.ServiceInfo()
+
+			// THEN the action links are signed for the LatestVersion.
+			links := map[string]string{
+				actionlink.ActionApprove: got.ApproveURL,
+				actionlink.ActionSkip:    got.SkipURL}
+			for id, url := range got.WebHookURLs {
+				links[actionlink.ActionWebHook+":"+id] = url
+			}
+			if tc.wantLinks != (got.ApproveURL != "") || tc.wantLinks != (got.SkipURL != "") {
+				t.Errorf("want action links: %t, got approve_url=%q, skip_url=%q",
+					tc.wantLinks, got.ApproveURL, got.SkipURL)
+			}
+			if len(got.WebHookURLs) != len(tc.wantHooks) {
+				t.Errorf("want webhook_urls for %v, got %v",
+					tc.wantHooks, got.WebHookURLs)
+			}
+			for key, url := range links {
+				if url == "" {
+					continue
+				}
+				prefix := strings.TrimSuffix(tc.publicURL, "/") + actionlink.Path
+				link, err := actionlink.Parse(strings.TrimPrefix(url, prefix), time.Now())
+				action, target, _ := strings.Cut(key, ":")
+				if !strings.HasPrefix(url, prefix) || err != nil ||
+					link.ServiceID != "test-service" || link.Version != "2.2.2" ||
+					link.Action != action || link.Target != target {
+					t.Errorf("%s link invalid: %q (%v) -> %+v",
+						key, url, err, link)
+				}
+			}
+			got.ApproveURL, got.SkipURL, got.WebHookURLs = "", "", nil
+			// AND the rest of the ServiceInfo is as expected.
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("want: %#v\ngot:  %#v",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestStatus_SetLatestVersion_Release(t *testing.T) {
+	// GIVEN a Status with release details.
+	status := testStatus()
+	status.SetRelease(Release{Notes: "notes"})
+	previousLatestVersion := status.LatestVersion()
+
+	// WHEN SetLatestVersion is called with a new version.
+	status.SetLatestVersion("3.3.3", "", true)
+
+	// THEN the release details are cleared.
+	if got := status.Release(); !reflect.DeepEqual(got, Release{}) {
+		t.Errorf("release not cleared, got %#v",
+			got)
+	}
+	// AND the PreviousLatestVersion is the version before.
+	if got := status.PreviousLatestVersion(); got != previousLatestVersion {
+		t.Errorf("PreviousLatestVersion want %q, got %q",
+			previousLatestVersion, got)
+	}
+	// AND it's written to the database, to be restored after a restart.
+	if got := len(*status.DatabaseChannel); got != 1 {
+		t.Fatalf("want 1 database message, got %d",
+			got)
+	}
+	msg := <-*status.DatabaseChannel
+	written := false
+	for _, cell := range msg.Cells {
+		written = written || (cell.Column == "previous_version" && cell.Value == previousLatestVersion)
+	}
+	if !written {
+		t.Errorf("want previous_version=%q written, got %+v",
+			previousLatestVersion, msg.Cells)
+	}
+}
+
+func TestStatus_WebHookOutputs(t *testing.T) {
+	// GIVEN a Status.
+	status := testStatus()
+
+	// WHEN SetWebHookOutputs is called for a WebHook.
+	outputs := map[string]string{"job_id": "42"}
+	status.SetWebHookOutputs("deploy", outputs)
+	outputs["job_id"] = "changed"
+
+	// THEN a copy of the outputs are stored for that WebHook.
+	if got := status.WebHookOutputs("deploy"); !reflect.DeepEqual(got, map[string]string{"job_id": "42"}) {
+		t.Errorf("WebHookOutputs want %v, got %v",
+			map[string]string{"job_id": "42"}, got)
+	}
+	// AND they are in the ServiceInfo.
+	want := map[string]map[string]string{"deploy": {"job_id": "42"}}
+	if got := status.ServiceInfo().WebHookOutputs; !reflect.DeepEqual(got, want) {
+		t.Errorf("ServiceInfo().WebHookOutputs want %v, got %v",
+			want, got)
+	}
+
+	// WHEN SetWebHookOutputs is called with no outputs.
+	status.SetWebHookOutputs("deploy", nil)
+
+	// THEN the outputs of that WebHook are removed.
+	if got := status.ServiceInfo().WebHookOutputs; got != nil {
+		t.Errorf("ServiceInfo().WebHookOutputs want nil, got %v",
+			got)
+	}
+
+	// WHEN SetLatestVersion is called with a new version.
+	status.SetWebHookOutputs("deploy", map[string]string{"job_id": "43"})
+	status.SetLatestVersion("3.3.3", "", false)
+
+	// THEN the outputs are cleared.
+	if got := status.WebHookOutputs("deploy"); len(got) != 0 {
+		t.Errorf("WebHookOutputs not cleared, got %v",
+			got)
+	}
+}
+
+func TestStatus_RollbackVersion(t *testing.T) {
+	// GIVEN a Status.
+	status := testStatus()
+
+	// WHEN SetRollbackVersion is called.
+	status.SetRollbackVersion("0.0.0")
+
+	// THEN the RollbackVersion is set.
+	if got := status.RollbackVersion(); got != "0.0.0" {
+		t.Errorf("RollbackVersion want %q, got %q",
+			"0.0.0", got)
+	}
+	// AND it is in the ServiceInfo.
+	if got := status.ServiceInfo().RollbackVersion; got != "0.0.0" {
+		t.Errorf("ServiceInfo().RollbackVersion want %q, got %q",
+			"0.0.0", got)
+	}
+}
+
+func TestUpdateType(t *testing.T) {
+	// GIVEN two versions.
+	tests := map[string]struct {
+		from, to string
+		want     string
+	}{
+		"major":             {from: "1.2.3", to: "2.0.0", want: "major"},
+		"minor":             {from: "1.2.3", to: "1.3.0", want: "minor"},
+		"patch":             {from: "1.2.3", to: "1.2.4", want: "patch"},
+		"prerelease":        {from: "1.2.3-rc.1", to: "1.2.3", want: "prerelease"},
+		"v prefix":          {from: "v1.2.3", to: "v1.3.0", want: "minor"},
+		"older":             {from: "1.2.3", to: "1.2.2", want: ""},
+		"same":              {from: "1.2.3", to: "1.2.3", want: ""},
+		"from not semantic": {from: "foo", to: "1.2.3", want: ""},
+		"to not semantic":   {from: "1.2.3", to: "bar", want: ""},
+		"no deployed":       {from: "", to: "1.2.3", want: ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN updateType is called.
+			got := updateType(tc.from, tc.to)
+
+			// THEN the type of update is returned.
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
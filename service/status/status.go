@@ -16,12 +16,14 @@
 package status
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
 	"time"
 
 	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/outbox"
 	"github.com/release-argus/Argus/util"
 	"github.com/release-argus/Argus/web/metric"
 )
@@ -59,17 +61,26 @@ type Status struct {
 	ServiceName *string `yaml:"-" json:"-"` // Name of the Service.
 	WebURL      *string `yaml:"-" json:"-"` // Web URL of the Service.
 
-	mutex                    sync.RWMutex // Lock for the Status.
-	approvedVersion          string       // The version of the Service that has been approved for deployment.
-	deployedVersion          string       // The version of the Service that is deployed.
-	deployedVersionTimestamp string       // UTC timestamp of latest DeployedVersion change.
-	latestVersion            string       // The latest version of the Service found from query().
-	latestVersionTimestamp   string       // UTC timestamp of latest LatestVersion change.
-	lastQueried              string       // UTC timestamp of latest LatestVersion query.
-	regexMissesContent       uint         // Counter for the amount of regex misses on the URL content.
-	regexMissesVersion       uint         // Counter for the amount of regex misses on the version.
-	Fails                    Fails        // Track the Notify/WebHook fails.
-	deleting                 bool         // Flag to indicate undergoing deletion.
+	mutex                    sync.RWMutex                 // Lock for the Status.
+	approvedVersion          string                       // The version of the Service that has been approved for deployment.
+	approvedBy               string                       // Who is approving/skipping the LatestVersion.
+	deployedVersion          string                       // The version of the Service that is deployed.
+	deployedVersionTimestamp string                       // UTC timestamp of latest DeployedVersion change.
+	latestVersion            string                       // The latest version of the Service found from query().
+	latestVersionTimestamp   string                       // UTC timestamp of latest LatestVersion change.
+	previousLatestVersion    string                       // The LatestVersion before the current one.
+	rollbackVersion          string                       // The DeployedVersion before the actions of the ApprovedVersion ran.
+	queuedVersion            string                       // The version the automated actions are queued for, waiting for a change window.
+	release                  Release                      // Details of the LatestVersion release.
+	webhookOutputs           map[string]map[string]string // Values captured from the WebHook responses for the LatestVersion.
+	lastQueried              string                       // UTC timestamp of latest LatestVersion query.
+	regexMissesContent       uint                         // Counter for the amount of regex misses on the URL content.
+	regexMissesVersion       uint                         // Counter for the amount of regex misses on the version.
+	Fails                    Fails                        // Track the Notify/WebHook fails.
+	Backoffs                 Backoffs                     // Backoff of the latest/deployed version queries after failures.
+	deleting                 bool                         // Flag to indicate undergoing deletion.
+	ctx                      context.Context              // Context the Service is tracked with (done on deletion/shutdown).
+	cancel                   context.CancelFunc           // Cancels ctx.
 }
 
 // New Status struct.
@@ -175,6 +186,8 @@ func (s *Status) Init(
 	s.Fails.Shoutrrr.Init(shoutrrrs)
 	s.Fails.Command.Init(commands)
 	s.Fails.WebHook.Init(webhooks)
+	s.Backoffs.LatestVersion.Init(serviceID, "latest_version")
+	s.Backoffs.DeployedVersion.Init(serviceID, "deployed_version")
 
 	s.ServiceID = serviceID
 	s.ServiceName = serviceName
@@ -222,6 +235,15 @@ func (s *Status) ApprovedVersion() string {
 	return s.approvedVersion
 }
 
+// SetApprovedBy records who is approving/skipping the LatestVersion,
+// to attribute the next ApprovedVersion change to in the version history.
+func (s *Status) SetApprovedBy(approver string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.approvedBy = approver
+}
+
 // SetApprovedVersion will set .ApprovedVersion to `version`.
 func (s *Status) SetApprovedVersion(version string, writeToDB bool) {
 	s.mutex.Lock()
@@ -259,11 +281,45 @@ func (s *Status) SetApprovedVersion(version string, writeToDB bool) {
 		message := dbtype.Message{
 			ServiceID: *s.ServiceID,
 			Cells: []dbtype.Cell{
-				{Column: "approved_version", Value: version}}}
+				{Column: "approved_version", Value: version}},
+			Actor: s.approvedBy}
 		s.sendDatabase(&message)
 	}
 }
 
+// QueuedVersion returns the version the automated actions are queued for (empty if none are).
+func (s *Status) QueuedVersion() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.queuedVersion
+}
+
+// SetQueuedVersion sets the version the automated actions are queued for (empty = none),
+// so they can be queued again after a restart.
+func (s *Status) SetQueuedVersion(version string, writeToDB bool) {
+	s.mutex.Lock()
+	// Do not modify if unchanged.
+	if s.queuedVersion == version {
+		s.mutex.Unlock()
+		return
+	}
+
+	s.queuedVersion = version
+	s.mutex.Unlock()
+
+	if writeToDB {
+		s.mutex.RLock()
+		defer s.mutex.RUnlock()
+
+		// Database.
+		s.sendDatabase(&dbtype.Message{
+			ServiceID: *s.ServiceID,
+			Cells: []dbtype.Cell{
+				{Column: "queued_version", Value: version}}})
+	}
+}
+
 // DeployedVersion returns the DeployedVersion.
 func (s *Status) DeployedVersion() string {
 	s.mutex.RLock()
@@ -305,6 +361,8 @@ func (s *Status) SetDeployedVersion(version, releaseDate string, writeToDB bool)
 
 		// Clear the fail status of WebHooks/Commands.
 		s.Fails.resetFails()
+		// Drop the retries of the WebHooks/Commands of the previous version.
+		outbox.RemovePendingActions(*s.ServiceID)
 
 		message := dbtype.Message{
 			ServiceID: *s.ServiceID,
@@ -342,7 +400,11 @@ func (s *Status) SetLatestVersion(version, releaseDate string, writeToDB bool) {
 	}
 
 	previousLatestVersion := s.latestVersion
+	s.previousLatestVersion = previousLatestVersion
 	s.latestVersion = version
+	s.approvedBy = ""
+	s.release = Release{}
+	s.webhookOutputs = nil
 	if releaseDate != "" {
 		s.latestVersionTimestamp = releaseDate
 	} else {
@@ -365,7 +427,8 @@ func (s *Status) SetLatestVersion(version, releaseDate string, writeToDB bool) {
 			ServiceID: *s.ServiceID,
 			Cells: []dbtype.Cell{
 				{Column: "latest_version", Value: s.latestVersion},
-				{Column: "latest_version_timestamp", Value: s.latestVersionTimestamp}}}
+				{Column: "latest_version_timestamp", Value: s.latestVersionTimestamp},
+				{Column: "previous_version", Value: previousLatestVersion}}}
 		s.sendDatabase(&message)
 	}
 }
@@ -421,10 +484,16 @@ func (s *Status) ResetRegexMisses() {
 }
 
 // SetDeleting will set `deleting` flag.
+//
+// This cancels the Context of the Service, stopping any lookups/actions in progress.
 func (s *Status) SetDeleting() {
 	s.mutex.Lock()
 	{
 		s.deleting = true
+		if s.cancel == nil {
+			s.ctx, s.cancel = context.WithCancel(context.Background())
+		}
+		s.cancel()
 	}
 	s.mutex.Unlock()
 }
@@ -437,6 +506,36 @@ func (s *Status) Deleting() bool {
 	return s.deleting
 }
 
+// SetContext sets the context the Service is tracked with,
+// (cancelled early if the Service is deleted).
+func (s *Status) SetContext(ctx context.Context) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	if s.deleting {
+		s.cancel()
+	}
+}
+
+// Context returns the context the Service is tracked with
+// (background if it isn't being tracked, and done once it's deleted).
+func (s *Status) Context() context.Context {
+	if s == nil {
+		return context.Background()
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
 // SendAnnounce payload to the AnnounceChannel.
 func (s *Status) SendAnnounce(payload *[]byte) {
 	s.mutex.RLock()
@@ -515,6 +614,8 @@ func (s *Status) InitMetrics() {
 func (s *Status) DeleteMetrics() {
 	metric.DeletePrometheusGauge(metric.LatestVersionIsDeployed,
 		*s.ServiceID, "")
+	s.Backoffs.LatestVersion.DeleteMetrics()
+	s.Backoffs.DeployedVersion.DeleteMetrics()
 	metric.SetUpdatesCurrent(-1,
 		metric.GetVersionDeployedState(s.approvedVersion, s.latestVersion, s.deployedVersion))
 }
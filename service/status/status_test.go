@@ -17,6 +17,7 @@
 package status
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -179,6 +180,67 @@ func TestStatus_SetLastQueried(t *testing.T) {
 	}
 }
 
+func TestStatus_SetQueuedVersion(t *testing.T) {
+	// GIVEN a Status with a DatabaseChannel.
+	tests := map[string]struct {
+		hadQueuedVersion string
+		queuing          string
+		writeToDB        bool
+		wantMessages     int
+	}{
+		"queue, writing to DB": {
+			queuing:      "1.2.3",
+			writeToDB:    true,
+			wantMessages: 1},
+		"queue, not writing to DB": {
+			queuing:      "1.2.3",
+			writeToDB:    false,
+			wantMessages: 0},
+		"same version": {
+			hadQueuedVersion: "1.2.3",
+			queuing:          "1.2.3",
+			writeToDB:        true,
+			wantMessages:     0},
+		"clear": {
+			hadQueuedVersion: "1.2.3",
+			queuing:          "",
+			writeToDB:        true,
+			wantMessages:     1},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			databaseChannel := make(chan dbtype.Message, 4)
+			status := Status{ServiceID: test.StringPtr(name)}
+			status.DatabaseChannel = &databaseChannel
+			status.queuedVersion = tc.hadQueuedVersion
+
+			// WHEN SetQueuedVersion is called.
+			status.SetQueuedVersion(tc.queuing, tc.writeToDB)
+
+			// THEN the QueuedVersion is set.
+			if got := status.QueuedVersion(); got != tc.queuing {
+				t.Errorf("QueuedVersion\nwant: %q\ngot:  %q",
+					tc.queuing, got)
+			}
+			// AND it's only written to the database when it changed, and writeToDB.
+			if got := len(databaseChannel); got != tc.wantMessages {
+				t.Fatalf("want %d database messages, got %d",
+					tc.wantMessages, got)
+			}
+			if tc.wantMessages != 0 {
+				msg := <-databaseChannel
+				if len(msg.Cells) != 1 || msg.Cells[0].Column != "queued_version" || msg.Cells[0].Value != tc.queuing {
+					t.Errorf("want queued_version=%q written, got %+v",
+						tc.queuing, msg)
+				}
+			}
+		})
+	}
+}
+
 func TestStatus_ApprovedVersion(t *testing.T) {
 	deployedVersion := "0.0.1"
 	latestVersion := "0.0.3"
@@ -186,6 +248,7 @@ func TestStatus_ApprovedVersion(t *testing.T) {
 	tests := map[string]struct {
 		hadApprovedVersion            string
 		approving                     string
+		approvedBy                    string
 		latestVersionIsDeployedMetric float64
 		wantMessages                  int
 	}{
@@ -202,6 +265,7 @@ func TestStatus_ApprovedVersion(t *testing.T) {
 		},
 		"Skipping LatestVersion": {
 			approving:                     "SKIP_" + latestVersion,
+			approvedBy:                    "bob",
 			latestVersionIsDeployedMetric: 3,
 			wantMessages:                  1,
 		},
@@ -234,6 +298,7 @@ func TestStatus_ApprovedVersion(t *testing.T) {
 				test.StringPtr("https://example.com"))
 			status.SetLatestVersion(latestVersion, "", false)
 			status.SetDeployedVersion(deployedVersion, "", false)
+			status.SetApprovedBy(tc.approvedBy)
 
 			// WHEN SetApprovedVersion is called.
 			status.SetApprovedVersion(tc.approving, true)
@@ -267,6 +332,13 @@ func TestStatus_ApprovedVersion(t *testing.T) {
 				t.Errorf("DatabaseChannel should have %d message(s), but has %d",
 					tc.wantMessages, len(*status.DatabaseChannel))
 			}
+			// 	attributed to the approver.
+			if tc.wantMessages != 0 {
+				if message := <-*status.DatabaseChannel; message.Actor != tc.approvedBy {
+					t.Errorf("DatabaseChannel message Actor\nwant: %q\ngot:  %q",
+						tc.approvedBy, message.Actor)
+				}
+			}
 			// AND LatestVersionIsDeployedVersion metric is updated.
 			gotMetric := testutil.ToFloat64(metric.LatestVersionIsDeployed.WithLabelValues(*status.ServiceID))
 			if gotMetric != tc.latestVersionIsDeployedMetric {
@@ -1294,6 +1366,74 @@ func TestStatus_SetDeleting(t *testing.T) {
 	}
 }
 
+func TestStatus_Context(t *testing.T) {
+	// GIVEN a nil Status, and a Status that isn't tracked.
+	var nilStatus *Status
+	status := Status{}
+
+	// WHEN Context is called on them.
+	// THEN they give the background context.
+	if got := nilStatus.Context(); got != context.Background() {
+		t.Errorf("nil Status: want the background context, got %v", got)
+	}
+	if got := status.Context(); got != context.Background() {
+		t.Errorf("untracked Status: want the background context, got %v", got)
+	}
+
+	// WHEN SetContext is called on it.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	status.SetContext(ctx)
+
+	// THEN Context gives a context derived from it.
+	got := status.Context()
+	if got.Err() != nil {
+		t.Fatalf("want a context that isn't done, got %v", got.Err())
+	}
+
+	// WHEN the Status is set as deleting.
+	status.SetDeleting()
+
+	// THEN its Context is done, but not the parent.
+	if got.Err() == nil {
+		t.Errorf("want the context done after SetDeleting")
+	}
+	if ctx.Err() != nil {
+		t.Errorf("want the parent context untouched, got %v", ctx.Err())
+	}
+}
+
+func TestStatus_Context_Cancelled(t *testing.T) {
+	// GIVEN a tracked Status.
+	ctx, cancel := context.WithCancel(context.Background())
+	status := Status{}
+	status.SetContext(ctx)
+
+	// WHEN the context it's tracked with is cancelled.
+	cancel()
+
+	// THEN its Context is done.
+	if err := status.Context().Err(); err == nil {
+		t.Errorf("want the context done after the parent was cancelled")
+	}
+
+	// GIVEN an untracked Status.
+	status = Status{}
+
+	// WHEN it's set as deleting.
+	status.SetDeleting()
+
+	// THEN its Context is done.
+	if err := status.Context().Err(); err == nil {
+		t.Errorf("want the context done after SetDeleting")
+	}
+	// AND contexts it's tracked with afterwards are cancelled.
+	status.SetContext(context.Background())
+	if err := status.Context().Err(); err == nil {
+		t.Errorf("want the context done when set on a deleting Status")
+	}
+}
+
 func TestStatus_SameVersions(t *testing.T) {
 	type versions struct {
 		approvedVersion, deployedVersion, latestVersion string
@@ -16,6 +16,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -25,25 +26,35 @@ import (
 )
 
 // Track the Service and send Notify messages and WebHooks when a new release is found.
-// Pause for s.Interval between each check.
-func (s *Service) Track() {
+// Pause until the next query is due (by the interval/schedule, with jitter) between each check,
+// backing off exponentially after consecutive failures, and stop once `ctx` is done (or the Service is deleted).
+func (s *Service) Track(ctx context.Context) {
 	// Skip inactive Services.
 	if !s.Options.GetActive() {
 		return
 	}
+	s.Status.SetContext(ctx)
+	ctx = s.Status.Context()
 	s.initMetrics()
 
-	// Wait until the interval has elapsed.
-	lastQueriedAt, _ := time.Parse(time.RFC3339, s.Status.LastQueried())
-	if time.Since(lastQueriedAt) < s.Options.GetIntervalDuration() {
-		time.Sleep(s.Options.GetIntervalDuration() - time.Since(lastQueriedAt))
+	// Queue the actions that were waiting for the change window before a restart.
+	s.RequeueActions(s.Status.QueuedVersion())
+
+	// Wait until the next query after the last one is due.
+	if lastQueriedAt, err := time.Parse(time.RFC3339, s.Status.LastQueried()); err == nil {
+		if !util.Sleep(ctx, time.Until(s.Options.NextQuery(lastQueriedAt))) {
+			return
+		}
 	}
 
 	// Track the deployed version in an infinite loop goroutine.
 	go func() {
-		time.Sleep(2 * time.Second) // Give LatestVersion some time to query first.
+		// Give LatestVersion some time to query first.
+		if !util.Sleep(ctx, 2*time.Second) {
+			return
+		}
 
-		go s.DeployedVersionLookup.Track()
+		go s.DeployedVersionLookup.Track(ctx)
 	}()
 
 	// If we have no LatestVersion, we can't track.
@@ -53,9 +64,13 @@ func (s *Service) Track() {
 
 	// Track forever.
 	logFrom := util.LogFrom{Primary: s.ID}
+	every := "every " + s.Options.GetInterval()
+	if schedule := s.Options.GetSchedule(); schedule != "" {
+		every = fmt.Sprintf("on the schedule %q", schedule)
+	}
 	jLog.Verbose(
-		fmt.Sprintf("Tracking %s at %s every %s",
-			s.ID, s.LatestVersion.ServiceURL(true), s.Options.GetInterval()),
+		fmt.Sprintf("Tracking %s at %s %s",
+			s.ID, s.LatestVersion.ServiceURL(true), every),
 		logFrom,
 		true)
 	for {
@@ -65,20 +80,35 @@ func (s *Service) Track() {
 		}
 
 		// Query the Lookup.
-		newVersion, _ := s.LatestVersion.Query(true, logFrom)
+		newVersion, err := s.LatestVersion.Query(ctx, true, logFrom)
+		backoff := &s.Status.Backoffs.LatestVersion
+		switch {
+		case err == nil:
+			backoff.Reset()
+		case ctx.Err() == nil:
+			backoff.Fail()
+			jLog.Verbose(
+				fmt.Sprintf("%d consecutive failures, backing off until %s",
+					backoff.Failures(), backoff.Until().Format(time.RFC3339)),
+				logFrom,
+				true)
+		}
 
 		// If new version found.
 		if newVersion {
 			go s.HandleUpdateActions(true)
 		}
 
-		// Sleep interval between checks.
-		time.Sleep(s.Options.GetIntervalDuration())
+		// Sleep until the next check (backing off after failures).
+		if !util.Sleep(ctx, time.Until(backoff.Next(s.Options.NextQuery(time.Now())))) {
+			return
+		}
 	}
 }
 
-// Track will call Track on all Services in this Slice, each in their own goroutine.
-func (s *Slice) Track(ordering *[]string, orderMutex *sync.RWMutex) {
+// Track will call Track on all Services in this Slice, each in their own goroutine,
+// until `ctx` is done.
+func (s *Slice) Track(ctx context.Context, ordering *[]string, orderMutex *sync.RWMutex) {
 	metric.InitMetrics()
 
 	orderMutex.RLock()
@@ -92,9 +122,11 @@ func (s *Slice) Track(ordering *[]string, orderMutex *sync.RWMutex) {
 		svc.Options.Active = nil
 
 		// Track this Service in an infinite loop goroutine.
-		go svc.Track()
+		go svc.Track(ctx)
 
 		// Space out the tracking of each Service.
-		time.Sleep(time.Second / 2)
+		if !util.Sleep(ctx, time.Second/2) {
+			return
+		}
 	}
 }
@@ -17,7 +17,10 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 	"time"
@@ -36,7 +39,7 @@ import (
 
 func TestService_Track(t *testing.T) {
 	testURLService := testService(t, "TestService_Track", "url")
-	testURLService.LatestVersion.Query(false, util.LogFrom{})
+	testURLService.LatestVersion.Query(context.Background(), false, util.LogFrom{})
 	testURLLatestVersion := testURLService.Status.LatestVersion()
 
 	type overrides struct {
@@ -490,7 +493,7 @@ func TestService_Track(t *testing.T) {
 
 			// WHEN Track is called on it.
 			go func() {
-				svc.Track()
+				svc.Track(context.Background())
 				didFinish <- true
 			}()
 			for i := 0; i < 200; i++ {
@@ -593,6 +596,90 @@ func TestService_Track(t *testing.T) {
 	}
 }
 
+func TestService_Track_Shutdown(t *testing.T) {
+	// GIVEN a Service waiting for its interval to elapse.
+	svc := testService(t, "TestService_Track_Shutdown", "url")
+	svc.Options.Interval = "1h"
+	svc.Status.SetLastQueried(time.Now().UTC().Format(time.RFC3339))
+	ctx, cancel := context.WithCancel(context.Background())
+	didFinish := make(chan bool, 1)
+
+	// WHEN Track is called on it, and the context is cancelled.
+	go func() {
+		svc.Track(ctx)
+		didFinish <- true
+	}()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	// THEN Track stops without waiting for the interval.
+	select {
+	case <-didFinish:
+	case <-time.After(time.Second):
+		t.Fatal("Track didn't stop on shutdown")
+	}
+	// AND the context the Service holds for its actions is done.
+	if svc.Status.Context().Err() == nil {
+		t.Error("Status.Context() isn't derived from the context Track was called with")
+	}
+}
+
+func TestService_Track_Delete(t *testing.T) {
+	// GIVEN a Service waiting for its interval to elapse.
+	svc := testService(t, "TestService_Track_Delete", "url")
+	svc.Options.Interval = "1h"
+	svc.Status.SetLastQueried(time.Now().UTC().Format(time.RFC3339))
+	didFinish := make(chan bool, 1)
+
+	// WHEN Track is called on it, and the Service is deleted.
+	go func() {
+		svc.Track(context.Background())
+		didFinish <- true
+	}()
+	time.Sleep(100 * time.Millisecond)
+	svc.Status.SetDeleting()
+
+	// THEN Track stops without waiting for the interval.
+	select {
+	case <-didFinish:
+	case <-time.After(time.Second):
+		t.Fatal("Track didn't stop on deletion")
+	}
+	// AND the context the Service holds for its actions is done.
+	if svc.Status.Context().Err() == nil {
+		t.Error("Status.Context() not cancelled on deletion")
+	}
+}
+
+func TestService_Track_Backoff(t *testing.T) {
+	// GIVEN a Service whose source is unavailable, and a short interval.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+	svc := testService(t, "TestService_Track_Backoff", "url")
+	svc.Options.Interval = "1s"
+	svc.DeployedVersionLookup = nil
+	svc.LatestVersion.(*web.Lookup).URL = server.URL
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	// WHEN Track is called on it, and its query fails.
+	go svc.Track(ctx)
+	time.Sleep(2500 * time.Millisecond)
+
+	// THEN it only queried once, backing off instead of querying every interval.
+	backoff := &svc.Status.Backoffs.LatestVersion
+	if got := backoff.Failures(); got != 1 {
+		t.Errorf("want 1 consecutive failure, not %d",
+			got)
+	}
+	// AND the backoff is exposed in the Summary.
+	if got := svc.Summary().Status.LatestVersionBackoff; got == "" {
+		t.Error("want latest_version_backoff in the Summary, got none")
+	}
+}
+
 func TestSlice_Track(t *testing.T) {
 	// GIVEN a Slice.
 	tests := map[string]struct {
@@ -634,7 +721,7 @@ func TestSlice_Track(t *testing.T) {
 			t.Parallel()
 
 			// WHEN Track is called on it.
-			slice.Track(&tc.ordering, &sync.RWMutex{})
+			slice.Track(context.Background(), &tc.ordering, &sync.RWMutex{})
 
 			// THEN the function exits straight away.
 			time.Sleep(2 * time.Second)
@@ -22,11 +22,15 @@ import (
 
 	"github.com/release-argus/Argus/command"
 	"github.com/release-argus/Argus/notify/shoutrrr"
+	"github.com/release-argus/Argus/service/approval"
 	deployedver "github.com/release-argus/Argus/service/deployed_version"
 	latestver "github.com/release-argus/Argus/service/latest_version"
 	latestver_base "github.com/release-argus/Argus/service/latest_version/types/base"
 	opt "github.com/release-argus/Argus/service/option"
+	"github.com/release-argus/Argus/service/pipeline"
+	"github.com/release-argus/Argus/service/rollback"
 	"github.com/release-argus/Argus/service/status"
+	"github.com/release-argus/Argus/service/window"
 	"github.com/release-argus/Argus/util"
 	apitype "github.com/release-argus/Argus/web/api/types"
 	"github.com/release-argus/Argus/webhook"
@@ -94,6 +98,7 @@ type Defaults struct {
 	Notify                map[string]struct{}      `yaml:"notify,omitempty" json:"notify,omitempty"`                     // Default Notifiers to give a Service.
 	Command               command.Slice            `yaml:"command,omitempty" json:"command,omitempty"`                   // Default Commands to give a Service.
 	WebHook               map[string]struct{}      `yaml:"webhook,omitempty" json:"webhook,omitempty"`                   // Default WebHooks to give a Service.
+	ChangeWindow          window.Schedule          `yaml:"change_window,omitempty" json:"change_window,omitempty"`       // Times the automated actions of every Service may run.
 	Dashboard             DashboardOptionsDefaults `yaml:"dashboard,omitempty" json:"dashboard,omitempty"`               // Dashboard defaults.
 
 	Status status.Defaults `yaml:"-" json:"-"` // Track the Status of this source (version and regex misses).
@@ -111,12 +116,18 @@ type Service struct {
 	DeployedVersionLookup *deployedver.Lookup `yaml:"deployed_version,omitempty" json:"deployed_version,omitempty"` // Vars to scrape the Service's current deployed version.
 	Notify                shoutrrr.Slice      `yaml:"notify,omitempty" json:"notify,omitempty"`                     // Service-specific Shoutrrr vars.
 	notifyFromDefaults    bool
-	CommandController     *command.Controller `yaml:"-" json:"-"`                                 // The controller for the OS Commands that tracks fails and has the announce channel.
-	Command               command.Slice       `yaml:"command,omitempty" json:"command,omitempty"` // OS Commands to run on new release.
+	CommandController     *command.Controller  `yaml:"-" json:"-"`                                                 // The controller for the OS Commands that tracks fails and has the announce channel.
+	Command               command.Slice        `yaml:"command,omitempty" json:"command,omitempty"`                 // OS Commands to run on new release.
+	CommandOptions        command.OptionsSlice `yaml:"command_options,omitempty" json:"command_options,omitempty"` // Options to run each Command with (by Command).
 	commandFromDefaults   bool
 	WebHook               webhook.Slice `yaml:"webhook,omitempty" json:"webhook,omitempty"` // Service-specific WebHook vars.
 	webhookFromDefaults   bool
-	Dashboard             DashboardOptions `yaml:"dashboard,omitempty" json:"dashboard,omitempty"` // Options for the dashboard.
+	Pipeline              *pipeline.Pipeline `yaml:"pipeline,omitempty" json:"pipeline,omitempty"`           // Order to run the Command(s)/WebHook(s)/Notify(s) in on approval.
+	Rollback              *rollback.Rollback `yaml:"rollback,omitempty" json:"rollback,omitempty"`           // Verify the actions deployed the new version, and roll back if not.
+	Approval              *approval.Policy   `yaml:"approval,omitempty" json:"approval,omitempty"`           // Approvals required before the actions of a new release run.
+	ChangeWindow          *window.Schedule   `yaml:"change_window,omitempty" json:"change_window,omitempty"` // Times the automated actions may run.
+	actionQueue           window.Queue       // Automated actions waiting for the change window to open.
+	Dashboard             DashboardOptions   `yaml:"dashboard,omitempty" json:"dashboard,omitempty"` // Options for the dashboard.
 
 	Status status.Status `yaml:"-" json:"-"` // Track the Status of this source (version and regex misses).
 
@@ -168,7 +179,9 @@ func (s *Service) Summary() *apitype.ServiceSummary {
 			DeployedVersionTimestamp: s.Status.DeployedVersionTimestamp(),
 			LatestVersion:            s.Status.LatestVersion(),
 			LatestVersionTimestamp:   s.Status.LatestVersionTimestamp(),
-			LastQueried:              s.Status.LastQueried()}}
+			LastQueried:              s.Status.LastQueried(),
+			LatestVersionBackoff:     s.Status.Backoffs.LatestVersion.String(),
+			DeployedVersionBackoff:   s.Status.Backoffs.DeployedVersion.String()}}
 
 	// Name
 	if s.MarshalName() {
@@ -48,6 +48,8 @@ func (d *Defaults) CheckValues(prefix string) error {
 
 	util.AppendCheckError(&errs, prefix, "options", d.Options.CheckValues(prefix+"  "))
 	util.AppendCheckError(&errs, prefix, "latest_version", d.LatestVersion.CheckValues(prefix+"  "))
+	util.AppendCheckError(&errs, prefix, "deployed_version", d.DeployedVersionLookup.CheckValues(prefix+"  "))
+	util.AppendCheckError(&errs, prefix, "change_window", d.ChangeWindow.CheckValues(prefix+"  "))
 
 	if len(errs) == 0 {
 		return nil
@@ -89,7 +91,19 @@ func (s *Service) CheckValues(prefix string) error {
 	util.AppendCheckError(&errs, prefix, "deployed_version", s.DeployedVersionLookup.CheckValues(errPrefix))
 	util.AppendCheckError(&errs, prefix, "notify", s.Notify.CheckValues(errPrefix))
 	util.AppendCheckError(&errs, prefix, "command", s.Command.CheckValues(errPrefix))
+	util.AppendCheckError(&errs, prefix, "command_options", s.CommandOptions.CheckValues(errPrefix, s.commandStrings()))
 	util.AppendCheckError(&errs, prefix, "webhook", s.WebHook.CheckValues(errPrefix))
+	util.AppendCheckError(&errs, prefix, "pipeline", s.Pipeline.CheckValues(errPrefix,
+		s.commandStrings(), util.SortedKeys(s.WebHook), util.SortedKeys(s.Notify)))
+	if s.Rollback != nil && s.DeployedVersionLookup == nil {
+		errs = append(errs,
+			fmt.Errorf("%srollback: <invalid> (requires deployed_version to verify the deployment)",
+				prefix))
+	} else {
+		util.AppendCheckError(&errs, prefix, "rollback", s.Rollback.CheckValues(errPrefix))
+	}
+	util.AppendCheckError(&errs, prefix, "approval", s.Approval.CheckValues(errPrefix))
+	util.AppendCheckError(&errs, prefix, "change_window", s.ChangeWindow.CheckValues(errPrefix))
 	util.AppendCheckError(&errs, prefix, "dashboard", s.Dashboard.CheckValues(errPrefix))
 
 	if len(errs) == 0 {
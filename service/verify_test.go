@@ -22,11 +22,14 @@ import (
 
 	"github.com/release-argus/Argus/command"
 	"github.com/release-argus/Argus/notify/shoutrrr"
+	"github.com/release-argus/Argus/service/approval"
 	deployedver "github.com/release-argus/Argus/service/deployed_version"
 	latestver "github.com/release-argus/Argus/service/latest_version"
 	"github.com/release-argus/Argus/service/latest_version/filter"
 	latestver_base "github.com/release-argus/Argus/service/latest_version/types/base"
 	opt "github.com/release-argus/Argus/service/option"
+	"github.com/release-argus/Argus/service/rollback"
+	"github.com/release-argus/Argus/service/window"
 	"github.com/release-argus/Argus/test"
 	"github.com/release-argus/Argus/util"
 	"github.com/release-argus/Argus/webhook"
@@ -109,6 +112,7 @@ func TestDefaults_CheckValues(t *testing.T) {
 	tests := map[string]struct {
 		options       opt.Defaults
 		latestVersion latestver_base.Defaults
+		changeWindow  window.Schedule
 		errRegex      string
 	}{
 		"valid": {
@@ -139,6 +143,18 @@ func TestDefaults_CheckValues(t *testing.T) {
 						docker:
 							type: "[^"]+" <invalid>`),
 		},
+		"change_window with errs": {
+			options: *opt.NewDefaults(
+				"10s", nil),
+			changeWindow: window.Schedule{
+				Freezes: []window.Freeze{{
+					Start: "2025-01-01"}}},
+			errRegex: test.TrimYAML(`
+				^change_window:
+					freezes:
+						- item_0:
+							end: <required>$`),
+		},
 		"all errs": {
 			options: *opt.NewDefaults(
 				"10x", nil),
@@ -162,7 +178,8 @@ func TestDefaults_CheckValues(t *testing.T) {
 
 			svc := &Defaults{
 				Options:       tc.options,
-				LatestVersion: tc.latestVersion}
+				LatestVersion: tc.latestVersion,
+				ChangeWindow:  tc.changeWindow}
 
 			// WHEN CheckValues is called
 			err := svc.CheckValues("")
@@ -540,6 +557,107 @@ func TestService_CheckValues(t *testing.T) {
 					nil, nil, nil)},
 			errRegex: "",
 		},
+		"command_options for an unknown command": {
+			svc: &Service{
+				ID: "test",
+				CommandOptions: command.OptionsSlice{
+					"./other.sh": {}}},
+			latestVersion: test.IgnoreError(t, func() (latestver.Lookup, error) {
+				return latestver.New(
+					"github",
+					"yaml", test.TrimYAML(`
+						url: release-argus/Argus
+					`),
+					nil,
+					nil,
+					nil, nil)
+			}),
+			commands: command.Slice{{
+				"./deploy.sh"}},
+			errRegex: test.TrimYAML(`
+				^command_options:
+					"./other.sh": <invalid>.*$`),
+		},
+		"rollback without deployed_version": {
+			svc: &Service{
+				ID: "test",
+				Rollback: &rollback.Rollback{
+					Timeout: "10m"}},
+			latestVersion: test.IgnoreError(t, func() (latestver.Lookup, error) {
+				return latestver.New(
+					"github",
+					"yaml", test.TrimYAML(`
+						url: release-argus/Argus
+					`),
+					nil,
+					nil,
+					nil, nil)
+			}),
+			errRegex: `^rollback: <invalid> \(requires deployed_version.*$`,
+		},
+		"rollback with errs": {
+			svc: &Service{
+				ID: "test",
+				Rollback: &rollback.Rollback{
+					Timeout: "10x"}},
+			latestVersion: test.IgnoreError(t, func() (latestver.Lookup, error) {
+				return latestver.New(
+					"github",
+					"yaml", test.TrimYAML(`
+						url: release-argus/Argus
+					`),
+					nil,
+					nil,
+					nil, nil)
+			}),
+			deployedVersion: &deployedver.Lookup{
+				URL: "https://example.com"},
+			errRegex: test.TrimYAML(`
+				^rollback:
+					timeout: "10x" <invalid>.*$`),
+		},
+		"approval with errs": {
+			svc: &Service{
+				ID: "test",
+				Approval: &approval.Policy{
+					Expiry: "a day"}},
+			latestVersion: test.IgnoreError(t, func() (latestver.Lookup, error) {
+				return latestver.New(
+					"github",
+					"yaml", test.TrimYAML(`
+						url: release-argus/Argus
+					`),
+					nil,
+					nil,
+					nil, nil)
+			}),
+			errRegex: test.TrimYAML(`
+				^approval:
+					approvers: <invalid> \(approvers can't be identified.*\)
+					expiry: "a day" <invalid>.*$`),
+		},
+		"change_window with errs": {
+			svc: &Service{
+				ID: "test",
+				ChangeWindow: &window.Schedule{
+					Windows: []window.Window{{
+						Start: "9am"}}}},
+			latestVersion: test.IgnoreError(t, func() (latestver.Lookup, error) {
+				return latestver.New(
+					"github",
+					"yaml", test.TrimYAML(`
+						url: release-argus/Argus
+					`),
+					nil,
+					nil,
+					nil, nil)
+			}),
+			errRegex: test.TrimYAML(`
+				^change_window:
+					windows:
+						- item_0:
+							start: "9am" <invalid>.*$`),
+		},
 	}
 
 	for name, tc := range tests {
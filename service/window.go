@@ -0,0 +1,118 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package service provides the service functionality for Argus.
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/release-argus/Argus/service/window"
+	"github.com/release-argus/Argus/util"
+	apitype "github.com/release-argus/Argus/web/api/types"
+)
+
+// changeWindow returns the change windows/freezes the automated actions run in
+// (the windows of the Service replace the defaults, and the freezes of both apply).
+func (s *Service) changeWindow() *window.Schedule {
+	var defaults *window.Schedule
+	if s.Defaults != nil {
+		defaults = &s.Defaults.ChangeWindow
+	}
+	return window.Merge(s.ChangeWindow, defaults)
+}
+
+// ChangeWindowOpen returns whether the change window of the automated actions is open now,
+// and if not, the time it next opens (zero if it never does).
+func (s *Service) ChangeWindowOpen() (bool, time.Time) {
+	now := time.Now().UTC()
+	schedule := s.changeWindow()
+	if schedule.Open(now) {
+		return true, time.Time{}
+	}
+	opensAt, _ := schedule.Next(now)
+	return false, opensAt
+}
+
+// runOrQueueActions runs the automated actions of the LatestVersion if the change window is open,
+// otherwise queues them until it opens.
+func (s *Service) runOrQueueActions(serviceInfo util.ServiceInfo, writeToDB bool) {
+	now := time.Now().UTC()
+	schedule := s.changeWindow()
+	if schedule.Open(now) {
+		s.Status.SetQueuedVersion("", writeToDB)
+		s.runUpdateActions(serviceInfo, writeToDB)
+		return
+	}
+
+	version := s.Status.LatestVersion()
+	logFrom := util.LogFrom{Primary: "ChangeWindow", Secondary: s.ID}
+	runAt, ok := schedule.Next(now)
+	if !ok {
+		jLog.Warn(
+			fmt.Sprintf("No change window opens for %q, waiting for approval on the Web UI", version),
+			logFrom, true)
+		s.Status.SetQueuedVersion("", writeToDB)
+		s.Status.AnnounceQueryNewVersion()
+		return
+	}
+
+	jLog.Info(
+		fmt.Sprintf("Queued WebHooks/Commands for %q until the change window opens at %s",
+			version, runAt.Format(time.RFC3339)),
+		logFrom, true)
+	// Persisted, so they're queued again after a restart.
+	s.Status.SetQueuedVersion(version, writeToDB)
+	s.actionQueue.Add(version, runAt, func() {
+		// Only for the version queued (and if the Service wasn't removed).
+		if s.Status.Deleting() || s.Status.LatestVersion() != version {
+			s.Status.SetQueuedVersion("", writeToDB)
+			return
+		}
+		s.runOrQueueActions(s.ServiceInfo(), writeToDB)
+	})
+	s.Status.AnnounceQueryNewVersion()
+}
+
+// QueuedActions returns the automated actions waiting for the change window to open
+// (nil if none are).
+func (s *Service) QueuedActions() *apitype.QueuedActions {
+	return s.actionQueue.Summary()
+}
+
+// CancelQueuedActions cancels the automated actions waiting for the change window to open,
+// returning whether any were queued.
+func (s *Service) CancelQueuedActions() bool {
+	s.Status.SetQueuedVersion("", true)
+	return s.actionQueue.Cancel()
+}
+
+// RequeueActions queues the automated actions of `version` again
+// (e.g. after a restart, or an edit replaced the Service that had them queued).
+func (s *Service) RequeueActions(version string) {
+	if version == "" {
+		return
+	}
+	//nolint:typecheck
+	if version != s.Status.LatestVersion() ||
+		(s.WebHook == nil && s.Command == nil && s.Pipeline == nil) ||
+		!s.Dashboard.GetAutoApprove() || !s.Approval.Satisfied() {
+		// No longer to run.
+		s.Status.SetQueuedVersion("", true)
+		return
+	}
+
+	s.runOrQueueActions(s.ServiceInfo(), true)
+}
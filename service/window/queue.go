@@ -0,0 +1,102 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package window provides the change windows and freeze periods the automated actions of a Service run in.
+package window
+
+import (
+	"sync"
+	"time"
+
+	apitype "github.com/release-argus/Argus/web/api/types"
+)
+
+// Queue of the automated actions waiting for a change window to open.
+type Queue struct {
+	mutex   sync.Mutex  // Mutex for concurrent access.
+	version string      // Version the actions are for.
+	queued  time.Time   // Time the actions were queued.
+	runAt   time.Time   // Time the actions will run.
+	timer   *time.Timer // Timer to run the actions at runAt.
+}
+
+// Add `run` to the Queue for `version`, to run at `runAt`
+// (replacing any actions already queued).
+func (q *Queue) Add(version string, runAt time.Time, run func()) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.stop()
+	q.version = version
+	q.queued = time.Now().UTC()
+	q.runAt = runAt
+
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Until(runAt), func() {
+		q.mutex.Lock()
+		// Cancelled/replaced since.
+		if q.timer != timer {
+			q.mutex.Unlock()
+			return
+		}
+		q.stop()
+		q.mutex.Unlock()
+
+		run()
+	})
+	q.timer = timer
+}
+
+// Cancel the queued actions, returning whether any were queued.
+func (q *Queue) Cancel() bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	queued := q.timer != nil
+	q.stop()
+	return queued
+}
+
+// stop the timer and clear the Queue.
+func (q *Queue) stop() {
+	if q.timer != nil {
+		q.timer.Stop()
+	}
+	q.timer = nil
+	q.version = ""
+	q.queued = time.Time{}
+	q.runAt = time.Time{}
+}
+
+// Version returns the version the actions are queued for (empty if none are).
+func (q *Queue) Version() string {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.version
+}
+
+// Summary returns the queued actions (nil if none are).
+func (q *Queue) Summary() *apitype.QueuedActions {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.timer == nil {
+		return nil
+	}
+	return &apitype.QueuedActions{
+		Version: q.version,
+		Queued:  q.queued,
+		RunAt:   q.runAt}
+}
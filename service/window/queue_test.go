@@ -0,0 +1,126 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package window
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQueue_Add(t *testing.T) {
+	// GIVEN a Queue.
+	tests := map[string]struct {
+		runIn   time.Duration
+		cancel  bool
+		replace bool
+		wantRun bool
+	}{
+		"runs at the time": {
+			runIn:   10 * time.Millisecond,
+			wantRun: true},
+		"runs immediately if in the past": {
+			runIn:   -time.Minute,
+			wantRun: true},
+		"doesn't run if cancelled": {
+			runIn:   50 * time.Millisecond,
+			cancel:  true,
+			wantRun: false},
+		"doesn't run if replaced": {
+			runIn:   50 * time.Millisecond,
+			replace: true,
+			wantRun: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var queue Queue
+			ran := make(chan string, 2)
+
+			// WHEN actions are added to the Queue.
+			queue.Add("1.2.3", time.Now().Add(tc.runIn), func() { ran <- "1.2.3" })
+			if tc.cancel {
+				if !queue.Cancel() {
+					t.Error("Cancel() = false, want true for queued actions")
+				}
+			}
+			if tc.replace {
+				queue.Add("1.2.4", time.Now().Add(time.Hour), func() { ran <- "1.2.4" })
+				t.Cleanup(func() { queue.Cancel() })
+			}
+
+			// THEN they only run if not cancelled/replaced.
+			select {
+			case version := <-ran:
+				if !tc.wantRun {
+					t.Errorf("want no run, got run for %q",
+						version)
+				}
+			case <-time.After(200 * time.Millisecond):
+				if tc.wantRun {
+					t.Error("want run, got none")
+				}
+			}
+			// AND the Queue is only empty if they ran/were cancelled.
+			wantQueued := tc.replace
+			if got := queue.Summary() != nil; got != wantQueued {
+				t.Errorf("queued\nwant: %t\ngot:  %t",
+					wantQueued, got)
+			}
+		})
+	}
+}
+
+func TestQueue_Summary(t *testing.T) {
+	// GIVEN a Queue with actions.
+	var queue Queue
+	runAt := time.Now().Add(time.Hour).UTC()
+	queue.Add("1.2.3", runAt, func() {})
+	t.Cleanup(func() { queue.Cancel() })
+
+	// WHEN Summary and Version are called.
+	summary := queue.Summary()
+	version := queue.Version()
+
+	// THEN they describe the queued actions.
+	if summary == nil {
+		t.Fatal("Summary() = nil, want the queued actions")
+	}
+	if summary.Version != "1.2.3" || version != "1.2.3" {
+		t.Errorf("Version\nwant: %q\ngot:  %q / %q",
+			"1.2.3", summary.Version, version)
+	}
+	if !summary.RunAt.Equal(runAt) {
+		t.Errorf("RunAt\nwant: %s\ngot:  %s",
+			runAt, summary.RunAt)
+	}
+	if summary.Queued.IsZero() || summary.Queued.After(time.Now()) {
+		t.Errorf("Queued\nwant: a time before now\ngot:  %s",
+			summary.Queued)
+	}
+
+	// AND once cancelled, nothing is queued.
+	queue.Cancel()
+	if got := queue.Summary(); got != nil {
+		t.Errorf("Summary() after Cancel\nwant: nil\ngot:  %+v",
+			got)
+	}
+	if got := queue.Cancel(); got {
+		t.Error("Cancel() = true, want false with nothing queued")
+	}
+}
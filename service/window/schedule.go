@@ -0,0 +1,158 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package window provides the change windows and freeze periods the automated actions of a Service run in.
+package window
+
+import (
+	"time"
+)
+
+// maxSteps is the most windows/freezes to step through when looking for the next opening.
+const maxSteps = 1000
+
+// Merge returns a Schedule with the Windows of `schedule` (or of `defaults` if it has none),
+// and the Freezes of both. nil if neither has any.
+func Merge(schedule, defaults *Schedule) *Schedule {
+	merged := &Schedule{}
+	if defaults != nil {
+		merged.Windows = defaults.Windows
+		merged.Freezes = defaults.Freezes
+	}
+	if schedule != nil {
+		if len(schedule.Windows) != 0 {
+			merged.Windows = schedule.Windows
+		}
+		merged.Freezes = append(append([]Freeze{}, merged.Freezes...), schedule.Freezes...)
+	}
+
+	if len(merged.Windows) == 0 && len(merged.Freezes) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// open returns whether the Window is open at `now`.
+func (w *Window) open(now time.Time) bool {
+	days, start, end, location, err := w.bounds()
+	if err != nil {
+		return false
+	}
+
+	local := now.In(location)
+	// The window may have opened yesterday and run past midnight.
+	for _, daysAgo := range []int{0, 1} {
+		day := local.AddDate(0, 0, -daysAgo)
+		if !days[day.Weekday()] {
+			continue
+		}
+
+		opens := time.Date(day.Year(), day.Month(), day.Day(), 0, start, 0, 0, location)
+		closes := time.Date(day.Year(), day.Month(), day.Day(), 0, end, 0, 0, location)
+		if end <= start {
+			closes = closes.AddDate(0, 0, 1)
+		}
+		if !now.Before(opens) && now.Before(closes) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextOpen returns the next time after `now` that the Window opens.
+func (w *Window) nextOpen(now time.Time) (time.Time, bool) {
+	days, start, _, location, err := w.bounds()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	local := now.In(location)
+	for day := 0; day <= 7; day++ {
+		date := local.AddDate(0, 0, day)
+		opens := time.Date(date.Year(), date.Month(), date.Day(), 0, start, 0, 0, location)
+		if days[opens.Weekday()] && opens.After(now) {
+			return opens, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// frozenUntil returns the end of the Freeze(s) that `now` is in.
+func (s *Schedule) frozenUntil(now time.Time) (time.Time, bool) {
+	var until time.Time
+	frozen := false
+	for i := range s.Freezes {
+		start, end, err := s.Freezes[i].bounds()
+		if err != nil || now.Before(start) || !now.Before(end) {
+			continue
+		}
+		if !frozen || end.After(until) {
+			until = end
+		}
+		frozen = true
+	}
+	return until, frozen
+}
+
+// Open returns whether the actions may run at `now`.
+func (s *Schedule) Open(now time.Time) bool {
+	if s == nil {
+		return true
+	}
+	if _, frozen := s.frozenUntil(now); frozen {
+		return false
+	}
+	if len(s.Windows) == 0 {
+		return true
+	}
+
+	for i := range s.Windows {
+		if s.Windows[i].open(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the first time from `now` that the actions may run
+// (false if there is none).
+func (s *Schedule) Next(now time.Time) (time.Time, bool) {
+	at := now
+	for range maxSteps {
+		if s.Open(at) {
+			return at, true
+		}
+		// Skip to the end of the Freeze.
+		if until, frozen := s.frozenUntil(at); frozen {
+			at = until
+			continue
+		}
+
+		// Skip to the next Window.
+		var next time.Time
+		found := false
+		for i := range s.Windows {
+			if opens, ok := s.Windows[i].nextOpen(at); ok && (!found || opens.Before(next)) {
+				next = opens
+				found = true
+			}
+		}
+		if !found {
+			break
+		}
+		at = next
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,209 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package window
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	// GIVEN a Schedule and the defaults.
+	serviceWindows := []Window{{Days: []string{"mon"}}}
+	defaultWindows := []Window{{Days: []string{"tue"}}}
+	serviceFreezes := []Freeze{{Start: "2025-12-24", End: "2025-12-26"}}
+	defaultFreezes := []Freeze{{Start: "2025-12-31", End: "2026-01-01"}}
+	tests := map[string]struct {
+		schedule, defaults *Schedule
+		want               *Schedule
+	}{
+		"neither": {
+			want: nil},
+		"both empty": {
+			schedule: &Schedule{},
+			defaults: &Schedule{},
+			want:     nil},
+		"only defaults": {
+			defaults: &Schedule{Windows: defaultWindows, Freezes: defaultFreezes},
+			want:     &Schedule{Windows: defaultWindows, Freezes: defaultFreezes}},
+		"only service": {
+			schedule: &Schedule{Windows: serviceWindows, Freezes: serviceFreezes},
+			defaults: &Schedule{},
+			want:     &Schedule{Windows: serviceWindows, Freezes: serviceFreezes}},
+		"service windows replace the defaults, freezes of both apply": {
+			schedule: &Schedule{Windows: serviceWindows, Freezes: serviceFreezes},
+			defaults: &Schedule{Windows: defaultWindows, Freezes: defaultFreezes},
+			want: &Schedule{
+				Windows: serviceWindows,
+				Freezes: []Freeze{defaultFreezes[0], serviceFreezes[0]}}},
+		"service freezes only, keeps the default windows": {
+			schedule: &Schedule{Freezes: serviceFreezes},
+			defaults: &Schedule{Windows: defaultWindows},
+			want: &Schedule{
+				Windows: defaultWindows,
+				Freezes: serviceFreezes}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN Merge is called.
+			got := Merge(tc.schedule, tc.defaults)
+
+			// THEN the merged Schedule is as expected.
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("want: %+v\ngot:  %+v",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSchedule_OpenNext(t *testing.T) {
+	// GIVEN a Schedule and a time.
+	// 2025-01-07 is a Tuesday.
+	tueThu := Window{Days: []string{"tue-thu"}, Start: "10:00", End: "16:00"}
+	tests := map[string]struct {
+		schedule *Schedule
+		now      string
+		wantOpen bool
+		wantNext string // empty = none.
+	}{
+		"nil Schedule": {
+			schedule: nil,
+			now:      "2025-01-05T03:00:00Z",
+			wantOpen: true,
+			wantNext: "2025-01-05T03:00:00Z"},
+		"no windows": {
+			schedule: &Schedule{},
+			now:      "2025-01-05T03:00:00Z",
+			wantOpen: true,
+			wantNext: "2025-01-05T03:00:00Z"},
+		"in window": {
+			schedule: &Schedule{Windows: []Window{tueThu}},
+			now:      "2025-01-07T10:00:00Z",
+			wantOpen: true,
+			wantNext: "2025-01-07T10:00:00Z"},
+		"before window opens that day": {
+			schedule: &Schedule{Windows: []Window{tueThu}},
+			now:      "2025-01-07T09:59:00Z",
+			wantOpen: false,
+			wantNext: "2025-01-07T10:00:00Z"},
+		"window closed for the day": {
+			schedule: &Schedule{Windows: []Window{tueThu}},
+			now:      "2025-01-07T16:00:00Z",
+			wantOpen: false,
+			wantNext: "2025-01-08T10:00:00Z"},
+		"after the last day of the week": {
+			schedule: &Schedule{Windows: []Window{tueThu}},
+			now:      "2025-01-09T17:00:00Z",
+			wantOpen: false,
+			wantNext: "2025-01-14T10:00:00Z"},
+		"timezone": {
+			schedule: &Schedule{Windows: []Window{{
+				Days: []string{"tue"}, Start: "10:00", End: "16:00", Timezone: "America/New_York"}}},
+			now:      "2025-01-07T12:00:00Z",
+			wantOpen: false,
+			wantNext: "2025-01-07T15:00:00Z"},
+		"overnight window, after midnight": {
+			schedule: &Schedule{Windows: []Window{{Days: []string{"fri"}, Start: "22:00", End: "02:00"}}},
+			now:      "2025-01-11T01:00:00Z", // Saturday.
+			wantOpen: true,
+			wantNext: "2025-01-11T01:00:00Z"},
+		"overnight window, closed": {
+			schedule: &Schedule{Windows: []Window{{Days: []string{"fri"}, Start: "22:00", End: "02:00"}}},
+			now:      "2025-01-11T02:00:00Z",
+			wantOpen: false,
+			wantNext: "2025-01-17T22:00:00Z"},
+		"earliest of multiple windows": {
+			schedule: &Schedule{Windows: []Window{
+				tueThu,
+				{Days: []string{"sat"}, Start: "08:00", End: "09:00"}}},
+			now:      "2025-01-09T17:00:00Z",
+			wantOpen: false,
+			wantNext: "2025-01-11T08:00:00Z"},
+		"frozen": {
+			schedule: &Schedule{Freezes: []Freeze{{Start: "2025-01-07", End: "2025-01-08"}}},
+			now:      "2025-01-07T12:00:00Z",
+			wantOpen: false,
+			wantNext: "2025-01-09T00:00:00Z"},
+		"frozen, then waits for the window": {
+			schedule: &Schedule{
+				Windows: []Window{tueThu},
+				Freezes: []Freeze{{Start: "2025-01-07", End: "2025-01-08"}}},
+			now:      "2025-01-07T12:00:00Z",
+			wantOpen: false,
+			wantNext: "2025-01-09T10:00:00Z"},
+		"freeze ends inside the window": {
+			schedule: &Schedule{
+				Windows: []Window{tueThu},
+				Freezes: []Freeze{{Start: "2025-01-07T00:00:00Z", End: "2025-01-07T12:30:00Z"}}},
+			now:      "2025-01-07T11:00:00Z",
+			wantOpen: false,
+			wantNext: "2025-01-07T12:30:00Z"},
+		"overlapping freezes": {
+			schedule: &Schedule{Freezes: []Freeze{
+				{Start: "2025-01-07", End: "2025-01-08"},
+				{Start: "2025-01-08T12:00:00Z", End: "2025-01-10T06:00:00Z"}}},
+			now:      "2025-01-07T12:00:00Z",
+			wantOpen: false,
+			wantNext: "2025-01-10T06:00:00Z"},
+		"freeze over": {
+			schedule: &Schedule{Freezes: []Freeze{{Start: "2025-01-01", End: "2025-01-02"}}},
+			now:      "2025-01-07T12:00:00Z",
+			wantOpen: true,
+			wantNext: "2025-01-07T12:00:00Z"},
+		"invalid window never opens": {
+			schedule: &Schedule{Windows: []Window{{Days: []string{"someday"}}}},
+			now:      "2025-01-07T12:00:00Z",
+			wantOpen: false,
+			wantNext: ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			now, _ := time.Parse(time.RFC3339, tc.now)
+
+			// WHEN Open and Next are called.
+			gotOpen := tc.schedule.Open(now)
+			gotNext, found := tc.schedule.Next(now)
+
+			// THEN whether the Schedule is open is as expected.
+			if gotOpen != tc.wantOpen {
+				t.Errorf("Open\nwant: %t\ngot:  %t",
+					tc.wantOpen, gotOpen)
+			}
+			// AND the next time it opens is as expected.
+			if tc.wantNext == "" {
+				if found {
+					t.Errorf("Next\nwant: none\ngot:  %s",
+						gotNext)
+				}
+				return
+			}
+			wantNext, _ := time.Parse(time.RFC3339, tc.wantNext)
+			if !found || !gotNext.Equal(wantNext) {
+				t.Errorf("Next\nwant: %s\ngot:  %s (found=%t)",
+					wantNext, gotNext, found)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package window provides the change windows and freeze periods the automated actions of a Service run in.
+package window
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Schedule of change windows and freeze periods for the automated actions of a Service.
+type Schedule struct {
+	Windows []Window `yaml:"windows,omitempty" json:"windows,omitempty"` // Times the actions may run (default = any time).
+	Freezes []Freeze `yaml:"freezes,omitempty" json:"freezes,omitempty"` // Periods the actions may not run.
+}
+
+// Window of time, on days of the week, that the actions may run in.
+type Window struct {
+	Days     []string `yaml:"days,omitempty" json:"days,omitempty"`         // Days of the week, e.g. [tue-thu, sat] (default = every day).
+	Start    string   `yaml:"start,omitempty" json:"start,omitempty"`       // Time of day the window opens, HH:MM (default = 00:00).
+	End      string   `yaml:"end,omitempty" json:"end,omitempty"`           // Time of day the window closes, HH:MM (default = 00:00, on the next day if <= start).
+	Timezone string   `yaml:"timezone,omitempty" json:"timezone,omitempty"` // IANA timezone of the times, e.g. Europe/London (default = UTC).
+}
+
+// Freeze is a period the actions may not run in.
+type Freeze struct {
+	Start  string `yaml:"start,omitempty" json:"start,omitempty"`   // Start of the freeze, YYYY-MM-DD or RFC3339.
+	End    string `yaml:"end,omitempty" json:"end,omitempty"`       // End of the freeze, YYYY-MM-DD (inclusive) or RFC3339.
+	Reason string `yaml:"reason,omitempty" json:"reason,omitempty"` // Reason for the freeze.
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday}
+
+// days returns the weekdays the Window opens on (indexed by time.Weekday).
+func (w *Window) days() ([7]bool, error) {
+	var days [7]bool
+	if len(w.Days) == 0 {
+		for i := range days {
+			days[i] = true
+		}
+		return days, nil
+	}
+
+	for _, part := range w.Days {
+		from, to, isRange := strings.Cut(strings.ToLower(part), "-")
+		fromDay, ok := weekdays[from]
+		if !ok {
+			return days, fmt.Errorf("%q <invalid> (unknown weekday, use mon/tue/...)", from)
+		}
+		toDay := fromDay
+		if isRange {
+			if toDay, ok = weekdays[to]; !ok {
+				return days, fmt.Errorf("%q <invalid> (unknown weekday, use mon/tue/...)", to)
+			}
+		}
+
+		// Ranges may wrap, e.g. fri-mon.
+		for day := fromDay; ; day = (day + 1) % 7 {
+			days[day] = true
+			if day == toDay {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// parseMinuteOfDay returns the minute of the day of `timeOfDay` (HH:MM, empty = 00:00).
+func parseMinuteOfDay(timeOfDay string) (int, error) {
+	if timeOfDay == "" {
+		return 0, nil
+	}
+	t, err := time.Parse("15:04", timeOfDay)
+	if err != nil {
+		return 0, fmt.Errorf("%q <invalid> (want 'HH:MM')", timeOfDay)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// bounds returns the days, start/end minutes and location of the Window.
+func (w *Window) bounds() (days [7]bool, start, end int, location *time.Location, err error) {
+	if days, err = w.days(); err != nil {
+		return
+	}
+	if start, err = parseMinuteOfDay(w.Start); err != nil {
+		return
+	}
+	if end, err = parseMinuteOfDay(w.End); err != nil {
+		return
+	}
+	location, err = time.LoadLocation(w.Timezone)
+	return
+}
+
+// parseInstant parses `value` as RFC3339, or as a date (UTC) - the day after if `endOfDay`.
+func parseInstant(value string, endOfDay bool) (time.Time, error) {
+	if instant, err := time.Parse(time.RFC3339, value); err == nil {
+		return instant, nil
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q <invalid> (want 'YYYY-MM-DD' or RFC3339)", value)
+	}
+	if endOfDay {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date, nil
+}
+
+// bounds returns the start and end of the Freeze.
+func (f *Freeze) bounds() (start, end time.Time, err error) {
+	if start, err = parseInstant(f.Start, false); err != nil {
+		return
+	}
+	end, err = parseInstant(f.End, true)
+	return
+}
@@ -0,0 +1,123 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package window provides the change windows and freeze periods the automated actions of a Service run in.
+package window
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/release-argus/Argus/util"
+)
+
+// CheckValues validates the fields of the Schedule.
+func (s *Schedule) CheckValues(prefix string) error {
+	if s == nil {
+		return nil
+	}
+
+	var errs []error
+	itemPrefix := prefix + "    "
+	// windows
+	var windowErrs []error
+	for i := range s.Windows {
+		util.AppendCheckError(&windowErrs, prefix+"  ", fmt.Sprintf("- item_%d", i),
+			s.Windows[i].checkValues(itemPrefix))
+	}
+	util.AppendCheckError(&errs, prefix, "windows", errors.Join(windowErrs...))
+	// freezes
+	var freezeErrs []error
+	for i := range s.Freezes {
+		util.AppendCheckError(&freezeErrs, prefix+"  ", fmt.Sprintf("- item_%d", i),
+			s.Freezes[i].checkValues(itemPrefix))
+	}
+	util.AppendCheckError(&errs, prefix, "freezes", errors.Join(freezeErrs...))
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// checkValues validates the fields of the Window.
+func (w *Window) checkValues(prefix string) error {
+	var errs []error
+	// days
+	if _, err := w.days(); err != nil {
+		errs = append(errs,
+			fmt.Errorf("%sdays: %w",
+				prefix, err))
+	}
+	// start/end
+	if _, err := parseMinuteOfDay(w.Start); err != nil {
+		errs = append(errs,
+			fmt.Errorf("%sstart: %w",
+				prefix, err))
+	}
+	if _, err := parseMinuteOfDay(w.End); err != nil {
+		errs = append(errs,
+			fmt.Errorf("%send: %w",
+				prefix, err))
+	}
+	// timezone
+	if _, err := time.LoadLocation(w.Timezone); err != nil {
+		errs = append(errs,
+			fmt.Errorf("%stimezone: %q <invalid> (unknown IANA timezone, e.g. Europe/London)",
+				prefix, w.Timezone))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// checkValues validates the fields of the Freeze.
+func (f *Freeze) checkValues(prefix string) error {
+	var errs []error
+	// start
+	start, startErr := parseInstant(f.Start, false)
+	if f.Start == "" {
+		errs = append(errs,
+			fmt.Errorf("%sstart: <required>",
+				prefix))
+	} else if startErr != nil {
+		errs = append(errs,
+			fmt.Errorf("%sstart: %w",
+				prefix, startErr))
+	}
+	// end
+	end, endErr := parseInstant(f.End, true)
+	switch {
+	case f.End == "":
+		errs = append(errs,
+			fmt.Errorf("%send: <required>",
+				prefix))
+	case endErr != nil:
+		errs = append(errs,
+			fmt.Errorf("%send: %w",
+				prefix, endErr))
+	case startErr == nil && !end.After(start):
+		errs = append(errs,
+			fmt.Errorf("%send: %q <invalid> (must be after the start)",
+				prefix, f.End))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,93 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package window
+
+import (
+	"testing"
+
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestSchedule_CheckValues(t *testing.T) {
+	// GIVEN a Schedule.
+	tests := map[string]struct {
+		schedule *Schedule
+		errRegex string
+	}{
+		"nil": {
+			schedule: nil,
+			errRegex: `^$`},
+		"valid": {
+			schedule: &Schedule{
+				Windows: []Window{
+					{Days: []string{"tue-thu", "SAT"}, Start: "10:00", End: "16:00", Timezone: "Europe/London"},
+					{}},
+				Freezes: []Freeze{
+					{Start: "2025-12-20", End: "2026-01-02", Reason: "holidays"},
+					{Start: "2025-06-01T10:00:00Z", End: "2025-06-01T12:00:00+01:00"}}},
+			errRegex: `^$`},
+		"invalid window": {
+			schedule: &Schedule{
+				Windows: []Window{
+					{},
+					{Days: []string{"tue-someday"}, Start: "25:00", End: "1pm", Timezone: "Mars/Olympus"}}},
+			errRegex: test.TrimYAML(`
+				^windows:
+					- item_1:
+						days: "someday" <invalid>.*
+						start: "25:00" <invalid>.*
+						end: "1pm" <invalid>.*
+						timezone: "Mars/Olympus" <invalid>.*$`)},
+		"invalid freezes": {
+			schedule: &Schedule{
+				Freezes: []Freeze{
+					{},
+					{Start: "tomorrow", End: "2025-01-01"},
+					{Start: "2025-01-02", End: "2025-01-01"}}},
+			errRegex: test.TrimYAML(`
+				^freezes:
+					- item_0:
+						start: <required>
+						end: <required>
+					- item_1:
+						start: "tomorrow" <invalid>.*
+					- item_2:
+						end: "2025-01-01" <invalid> \(must be after the start\)$`)},
+		"single day freeze": {
+			schedule: &Schedule{
+				Freezes: []Freeze{
+					{Start: "2025-01-01", End: "2025-01-01"}}},
+			errRegex: `^$`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN CheckValues is called.
+			err := tc.schedule.CheckValues("")
+
+			// THEN the error is as expected.
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
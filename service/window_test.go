@@ -0,0 +1,156 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/release-argus/Argus/command"
+	"github.com/release-argus/Argus/service/window"
+	"github.com/release-argus/Argus/test"
+)
+
+func TestService_runOrQueueActions(t *testing.T) {
+	// GIVEN a Service with a change window that is closed.
+	now := time.Now().UTC()
+	freezeEnd := now.Add(24 * time.Hour).Truncate(time.Second)
+	freeze := window.Freeze{
+		Start: now.Add(-time.Hour).Format(time.RFC3339),
+		End:   freezeEnd.Format(time.RFC3339)}
+	tests := map[string]struct {
+		changeWindow, defaults *window.Schedule
+		wantRunAt              *time.Time
+	}{
+		"service freeze": {
+			changeWindow: &window.Schedule{Freezes: []window.Freeze{freeze}},
+			wantRunAt:    &freezeEnd},
+		"default freeze": {
+			defaults:  &window.Schedule{Freezes: []window.Freeze{freeze}},
+			wantRunAt: &freezeEnd},
+		"window that never opens": {
+			changeWindow: &window.Schedule{Windows: []window.Window{{Days: []string{"someday"}}}},
+			wantRunAt:    nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := testService(t, name, "url")
+			svc.ChangeWindow = tc.changeWindow
+			if tc.defaults != nil {
+				svc.Defaults.ChangeWindow = *tc.defaults
+			}
+			t.Cleanup(func() { svc.CancelQueuedActions() })
+
+			// WHEN runOrQueueActions is called.
+			svc.runOrQueueActions(svc.ServiceInfo(), false)
+
+			// THEN the actions are queued until the window opens.
+			got := svc.QueuedActions()
+			if tc.wantRunAt == nil {
+				if got != nil || svc.Status.QueuedVersion() != "" {
+					t.Errorf("want nothing queued, got %+v (QueuedVersion=%q)",
+						got, svc.Status.QueuedVersion())
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("want queued actions, got none")
+			}
+			if got.Version != svc.Status.LatestVersion() || !got.RunAt.Equal(*tc.wantRunAt) {
+				t.Errorf("want %q queued until %s\ngot:  %+v",
+					svc.Status.LatestVersion(), tc.wantRunAt, got)
+			}
+			// AND the version queued is recorded in the Status.
+			if got := svc.Status.QueuedVersion(); got != svc.Status.LatestVersion() {
+				t.Errorf("QueuedVersion\nwant: %q\ngot:  %q",
+					svc.Status.LatestVersion(), got)
+			}
+			// AND they can be cancelled.
+			if !svc.CancelQueuedActions() || svc.QueuedActions() != nil || svc.Status.QueuedVersion() != "" {
+				t.Errorf("CancelQueuedActions didn't cancel the queued actions")
+			}
+		})
+	}
+}
+
+func TestService_RequeueActions(t *testing.T) {
+	// GIVEN a Service with a closed change window, and actions queued for a version.
+	tests := map[string]struct {
+		version     string
+		autoApprove bool
+		noActions   bool
+		wantQueued  bool
+	}{
+		"no version queued": {
+			version:     "",
+			autoApprove: true,
+			wantQueued:  false},
+		"other version queued": {
+			version:     "1.0.0",
+			autoApprove: true,
+			wantQueued:  false},
+		"latest version queued": {
+			version:     "2.2.2",
+			autoApprove: true,
+			wantQueued:  true},
+		"latest version queued, no longer auto-approved": {
+			version:     "2.2.2",
+			autoApprove: false,
+			wantQueued:  false},
+		"latest version queued, no actions": {
+			version:     "2.2.2",
+			autoApprove: true,
+			noActions:   true,
+			wantQueued:  false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := testService(t, name, "url")
+			if !tc.noActions {
+				svc.Command = command.Slice{{"true"}}
+			}
+			svc.Dashboard.AutoApprove = test.BoolPtr(tc.autoApprove)
+			svc.ChangeWindow = &window.Schedule{
+				Freezes: []window.Freeze{{
+					Start: time.Now().Add(-time.Hour).Format(time.RFC3339),
+					End:   time.Now().Add(time.Hour).Format(time.RFC3339)}}}
+			t.Cleanup(func() { svc.CancelQueuedActions() })
+
+			svc.Status.SetQueuedVersion(tc.version, false)
+
+			// WHEN RequeueActions is called with the version queued before a restart.
+			svc.RequeueActions(svc.Status.QueuedVersion())
+
+			// THEN the actions are only queued again for the LatestVersion.
+			if got := svc.QueuedActions() != nil; got != tc.wantQueued {
+				t.Errorf("queued\nwant: %t\ngot:  %t",
+					tc.wantQueued, got)
+			}
+			// AND the version queued is only kept if they were.
+			if got := svc.Status.QueuedVersion() != ""; got != tc.wantQueued {
+				t.Errorf("QueuedVersion %q kept\nwant: %t\ngot:  %t",
+					svc.Status.QueuedVersion(), tc.wantQueued, got)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@
 package testing
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -64,7 +65,7 @@ func CommandTest(
 
 	//#nosec G104 -- Disregard.
 	//nolint:errcheck // ^
-	service.CommandController.Exec(logFrom)
+	service.CommandController.Exec(context.Background(), logFrom)
 	if !log.Testing {
 		os.Exit(0)
 	}
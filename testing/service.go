@@ -16,6 +16,7 @@
 package testing
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -59,7 +60,7 @@ func ServiceTest(
 	service := cfg.Service[*flag]
 
 	// LatestVersion.
-	_, err := service.LatestVersion.Query(false, logFrom)
+	_, err := service.LatestVersion.Query(context.Background(), false, logFrom)
 	if err != nil {
 		log.Error(
 			fmt.Sprintf(
@@ -74,7 +75,7 @@ func ServiceTest(
 
 	// DeployedVersionLookup.
 	if service.DeployedVersionLookup != nil {
-		version, err := service.DeployedVersionLookup.Query(false, logFrom)
+		version, err := service.DeployedVersionLookup.Query(context.Background(), false, logFrom)
 		log.Info(
 			fmt.Sprintf(
 				"Deployed version - %q",
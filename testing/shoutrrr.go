@@ -16,6 +16,7 @@
 package testing
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sort"
@@ -48,7 +49,7 @@ func NotifyTest(
 		webURL := ""
 		notify.ServiceStatus.WebURL = &webURL
 	}
-	err := notify.TestSend("https://example.com/service_url")
+	err := notify.TestSend(context.Background(), "https://example.com/service_url")
 
 	if err == nil {
 		log.Info(
@@ -0,0 +1,173 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package util provides utility functions for the Argus project.
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// HTTPClientOptions are the timeout/TLS options of an outbound HTTP client
+// (shared by the WebHooks and the latest_version/deployed_version lookups).
+type HTTPClientOptions struct {
+	Timeout  string `yaml:"timeout,omitempty" json:"timeout,omitempty"`     // Timeout of each request, e.g. 30s.
+	CAFile   string `yaml:"ca_file,omitempty" json:"ca_file,omitempty"`     // PEM bundle of CAs to trust (in addition to the system roots).
+	CertFile string `yaml:"cert_file,omitempty" json:"cert_file,omitempty"` // PEM client certificate for mutual TLS.
+	KeyFile  string `yaml:"key_file,omitempty" json:"key_file,omitempty"`   // PEM client key for mutual TLS.
+}
+
+// CheckValues validates the fields of the HTTPClientOptions.
+func (o *HTTPClientOptions) CheckValues(prefix string) error {
+	if o == nil {
+		return nil
+	}
+
+	var errs []error
+	// timeout
+	if o.Timeout != "" {
+		if _, err := time.ParseDuration(o.Timeout); err != nil {
+			errs = append(errs,
+				fmt.Errorf("%stimeout: %q <invalid> (Use 'AhBmCs' duration format)",
+					prefix, o.Timeout))
+		}
+	}
+	// ca_file
+	if o.CAFile != "" {
+		if _, err := loadCAFile(o.CAFile); err != nil {
+			errs = append(errs,
+				fmt.Errorf("%sca_file: %q <invalid> (%s)",
+					prefix, o.CAFile, err))
+		}
+	}
+	// cert_file/key_file
+	switch {
+	case o.CertFile == "" && o.KeyFile == "":
+	case o.CertFile == "":
+		errs = append(errs,
+			fmt.Errorf("%scert_file: <required> (when key_file is set)",
+				prefix))
+	case o.KeyFile == "":
+		errs = append(errs,
+			fmt.Errorf("%skey_file: <required> (when cert_file is set)",
+				prefix))
+	default:
+		if _, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile); err != nil {
+			errs = append(errs,
+				fmt.Errorf("%scert_file: %q <invalid> (%s)",
+					prefix, o.CertFile, err))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// NewHTTPClient returns an http.Client using the first non-default value of each option
+// in `options` (in order of precedence).
+func NewHTTPClient(allowInvalidCerts bool, options ...*HTTPClientOptions) (*http.Client, error) {
+	var resolved HTTPClientOptions
+	for _, o := range options {
+		if o == nil {
+			continue
+		}
+		resolved.Timeout = FirstNonDefault(resolved.Timeout, o.Timeout)
+		resolved.CAFile = FirstNonDefault(resolved.CAFile, o.CAFile)
+		resolved.CertFile = FirstNonDefault(resolved.CertFile, o.CertFile)
+		resolved.KeyFile = FirstNonDefault(resolved.KeyFile, o.KeyFile)
+	}
+
+	client := &http.Client{}
+	client.Timeout, _ = time.ParseDuration(resolved.Timeout)
+
+	// Default TLS.
+	if !allowInvalidCerts && resolved.CAFile == "" && resolved.CertFile == "" {
+		client.Transport = &http.Transport{}
+		return client, nil
+	}
+
+	//#nosec G402 -- InsecureSkipVerify only when explicitly wanted.
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: allowInvalidCerts,
+		MinVersion:         tls.VersionTLS12}
+	// Custom CAs.
+	if resolved.CAFile != "" {
+		pool, err := loadCAFile(resolved.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("ca_file %q: %w", resolved.CAFile, err)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	// Client certificate.
+	if resolved.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(resolved.CertFile, resolved.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cert_file %q: %w", resolved.CertFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	client.Transport = transport
+	return client, nil
+}
+
+// loadCAFile returns the system cert pool with the certificates of the PEM `path` added.
+func loadCAFile(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.New("no certificates found")
+	}
+	return pool, nil
+}
+
+// RetryAfter returns the time given by the Retry-After `header` of a response,
+// (either delay-seconds, or an HTTP date), or zero if it is missing/invalid.
+func RetryAfter(header http.Header, now time.Time) time.Time {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return time.Time{}
+	}
+
+	// delay-seconds.
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return time.Time{}
+		}
+		return now.Add(time.Duration(seconds) * time.Second)
+	}
+	// HTTP-date.
+	if date, err := http.ParseTime(value); err == nil {
+		return date
+	}
+	return time.Time{}
+}
@@ -0,0 +1,340 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package util
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPKI holds the files of a CA, and a server+client certificate signed by that CA.
+type testPKI struct {
+	caFile, certFile, keyFile string
+	serverCert                tls.Certificate
+	caPool                    *x509.CertPool
+}
+
+func newTestPKI(t *testing.T) testPKI {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Argus Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create CA: %v", err)
+	}
+	caCert, _ := x509.ParseCertificate(caDER)
+
+	issue := func(serial int64, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+		key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "argus"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")}}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatalf("failed to create certificate: %v", err)
+		}
+		keyDER, _ := x509.MarshalECPrivateKey(key)
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	}
+
+	pki := testPKI{
+		caFile:   filepath.Join(dir, "ca.pem"),
+		certFile: filepath.Join(dir, "client.pem"),
+		keyFile:  filepath.Join(dir, "client-key.pem"),
+		caPool:   x509.NewCertPool()}
+	pki.caPool.AddCert(caCert)
+	os.WriteFile(pki.caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), 0o600)
+	clientCert, clientKey := issue(2, x509.ExtKeyUsageClientAuth)
+	os.WriteFile(pki.certFile, clientCert, 0o600)
+	os.WriteFile(pki.keyFile, clientKey, 0o600)
+	serverCert, serverKey := issue(3, x509.ExtKeyUsageServerAuth)
+	pki.serverCert, err = tls.X509KeyPair(serverCert, serverKey)
+	if err != nil {
+		t.Fatalf("failed to load server certificate: %v", err)
+	}
+
+	return pki
+}
+
+func TestHTTPClientOptions_CheckValues(t *testing.T) {
+	pki := newTestPKI(t)
+	notPEM := filepath.Join(t.TempDir(), "not.pem")
+	os.WriteFile(notPEM, []byte("foo"), 0o600)
+
+	// GIVEN HTTPClientOptions
+	tests := map[string]struct {
+		options  *HTTPClientOptions
+		errRegex string
+	}{
+		"nil": {
+			options:  nil,
+			errRegex: `^$`},
+		"empty": {
+			options:  &HTTPClientOptions{},
+			errRegex: `^$`},
+		"valid": {
+			options: &HTTPClientOptions{
+				Timeout:  "10s",
+				CAFile:   pki.caFile,
+				CertFile: pki.certFile,
+				KeyFile:  pki.keyFile},
+			errRegex: `^$`},
+		"invalid timeout": {
+			options: &HTTPClientOptions{
+				Timeout: "10x"},
+			errRegex: `^timeout: "10x" <invalid>`},
+		"ca_file that doesn't exist": {
+			options: &HTTPClientOptions{
+				CAFile: pki.caFile + ".missing"},
+			errRegex: `^ca_file: ".*" <invalid> \(.*no such file`},
+		"ca_file without certificates": {
+			options: &HTTPClientOptions{
+				CAFile: notPEM},
+			errRegex: `^ca_file: ".*" <invalid> \(no certificates found\)$`},
+		"cert_file without key_file": {
+			options: &HTTPClientOptions{
+				CertFile: pki.certFile},
+			errRegex: `^key_file: <required>`},
+		"key_file without cert_file": {
+			options: &HTTPClientOptions{
+				KeyFile: pki.keyFile},
+			errRegex: `^cert_file: <required>`},
+		"mismatched cert_file/key_file": {
+			options: &HTTPClientOptions{
+				CertFile: pki.certFile,
+				KeyFile:  pki.caFile},
+			errRegex: `^cert_file: ".*" <invalid>`},
+		"all invalid": {
+			options: &HTTPClientOptions{
+				Timeout: "foo",
+				CAFile:  notPEM,
+				KeyFile: pki.keyFile},
+			errRegex: `^timeout: .*\nca_file: .*\ncert_file: <required>.*$`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN CheckValues is called
+			err := tc.options.CheckValues("")
+
+			// THEN the expected error is returned
+			e := ErrorToString(err)
+			if !RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	pki := newTestPKI(t)
+	// A server requiring a client certificate signed by the test CA.
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{pki.serverCert},
+		ClientCAs:    pki.caPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	// GIVEN options for a HTTP client
+	tests := map[string]struct {
+		allowInvalidCerts bool
+		options           []*HTTPClientOptions
+		wantTimeout       time.Duration
+		errRegex          string
+		requestErrRegex   string
+	}{
+		"no options": {
+			requestErrRegex: `certificate`},
+		"allow_invalid_certs, but no client certificate": {
+			allowInvalidCerts: true,
+			requestErrRegex:   `certificate`},
+		"ca_file, but no client certificate": {
+			options: []*HTTPClientOptions{
+				{CAFile: pki.caFile}},
+			requestErrRegex: `certificate`},
+		"ca_file and client certificate": {
+			options: []*HTTPClientOptions{
+				{CAFile: pki.caFile, CertFile: pki.certFile, KeyFile: pki.keyFile}},
+			requestErrRegex: `^$`},
+		"client certificate and allow_invalid_certs": {
+			allowInvalidCerts: true,
+			options: []*HTTPClientOptions{
+				{CertFile: pki.certFile, KeyFile: pki.keyFile}},
+			requestErrRegex: `^$`},
+		"options inherited from later options": {
+			options: []*HTTPClientOptions{
+				nil,
+				{Timeout: "2s"},
+				{Timeout: "5s", CAFile: pki.caFile},
+				{CertFile: pki.certFile, KeyFile: pki.keyFile}},
+			wantTimeout:     2 * time.Second,
+			requestErrRegex: `^$`},
+		"invalid ca_file": {
+			options: []*HTTPClientOptions{
+				{CAFile: pki.certFile + ".missing"}},
+			errRegex: `^ca_file ".*":`},
+		"invalid cert_file": {
+			options: []*HTTPClientOptions{
+				{CertFile: pki.certFile, KeyFile: pki.caFile}},
+			errRegex: `^cert_file ".*":`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN NewHTTPClient is called
+			client, err := NewHTTPClient(tc.allowInvalidCerts, tc.options...)
+
+			// THEN the expected error is returned
+			if tc.errRegex != "" {
+				e := ErrorToString(err)
+				if !RegexCheck(tc.errRegex, e) {
+					t.Fatalf("want match for %q\nnot: %q",
+						tc.errRegex, e)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			// AND the timeout is set
+			if client.Timeout != tc.wantTimeout {
+				t.Errorf("want Timeout=%s, not %s",
+					tc.wantTimeout, client.Timeout)
+			}
+			// AND the request succeeds/fails as expected
+			resp, err := client.Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			e := ErrorToString(err)
+			if !RegexCheck(tc.requestErrRegex, e) {
+				t.Errorf("want request error match for %q\nnot: %q",
+					tc.requestErrRegex, e)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient_Timeout(t *testing.T) {
+	// GIVEN a server that is slower than the timeout
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Second)
+	}))
+	t.Cleanup(server.Close)
+	client, _ := NewHTTPClient(false, &HTTPClientOptions{Timeout: "100ms"})
+
+	// WHEN a request is made
+	_, err := client.Get(server.URL)
+
+	// THEN it times out
+	if err == nil || !strings.Contains(err.Error(), "Timeout") {
+		t.Errorf("want a timeout error, not %v", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, time.April, 1, 12, 0, 0, 0, time.UTC)
+	// GIVEN a Retry-After header
+	tests := map[string]struct {
+		header string
+		want   time.Time
+	}{
+		"missing": {
+			header: "",
+			want:   time.Time{},
+		},
+		"delay-seconds": {
+			header: "120",
+			want:   now.Add(2 * time.Minute),
+		},
+		"zero seconds": {
+			header: "0",
+			want:   now,
+		},
+		"negative seconds": {
+			header: "-5",
+			want:   time.Time{},
+		},
+		"HTTP date": {
+			header: "Tue, 01 Apr 2025 13:00:00 GMT",
+			want:   now.Add(time.Hour),
+		},
+		"invalid": {
+			header: "soon",
+			want:   time.Time{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			header := http.Header{}
+			if tc.header != "" {
+				header.Set("Retry-After", tc.header)
+			}
+
+			// WHEN RetryAfter is called on it
+			got := RetryAfter(header, now)
+
+			// THEN the time to retry after is returned
+			if !got.Equal(tc.want) {
+				t.Errorf("want %s, not %s",
+					tc.want, got)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@
 package util
 
 import (
+	"context"
 	"errors"
 	"math"
 	"math/rand"
@@ -25,12 +26,14 @@ import (
 // RetryWithBackoff retries the operation with jitter and exponential backoff.
 //
 // Parameters:
+//   - ctx: Context to stop retrying on (returning its error).
 //   - operation: Function to execute.
 //   - maxTries: Maximum amount retries.
 //   - baseDelay: Initial delay before the first retry.
 //   - maxDelay: Maximum delay between retries (exponential backoff).
 //   - shouldStop: Function to check if the operation should stop.
 func RetryWithBackoff(
+	ctx context.Context,
 	operation func() error,
 	maxTries uint8,
 	baseDelay, maxDelay time.Duration,
@@ -43,6 +46,9 @@ func RetryWithBackoff(
 		if shouldStop != nil && shouldStop() {
 			return nil
 		}
+		if err := ctx.Err(); err != nil {
+			return err //nolint:wrapcheck
+		}
 
 		err := operation()
 
@@ -65,9 +71,29 @@ func RetryWithBackoff(
 		delay += jitter
 
 		// Wait before retrying.
-		time.Sleep(delay)
+		if !Sleep(ctx, delay) {
+			return ctx.Err() //nolint:wrapcheck
+		}
 	}
 
 	// All retries exhausted.
+	if err := ctx.Err(); err != nil {
+		return err //nolint:wrapcheck
+	}
 	return errors.Join(errs...)
 }
+
+// Sleep for `duration`, or until `ctx` is done.
+//
+// Returns false if `ctx` was done before the `duration` elapsed.
+func Sleep(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
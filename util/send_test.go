@@ -17,6 +17,7 @@
 package util
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -27,7 +28,7 @@ func TestRetryWithBackoff_Success(t *testing.T) {
 		return nil
 	}
 
-	err := RetryWithBackoff(operation, 3, 100*time.Millisecond, 1*time.Second, nil)
+	err := RetryWithBackoff(context.Background(), operation, 3, 100*time.Millisecond, 1*time.Second, nil)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -39,7 +40,7 @@ func TestRetryWithBackoff_Failure(t *testing.T) {
 		return expectedErr
 	}
 
-	err := RetryWithBackoff(operation, 3, 100*time.Millisecond, 1*time.Second, nil)
+	err := RetryWithBackoff(context.Background(), operation, 3, 100*time.Millisecond, 1*time.Second, nil)
 	if err == nil {
 		t.Fatalf("expected error, got nil")
 	}
@@ -56,7 +57,7 @@ func TestRetryWithBackoff_StopCondition(t *testing.T) {
 		return true
 	}
 
-	err := RetryWithBackoff(operation, 3, 100*time.Millisecond, 1*time.Second, shouldStop)
+	err := RetryWithBackoff(context.Background(), operation, 3, 100*time.Millisecond, 1*time.Second, shouldStop)
 	if err != nil {
 		t.Fatalf("expected no error due to stop condition, got %v", err)
 	}
@@ -68,7 +69,7 @@ func TestRetryWithBackoff_ExponentialBackoff(t *testing.T) {
 	}
 
 	start := time.Now()
-	err := RetryWithBackoff(operation, 3, 100*time.Millisecond, 1*time.Second, nil)
+	err := RetryWithBackoff(context.Background(), operation, 3, 100*time.Millisecond, 1*time.Second, nil)
 	elapsed := time.Since(start)
 
 	if err == nil {
@@ -78,3 +79,68 @@ func TestRetryWithBackoff_ExponentialBackoff(t *testing.T) {
 		t.Fatalf("expected at least 300ms delay, got %v", elapsed)
 	}
 }
+
+func TestRetryWithBackoff_Cancelled(t *testing.T) {
+	// GIVEN an operation that fails, and a context cancelled during the backoff.
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	operation := func() error {
+		calls++
+		time.AfterFunc(10*time.Millisecond, cancel)
+		return errors.New("operation failed")
+	}
+
+	// WHEN RetryWithBackoff is called.
+	start := time.Now()
+	err := RetryWithBackoff(ctx, operation, 3, time.Second, 10*time.Second, nil)
+
+	// THEN it stops during the backoff with the error of the context.
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Fatalf("expected to stop within the backoff, took %v", elapsed)
+	}
+}
+
+func TestSleep(t *testing.T) {
+	// GIVEN a context that is/isn't cancelled mid-sleep.
+	tests := map[string]struct {
+		cancelAfter time.Duration
+		want        bool
+	}{
+		"sleeps the full duration": {
+			want: true},
+		"cancelled mid-sleep": {
+			cancelAfter: 10 * time.Millisecond,
+			want:        false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancelAfter != 0 {
+				time.AfterFunc(tc.cancelAfter, cancel)
+			}
+
+			// WHEN Sleep is called.
+			start := time.Now()
+			got := Sleep(ctx, 200*time.Millisecond)
+
+			// THEN it returns whether the full duration elapsed.
+			if got != tc.want {
+				t.Errorf("want %t, got %t", tc.want, got)
+			}
+			// AND it returns early when cancelled.
+			if elapsed := time.Since(start); !tc.want && elapsed >= 200*time.Millisecond {
+				t.Errorf("should have returned early, took %s", elapsed)
+			}
+		})
+	}
+}
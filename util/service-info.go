@@ -15,11 +15,59 @@
 // Package util provides utility functions for the Argus project.
 package util
 
+// TemplateVarsVersion is the version of the set of variables available to templates.
+//
+// Bump this whenever a variable is renamed or removed, so that templates
+// can check `template_vars_version` before relying on a variable.
+const TemplateVarsVersion = 2
+
 // ServiceInfo holds information about a service.
+//
+// Template variables (v2):
+//
+//	service_id            - ID of the Service.
+//	service_name          - Name of the Service.
+//	service_url           - URL of the Service.
+//	web_url               - Web URL of the Service.
+//	version               - Latest version of the Service.
+//	previous_version      - Latest version before `version` was found.
+//	deployed_version      - Version currently deployed.
+//	approved_version      - Version approved for deployment.
+//	rollback_version      - Version deployed before the actions of `approved_version` ran (requires `rollback`).
+//	update_type           - "major", "minor", "patch" or "prerelease" from deployed_version to version (semantic versions only).
+//	release_date          - RFC3339 timestamp of the latest release (the time it was found, if the lookup gives no date, e.g. type: url).
+//	release_notes         - Notes of the latest release.
+//	release_url           - URL of the latest release.
+//	release_assets        - List of asset download URLs of the latest release.
+//	approval_url          - URL to approve the latest version on the web UI (requires `web.public_url`).
+//	approve_url           - Signed, single-use link to approve all actions of `version` without logging in (requires `web.public_url`, not usable with an approval policy).
+//	skip_url              - Signed, single-use link to skip `version` without logging in (requires `web.public_url`).
+//	webhook_urls          - Signed, single-use links to send each WebHook for `version`, by WebHook ID (requires `web.public_url`, not usable with an approval policy).
+//	failure               - Error details (only on failure notifications).
+//	notifier_type         - Type of the notifier being templated (e.g. slack).
+//	webhook_outputs       - Values captured from WebHook responses, by WebHook ID (e.g. webhook_outputs.deploy.job_id).
+//	template_vars_version - TemplateVarsVersion.
 type ServiceInfo struct {
 	ID            string
 	Name          string
 	URL           string
 	WebURL        string
 	LatestVersion string
+
+	PreviousVersion string
+	DeployedVersion string
+	ApprovedVersion string
+	RollbackVersion string
+	UpdateType      string
+	ReleaseDate     string
+	ReleaseNotes    string
+	ReleaseURL      string
+	ReleaseAssets   []string
+	ApprovalURL     string
+	ApproveURL      string
+	SkipURL         string
+	WebHookURLs     map[string]string
+	Failure         string
+	NotifierType    string
+	WebHookOutputs  map[string]map[string]string
 }
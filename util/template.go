@@ -24,8 +24,87 @@ import (
 
 var pongoMutex = sync.Mutex{}
 
+func init() {
+	pongo2.RegisterFilter("escape_markdown", filterEscapeMarkdown)
+	pongo2.RegisterFilter("truncate_lines", filterTruncateLines)
+}
+
 // TemplateString with pongo2 and `context`.
 func TemplateString(template string, context ServiceInfo) string {
+	return renderTemplate(template, context.templateContext())
+}
+
+// TemplateShell with pongo2 and `context`, for a command run by 'sh'.
+//
+// The values of the context are single-quoted so that each stays one word
+// (they come from upstream, e.g. release notes, and mustn't be run as shell syntax),
+// leaving the shell syntax of the `template` itself as it is.
+// Empty values are left empty, so conditions on them still hold.
+func TemplateShell(template string, context ServiceInfo) string {
+	tplContext := context.templateContext()
+	for key, value := range tplContext {
+		tplContext[key] = shellQuoteValue(value)
+	}
+	return renderTemplate(template, tplContext)
+}
+
+// shellQuoteValue returns the strings in `value` shell-quoted, and marked safe from HTML escaping.
+func shellQuoteValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return v
+		}
+		return pongo2.AsSafeValue(shellQuote(v))
+	case []string:
+		quoted := make([]any, len(v))
+		for i := range v {
+			quoted[i] = shellQuoteValue(v[i])
+		}
+		return quoted
+	case map[string]string:
+		quoted := make(map[string]any, len(v))
+		for key := range v {
+			quoted[key] = shellQuoteValue(v[key])
+		}
+		return quoted
+	case map[string]map[string]string:
+		quoted := make(map[string]any, len(v))
+		for key := range v {
+			quoted[key] = shellQuoteValue(v[key])
+		}
+		return quoted
+	default:
+		return value
+	}
+}
+
+// shellQuote returns `s` single-quoted for 'sh'.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// TemplateDigest with pongo2 and the `contexts` of every Service in the digest.
+//
+// Template variables:
+//
+//	services              - List of the ServiceInfo variables of each Service.
+//	count                 - Number of Services.
+//	template_vars_version - TemplateVarsVersion.
+func TemplateDigest(template string, contexts []ServiceInfo) string {
+	services := make([]pongo2.Context, len(contexts))
+	for i := range contexts {
+		services[i] = contexts[i].templateContext()
+	}
+
+	return renderTemplate(template, pongo2.Context{
+		"services":              services,
+		"count":                 len(services),
+		"template_vars_version": TemplateVarsVersion})
+}
+
+// renderTemplate renders the `template` with pongo2 and `context`.
+func renderTemplate(template string, context pongo2.Context) string {
 	// If the string does not represent a Jinja template.
 	if !strings.Contains(template, "{") {
 		return template
@@ -41,18 +120,53 @@ func TemplateString(template string, context ServiceInfo) string {
 	}
 
 	// Render the template.
-	result, err := tpl.Execute(pongo2.Context{
-		"service_id":   context.ID,
-		"service_name": context.Name,
-		"service_url":  context.URL,
-		"web_url":      context.WebURL,
-		"version":      context.LatestVersion})
+	result, err := tpl.Execute(context)
 	if err != nil {
 		panic(err)
 	}
 	return result
 }
 
+// templateContext returns the pongo2 context of the ServiceInfo.
+func (s ServiceInfo) templateContext() pongo2.Context {
+	releaseAssets := s.ReleaseAssets
+	if releaseAssets == nil {
+		releaseAssets = []string{}
+	}
+	webhookOutputs := s.WebHookOutputs
+	if webhookOutputs == nil {
+		webhookOutputs = map[string]map[string]string{}
+	}
+	webhookURLs := s.WebHookURLs
+	if webhookURLs == nil {
+		webhookURLs = map[string]string{}
+	}
+
+	return pongo2.Context{
+		"service_id":            s.ID,
+		"service_name":          s.Name,
+		"service_url":           s.URL,
+		"web_url":               s.WebURL,
+		"version":               s.LatestVersion,
+		"previous_version":      s.PreviousVersion,
+		"deployed_version":      s.DeployedVersion,
+		"approved_version":      s.ApprovedVersion,
+		"rollback_version":      s.RollbackVersion,
+		"update_type":           s.UpdateType,
+		"release_date":          s.ReleaseDate,
+		"release_notes":         s.ReleaseNotes,
+		"release_url":           s.ReleaseURL,
+		"release_assets":        releaseAssets,
+		"approval_url":          s.ApprovalURL,
+		"approve_url":           s.ApproveURL,
+		"skip_url":              s.SkipURL,
+		"webhook_urls":          webhookURLs,
+		"failure":               s.Failure,
+		"notifier_type":         s.NotifierType,
+		"webhook_outputs":       webhookOutputs,
+		"template_vars_version": TemplateVarsVersion}
+}
+
 // CheckTemplate verifies the validity of the template.
 func CheckTemplate(template string) bool {
 	// pongo2 DATA RACE.
@@ -62,3 +176,62 @@ func CheckTemplate(template string) bool {
 	_, err := pongo2.FromString(template)
 	return err == nil
 }
+
+// markdownEscapers for the notifier types that differ from the common markdown escaping.
+var markdownEscapers = map[string]*strings.Replacer{
+	// Slack mrkdwn cannot escape the formatting characters, only the control characters.
+	"slack": strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;"),
+	// Telegram MarkdownV2.
+	"telegram": newBackslashReplacer(`\_*[]()~` + "`" + `>#+-=|{}.!`),
+	// Discord, Matrix, Mattermost, ...
+	"": newBackslashReplacer(`\*_~|>[]()#` + "`"),
+}
+
+// newBackslashReplacer returns a strings.Replacer that prefixes each of `chars` with a backslash.
+func newBackslashReplacer(chars string) *strings.Replacer {
+	oldNew := make([]string, 0, 2*len(chars))
+	for _, char := range chars {
+		oldNew = append(oldNew, string(char), `\`+string(char))
+	}
+	return strings.NewReplacer(oldNew...)
+}
+
+// EscapeMarkdown escapes the markdown formatting characters in `text` for the `notifierType`.
+func EscapeMarkdown(text, notifierType string) string {
+	escaper, ok := markdownEscapers[strings.ToLower(notifierType)]
+	if !ok {
+		escaper = markdownEscapers[""]
+	}
+	return escaper.Replace(text)
+}
+
+// filterEscapeMarkdown is the pongo2 filter for EscapeMarkdown.
+//
+//	e.g. {{ release_notes | escape_markdown:notifier_type }}
+func filterEscapeMarkdown(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	return pongo2.AsValue(EscapeMarkdown(in.String(), param.String())), nil
+}
+
+// TruncateLines returns the first `lines` lines of `text`,
+// with an ellipsis line appended if any lines were removed.
+func TruncateLines(text string, lines int) string {
+	if lines <= 0 {
+		return text
+	}
+
+	split := strings.SplitN(text, "\n", lines+1)
+	if len(split) <= lines {
+		return text
+	}
+	return strings.Join(split[:lines], "\n") + "\n…"
+}
+
+// filterTruncateLines is the pongo2 filter for TruncateLines.
+//
+//	e.g. {{ release_notes | truncate_lines:10 }}
+func filterTruncateLines(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	return pongo2.AsValue(TruncateLines(in.String(), param.Integer())), nil
+}
@@ -61,6 +61,80 @@ func TestTemplate_String(t *testing.T) {
 			template:    "{{ service_id }}-{{ service_name }}-{{ service_url }}-{{ web_url }}-{{ version }}",
 			want:        "something-another-example.com-other.com-NEW",
 			serviceInfo: testServiceInfo()},
+		"release context vars": {
+			template: "{{ previous_version }}>{{ version }} ({{ update_type }}) on {{ release_date }}" +
+				" - deployed={{ deployed_version }},approved={{ approved_version }}" +
+				" - {{ release_url }} - {{ approval_url }}",
+			want: "1.2.3>NEW (major) on 2025-01-01T00:00:00Z" +
+				" - deployed=1.0.0,approved=2.0.0" +
+				" - https://example.com/release - https://argus.example.com/approvals",
+			serviceInfo: ServiceInfo{
+				LatestVersion:   "NEW",
+				PreviousVersion: "1.2.3",
+				DeployedVersion: "1.0.0",
+				ApprovedVersion: "2.0.0",
+				UpdateType:      "major",
+				ReleaseDate:     "2025-01-01T00:00:00Z",
+				ReleaseURL:      "https://example.com/release",
+				ApprovalURL:     "https://argus.example.com/approvals"}},
+		"rollback version": {
+			template: "rollback {{ version }} to {{ rollback_version }}",
+			want:     "rollback NEW to 1.0.0",
+			serviceInfo: ServiceInfo{
+				LatestVersion:   "NEW",
+				RollbackVersion: "1.0.0"}},
+		"webhook outputs": {
+			template: "job {{ webhook_outputs.deploy.job_id }}{% if webhook_outputs.other %} other{% endif %}",
+			want:     "job 42",
+			serviceInfo: ServiceInfo{
+				WebHookOutputs: map[string]map[string]string{
+					"deploy": {"job_id": "42"}}}},
+		"no webhook outputs": {
+			template:    "job {{ webhook_outputs.deploy.job_id | default:'none' }}",
+			want:        "job none",
+			serviceInfo: ServiceInfo{}},
+		"action links": {
+			template: "{% if approve_url %}approve={{ approve_url }} skip={{ skip_url }}{% endif %}" +
+				"{% for id, url in webhook_urls %} {{ id }}={{ url }}{% endfor %}",
+			want: "approve=https://argus.example.com/a skip=https://argus.example.com/s" +
+				" deploy=https://argus.example.com/d",
+			serviceInfo: ServiceInfo{
+				ApproveURL:  "https://argus.example.com/a",
+				SkipURL:     "https://argus.example.com/s",
+				WebHookURLs: map[string]string{"deploy": "https://argus.example.com/d"}}},
+		"no action links": {
+			template:    "{% if approve_url %}approve{% else %}none{% endif %}{{ webhook_urls.deploy | default:'' }}",
+			want:        "none",
+			serviceInfo: ServiceInfo{}},
+		"release assets": {
+			template: "{% for asset in release_assets %}[{{ asset }}]{% endfor %}",
+			want:     "[a.tar.gz][b.zip]",
+			serviceInfo: ServiceInfo{
+				ReleaseAssets: []string{"a.tar.gz", "b.zip"}}},
+		"no release assets": {
+			template:    "{% if release_assets %}assets{% else %}none{% endif %}",
+			want:        "none",
+			serviceInfo: testServiceInfo()},
+		"failure": {
+			template: "{% if failure %}FAILED: {{ failure }}{% endif %}",
+			want:     "FAILED: exit status 1",
+			serviceInfo: ServiceInfo{
+				Failure: "exit status 1"}},
+		"template_vars_version": {
+			template:    "v{{ template_vars_version }}",
+			want:        fmt.Sprintf("v%d", TemplateVarsVersion),
+			serviceInfo: testServiceInfo()},
+		"escape_markdown filter with notifier_type": {
+			template: "{{ release_notes | escape_markdown:notifier_type }}",
+			want:     `\*bold\* \_it\_ v1\.2\.3\!`,
+			serviceInfo: ServiceInfo{
+				ReleaseNotes: "*bold* _it_ v1.2.3!",
+				NotifierType: "telegram"}},
+		"truncate_lines filter": {
+			template: "{{ release_notes | truncate_lines:2 }}",
+			want:     "a\nb\n…",
+			serviceInfo: ServiceInfo{
+				ReleaseNotes: "a\nb\nc"}},
 	}
 
 	for name, tc := range tests {
@@ -92,6 +166,54 @@ func TestTemplate_String(t *testing.T) {
 	}
 }
 
+func TestTemplateShell(t *testing.T) {
+	// GIVEN a ServiceInfo with shell syntax in its values, and a variety of shell templates
+	serviceInfo := ServiceInfo{
+		ID:             "something",
+		LatestVersion:  "1.2.3; touch pwned #",
+		ReleaseNotes:   "it's <fixed>",
+		ReleaseAssets:  []string{"a b", "$(c)"},
+		WebHookOutputs: map[string]map[string]string{"wh": {"out": "`d`"}}}
+	tests := map[string]struct {
+		template string
+		want     string
+	}{
+		"no django template": {
+			template: "echo done && exit 0",
+			want:     "echo done && exit 0"},
+		"shell syntax of the template is kept": {
+			template: "./deploy.sh {{ version }} && ./notify {{ service_id }}",
+			want:     "./deploy.sh '1.2.3; touch pwned #' && ./notify 'something'"},
+		"quotes are escaped, and nothing is HTML escaped": {
+			template: "echo {{ release_notes }}",
+			want:     `echo 'it'"'"'s <fixed>'`},
+		"list values": {
+			template: "{% for asset in release_assets %}{{ asset }} {% endfor %}",
+			want:     "'a b' '$(c)' "},
+		"map values": {
+			template: "echo {{ webhook_outputs.wh.out }}",
+			want:     "echo '`d`'"},
+		"conditions on values": {
+			template: "{% if release_notes %}notes{% endif %}{% if skip_url %}skip{% endif %}",
+			want:     "notes"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN TemplateShell is called
+			got := TemplateShell(tc.template, serviceInfo)
+
+			// THEN only the values are quoted
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
 func TestCheckTemplate(t *testing.T) {
 	// GIVEN a variety of string templates
 	tests := map[string]struct {
@@ -118,3 +240,135 @@ func TestCheckTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestEscapeMarkdown(t *testing.T) {
+	// GIVEN text to escape for a notifier type.
+	tests := map[string]struct {
+		text, notifierType string
+		want               string
+	}{
+		"no formatting": {
+			text:         "plain text",
+			notifierType: "discord",
+			want:         "plain text"},
+		"discord": {
+			text:         "*bold* _it_ ~strike~ `code` [link](url) # h",
+			notifierType: "discord",
+			want:         "\\*bold\\* \\_it\\_ \\~strike\\~ \\`code\\` \\[link\\]\\(url\\) \\# h"},
+		"unknown type uses common escaping": {
+			text:         "*a*",
+			notifierType: "something",
+			want:         `\*a\*`},
+		"slack only escapes control characters": {
+			text:         "*a* <b> & c",
+			notifierType: "slack",
+			want:         "*a* &lt;b&gt; &amp; c"},
+		"telegram": {
+			text:         "v1.2.3-rc.1 (beta)!",
+			notifierType: "telegram",
+			want:         `v1\.2\.3\-rc\.1 \(beta\)\!`},
+		"type is case-insensitive": {
+			text:         "<a>",
+			notifierType: "Slack",
+			want:         "&lt;a&gt;"},
+		"backslashes are escaped": {
+			text:         `a\b`,
+			notifierType: "discord",
+			want:         `a\\b`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN EscapeMarkdown is called.
+			got := EscapeMarkdown(tc.text, tc.notifierType)
+
+			// THEN the text is escaped as expected.
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestTruncateLines(t *testing.T) {
+	// GIVEN text to truncate to a number of lines.
+	tests := map[string]struct {
+		text  string
+		lines int
+		want  string
+	}{
+		"fewer lines than limit": {
+			text:  "a\nb",
+			lines: 3,
+			want:  "a\nb"},
+		"same lines as limit": {
+			text:  "a\nb\nc",
+			lines: 3,
+			want:  "a\nb\nc"},
+		"more lines than limit": {
+			text:  "a\nb\nc\nd",
+			lines: 2,
+			want:  "a\nb\n…"},
+		"limit of 0 leaves text untouched": {
+			text:  "a\nb",
+			lines: 0,
+			want:  "a\nb"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN TruncateLines is called.
+			got := TruncateLines(tc.text, tc.lines)
+
+			// THEN the text is truncated as expected.
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestTemplateDigest(t *testing.T) {
+	// GIVEN a digest template and the contexts of several Services.
+	contexts := []ServiceInfo{
+		{ID: "alpha", LatestVersion: "1.0.0"},
+		{ID: "beta", Name: "Beta", LatestVersion: "2.0.0"}}
+	tests := map[string]struct {
+		template string
+		want     string
+	}{
+		"no django template": {
+			template: "digest",
+			want:     "digest"},
+		"count": {
+			template: "{{ count }} releases",
+			want:     "2 releases"},
+		"services": {
+			template: "{% for service in services %}{{ service.service_name | default:service.service_id }}={{ service.version }};{% endfor %}",
+			want:     "alpha=1.0.0;Beta=2.0.0;"},
+		"template_vars_version": {
+			template: "v{{ template_vars_version }}",
+			want:     fmt.Sprintf("v%d", TemplateVarsVersion)},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN TemplateDigest is called.
+			got := TemplateDigest(tc.template, contexts)
+
+			// THEN the template is rendered as expected.
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
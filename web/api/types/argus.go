@@ -139,6 +139,8 @@ type Status struct {
 	LastQueried              string `json:"last_queried,omitempty" yaml:"last_queried,omitempty"`                             // UTC timestamp of the last query.
 	RegexMissesContent       uint   `json:"regex_misses_content,omitempty" yaml:"regex_misses_content,omitempty"`             // Counter for the amount of regex misses on URL content.
 	RegexMissesVersion       uint   `json:"regex_misses_version,omitempty" yaml:"regex_misses_version,omitempty"`             // Counter for the amount of regex misses on version.
+	LatestVersionBackoff     string `json:"latest_version_backoff,omitempty" yaml:"latest_version_backoff,omitempty"`         // UTC timestamp the latest version queries are backed off until (after consecutive failures).
+	DeployedVersionBackoff   string `json:"deployed_version_backoff,omitempty" yaml:"deployed_version_backoff,omitempty"`     // UTC timestamp the deployed version queries are backed off until (after consecutive failures).
 }
 
 // String returns a JSON string representation of the Status.
@@ -163,14 +165,51 @@ type StatusFailsSummary struct {
 
 // ActionSummary is the summary of all Actions for a Service.
 type ActionSummary struct {
-	Command map[string]CommandSummary `json:"command" yaml:"command"` // Summary of all Commands.
-	WebHook map[string]WebHookSummary `json:"webhook" yaml:"webhook"` // Summary of all WebHooks.
+	Command  map[string]CommandSummary `json:"command" yaml:"command"`                       // Summary of all Commands.
+	WebHook  map[string]WebHookSummary `json:"webhook" yaml:"webhook"`                       // Summary of all WebHooks.
+	Pipeline *PipelineSummary          `json:"pipeline,omitempty" yaml:"pipeline,omitempty"` // Progress of the Pipeline.
+	Approval *ApprovalSummary          `json:"approval,omitempty" yaml:"approval,omitempty"` // Approvals of the latest version.
+	Queued   *QueuedActions            `json:"queued,omitempty" yaml:"queued,omitempty"`     // Actions waiting for a change window.
+}
+
+// QueuedActions are the automated actions of a release waiting for a change window to open.
+type QueuedActions struct {
+	Version string    `json:"version" yaml:"version"` // Version the actions are for.
+	Queued  time.Time `json:"queued" yaml:"queued"`   // Time the actions were queued.
+	RunAt   time.Time `json:"run_at" yaml:"run_at"`   // Time the actions will run.
+}
+
+// ApprovalSummary is the progress of the approval policy for the latest version.
+type ApprovalSummary struct {
+	Required  int              `json:"required" yaml:"required"`                       // Number of distinct approvers required.
+	Approvals []ApprovalRecord `json:"approvals,omitempty" yaml:"approvals,omitempty"` // Approvals given.
+}
+
+// ApprovalRecord is an approval given towards the approval policy.
+type ApprovalRecord struct {
+	Approver string    `json:"approver" yaml:"approver"`                 // Identity of the approver.
+	Reason   string    `json:"reason,omitempty" yaml:"reason,omitempty"` // Reason given.
+	Time     time.Time `json:"time" yaml:"time"`                         // Time of the approval.
+}
+
+// PipelineSummary is the progress of a Pipeline.
+type PipelineSummary struct {
+	Running bool                   `json:"running" yaml:"running"` // Whether the Pipeline is running.
+	Stages  []PipelineStageSummary `json:"stages" yaml:"stages"`   // Progress of each Stage.
+}
+
+// PipelineStageSummary is the progress of a Stage in a Pipeline.
+type PipelineStageSummary struct {
+	Name   string `json:"name" yaml:"name"`                       // Name of the Stage.
+	Status string `json:"status" yaml:"status"`                   // "pending"/"running"/"success"/"failed"/"skipped".
+	Error  string `json:"error,omitempty" yaml:"error,omitempty"` // Error of the Stage, if it failed.
 }
 
 // WebHookSummary is the summary of a WebHook.
 type WebHookSummary struct {
-	Failed       *bool     `json:"failed,omitempty" yaml:"failed,omitempty"`               // Whether this WebHook failed to send successfully for the LatestVersion.
-	NextRunnable time.Time `json:"next_runnable,omitempty" yaml:"next_runnable,omitempty"` // Time the WebHook can next run (for staggering).
+	Failed       *bool             `json:"failed,omitempty" yaml:"failed,omitempty"`               // Whether this WebHook failed to send successfully for the LatestVersion.
+	NextRunnable time.Time         `json:"next_runnable,omitempty" yaml:"next_runnable,omitempty"` // Time the WebHook can next run (for staggering).
+	Outputs      map[string]string `json:"outputs,omitempty" yaml:"outputs,omitempty"`             // Values captured from the WebHook response.
 }
 
 // BuildInfo is information from build time.
@@ -272,6 +311,7 @@ type WebSettings struct {
 	CertFile    string `json:"cert_file,omitempty" yaml:"cert_file,omitempty"`       // HTTPS certificate path.
 	KeyFile     string `json:"pkey_file,omitempty" yaml:"pkey_file,omitempty"`       // HTTPS privkey path.
 	RoutePrefix string `json:"route_prefix,omitempty" yaml:"route_prefix,omitempty"` // Web endpoint prefix.
+	PublicURL   string `json:"public_url,omitempty" yaml:"public_url,omitempty"`     // URL Argus is reachable at.
 }
 
 // NotifySlice is a slice map of Notify.
@@ -370,8 +410,13 @@ type Service struct {
 	Options               *ServiceOptions        `json:"options,omitempty" yaml:"options,omitempty"`                   // Options to give the Service.
 	LatestVersion         *LatestVersion         `json:"latest_version,omitempty" yaml:"latest_version,omitempty"`     // Latest version lookup for the Service.
 	Command               *CommandSlice          `json:"command,omitempty" yaml:"command,omitempty"`                   // OS Commands to run on new release.
+	CommandOptions        *CommandOptionsSlice   `json:"command_options,omitempty" yaml:"command_options,omitempty"`   // Options to run each Command with.
 	Notify                *NotifySlice           `json:"notify,omitempty" yaml:"notify,omitempty"`                     // Service-specific Notify vars.
 	WebHook               *WebHookSlice          `json:"webhook,omitempty" yaml:"webhook,omitempty"`                   // Service-specific WebHook vars.
+	Pipeline              *Pipeline              `json:"pipeline,omitempty" yaml:"pipeline,omitempty"`                 // Order to run the Command(s)/WebHook(s)/Notify(s) in on approval.
+	Rollback              *Rollback              `json:"rollback,omitempty" yaml:"rollback,omitempty"`                 // Verify the actions deployed the new version, and roll back if not.
+	Approval              *ApprovalPolicy        `json:"approval,omitempty" yaml:"approval,omitempty"`                 // Approvals required before the actions run.
+	ChangeWindow          *ChangeWindow          `json:"change_window,omitempty" yaml:"change_window,omitempty"`       // Times the automated actions may run.
 	DeployedVersionLookup *DeployedVersionLookup `json:"deployed_version,omitempty" yaml:"deployed_version,omitempty"` // Var to scrape the Service's current deployed version.
 	Dashboard             *DashboardOptions      `json:"dashboard,omitempty" yaml:"dashboard,omitempty"`               // Dashboard options.
 	Status                *Status                `json:"status,omitempty" yaml:"status,omitempty"`                     // Track the Status of this source (version and regex misses).
@@ -385,6 +430,58 @@ func (r *Service) String() string {
 	return util.ToJSONString(r)
 }
 
+// Pipeline is an ordered list of Stages to run on approval of a release.
+type Pipeline struct {
+	Stages []PipelineStage `json:"stages,omitempty" yaml:"stages,omitempty"` // Stages to run, in order.
+}
+
+// PipelineStage is a set of actions that run concurrently, and the Stage to move to once they finish.
+type PipelineStage struct {
+	Name      string   `json:"name,omitempty" yaml:"name,omitempty"`             // Unique name of the Stage.
+	Command   []string `json:"command,omitempty" yaml:"command,omitempty"`       // Commands to run.
+	WebHook   []string `json:"webhook,omitempty" yaml:"webhook,omitempty"`       // IDs of the WebHooks to send.
+	Notify    []string `json:"notify,omitempty" yaml:"notify,omitempty"`         // IDs of the Notifiers to send.
+	OnSuccess string   `json:"on_success,omitempty" yaml:"on_success,omitempty"` // Stage to run if every action succeeds.
+	OnFailure string   `json:"on_failure,omitempty" yaml:"on_failure,omitempty"` // Stage to run if any action fails.
+}
+
+// Rollback verifies the deployed_version reports the approved version after the actions, and rolls back if not.
+type Rollback struct {
+	Timeout  string        `json:"timeout,omitempty" yaml:"timeout,omitempty"`   // Time for deployed_version to report the approved version.
+	Interval string        `json:"interval,omitempty" yaml:"interval,omitempty"` // Time between deployed_version queries whilst verifying.
+	Command  *CommandSlice `json:"command,omitempty" yaml:"command,omitempty"`   // Commands to roll back with.
+	WebHook  *WebHookSlice `json:"webhook,omitempty" yaml:"webhook,omitempty"`   // WebHooks to roll back with.
+}
+
+// ApprovalPolicy defines the approvals a release needs before the actions of a Service run.
+type ApprovalPolicy struct {
+	Required      int      `json:"required,omitempty" yaml:"required,omitempty"`             // Number of distinct approvers required.
+	Approvers     []string `json:"approvers,omitempty" yaml:"approvers,omitempty"`           // Identities allowed to approve.
+	Expiry        string   `json:"expiry,omitempty" yaml:"expiry,omitempty"`                 // Time an approval counts for.
+	RequireReason bool     `json:"require_reason,omitempty" yaml:"require_reason,omitempty"` // Whether each approval must give a reason.
+}
+
+// ChangeWindow defines the change windows and freeze periods the automated actions run in.
+type ChangeWindow struct {
+	Windows []ChangeWindowWindow `json:"windows,omitempty" yaml:"windows,omitempty"` // Times the actions may run.
+	Freezes []ChangeWindowFreeze `json:"freezes,omitempty" yaml:"freezes,omitempty"` // Periods the actions may not run.
+}
+
+// ChangeWindowWindow is a time of day, on days of the week, that the actions may run in.
+type ChangeWindowWindow struct {
+	Days     []string `json:"days,omitempty" yaml:"days,omitempty"`         // Days of the week.
+	Start    string   `json:"start,omitempty" yaml:"start,omitempty"`       // Time of day the window opens.
+	End      string   `json:"end,omitempty" yaml:"end,omitempty"`           // Time of day the window closes.
+	Timezone string   `json:"timezone,omitempty" yaml:"timezone,omitempty"` // Timezone of the times.
+}
+
+// ChangeWindowFreeze is a period the actions may not run in.
+type ChangeWindowFreeze struct {
+	Start  string `json:"start,omitempty" yaml:"start,omitempty"`   // Start of the freeze.
+	End    string `json:"end,omitempty" yaml:"end,omitempty"`       // End of the freeze.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"` // Reason for the freeze.
+}
+
 // ServiceDefaults defines default values for a Service.
 type ServiceDefaults struct {
 	Comment               string                 `json:"comment,omitempty" yaml:"comment,omitempty"`                   // Comment on the Service.
@@ -394,6 +491,7 @@ type ServiceDefaults struct {
 	Command               CommandSlice           `json:"command,omitempty" yaml:"command,omitempty"`                   // OS Commands to run on new release.
 	WebHook               map[string]struct{}    `json:"webhook,omitempty" yaml:"webhook,omitempty"`                   // Service-specific WebHook vars.
 	DeployedVersionLookup *DeployedVersionLookup `json:"deployed_version,omitempty" yaml:"deployed_version,omitempty"` // Var to scrape the Service's current deployed version.
+	ChangeWindow          *ChangeWindow          `json:"change_window,omitempty" yaml:"change_window,omitempty"`       // Times the automated actions may run.
 	Dashboard             *DashboardOptions      `json:"dashboard,omitempty" yaml:"dashboard,omitempty"`               // Dashboard options.
 	Status                *Status                `json:"status,omitempty" yaml:"status,omitempty"`
 }
@@ -402,6 +500,8 @@ type ServiceDefaults struct {
 type ServiceOptions struct {
 	Active             *bool  `json:"active,omitempty" yaml:"active,omitempty"`                           // Active Service?.
 	Interval           string `json:"interval,omitempty" yaml:"interval,omitempty"`                       // AhBmCs = Sleep A hours, B minutes and C seconds between queries.
+	Schedule           string `json:"schedule,omitempty" yaml:"schedule,omitempty"`                       // Cron expression of the query times (takes precedence over interval).
+	Jitter             string `json:"jitter,omitempty" yaml:"jitter,omitempty"`                           // AhBmCs = Delay each query by up to A hours, B minutes and C seconds at random.
 	SemanticVersioning *bool  `json:"semantic_versioning,omitempty" yaml:"semantic_versioning,omitempty"` // Default - true = Version must exceed the previous version to trigger alerts/Commands/WebHooks.
 }
 
@@ -419,6 +519,10 @@ type LatestVersion struct {
 	URL               string                `json:"url,omitempty" yaml:"url,omitempty"`                                 // URL to query.
 	AccessToken       string                `json:"access_token,omitempty" yaml:"access_token,omitempty"`               // GitHub access token to use.
 	AllowInvalidCerts *bool                 `json:"allow_invalid_certs,omitempty" yaml:"allow_invalid_certs,omitempty"` // Default - false = Disallows invalid HTTPS certificates.
+	Timeout           string                `json:"timeout,omitempty" yaml:"timeout,omitempty"`                         // Timeout of each request, e.g. 30s.
+	CAFile            string                `json:"ca_file,omitempty" yaml:"ca_file,omitempty"`                         // PEM bundle of CAs to trust.
+	CertFile          string                `json:"cert_file,omitempty" yaml:"cert_file,omitempty"`                     // PEM client certificate for mutual TLS.
+	KeyFile           string                `json:"key_file,omitempty" yaml:"key_file,omitempty"`                       // PEM client key for mutual TLS.
 	UsePreRelease     *bool                 `json:"use_prerelease,omitempty" yaml:"use_prerelease,omitempty"`           // Whether to use GitHub prereleases.
 	URLCommands       *URLCommandSlice      `json:"url_commands,omitempty" yaml:"url_commands,omitempty"`               // Commands to filter the release from the URL request.
 	Require           *LatestVersionRequire `json:"require,omitempty" yaml:"require,omitempty"`                         // Requirements before treating a release as valid.
@@ -438,6 +542,10 @@ type LatestVersionDefaults struct {
 	URL               string                        `json:"url,omitempty" yaml:"url,omitempty"`                                 // URL to query.
 	AccessToken       string                        `json:"access_token,omitempty" yaml:"access_token,omitempty"`               // GitHub access token to use.
 	AllowInvalidCerts *bool                         `json:"allow_invalid_certs,omitempty" yaml:"allow_invalid_certs,omitempty"` // Default - false = Disallows invalid HTTPS certificates.
+	Timeout           string                        `json:"timeout,omitempty" yaml:"timeout,omitempty"`                         // Timeout of each request, e.g. 30s.
+	CAFile            string                        `json:"ca_file,omitempty" yaml:"ca_file,omitempty"`                         // PEM bundle of CAs to trust.
+	CertFile          string                        `json:"cert_file,omitempty" yaml:"cert_file,omitempty"`                     // PEM client certificate for mutual TLS.
+	KeyFile           string                        `json:"key_file,omitempty" yaml:"key_file,omitempty"`                       // PEM client key for mutual TLS.
 	UsePreRelease     *bool                         `json:"use_prerelease,omitempty" yaml:"use_prerelease,omitempty"`           // Whether to use GitHub prereleases.
 	Require           *LatestVersionRequireDefaults `json:"require,omitempty" yaml:"require,omitempty"`
 }
@@ -508,6 +616,12 @@ type DeployedVersionLookup struct {
 	Method            string                 `json:"method,omitempty" yaml:"method,omitempty"`                           // HTTP method.
 	URL               string                 `json:"url,omitempty" yaml:"url,omitempty"`                                 // URL to query.
 	AllowInvalidCerts *bool                  `json:"allow_invalid_certs,omitempty" yaml:"allow_invalid_certs,omitempty"` // Default - false = Disallows invalid HTTPS certificates.
+	Interval          string                 `json:"interval,omitempty" yaml:"interval,omitempty"`                       // AhBmCs between queries (default = the interval/schedule of the Service).
+	Schedule          string                 `json:"schedule,omitempty" yaml:"schedule,omitempty"`                       // Cron expression of the query times (takes precedence over interval).
+	Timeout           string                 `json:"timeout,omitempty" yaml:"timeout,omitempty"`                         // Timeout of each request, e.g. 30s.
+	CAFile            string                 `json:"ca_file,omitempty" yaml:"ca_file,omitempty"`                         // PEM bundle of CAs to trust.
+	CertFile          string                 `json:"cert_file,omitempty" yaml:"cert_file,omitempty"`                     // PEM client certificate for mutual TLS.
+	KeyFile           string                 `json:"key_file,omitempty" yaml:"key_file,omitempty"`                       // PEM client key for mutual TLS.
 	BasicAuth         *BasicAuth             `json:"basic_auth,omitempty" yaml:"basic_auth,omitempty"`                   // Basic Auth credentials.
 	Headers           []Header               `json:"headers,omitempty" yaml:"headers,omitempty"`                         // Request Headers.
 	Body              string                 `json:"body,omitempty" yaml:"body,omitempty"`                               // Request Body.
@@ -566,6 +680,30 @@ type Command []string
 // CommandSlice is a slice of Command.
 type CommandSlice []Command
 
+// CommandOptionsSlice is a mapping of Command to its CommandOptions.
+type CommandOptionsSlice map[string]*CommandOptions
+
+// CommandOptions are the options to run a Command with.
+type CommandOptions struct {
+	Timeout       string            `json:"timeout,omitempty" yaml:"timeout,omitempty"`               // Time before the Command is killed.
+	Env           map[string]string `json:"env,omitempty" yaml:"env,omitempty"`                       // Environment variables to add.
+	Dir           string            `json:"dir,omitempty" yaml:"dir,omitempty"`                       // Working directory to run in.
+	Shell         bool              `json:"shell,omitempty" yaml:"shell,omitempty"`                   // Run the Command through 'sh -c'.
+	CaptureOutput bool              `json:"capture_output,omitempty" yaml:"capture_output,omitempty"` // Store the stdout/stderr of the latest run.
+	MaxOutput     int               `json:"max_output,omitempty" yaml:"max_output,omitempty"`         // Bytes of stdout/stderr to store.
+	Container     *CommandContainer `json:"container,omitempty" yaml:"container,omitempty"`           // Run the Command in this Container instead of on the host.
+}
+
+// CommandContainer is the container to run a Command in.
+type CommandContainer struct {
+	Image      string   `json:"image,omitempty" yaml:"image,omitempty"`             // Image to run the Command in.
+	Mounts     []string `json:"mounts,omitempty" yaml:"mounts,omitempty"`           // Bind mounts in the 'src:dst[:ro]' format.
+	Network    string   `json:"network,omitempty" yaml:"network,omitempty"`         // Network mode.
+	Memory     string   `json:"memory,omitempty" yaml:"memory,omitempty"`           // Memory limit.
+	CPUs       float64  `json:"cpus,omitempty" yaml:"cpus,omitempty"`               // CPU limit.
+	AutoRemove *bool    `json:"auto_remove,omitempty" yaml:"auto_remove,omitempty"` // Remove the container once finished.
+}
+
 // WebHookSlice is a slice map of WebHook.
 type WebHookSlice map[string]*WebHook
 
@@ -597,17 +735,46 @@ func (slice *WebHookSlice) Flatten() []*WebHook {
 
 // WebHook is a WebHook to send.
 type WebHook struct {
-	ServiceID         string    `json:"-" yaml:"-"`                                                         // ID of the service this WebHook belongs to.
-	ID                string    `json:"name,omitempty" yaml:"name,omitempty"`                               // Name of this WebHook.
-	Type              string    `json:"type,omitempty" yaml:"type,omitempty"`                               // "github"/"url".
-	URL               string    `json:"url,omitempty" yaml:"url,omitempty"`                                 // "https://example.com".
-	AllowInvalidCerts *bool     `json:"allow_invalid_certs,omitempty" yaml:"allow_invalid_certs,omitempty"` // Default - false = Disallows invalid HTTPS certificates.
-	Secret            string    `json:"secret,omitempty" yaml:"secret,omitempty"`                           // "SECRET".
-	CustomHeaders     *[]Header `json:"custom_headers,omitempty" yaml:"custom_headers,omitempty"`           // Custom Headers for the WebHook.
-	DesiredStatusCode *uint16   `json:"desired_status_code,omitempty" yaml:"desired_status_code,omitempty"` // e.g. 202.
-	Delay             string    `json:"delay,omitempty" yaml:"delay,omitempty"`                             // The delay before sending the WebHook.
-	MaxTries          *uint8    `json:"max_tries,omitempty" yaml:"max_tries,omitempty"`                     // Amount of times to send the WebHook until we receive the desired status code.
-	SilentFails       *bool     `json:"silent_fails,omitempty" yaml:"silent_fails,omitempty"`               // Whether to notify if this WebHook fails MaxTries times.
+	ServiceID         string                    `json:"-" yaml:"-"`                                                         // ID of the service this WebHook belongs to.
+	ID                string                    `json:"name,omitempty" yaml:"name,omitempty"`                               // Name of this WebHook.
+	Type              string                    `json:"type,omitempty" yaml:"type,omitempty"`                               // "github"/"gitlab"/"generic".
+	URL               string                    `json:"url,omitempty" yaml:"url,omitempty"`                                 // "https://example.com".
+	AllowInvalidCerts *bool                     `json:"allow_invalid_certs,omitempty" yaml:"allow_invalid_certs,omitempty"` // Default - false = Disallows invalid HTTPS certificates.
+	Timeout           string                    `json:"timeout,omitempty" yaml:"timeout,omitempty"`                         // Timeout of each request, e.g. 30s.
+	CAFile            string                    `json:"ca_file,omitempty" yaml:"ca_file,omitempty"`                         // PEM bundle of CAs to trust.
+	CertFile          string                    `json:"cert_file,omitempty" yaml:"cert_file,omitempty"`                     // PEM client certificate for mutual TLS.
+	KeyFile           string                    `json:"key_file,omitempty" yaml:"key_file,omitempty"`                       // PEM client key for mutual TLS.
+	Secret            string                    `json:"secret,omitempty" yaml:"secret,omitempty"`                           // "SECRET".
+	CustomHeaders     *[]Header                 `json:"custom_headers,omitempty" yaml:"custom_headers,omitempty"`           // Custom Headers for the WebHook.
+	DesiredStatusCode *uint16                   `json:"desired_status_code,omitempty" yaml:"desired_status_code,omitempty"` // e.g. 202.
+	Delay             string                    `json:"delay,omitempty" yaml:"delay,omitempty"`                             // The delay before sending the WebHook.
+	MaxTries          *uint8                    `json:"max_tries,omitempty" yaml:"max_tries,omitempty"`                     // Amount of times to send the WebHook until we receive the desired status code.
+	SilentFails       *bool                     `json:"silent_fails,omitempty" yaml:"silent_fails,omitempty"`               // Whether to notify if this WebHook fails MaxTries times.
+	Method            string                    `json:"method,omitempty" yaml:"method,omitempty"`                           // generic - HTTP method, e.g. POST.
+	QueryParams       *[]Header                 `json:"query_params,omitempty" yaml:"query_params,omitempty"`               // generic - Templated query parameters to add to the URL.
+	ContentType       string                    `json:"content_type,omitempty" yaml:"content_type,omitempty"`               // generic - "json"/"form"/"text"/"xml".
+	Body              string                    `json:"body,omitempty" yaml:"body,omitempty"`                               // generic - Templated request body.
+	Signing           string                    `json:"signing,omitempty" yaml:"signing,omitempty"`                         // generic - "none"/"hmac-sha256"/"bearer".
+	SigningHeader     string                    `json:"signing_header,omitempty" yaml:"signing_header,omitempty"`           // generic - Header to put the HMAC signature in.
+	SigningFormat     string                    `json:"signing_format,omitempty" yaml:"signing_format,omitempty"`           // generic - Format of the HMAC header value.
+	ResponseChecks    *[]WebHookResponseCheck   `json:"response_checks,omitempty" yaml:"response_checks,omitempty"`         // Assertions the response must pass.
+	ResponseCaptures  *[]WebHookResponseCapture `json:"response_captures,omitempty" yaml:"response_captures,omitempty"`     // Values to capture from the response.
+}
+
+// WebHookResponseCheck is an assertion on the response to a WebHook.
+type WebHookResponseCheck struct {
+	JSONPath string `json:"json_path,omitempty" yaml:"json_path,omitempty"` // Key of the value in the JSON body.
+	Header   string `json:"header,omitempty" yaml:"header,omitempty"`       // Header that must be present.
+	Equals   string `json:"equals,omitempty" yaml:"equals,omitempty"`       // Value the target must equal.
+	Regex    string `json:"regex,omitempty" yaml:"regex,omitempty"`         // RegEx the target must match.
+}
+
+// WebHookResponseCapture is a value to capture from the response to a WebHook.
+type WebHookResponseCapture struct {
+	Name     string `json:"name" yaml:"name"`                               // Name to store the value as.
+	JSONPath string `json:"json_path,omitempty" yaml:"json_path,omitempty"` // Key of the value in the JSON body.
+	Header   string `json:"header,omitempty" yaml:"header,omitempty"`       // Header to capture.
+	Regex    string `json:"regex,omitempty" yaml:"regex,omitempty"`         // RegEx with a capture group to extract.
 }
 
 // String returns a string representation of the WebHook.
@@ -644,8 +811,16 @@ type Notifiers struct {
 
 // CommandSummary holds the summary of a Command.
 type CommandSummary struct {
-	Failed       *bool     `json:"failed,omitempty" yaml:"failed,omitempty"`               // Whether the last run failed.
-	NextRunnable time.Time `json:"next_runnable,omitempty" yaml:"next_runnable,omitempty"` // Time at which the Command can next run (for staggering).
+	Failed       *bool          `json:"failed,omitempty" yaml:"failed,omitempty"`               // Whether the last run failed.
+	NextRunnable time.Time      `json:"next_runnable,omitempty" yaml:"next_runnable,omitempty"` // Time at which the Command can next run (for staggering).
+	Output       *CommandOutput `json:"output,omitempty" yaml:"output,omitempty"`               // Output of the last run (if captured).
+}
+
+// CommandOutput is the captured output of a Command run.
+type CommandOutput struct {
+	Stdout    string `json:"stdout,omitempty" yaml:"stdout,omitempty"`       // Standard output.
+	Stderr    string `json:"stderr,omitempty" yaml:"stderr,omitempty"`       // Standard error.
+	Truncated bool   `json:"truncated,omitempty" yaml:"truncated,omitempty"` // Whether the output was cut short.
 }
 
 // CommandStatusUpdate holds an update of the current state of the Command.
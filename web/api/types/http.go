@@ -34,3 +34,84 @@ type Response struct {
 	Error   string `json:"error,omitempty"`
 	Message string `json:"message,omitempty"`
 }
+
+// HistoryRecord used in /api/v1/history
+type HistoryRecord struct {
+	ID             string    `json:"id"`
+	Time           time.Time `json:"time"`
+	Kind           string    `json:"kind"`
+	ServiceID      string    `json:"service_id"`
+	Target         string    `json:"target"`
+	Version        string    `json:"version,omitempty"`
+	Payload        string    `json:"payload,omitempty"`
+	ResponseStatus int       `json:"response_status,omitempty"`
+	DurationMS     int64     `json:"duration_ms"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// History used in /api/v1/history
+type History struct {
+	Records []HistoryRecord `json:"records"`
+	Total   int             `json:"total"`
+	Limit   int             `json:"limit"`
+	Offset  int             `json:"offset"`
+}
+
+// VersionEvent used in /api/v1/service/timeline
+type VersionEvent struct {
+	ID      string    `json:"id"`
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Version string    `json:"version"`
+	Actor   string    `json:"actor,omitempty"`
+}
+
+// VersionTimeline used in /api/v1/service/timeline
+type VersionTimeline struct {
+	Version    string     `json:"version"`
+	Seen       *time.Time `json:"seen,omitempty"`
+	Approved   *time.Time `json:"approved,omitempty"`
+	ApprovedBy string     `json:"approved_by,omitempty"`
+	Skipped    *time.Time `json:"skipped,omitempty"`
+	SkippedBy  string     `json:"skipped_by,omitempty"`
+	Deployed   *time.Time `json:"deployed,omitempty"`
+	LeadTimeMS *int64     `json:"lead_time_ms,omitempty"`
+}
+
+// ServiceTimeline used in /api/v1/service/timeline
+type ServiceTimeline struct {
+	ServiceID string            `json:"service_id"`
+	Versions  []VersionTimeline `json:"versions"`
+	Events    []VersionEvent    `json:"events"`
+}
+
+// Orphan used in /api/v1/orphans
+type Orphan struct {
+	ID                       string    `json:"id"`
+	LatestVersion            string    `json:"latest_version,omitempty"`
+	LatestVersionTimestamp   string    `json:"latest_version_timestamp,omitempty"`
+	DeployedVersion          string    `json:"deployed_version,omitempty"`
+	DeployedVersionTimestamp string    `json:"deployed_version_timestamp,omitempty"`
+	ApprovedVersion          string    `json:"approved_version,omitempty"`
+	Archived                 time.Time `json:"archived"`
+}
+
+// RestoreOrphan used in /api/v1/orphans/{id}/restore
+type RestoreOrphan struct {
+	ServiceID string `json:"service_id,omitempty"` // Service to restore to (default = the ID of the orphan).
+}
+
+// OutboxEntry used in /api/v1/outbox
+type OutboxEntry struct {
+	ID          string     `json:"id"`
+	Kind        string     `json:"kind"`
+	ServiceID   string     `json:"service_id"`
+	Target      string     `json:"target"`
+	Title       string     `json:"title,omitempty"`
+	Version     string     `json:"version,omitempty"`
+	Attempts    int        `json:"attempts"`
+	NextAttempt *time.Time `json:"next_attempt,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	State       string     `json:"state"`
+	Created     time.Time  `json:"created"`
+}
@@ -19,15 +19,16 @@ import "encoding/json"
 
 // WebSocketMessage is the message format to send/receive.
 type WebSocketMessage struct {
-	Version     *int                       `json:"version,omitempty"`
-	Page        string                     `json:"page"`
-	Type        string                     `json:"type"`
-	SubType     string                     `json:"sub_type,omitempty"`
-	Target      *string                    `json:"target,omitempty"`
-	Order       *[]string                  `json:"order,omitempty"`
-	ServiceData *ServiceSummary            `json:"service_data,omitempty"`
-	CommandData map[string]*CommandSummary `json:"command_data,omitempty"`
-	WebHookData map[string]*WebHookSummary `json:"webhook_data,omitempty"`
+	Version      *int                       `json:"version,omitempty"`
+	Page         string                     `json:"page"`
+	Type         string                     `json:"type"`
+	SubType      string                     `json:"sub_type,omitempty"`
+	Target       *string                    `json:"target,omitempty"`
+	Order        *[]string                  `json:"order,omitempty"`
+	ServiceData  *ServiceSummary            `json:"service_data,omitempty"`
+	CommandData  map[string]*CommandSummary `json:"command_data,omitempty"`
+	WebHookData  map[string]*WebHookSummary `json:"webhook_data,omitempty"`
+	PipelineData *PipelineSummary           `json:"pipeline_data,omitempty"`
 }
 
 // String returns a string representation of the WebSocketMessage.
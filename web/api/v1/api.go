@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	"github.com/gorilla/mux"
+	"github.com/release-argus/Argus/actionlink"
 	"github.com/release-argus/Argus/config"
 	"github.com/release-argus/Argus/util"
 	apitype "github.com/release-argus/Argus/web/api/types"
@@ -57,6 +58,14 @@ func NewAPI(cfg *config.Config, log *util.JLog) *API {
 			fmt.Fprintf(w, "Alive")
 		})))
 
+	// On baseRouter as the signature authorises the action (disable=action_links).
+	if disabledRoutes := cfg.Settings.Web.DisabledRoutes; !util.Contains(disabledRoutes, "action_links") &&
+		!util.Contains(disabledRoutes, "service_actions") {
+		baseRouter.Path(fmt.Sprintf("%s%s{token}", routePrefix, actionlink.Path)).
+			Methods(http.MethodGet, http.MethodPost).
+			HandlerFunc(api.httpActionLink)
+	}
+
 	api.Router = baseRouter.PathPrefix(routePrefix).Subrouter().StrictSlash(true)
 
 	baseRouter.Handle(routePrefix, http.RedirectHandler(routePrefix+"/", http.StatusPermanentRedirect))
@@ -20,11 +20,15 @@ import (
 	"github.com/release-argus/Argus/config"
 	"github.com/release-argus/Argus/notify/shoutrrr"
 	"github.com/release-argus/Argus/service"
+	"github.com/release-argus/Argus/service/approval"
 	deployedver "github.com/release-argus/Argus/service/deployed_version"
 	latestver "github.com/release-argus/Argus/service/latest_version"
 	"github.com/release-argus/Argus/service/latest_version/filter"
 	"github.com/release-argus/Argus/service/latest_version/types/github"
 	"github.com/release-argus/Argus/service/latest_version/types/web"
+	"github.com/release-argus/Argus/service/pipeline"
+	"github.com/release-argus/Argus/service/rollback"
+	"github.com/release-argus/Argus/service/window"
 	"github.com/release-argus/Argus/util"
 	apitype "github.com/release-argus/Argus/web/api/types"
 	"github.com/release-argus/Argus/webhook"
@@ -45,14 +49,27 @@ func convertAndCensorDefaults(input *config.Defaults) *apitype.Defaults {
 		Service: apitype.ServiceDefaults{
 			Options: &apitype.ServiceOptions{
 				Interval:           input.Service.Options.Interval,
+				Schedule:           input.Service.Options.Schedule,
+				Jitter:             input.Service.Options.Jitter,
 				SemanticVersioning: input.Service.Options.SemanticVersioning},
 			LatestVersion: &apitype.LatestVersionDefaults{
 				AccessToken:       util.ValueUnlessDefault(input.Service.LatestVersion.AccessToken, util.SecretValue),
 				AllowInvalidCerts: input.Service.LatestVersion.AllowInvalidCerts,
+				Timeout:           input.Service.LatestVersion.Timeout,
+				CAFile:            input.Service.LatestVersion.CAFile,
+				CertFile:          input.Service.LatestVersion.CertFile,
+				KeyFile:           input.Service.LatestVersion.KeyFile,
 				UsePreRelease:     input.Service.LatestVersion.UsePreRelease,
 				Require:           convertAndCensorLatestVersionRequireDefaults(&input.Service.LatestVersion.Require)},
 			DeployedVersionLookup: &apitype.DeployedVersionLookup{
-				AllowInvalidCerts: input.Service.DeployedVersionLookup.AllowInvalidCerts},
+				AllowInvalidCerts: input.Service.DeployedVersionLookup.AllowInvalidCerts,
+				Interval:          input.Service.DeployedVersionLookup.Interval,
+				Schedule:          input.Service.DeployedVersionLookup.Schedule,
+				Timeout:           input.Service.DeployedVersionLookup.Timeout,
+				CAFile:            input.Service.DeployedVersionLookup.CAFile,
+				CertFile:          input.Service.DeployedVersionLookup.CertFile,
+				KeyFile:           input.Service.DeployedVersionLookup.KeyFile},
+			ChangeWindow: convertChangeWindow(&input.Service.ChangeWindow),
 			Dashboard: &apitype.DashboardOptions{
 				AutoApprove: input.Service.Dashboard.AutoApprove}},
 		Notify:  *convertAndCensorNotifySliceDefaults(&input.Notify),
@@ -84,6 +101,8 @@ func convertAndCensorService(service *service.Service) *apitype.Service {
 	apiService.Options = &apitype.ServiceOptions{
 		Active:             service.Options.Active,
 		Interval:           service.Options.Interval,
+		Schedule:           service.Options.Schedule,
+		Jitter:             service.Options.Jitter,
 		SemanticVersioning: service.Options.SemanticVersioning}
 
 	// LatestVersion
@@ -94,8 +113,17 @@ func convertAndCensorService(service *service.Service) *apitype.Service {
 	apiService.Notify = convertAndCensorNotifySlice(&service.Notify)
 	// Command
 	apiService.Command = convertCommandSlice(&service.Command)
+	apiService.CommandOptions = convertAndCensorCommandOptionsSlice(service.CommandOptions)
 	// WebHook
 	apiService.WebHook = convertAndCensorWebHookSlice(&service.WebHook)
+	// Pipeline
+	apiService.Pipeline = convertPipeline(service.Pipeline)
+	// Rollback
+	apiService.Rollback = convertAndCensorRollback(service.Rollback)
+	// Approval
+	apiService.Approval = convertApprovalPolicy(service.Approval)
+	// ChangeWindow
+	apiService.ChangeWindow = convertChangeWindow(service.ChangeWindow)
 
 	apiService.Dashboard = &apitype.DashboardOptions{
 		AutoApprove: service.Dashboard.AutoApprove,
@@ -123,6 +151,10 @@ func convertAndCensorLatestVersion(lv latestver.Lookup) *apitype.LatestVersion {
 			URL:           v.URL,
 			AccessToken:   util.ValueUnlessDefault(v.AccessToken, util.SecretValue),
 			UsePreRelease: v.UsePreRelease,
+			Timeout:       v.Timeout,
+			CAFile:        v.CAFile,
+			CertFile:      v.CertFile,
+			KeyFile:       v.KeyFile,
 			URLCommands:   convertURLCommandSlice(&v.URLCommands),
 			Require:       convertAndCensorLatestVersionRequire(v.Require)}
 	case *web.Lookup:
@@ -130,6 +162,10 @@ func convertAndCensorLatestVersion(lv latestver.Lookup) *apitype.LatestVersion {
 			Type:              v.Type,
 			URL:               v.URL,
 			AllowInvalidCerts: v.AllowInvalidCerts,
+			Timeout:           v.Timeout,
+			CAFile:            v.CAFile,
+			CertFile:          v.CertFile,
+			KeyFile:           v.KeyFile,
 			URLCommands:       convertURLCommandSlice(&v.URLCommands),
 			Require:           convertAndCensorLatestVersionRequire(v.Require)}
 	default:
@@ -231,6 +267,12 @@ func convertAndCensorDeployedVersionLookup(dvl *deployedver.Lookup) *apitype.Dep
 		Method:            dvl.Method,
 		URL:               dvl.URL,
 		AllowInvalidCerts: dvl.AllowInvalidCerts,
+		Interval:          dvl.Interval,
+		Schedule:          dvl.Schedule,
+		Timeout:           dvl.Timeout,
+		CAFile:            dvl.CAFile,
+		CertFile:          dvl.CertFile,
+		KeyFile:           dvl.KeyFile,
 		Headers:           nil,
 		Body:              dvl.Body,
 		JSON:              dvl.JSON,
@@ -321,6 +363,45 @@ func convertCommandSlice(commands *command.Slice) *apitype.CommandSlice {
 	return &slice
 }
 
+// convertAndCensorCommandOptionsSlice converts OptionsSlice to API type, censoring the Env values.
+func convertAndCensorCommandOptionsSlice(input command.OptionsSlice) *apitype.CommandOptionsSlice {
+	if len(input) == 0 {
+		return nil
+	}
+
+	slice := make(apitype.CommandOptionsSlice, len(input))
+	for key, options := range input {
+		if options == nil {
+			continue
+		}
+		var env map[string]string
+		if len(options.Env) != 0 {
+			env = make(map[string]string, len(options.Env))
+			for name := range options.Env {
+				env[name] = util.SecretValue
+			}
+		}
+		slice[key] = &apitype.CommandOptions{
+			Timeout:       options.Timeout,
+			Env:           env,
+			Dir:           options.Dir,
+			Shell:         options.Shell,
+			CaptureOutput: options.CaptureOutput,
+			MaxOutput:     options.MaxOutput}
+		if container := options.Container; container != nil {
+			slice[key].Container = &apitype.CommandContainer{
+				Image:      container.Image,
+				Mounts:     container.Mounts,
+				Network:    container.Network,
+				Memory:     container.Memory,
+				CPUs:       container.CPUs,
+				AutoRemove: container.AutoRemove}
+		}
+	}
+
+	return &slice
+}
+
 //
 // WebHook
 //
@@ -339,6 +420,34 @@ func convertWebHookHeaders(headers *webhook.Headers) *[]apitype.Header {
 	return &apiHeaders
 }
 
+// convertWebHookResponseChecks converts ResponseChecks to the API type.
+func convertWebHookResponseChecks(checks *webhook.ResponseChecks) *[]apitype.WebHookResponseCheck {
+	if checks == nil {
+		return nil
+	}
+
+	apiChecks := make([]apitype.WebHookResponseCheck, len(*checks))
+	for index, check := range *checks {
+		apiChecks[index] = apitype.WebHookResponseCheck(check)
+	}
+
+	return &apiChecks
+}
+
+// convertWebHookResponseCaptures converts ResponseCaptures to the API type.
+func convertWebHookResponseCaptures(captures *webhook.ResponseCaptures) *[]apitype.WebHookResponseCapture {
+	if captures == nil {
+		return nil
+	}
+
+	apiCaptures := make([]apitype.WebHookResponseCapture, len(*captures))
+	for index, capture := range *captures {
+		apiCaptures[index] = apitype.WebHookResponseCapture(capture)
+	}
+
+	return &apiCaptures
+}
+
 // convertAndCensorWebHookSliceDefaults converts SliceDefaults to API Type, censoring any secrets.
 func convertAndCensorWebHookSliceDefaults(input *webhook.SliceDefaults) *apitype.WebHookSlice {
 	if input == nil {
@@ -364,12 +473,25 @@ func convertAndCensorWebHookDefaults(webhook *webhook.Defaults) *apitype.WebHook
 		Type:              webhook.Type,
 		URL:               webhook.URL,
 		AllowInvalidCerts: webhook.AllowInvalidCerts,
+		Timeout:           webhook.Timeout,
+		CAFile:            webhook.CAFile,
+		CertFile:          webhook.CertFile,
+		KeyFile:           webhook.KeyFile,
 		Secret:            util.ValueUnlessDefault(webhook.Secret, util.SecretValue),
 		CustomHeaders:     convertWebHookHeaders(webhook.CustomHeaders),
 		DesiredStatusCode: webhook.DesiredStatusCode,
 		Delay:             webhook.Delay,
 		MaxTries:          webhook.MaxTries,
-		SilentFails:       webhook.SilentFails}
+		SilentFails:       webhook.SilentFails,
+		Method:            webhook.Method,
+		QueryParams:       convertWebHookHeaders(webhook.QueryParams),
+		ContentType:       webhook.ContentType,
+		Body:              webhook.Body,
+		Signing:           webhook.Signing,
+		SigningHeader:     webhook.SigningHeader,
+		SigningFormat:     webhook.SigningFormat,
+		ResponseChecks:    convertWebHookResponseChecks(webhook.ResponseChecks),
+		ResponseCaptures:  convertWebHookResponseCaptures(webhook.ResponseCaptures)}
 	apiElement.Censor()
 
 	return apiElement
@@ -399,13 +521,122 @@ func convertAndCensorWebHook(webhook *webhook.WebHook) *apitype.WebHook {
 		Type:              webhook.Type,
 		URL:               webhook.URL,
 		AllowInvalidCerts: webhook.AllowInvalidCerts,
+		Timeout:           webhook.Timeout,
+		CAFile:            webhook.CAFile,
+		CertFile:          webhook.CertFile,
+		KeyFile:           webhook.KeyFile,
 		Secret:            util.ValueUnlessDefault(webhook.Secret, util.SecretValue),
 		CustomHeaders:     convertWebHookHeaders(webhook.CustomHeaders),
 		DesiredStatusCode: webhook.DesiredStatusCode,
 		Delay:             webhook.Delay,
 		MaxTries:          webhook.MaxTries,
-		SilentFails:       webhook.SilentFails}
+		SilentFails:       webhook.SilentFails,
+		Method:            webhook.Method,
+		QueryParams:       convertWebHookHeaders(webhook.QueryParams),
+		ContentType:       webhook.ContentType,
+		Body:              webhook.Body,
+		Signing:           webhook.Signing,
+		SigningHeader:     webhook.SigningHeader,
+		SigningFormat:     webhook.SigningFormat,
+		ResponseChecks:    convertWebHookResponseChecks(webhook.ResponseChecks),
+		ResponseCaptures:  convertWebHookResponseCaptures(webhook.ResponseCaptures)}
 	apiElement.Censor()
 
 	return apiElement
 }
+
+//
+// Pipeline
+//
+
+// convertPipeline converts a Pipeline to the API type.
+func convertPipeline(input *pipeline.Pipeline) *apitype.Pipeline {
+	if input == nil {
+		return nil
+	}
+
+	stages := make([]apitype.PipelineStage, 0, len(input.Stages))
+	for _, stage := range input.Stages {
+		if stage == nil {
+			continue
+		}
+		stages = append(stages, apitype.PipelineStage{
+			Name:      stage.Name,
+			Command:   stage.Command,
+			WebHook:   stage.WebHook,
+			Notify:    stage.Notify,
+			OnSuccess: stage.OnSuccess,
+			OnFailure: stage.OnFailure})
+	}
+
+	return &apitype.Pipeline{
+		Stages: stages}
+}
+
+//
+// Rollback
+//
+
+// convertAndCensorRollback converts a Rollback to the API type, censoring any secrets.
+func convertAndCensorRollback(input *rollback.Rollback) *apitype.Rollback {
+	if input == nil {
+		return nil
+	}
+
+	apiRollback := &apitype.Rollback{
+		Timeout:  input.Timeout,
+		Interval: input.Interval}
+	if len(input.Command) != 0 {
+		apiRollback.Command = convertCommandSlice(&input.Command)
+	}
+	if len(input.WebHook) != 0 {
+		apiRollback.WebHook = convertAndCensorWebHookSlice(&input.WebHook)
+	}
+
+	return apiRollback
+}
+
+//
+// Approval
+//
+
+// convertApprovalPolicy converts an approval.Policy to the API type.
+func convertApprovalPolicy(input *approval.Policy) *apitype.ApprovalPolicy {
+	if input == nil {
+		return nil
+	}
+
+	return &apitype.ApprovalPolicy{
+		Required:      input.Required,
+		Approvers:     input.Approvers,
+		Expiry:        input.Expiry,
+		RequireReason: input.RequireReason}
+}
+
+//
+// ChangeWindow
+//
+
+// convertChangeWindow converts a window.Schedule to the API type (nil if empty).
+func convertChangeWindow(input *window.Schedule) *apitype.ChangeWindow {
+	if input == nil || (len(input.Windows) == 0 && len(input.Freezes) == 0) {
+		return nil
+	}
+
+	apiChangeWindow := &apitype.ChangeWindow{}
+	for _, w := range input.Windows {
+		apiChangeWindow.Windows = append(apiChangeWindow.Windows, apitype.ChangeWindowWindow{
+			Days:     w.Days,
+			Start:    w.Start,
+			End:      w.End,
+			Timezone: w.Timezone})
+	}
+	for _, f := range input.Freezes {
+		apiChangeWindow.Freezes = append(apiChangeWindow.Freezes, apitype.ChangeWindowFreeze{
+			Start:  f.Start,
+			End:    f.End,
+			Reason: f.Reason})
+	}
+
+	return apiChangeWindow
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/release-argus/Argus/config"
 	"github.com/release-argus/Argus/notify/shoutrrr"
 	"github.com/release-argus/Argus/service"
+	"github.com/release-argus/Argus/service/approval"
 	deployedver "github.com/release-argus/Argus/service/deployed_version"
 	latestver "github.com/release-argus/Argus/service/latest_version"
 	"github.com/release-argus/Argus/service/latest_version/filter"
@@ -33,7 +34,9 @@ import (
 	"github.com/release-argus/Argus/service/latest_version/types/github"
 	"github.com/release-argus/Argus/service/latest_version/types/web"
 	opt "github.com/release-argus/Argus/service/option"
+	"github.com/release-argus/Argus/service/rollback"
 	"github.com/release-argus/Argus/service/status"
+	"github.com/release-argus/Argus/service/window"
 	"github.com/release-argus/Argus/test"
 	"github.com/release-argus/Argus/util"
 	apitype "github.com/release-argus/Argus/web/api/types"
@@ -1062,6 +1065,71 @@ func TestConvertCommandSlice(t *testing.T) {
 // WebHook
 //
 
+func TestConvertAndCensorCommandOptionsSlice(t *testing.T) {
+	// GIVEN a command.OptionsSlice
+	tests := map[string]struct {
+		input command.OptionsSlice
+		want  *apitype.CommandOptionsSlice
+	}{
+		"nil": {
+			input: nil,
+			want:  nil},
+		"censors env": {
+			input: command.OptionsSlice{
+				"./deploy.sh": {
+					Timeout: "5m",
+					Env: map[string]string{
+						"TOKEN": "shazam"},
+					Dir:           "/srv",
+					Shell:         true,
+					CaptureOutput: true,
+					MaxOutput:     1024}},
+			want: &apitype.CommandOptionsSlice{
+				"./deploy.sh": {
+					Timeout: "5m",
+					Env: map[string]string{
+						"TOKEN": util.SecretValue},
+					Dir:           "/srv",
+					Shell:         true,
+					CaptureOutput: true,
+					MaxOutput:     1024}}},
+		"container": {
+			input: command.OptionsSlice{
+				"./deploy.sh": {
+					Container: &command.Container{
+						Image:      "alpine:3.20",
+						Mounts:     []string{"/srv:/srv:ro"},
+						Network:    "none",
+						Memory:     "256m",
+						CPUs:       0.5,
+						AutoRemove: test.BoolPtr(false)}}},
+			want: &apitype.CommandOptionsSlice{
+				"./deploy.sh": {
+					Container: &apitype.CommandContainer{
+						Image:      "alpine:3.20",
+						Mounts:     []string{"/srv:/srv:ro"},
+						Network:    "none",
+						Memory:     "256m",
+						CPUs:       0.5,
+						AutoRemove: test.BoolPtr(false)}}}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN convertAndCensorCommandOptionsSlice is called
+			got := convertAndCensorCommandOptionsSlice(tc.input)
+
+			// THEN the result should be as expected
+			if util.ToJSONString(got) != util.ToJSONString(tc.want) {
+				t.Errorf("want\n%q\ngot\n%q",
+					util.ToJSONString(tc.want), util.ToJSONString(got))
+			}
+		})
+	}
+}
+
 func TestConvertAndCensorWebHookSliceDefaults(t *testing.T) {
 	// GIVEN a webhook.SliceDefaults
 	tests := map[string]struct {
@@ -1340,3 +1408,148 @@ func TestConvertWebHookHeaders(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertAndCensorRollback(t *testing.T) {
+	// GIVEN a Rollback
+	tests := map[string]struct {
+		input *rollback.Rollback
+		want  *apitype.Rollback
+	}{
+		"nil": {
+			input: nil,
+			want:  nil},
+		"timeout only": {
+			input: &rollback.Rollback{
+				Timeout: "10m"},
+			want: &apitype.Rollback{
+				Timeout: "10m"}},
+		"commands and censored webhooks": {
+			input: &rollback.Rollback{
+				Timeout:  "10m",
+				Interval: "30s",
+				Command: command.Slice{
+					{"./rollback.sh", "{{ rollback_version }}"}},
+				WebHook: webhook.Slice{
+					"rollback": webhook.New(
+						nil, nil, "", nil, nil, nil, nil, nil,
+						"shazam",
+						nil,
+						"github",
+						"https://example.com",
+						nil, nil, nil)}},
+			want: &apitype.Rollback{
+				Timeout:  "10m",
+				Interval: "30s",
+				Command: &apitype.CommandSlice{
+					{"./rollback.sh", "{{ rollback_version }}"}},
+				WebHook: &apitype.WebHookSlice{
+					"rollback": {
+						Type:   "github",
+						URL:    "https://example.com",
+						Secret: util.SecretValue}}}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN convertAndCensorRollback is called
+			got := convertAndCensorRollback(tc.input)
+
+			// THEN the result should be as expected
+			if util.ToJSONString(got) != util.ToJSONString(tc.want) {
+				t.Errorf("want\n%q\ngot\n%q",
+					util.ToJSONString(tc.want), util.ToJSONString(got))
+			}
+		})
+	}
+}
+
+func TestConvertApprovalPolicy(t *testing.T) {
+	// GIVEN an approval.Policy
+	tests := map[string]struct {
+		input *approval.Policy
+		want  *apitype.ApprovalPolicy
+	}{
+		"nil": {
+			input: nil,
+			want:  nil},
+		"full": {
+			input: &approval.Policy{
+				Required:      2,
+				Approvers:     []string{"alice", "bob"},
+				Expiry:        "24h",
+				RequireReason: true},
+			want: &apitype.ApprovalPolicy{
+				Required:      2,
+				Approvers:     []string{"alice", "bob"},
+				Expiry:        "24h",
+				RequireReason: true}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN convertApprovalPolicy is called
+			got := convertApprovalPolicy(tc.input)
+
+			// THEN the result should be as expected
+			if util.ToJSONString(got) != util.ToJSONString(tc.want) {
+				t.Errorf("want\n%q\ngot\n%q",
+					util.ToJSONString(tc.want), util.ToJSONString(got))
+			}
+		})
+	}
+}
+
+func TestConvertChangeWindow(t *testing.T) {
+	// GIVEN a window.Schedule
+	tests := map[string]struct {
+		input *window.Schedule
+		want  *apitype.ChangeWindow
+	}{
+		"nil": {
+			input: nil,
+			want:  nil},
+		"empty": {
+			input: &window.Schedule{},
+			want:  nil},
+		"full": {
+			input: &window.Schedule{
+				Windows: []window.Window{{
+					Days:     []string{"tue-thu"},
+					Start:    "10:00",
+					End:      "16:00",
+					Timezone: "Europe/London"}},
+				Freezes: []window.Freeze{{
+					Start:  "2025-12-20",
+					End:    "2026-01-02",
+					Reason: "holidays"}}},
+			want: &apitype.ChangeWindow{
+				Windows: []apitype.ChangeWindowWindow{{
+					Days:     []string{"tue-thu"},
+					Start:    "10:00",
+					End:      "16:00",
+					Timezone: "Europe/London"}},
+				Freezes: []apitype.ChangeWindowFreeze{{
+					Start:  "2025-12-20",
+					End:    "2026-01-02",
+					Reason: "holidays"}}}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN convertChangeWindow is called
+			got := convertChangeWindow(tc.input)
+
+			// THEN the result should be as expected
+			if util.ToJSONString(got) != util.ToJSONString(tc.want) {
+				t.Errorf("want\n%q\ngot\n%q",
+					util.ToJSONString(tc.want), util.ToJSONString(got))
+			}
+		})
+	}
+}
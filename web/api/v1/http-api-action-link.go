@@ -0,0 +1,170 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v1 provides the API for the webserver.
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/release-argus/Argus/actionlink"
+	"github.com/release-argus/Argus/util"
+)
+
+// actionLinkPage is the page shown for an action link.
+var actionLinkPage = template.Must(template.New("action-link").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<meta name="robots" content="noindex">
+	<title>Argus - {{ .Title }}</title>
+</head>
+<body>
+	<h1>{{ .Title }}</h1>
+	<p>{{ .Message }}</p>
+	{{- if .Confirm }}
+	<form method="POST">
+		<button type="submit">{{ .Confirm }}</button>
+	</form>
+	{{- end }}
+</body>
+</html>
+`))
+
+// actionLinkPageData is the data to fill the actionLinkPage with.
+type actionLinkPageData struct {
+	Title   string // Heading of the page.
+	Message string // Details of the action/result.
+	Confirm string // Label of the button to run the action (empty = no form).
+}
+
+// writeActionLinkPage writes the actionLinkPage with `data` and the `statusCode`.
+func writeActionLinkPage(w http.ResponseWriter, statusCode int, data actionLinkPageData, logFrom util.LogFrom) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.WriteHeader(statusCode)
+	if err := actionLinkPage.Execute(w, data); err != nil {
+		jLog.Error(err, logFrom, true)
+	}
+}
+
+// httpActionLink handles the signed action links of notifications (without basic auth).
+//
+// GET shows a page to confirm the action (so link previews don't run it),
+// and POST runs it as httpServiceRunActions would (once per link).
+//
+// A link has no identity to approve as, so only skip links can be used
+// for a Service with an approval policy.
+//
+// Required parameters:
+//
+//	token: The signed action link.
+func (api *API) httpActionLink(w http.ResponseWriter, r *http.Request) {
+	logFrom := util.LogFrom{Primary: "httpActionLink", Secondary: getIP(r)}
+
+	token := mux.Vars(r)["token"]
+	link, err := actionlink.Parse(token, time.Now())
+	if err != nil {
+		jLog.Warn(err, logFrom, true)
+		statusCode := http.StatusForbidden
+		if errors.Is(err, actionlink.ErrExpired) {
+			statusCode = http.StatusGone
+		}
+		writeActionLinkPage(w, statusCode, actionLinkPageData{
+			Title:   "Invalid link",
+			Message: err.Error()}, logFrom)
+		return
+	}
+
+	api.Config.OrderMutex.RLock()
+	defer api.Config.OrderMutex.RUnlock()
+	svc := api.Config.Service[link.ServiceID]
+	if svc == nil {
+		writeActionLinkPage(w, http.StatusNotFound, actionLinkPageData{
+			Title:   "Service not found",
+			Message: fmt.Sprintf("service %q not found", link.ServiceID)}, logFrom)
+		return
+	}
+	// Only act on the release the link was made for.
+	if latestVersion := svc.Status.LatestVersion(); latestVersion != link.Version {
+		writeActionLinkPage(w, http.StatusGone, actionLinkPageData{
+			Title: "Link outdated",
+			Message: fmt.Sprintf("%q is no longer the latest version of %q (now %q)",
+				link.Version, svc.Name, latestVersion)}, logFrom)
+		return
+	}
+
+	var target, action string
+	switch link.Action {
+	case actionlink.ActionApprove:
+		target = "ARGUS_ALL"
+		action = fmt.Sprintf("Approve %q of %q", link.Version, svc.Name)
+	case actionlink.ActionSkip:
+		target = "ARGUS_SKIP"
+		action = fmt.Sprintf("Skip %q of %q", link.Version, svc.Name)
+	case actionlink.ActionWebHook:
+		target = "webhook_" + link.Target
+		action = fmt.Sprintf("Send the %q WebHook for %q of %q", link.Target, link.Version, svc.Name)
+	}
+
+	// Approvals must be made by a named approver.
+	if svc.Approval != nil && link.Action != actionlink.ActionSkip {
+		writeActionLinkPage(w, http.StatusForbidden, actionLinkPageData{
+			Title:   action,
+			Message: fmt.Sprintf("%q needs approval by an allowed approver, so this link can't be used", svc.Name)}, logFrom)
+		return
+	}
+
+	// Confirm.
+	if r.Method != http.MethodPost {
+		writeActionLinkPage(w, http.StatusOK, actionLinkPageData{
+			Title:   action,
+			Message: "Confirm to continue.",
+			Confirm: action}, logFrom)
+		return
+	}
+
+	// Run (once).
+	if err := actionlink.Use(token, link, time.Now()); err != nil {
+		jLog.Warn(err, logFrom, true)
+		writeActionLinkPage(w, http.StatusGone, actionLinkPageData{
+			Title:   action,
+			Message: err.Error()}, logFrom)
+		return
+	}
+	msg, actionErr := api.runActions(svc, target, "", "", false, logFrom)
+	if actionErr != nil {
+		// Failed, so the link can be tried again.
+		actionlink.Release(token)
+		writeActionLinkPage(w, actionErr.statusCode, actionLinkPageData{
+			Title:   action,
+			Message: actionErr.Error()}, logFrom)
+		return
+	}
+	if msg == "" {
+		msg = "Nothing to action."
+	}
+
+	writeActionLinkPage(w, http.StatusOK, actionLinkPageData{
+		Title:   action,
+		Message: msg}, logFrom)
+}
@@ -0,0 +1,210 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package v1
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/release-argus/Argus/actionlink"
+	"github.com/release-argus/Argus/service/approval"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+	"github.com/release-argus/Argus/webhook"
+	webhook_test "github.com/release-argus/Argus/webhook/test"
+)
+
+func TestHTTP_httpActionLink(t *testing.T) {
+	// GIVEN an API and a request for an action link of a Service.
+	file := "TestHTTP_httpActionLink.yml"
+	api := testAPI(file)
+	t.Cleanup(func() {
+		os.RemoveAll(file)
+		if api.Config.Settings.Data.DatabaseFile != "" {
+			os.RemoveAll(api.Config.Settings.Data.DatabaseFile)
+		}
+	})
+	actionlink.SetKey("TestHTTP_httpActionLink")
+	tests := map[string]struct {
+		method      string
+		token       func(serviceID string) string
+		form        url.Values
+		approval    *approval.Policy
+		statusCode  int
+		bodyRegex   string
+		wantSkipped bool
+	}{
+		"invalid token": {
+			method:     http.MethodGet,
+			token:      func(string) string { return "foo.bar" },
+			statusCode: http.StatusForbidden,
+			bodyRegex:  `invalid action link`},
+		"expired token": {
+			method: http.MethodGet,
+			token: func(serviceID string) string {
+				return actionlink.Link{
+					ServiceID: serviceID, Version: "3.0.0", Action: actionlink.ActionSkip,
+					Expires: time.Now().Add(-time.Minute).Unix()}.Token()
+			},
+			statusCode: http.StatusGone,
+			bodyRegex:  `action link expired`},
+		"unknown service": {
+			method: http.MethodGet,
+			token: func(string) string {
+				return actionlink.New("unknown?", "3.0.0", actionlink.ActionSkip, "").Token()
+			},
+			statusCode: http.StatusNotFound,
+			bodyRegex:  `service &#34;unknown\?&#34; not found`},
+		"outdated version": {
+			method: http.MethodPost,
+			token: func(serviceID string) string {
+				return actionlink.New(serviceID, "2.9.0", actionlink.ActionSkip, "").Token()
+			},
+			statusCode: http.StatusGone,
+			bodyRegex:  `&#34;2.9.0&#34; is no longer the latest version`},
+		"GET asks for confirmation": {
+			method: http.MethodGet,
+			token: func(serviceID string) string {
+				return actionlink.New(serviceID, "3.0.0", actionlink.ActionSkip, "").Token()
+			},
+			statusCode: http.StatusOK,
+			bodyRegex:  `<form method="POST">\s*<button type="submit">Skip`},
+		"GET approve with an approval policy": {
+			method: http.MethodGet,
+			token: func(serviceID string) string {
+				return actionlink.New(serviceID, "3.0.0", actionlink.ActionApprove, "").Token()
+			},
+			approval:   &approval.Policy{Required: 2, Approvers: []string{"alice", "bob"}},
+			statusCode: http.StatusForbidden,
+			bodyRegex:  `needs approval by an allowed approver`},
+		"POST skip": {
+			method: http.MethodPost,
+			token: func(serviceID string) string {
+				return actionlink.New(serviceID, "3.0.0", actionlink.ActionSkip, "").Token()
+			},
+			statusCode:  http.StatusOK,
+			bodyRegex:   `release skip - &#34;3.0.0&#34;`,
+			wantSkipped: true},
+		"POST approve with an approval policy, naming an approver": {
+			method: http.MethodPost,
+			token: func(serviceID string) string {
+				return actionlink.New(serviceID, "3.0.0", actionlink.ActionApprove, "").Token()
+			},
+			form:       url.Values{"approver": {"alice"}},
+			approval:   &approval.Policy{Required: 2, Approvers: []string{"alice", "bob"}},
+			statusCode: http.StatusForbidden,
+			bodyRegex:  `needs approval by an allowed approver`},
+		"POST webhook with an approval policy": {
+			method: http.MethodPost,
+			token: func(serviceID string) string {
+				return actionlink.New(serviceID, "3.0.0", actionlink.ActionWebHook, "hook").Token()
+			},
+			approval:   &approval.Policy{Required: 1, Approvers: []string{"alice"}},
+			statusCode: http.StatusForbidden,
+			bodyRegex:  `needs approval by an allowed approver`},
+		"POST skip with an approval policy": {
+			method: http.MethodPost,
+			token: func(serviceID string) string {
+				return actionlink.New(serviceID, "3.0.0", actionlink.ActionSkip, "").Token()
+			},
+			approval:    &approval.Policy{Required: 2, Approvers: []string{"alice", "bob"}},
+			statusCode:  http.StatusOK,
+			bodyRegex:   `release skip - &#34;3.0.0&#34;`,
+			wantSkipped: true},
+		"POST skip, already used": {
+			method: http.MethodPost,
+			token: func(serviceID string) string {
+				link := actionlink.New(serviceID, "3.0.0", actionlink.ActionSkip, "")
+				token := link.Token()
+				actionlink.Use(token, link, time.Now())
+				return token
+			},
+			statusCode: http.StatusGone,
+			bodyRegex:  `action link already used`},
+	}
+	cfg := api.Config
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// t.Parallel() - Cannot run in parallel since we're using stdout
+			releaseStdout := test.CaptureStdout()
+			t.Cleanup(func() { releaseStdout() })
+
+			serviceID := name
+			svc := testService(name, true)
+			svc.Defaults = &cfg.Defaults.Service
+			svc.HardDefaults = &cfg.HardDefaults.Service
+			svc.WebHook = webhook.Slice{
+				"hook": webhook_test.WebHook(false, false, false)}
+			svc.Status.Init(
+				len(svc.Notify), 0, len(svc.WebHook),
+				&serviceID, nil,
+				test.StringPtr("https://example.com"))
+			svc.Status.SetAnnounceChannel(cfg.HardDefaults.Service.Status.AnnounceChannel)
+			svc.Status.SetApprovedVersion("2.0.0", false)
+			svc.Status.SetDeployedVersion("2.0.0", "", false)
+			svc.Status.SetLatestVersion("3.0.0", "", true)
+			svc.WebHook.Init(
+				&svc.Status,
+				&webhook.SliceDefaults{}, &webhook.Defaults{}, &webhook.Defaults{},
+				&svc.Notify,
+				&svc.Options.Interval)
+			svc.Approval = tc.approval
+			svc.Approval.Init(&svc.Status)
+			cfg.OrderMutex.Lock()
+			cfg.Service[name] = svc
+			cfg.Order = append(cfg.Order, name)
+			cfg.OrderMutex.Unlock()
+			t.Cleanup(func() { cfg.DeleteService(name) })
+			token := tc.token(serviceID)
+
+			// WHEN that HTTP request is sent.
+			req := httptest.NewRequest(tc.method, actionlink.Path+token,
+				strings.NewReader(tc.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req = mux.SetURLVars(req, map[string]string{
+				"token": token})
+			wHTTP := httptest.NewRecorder()
+			api.httpActionLink(wHTTP, req)
+			res := wHTTP.Result()
+			t.Cleanup(func() { res.Body.Close() })
+
+			// THEN we get the expected response.
+			body, _ := io.ReadAll(res.Body)
+			if res.StatusCode != tc.statusCode {
+				t.Errorf("expected status code %d but got %d\n%s",
+					tc.statusCode, res.StatusCode, body)
+			}
+			if !util.RegexCheck(tc.bodyRegex, string(body)) {
+				t.Errorf("match on %q not found in\n%q",
+					tc.bodyRegex, body)
+			}
+			// AND the release is only skipped on a POST of a skip link.
+			if got := svc.Status.ApprovedVersion(); (got == "SKIP_3.0.0") != tc.wantSkipped {
+				t.Errorf("want skipped: %t, got ApprovedVersion %q",
+					tc.wantSkipped, got)
+			}
+		})
+	}
+}
@@ -21,8 +21,10 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/release-argus/Argus/service"
 	"github.com/release-argus/Argus/util"
 	apitype "github.com/release-argus/Argus/web/api/types"
 )
@@ -52,9 +54,16 @@ func (api *API) httpServiceGetActions(w http.ResponseWriter, r *http.Request) {
 	if svc.CommandController != nil {
 		for i, cmd := range *svc.CommandController.Command {
 			command := cmd.ApplyTemplate(&svc.Status)
-			commandSummary[command.String()] = apitype.CommandSummary{
+			summary := apitype.CommandSummary{
 				Failed:       svc.Status.Fails.Command.Get(i),
 				NextRunnable: svc.CommandController.NextRunnable(i)}
+			if output := svc.CommandController.Output(i); output != nil {
+				summary.Output = &apitype.CommandOutput{
+					Stdout:    output.Stdout,
+					Stderr:    output.Stderr,
+					Truncated: output.Truncated}
+			}
+			commandSummary[command.String()] = summary
 		}
 	}
 	// WevHooks
@@ -63,19 +72,25 @@ func (api *API) httpServiceGetActions(w http.ResponseWriter, r *http.Request) {
 		webhookSummary[key] = apitype.WebHookSummary{
 			Failed:       svc.Status.Fails.WebHook.Get(key),
 			NextRunnable: wh.NextRunnable(),
+			Outputs:      svc.Status.WebHookOutputs(key),
 		}
 	}
 
 	msg := apitype.ActionSummary{
-		Command: commandSummary,
-		WebHook: webhookSummary}
+		Command:  commandSummary,
+		WebHook:  webhookSummary,
+		Pipeline: svc.Pipeline.Summary(),
+		Approval: svc.Approval.Summary(),
+		Queued:   svc.QueuedActions()}
 
 	api.writeJSON(w, msg, logFrom)
 }
 
 // RunActionsPayload holds the target actions to run for a Service.
 type RunActionsPayload struct {
-	Target string `json:"target"`
+	Target   string `json:"target"`
+	Reason   string `json:"reason,omitempty"`   // Reason for the approval.
+	Override bool   `json:"override,omitempty"` // Run all the actions outside the change window.
 }
 
 // httpServiceRunActions handles approvals/rejections of the latest version of a service.
@@ -86,9 +101,21 @@ type RunActionsPayload struct {
 //	target: The action to take. One of:
 //		"ARGUS_ALL": Approve all actions.
 //		"ARGUS_FAILED": Approve all failed actions.
+//		(With a pipeline, both re-run the pipeline from its first stage.)
 //		"ARGUS_SKIP": Skip this release.
+//		"ARGUS_CANCEL_QUEUED": Cancel the actions waiting for the change window.
 //		"webhook_<webhook_id>": Approve a specific WebHook.
 //		"command_<command_id>": Approve a specific Command.
+//
+// Optional parameters:
+//
+//	reason: Reason for the approval.
+//	override: Run "ARGUS_ALL"/"ARGUS_FAILED" now, even outside the change window.
+//
+// With an approval policy, the actions only run once enough approvals are given,
+// with the web.approver_header (set by a trusted auth proxy), or else the basic auth username,
+// as the identity of the approver.
+// "ARGUS_ALL"/"ARGUS_FAILED" are refused outside the change window unless overridden.
 func (api *API) httpServiceRunActions(w http.ResponseWriter, r *http.Request) {
 	logFrom := util.LogFrom{Primary: "httpServiceRunActions", Secondary: getIP(r)}
 	// Service to run actions of.
@@ -106,7 +133,7 @@ func (api *API) httpServiceRunActions(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get target from the payload.
-	payloadBytes := http.MaxBytesReader(w, r.Body, 512)
+	payloadBytes := http.MaxBytesReader(w, r.Body, 2048)
 	var payload RunActionsPayload
 	err := json.NewDecoder(payloadBytes).Decode(&payload)
 	if err != nil {
@@ -122,53 +149,156 @@ func (api *API) httpServiceRunActions(w http.ResponseWriter, r *http.Request) {
 		failRequest(&w, errMsg, http.StatusBadRequest)
 		return
 	}
+	approver := api.approver(r)
+	msg, actionErr := api.runActions(svc, payload.Target, approver, payload.Reason, payload.Override, logFrom)
+	if actionErr != nil {
+		failRequest(&w, actionErr.Error(), actionErr.statusCode)
+		return
+	}
+	if msg == "" {
+		return
+	}
+
+	api.writeJSON(w, apitype.Response{
+		Message: msg,
+	}, logFrom)
+}
+
+// actionError is an error running the actions of a Service, with the HTTP status to fail with.
+type actionError struct {
+	message    string
+	statusCode int
+}
+
+// Error returns the message of the actionError.
+func (e *actionError) Error() string {
+	return e.message
+}
+
+// approver returns the identity of the user making the request `r`,
+// from the web.approver_header, or else the basic auth username.
+func (api *API) approver(r *http.Request) string {
+	if header := api.Config.Settings.WebApproverHeader(); header != "" {
+		return r.Header.Get(header)
+	}
+	approver, _, _ := r.BasicAuth()
+	return approver
+}
+
+// runActions approves/skips the latest version of `svc`, running the actions of `target`
+// (see httpServiceRunActions) once any approval policy is satisfied.
+//
+// Returns the message to respond with (empty if there's nothing to report).
+func (api *API) runActions(
+	svc *service.Service,
+	target, approver, reason string,
+	override bool,
+	logFrom util.LogFrom,
+) (string, *actionError) {
 	if !svc.Options.GetActive() {
 		errMsg := "service is inactive, actions can't be run for it"
 		jLog.Error(errMsg, logFrom, true)
-		failRequest(&w, errMsg, http.StatusBadRequest)
-		return
+		return "", &actionError{message: errMsg, statusCode: http.StatusBadRequest}
+	}
+
+	// CANCEL the actions waiting for the change window.
+	if target == "ARGUS_CANCEL_QUEUED" {
+		if !svc.CancelQueuedActions() {
+			errMsg := fmt.Sprintf("%q has no actions queued", svc.ID)
+			jLog.Error(errMsg, logFrom, true)
+			return "", &actionError{message: errMsg, statusCode: http.StatusNotFound}
+		}
+		msg := fmt.Sprintf("%q queued actions cancelled - %q",
+			svc.ID, svc.Status.LatestVersion())
+		jLog.Info(msg, logFrom, true)
+		return msg, nil
 	}
 
 	// SKIP this release.
-	if payload.Target == "ARGUS_SKIP" {
+	if target == "ARGUS_SKIP" {
 		msg := fmt.Sprintf("%q release skip - %q",
-			targetService, svc.Status.LatestVersion())
+			svc.ID, svc.Status.LatestVersion())
 		jLog.Info(msg, logFrom, true)
+		// Attribute the skip in the version history.
+		svc.Status.SetApprovedBy(approver)
 		svc.HandleSkip()
-		return
+		return msg, nil
 	}
 
-	if svc.WebHook == nil && svc.Command == nil {
+	if svc.WebHook == nil && svc.Command == nil && svc.Pipeline == nil {
 		jLog.Error(
-			fmt.Sprintf("%q does not have any commands/webhooks to approve", targetService),
+			fmt.Sprintf("%q does not have any commands/webhooks to approve", svc.ID),
 			logFrom, true)
-		return
+		return "", nil
+	}
+
+	// Outside the change window, only run all the actions with an override.
+	if target == "ARGUS_ALL" || target == "ARGUS_FAILED" {
+		if open, opensAt := svc.ChangeWindowOpen(); !open {
+			opens := "never"
+			if !opensAt.IsZero() {
+				opens = opensAt.Format(time.RFC3339)
+			}
+			if !override {
+				errMsg := fmt.Sprintf("%q is outside its change window (opens %s), override to run the actions now",
+					svc.ID, opens)
+				jLog.Error(errMsg, logFrom, true)
+				return "", &actionError{message: errMsg, statusCode: http.StatusConflict}
+			}
+			jLog.Warn(
+				fmt.Sprintf("%q actions overridden by %q to run outside the change window (opens %s)",
+					svc.ID, approver, opens),
+				logFrom, true)
+		}
+	}
+
+	// Record the approval, and wait for the policy to be satisfied.
+	if svc.Approval != nil {
+		satisfied, err := svc.Approval.Approve(approver, reason)
+		if err != nil {
+			jLog.Error(
+				fmt.Sprintf("%q approval rejected - %s", svc.ID, err),
+				logFrom, true)
+			return "", &actionError{message: err.Error(), statusCode: http.StatusForbidden}
+		}
+		if !satisfied {
+			msg := fmt.Sprintf("%s %q approved by %q (%d/%d approvals)",
+				svc.ID,
+				svc.Status.LatestVersion(),
+				approver,
+				len(svc.Approval.Approvals()), svc.Approval.GetRequired())
+			jLog.Info(msg, logFrom, true)
+			return msg, nil
+		}
 	}
 
+	// Attribute the approval in the version history.
+	svc.Status.SetApprovedBy(approver)
+
 	// Send the WebHook(s).
 	msg := fmt.Sprintf("%s %q Release actioned - %q",
-		targetService,
+		svc.ID,
 		svc.Status.LatestVersion(),
 		strings.ReplaceAll(
 			strings.ReplaceAll(
-				strings.ReplaceAll(payload.Target,
+				strings.ReplaceAll(target,
 					"ARGUS_ALL", "ALL"),
 				"ARGUS_FAILED", "ALL UNSENT/FAILED"),
 			"ARGUS_SKIP", "SKIP"),
 	)
 	jLog.Info(msg, logFrom, true)
-	switch payload.Target {
+	switch target {
 	case "ARGUS_ALL", "ARGUS_FAILED":
+		// Run now rather than in the change window.
+		svc.CancelQueuedActions()
 		go svc.HandleFailedActions()
 	default:
-		if strings.HasPrefix(payload.Target, "webhook_") {
-			go svc.HandleWebHook(strings.TrimPrefix(payload.Target, "webhook_"))
+		if strings.HasPrefix(target, "webhook_") {
+			go svc.HandleWebHook(strings.TrimPrefix(target, "webhook_"))
 		} else {
-			go svc.HandleCommand(strings.TrimPrefix(payload.Target, "command_"))
+			go svc.HandleCommand(strings.TrimPrefix(target, "command_"))
 		}
 	}
 
-	api.writeJSON(w, apitype.Response{
-		Message: msg,
-	}, logFrom)
+	return msg, nil
 }
@@ -31,6 +31,8 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/release-argus/Argus/command"
+	"github.com/release-argus/Argus/service/approval"
+	"github.com/release-argus/Argus/service/window"
 	"github.com/release-argus/Argus/test"
 	"github.com/release-argus/Argus/util"
 	apitype "github.com/release-argus/Argus/web/api/types"
@@ -660,3 +662,219 @@ func TestHTTP_httpServiceRunActions(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTP_httpServiceRunActions_Approver(t *testing.T) {
+	// GIVEN an API and a Service with an approval policy needing 2 approvers.
+	file := "TestHTTP_httpServiceRunActions_Approver.yml"
+	api := testAPI(file)
+	t.Cleanup(func() {
+		os.RemoveAll(file)
+		if api.Config.Settings.Data.DatabaseFile != "" {
+			os.RemoveAll(api.Config.Settings.Data.DatabaseFile)
+		}
+	})
+	releaseStdout := test.CaptureStdout()
+	t.Cleanup(func() { releaseStdout() })
+	cfg := api.Config
+	serviceID := "TestHTTP_httpServiceRunActions_Approver"
+	svc := testService(serviceID, true)
+	svc.Defaults = &cfg.Defaults.Service
+	svc.HardDefaults = &cfg.HardDefaults.Service
+	svc.WebHook = webhook.Slice{
+		"hook": webhook_test.WebHook(false, false, false)}
+	svc.Status.Init(
+		len(svc.Notify), 0, len(svc.WebHook),
+		&serviceID, nil,
+		test.StringPtr("https://example.com"))
+	svc.Status.SetLatestVersion("3.0.0", "", false)
+	svc.WebHook.Init(
+		&svc.Status,
+		&webhook.SliceDefaults{}, &webhook.Defaults{}, &webhook.Defaults{},
+		&svc.Notify,
+		&svc.Options.Interval)
+	svc.Approval = &approval.Policy{Required: 2, Approvers: []string{"alice", "bob"}}
+	svc.Approval.Init(&svc.Status)
+	cfg.OrderMutex.Lock()
+	cfg.Service[serviceID] = svc
+	cfg.Order = append(cfg.Order, serviceID)
+	cfg.OrderMutex.Unlock()
+	t.Cleanup(func() { cfg.DeleteService(serviceID) })
+
+	// WHEN the same basic auth user approves twice, naming a different approver in the body each time.
+	for _, bodyApprover := range []string{"alice", "bob"} {
+		payload := fmt.Sprintf(`{"target":"ARGUS_ALL","approver":%q}`, bodyApprover)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/service/actions/"+url.QueryEscape(serviceID),
+			strings.NewReader(payload))
+		req.SetBasicAuth("alice", "")
+		req = mux.SetURLVars(req, map[string]string{
+			"service_id": serviceID})
+		wHTTP := httptest.NewRecorder()
+		api.httpServiceRunActions(wHTTP, req)
+		if res := wHTTP.Result(); res.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(res.Body)
+			t.Fatalf("approval as %q: want status %d, got %d\n%s",
+				bodyApprover, http.StatusOK, res.StatusCode, body)
+		}
+	}
+
+	// THEN only the one approval of the authenticated user counts.
+	approvals := svc.Approval.Approvals()
+	if len(approvals) != 1 || approvals[0].Approver != "alice" {
+		t.Errorf("want the single approval of %q, got %+v",
+			"alice", approvals)
+	}
+}
+
+func TestHTTP_httpServiceRunActions_ApproverHeader(t *testing.T) {
+	// GIVEN an API identifying approvers by a header, and a Service with an approval policy needing 2 approvers.
+	file := "TestHTTP_httpServiceRunActions_ApproverHeader.yml"
+	api := testAPI(file)
+	t.Cleanup(func() {
+		os.RemoveAll(file)
+		if api.Config.Settings.Data.DatabaseFile != "" {
+			os.RemoveAll(api.Config.Settings.Data.DatabaseFile)
+		}
+	})
+	releaseStdout := test.CaptureStdout()
+	t.Cleanup(func() { releaseStdout() })
+	cfg := api.Config
+	cfg.Settings.Web.ApproverHeader = "X-Forwarded-User"
+	serviceID := "TestHTTP_httpServiceRunActions_ApproverHeader"
+	svc := testService(serviceID, true)
+	svc.Defaults = &cfg.Defaults.Service
+	svc.HardDefaults = &cfg.HardDefaults.Service
+	svc.WebHook = webhook.Slice{
+		"hook": webhook_test.WebHook(false, false, false)}
+	svc.Status.Init(
+		len(svc.Notify), 0, len(svc.WebHook),
+		&serviceID, nil,
+		test.StringPtr("https://example.com"))
+	svc.Status.SetLatestVersion("3.0.0", "", false)
+	svc.WebHook.Init(
+		&svc.Status,
+		&webhook.SliceDefaults{}, &webhook.Defaults{}, &webhook.Defaults{},
+		&svc.Notify,
+		&svc.Options.Interval)
+	svc.Approval = &approval.Policy{Required: 2, Approvers: []string{"alice", "bob"}}
+	svc.Approval.Init(&svc.Status)
+	cfg.OrderMutex.Lock()
+	cfg.Service[serviceID] = svc
+	cfg.Order = append(cfg.Order, serviceID)
+	cfg.OrderMutex.Unlock()
+	t.Cleanup(func() { cfg.DeleteService(serviceID) })
+
+	// WHEN the same basic auth user approves as each approver in the header.
+	for _, headerApprover := range []string{"alice", "bob"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/service/actions/"+url.QueryEscape(serviceID),
+			strings.NewReader(`{"target":"ARGUS_ALL"}`))
+		req.SetBasicAuth("alice", "")
+		req.Header.Set("X-Forwarded-User", headerApprover)
+		req = mux.SetURLVars(req, map[string]string{
+			"service_id": serviceID})
+		wHTTP := httptest.NewRecorder()
+		api.httpServiceRunActions(wHTTP, req)
+		if res := wHTTP.Result(); res.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(res.Body)
+			t.Fatalf("approval as %q: want status %d, got %d\n%s",
+				headerApprover, http.StatusOK, res.StatusCode, body)
+		}
+	}
+
+	// THEN both approvals count, with the identities from the header.
+	approvals := svc.Approval.Approvals()
+	if len(approvals) != 2 || approvals[0].Approver != "alice" || approvals[1].Approver != "bob" {
+		t.Errorf("want the approvals of %q and %q, got %+v",
+			"alice", "bob", approvals)
+	}
+}
+
+func TestHTTP_httpServiceRunActions_ChangeWindow(t *testing.T) {
+	// GIVEN an API and a Service in a change freeze.
+	tests := map[string]struct {
+		target     string
+		override   bool
+		wantStatus int
+		wantBody   string
+	}{
+		"ARGUS_ALL refused": {
+			target:     "ARGUS_ALL",
+			wantStatus: http.StatusConflict,
+			wantBody:   `outside its change window \(opens [^)]+\), override to run the actions now`},
+		"ARGUS_FAILED refused": {
+			target:     "ARGUS_FAILED",
+			wantStatus: http.StatusConflict,
+			wantBody:   `outside its change window`},
+		"ARGUS_ALL overridden": {
+			target:     "ARGUS_ALL",
+			override:   true,
+			wantStatus: http.StatusOK,
+			wantBody:   `Release actioned - \\"ALL\\"`},
+		"ARGUS_SKIP not refused": {
+			target:     "ARGUS_SKIP",
+			wantStatus: http.StatusOK,
+			wantBody:   `release skip`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			file := fmt.Sprintf("TestHTTP_httpServiceRunActions_ChangeWindow-%s.yml",
+				strings.ReplaceAll(name, " ", "_"))
+			api := testAPI(file)
+			t.Cleanup(func() {
+				os.RemoveAll(file)
+				if api.Config.Settings.Data.DatabaseFile != "" {
+					os.RemoveAll(api.Config.Settings.Data.DatabaseFile)
+				}
+			})
+			releaseStdout := test.CaptureStdout()
+			t.Cleanup(func() { releaseStdout() })
+			cfg := api.Config
+			serviceID := "TestHTTP_httpServiceRunActions_ChangeWindow"
+			svc := testService(serviceID, true)
+			svc.Defaults = &cfg.Defaults.Service
+			svc.HardDefaults = &cfg.HardDefaults.Service
+			svc.WebHook = webhook.Slice{
+				"hook": webhook_test.WebHook(false, false, false)}
+			svc.Status.Init(
+				len(svc.Notify), 0, len(svc.WebHook),
+				&serviceID, nil,
+				test.StringPtr("https://example.com"))
+			svc.Status.SetLatestVersion("3.0.0", "", false)
+			svc.WebHook.Init(
+				&svc.Status,
+				&webhook.SliceDefaults{}, &webhook.Defaults{}, &webhook.Defaults{},
+				&svc.Notify,
+				&svc.Options.Interval)
+			svc.ChangeWindow = &window.Schedule{
+				Freezes: []window.Freeze{{
+					Start: time.Now().Add(-time.Hour).Format(time.RFC3339),
+					End:   time.Now().Add(time.Hour).Format(time.RFC3339)}}}
+			cfg.OrderMutex.Lock()
+			cfg.Service[serviceID] = svc
+			cfg.Order = append(cfg.Order, serviceID)
+			cfg.OrderMutex.Unlock()
+			t.Cleanup(func() { cfg.DeleteService(serviceID) })
+
+			// WHEN the actions are run.
+			payload := fmt.Sprintf(`{"target":%q,"override":%t}`, tc.target, tc.override)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/service/actions/"+url.QueryEscape(serviceID),
+				strings.NewReader(payload))
+			req = mux.SetURLVars(req, map[string]string{
+				"service_id": serviceID})
+			wHTTP := httptest.NewRecorder()
+			api.httpServiceRunActions(wHTTP, req)
+
+			// THEN only an override runs them outside the change window.
+			res := wHTTP.Result()
+			body, _ := io.ReadAll(res.Body)
+			if res.StatusCode != tc.wantStatus {
+				t.Errorf("want status %d, got %d\n%s",
+					tc.wantStatus, res.StatusCode, body)
+			}
+			if !util.RegexCheck(tc.wantBody, string(body)) {
+				t.Errorf("want body to match %q\ngot: %q",
+					tc.wantBody, body)
+			}
+		})
+	}
+}
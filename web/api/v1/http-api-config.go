@@ -38,7 +38,8 @@ func (api *API) httpConfig(w http.ResponseWriter, r *http.Request) {
 			ListenPort:  api.Config.Settings.Web.ListenPort,
 			CertFile:    api.Config.Settings.Web.CertFile,
 			KeyFile:     api.Config.Settings.Web.KeyFile,
-			RoutePrefix: api.Config.Settings.Web.RoutePrefix}}
+			RoutePrefix: api.Config.Settings.Web.RoutePrefix,
+			PublicURL:   api.Config.Settings.Web.PublicURL}}
 
 	// Defaults
 	serviceLatestVersionRequireDefaults := convertAndCensorLatestVersionRequireDefaults(&api.Config.Defaults.Service.LatestVersion.Require)
@@ -70,14 +71,26 @@ func (api *API) httpConfig(w http.ResponseWriter, r *http.Request) {
 		Service: apitype.ServiceDefaults{
 			Options: &apitype.ServiceOptions{
 				Interval:           api.Config.Defaults.Service.Options.Interval,
+				Schedule:           api.Config.Defaults.Service.Options.Schedule,
+				Jitter:             api.Config.Defaults.Service.Options.Jitter,
 				SemanticVersioning: api.Config.Defaults.Service.Options.SemanticVersioning},
 			DeployedVersionLookup: &apitype.DeployedVersionLookup{
-				AllowInvalidCerts: api.Config.Defaults.Service.DeployedVersionLookup.AllowInvalidCerts},
+				AllowInvalidCerts: api.Config.Defaults.Service.DeployedVersionLookup.AllowInvalidCerts,
+				Interval:          api.Config.Defaults.Service.DeployedVersionLookup.Interval,
+				Schedule:          api.Config.Defaults.Service.DeployedVersionLookup.Schedule,
+				Timeout:           api.Config.Defaults.Service.DeployedVersionLookup.Timeout,
+				CAFile:            api.Config.Defaults.Service.DeployedVersionLookup.CAFile,
+				CertFile:          api.Config.Defaults.Service.DeployedVersionLookup.CertFile,
+				KeyFile:           api.Config.Defaults.Service.DeployedVersionLookup.KeyFile},
 			Dashboard: &apitype.DashboardOptions{
 				AutoApprove: api.Config.Defaults.Service.Dashboard.AutoApprove},
 			LatestVersion: &apitype.LatestVersionDefaults{
 				AccessToken:       util.ValueUnlessDefault(api.Config.Defaults.Service.LatestVersion.AccessToken, util.SecretValue),
 				AllowInvalidCerts: api.Config.Defaults.Service.LatestVersion.AllowInvalidCerts,
+				Timeout:           api.Config.Defaults.Service.LatestVersion.Timeout,
+				CAFile:            api.Config.Defaults.Service.LatestVersion.CAFile,
+				CertFile:          api.Config.Defaults.Service.LatestVersion.CertFile,
+				KeyFile:           api.Config.Defaults.Service.LatestVersion.KeyFile,
 				UsePreRelease:     api.Config.Defaults.Service.LatestVersion.UsePreRelease,
 				Require:           serviceLatestVersionRequireDefaults},
 			Notify:  serviceNotifyDefaults,
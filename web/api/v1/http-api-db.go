@@ -0,0 +1,141 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v1 provides the API for the webserver.
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/release-argus/Argus/db"
+	"github.com/release-argus/Argus/util"
+	apitype "github.com/release-argus/Argus/web/api/types"
+)
+
+// maxImportBytes is the largest JSON export accepted by httpDatabaseImport.
+const maxImportBytes = 256 << 20
+
+// httpDatabaseBackup serves a consistent backup of the database.
+//
+// # GET
+//
+// Response:
+//
+//	On success: HTTP 200 OK with the SQLite database file as an attachment.
+//	On error: HTTP 500 Internal Server Error.
+func (api *API) httpDatabaseBackup(w http.ResponseWriter, r *http.Request) {
+	logFrom := util.LogFrom{Primary: "httpDatabaseBackup", Secondary: getIP(r)}
+
+	dir, err := os.MkdirTemp("", "argus-backup-")
+	if err != nil {
+		jLog.Error(err, logFrom, true)
+		failRequest(&w, "backup failed", http.StatusInternalServerError)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "argus.db")
+	if err := db.Backup(path); err != nil {
+		jLog.Error(
+			fmt.Sprintf("backup failed - %s", err),
+			logFrom, true)
+		failRequest(&w,
+			fmt.Sprintf("backup failed, %s", err),
+			http.StatusInternalServerError)
+		return
+	}
+	//#nosec G304 -- Path is of the temp dir.
+	file, err := os.Open(path)
+	if err != nil {
+		jLog.Error(err, logFrom, true)
+		failRequest(&w, "backup failed", http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	now := time.Now().UTC()
+	w.Header().Set("Content-Type", "application/vnd.sqlite3")
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf("attachment; filename=%q",
+			"argus-"+now.Format("20060102T150405Z")+".db"))
+	http.ServeContent(w, r, "", now, file)
+}
+
+// httpDatabaseExport serves the status, history and approvals in the database as JSON.
+//
+// # GET
+//
+// Response:
+//
+//	On success: HTTP 200 OK with the rows of each table, to import with httpDatabaseImport.
+//	On error: HTTP 500 Internal Server Error.
+func (api *API) httpDatabaseExport(w http.ResponseWriter, r *http.Request) {
+	logFrom := util.LogFrom{Primary: "httpDatabaseExport", Secondary: getIP(r)}
+
+	var buf bytes.Buffer
+	if err := db.Export(&buf); err != nil {
+		jLog.Error(
+			fmt.Sprintf("export failed - %s", err),
+			logFrom, true)
+		failRequest(&w,
+			fmt.Sprintf("export failed, %s", err),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf("attachment; filename=%q",
+			"argus-"+time.Now().UTC().Format("20060102T150405Z")+".json"))
+	//#nosec G104 -- Disregard.
+	//nolint:errcheck // ^
+	buf.WriteTo(w)
+}
+
+// httpDatabaseImport replaces the tables of the database with those of a JSON export,
+// and restores the status/approvals, pending digests and outbox of the Services from them.
+//
+// # POST
+//
+// Request body: A JSON export from httpDatabaseExport.
+//
+// Response:
+//
+//	On success: HTTP 200 OK with the number of rows imported.
+//	On error: HTTP 400 Bad Request if the export can't be imported.
+func (api *API) httpDatabaseImport(w http.ResponseWriter, r *http.Request) {
+	logFrom := util.LogFrom{Primary: "httpDatabaseImport", Secondary: getIP(r)}
+
+	count, err := db.Import(http.MaxBytesReader(w, r.Body, maxImportBytes))
+	if err != nil {
+		jLog.Error(
+			fmt.Sprintf("import failed - %s", err),
+			logFrom, true)
+		failRequest(&w,
+			fmt.Sprintf("import failed, %s", err),
+			http.StatusBadRequest)
+		return
+	}
+
+	msg := fmt.Sprintf("imported %d rows", count)
+	jLog.Info(msg, logFrom, true)
+	api.writeJSON(w, apitype.Response{
+		Message: msg},
+		logFrom)
+}
@@ -0,0 +1,75 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTP_httpDatabase_NotRunning(t *testing.T) {
+	// GIVEN the database isn't running.
+	api := &API{}
+	tests := map[string]struct {
+		handler        func(w http.ResponseWriter, r *http.Request)
+		method         string
+		wantStatusCode int
+		wantBody       string
+	}{
+		"backup": {
+			handler:        api.httpDatabaseBackup,
+			method:         http.MethodGet,
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       `{"message":"backup failed, database not running"}`},
+		"export": {
+			handler:        api.httpDatabaseExport,
+			method:         http.MethodGet,
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       `{"message":"export failed, database not running"}`},
+		"import": {
+			handler:        api.httpDatabaseImport,
+			method:         http.MethodPost,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"message":"import failed, database not running"}`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tc.method, "/api/v1/db/"+name, strings.NewReader("{}"))
+			w := httptest.NewRecorder()
+
+			// WHEN the handler is called.
+			tc.handler(w, req)
+
+			// THEN the request fails.
+			res := w.Result()
+			defer res.Body.Close()
+			if res.StatusCode != tc.wantStatusCode {
+				t.Errorf("want status code %d, got %d",
+					tc.wantStatusCode, res.StatusCode)
+			}
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("want body %q, got %q",
+					tc.wantBody, got)
+			}
+		})
+	}
+}
@@ -114,6 +114,7 @@ func (api *API) httpLatestVersionRefreshUncreated(w http.ResponseWriter, r *http
 
 	// Query the latest version lookup.
 	version, _, err := latestver.Refresh(
+		r.Context(),
 		lv,
 		nil, nil)
 	if err != nil {
@@ -191,6 +192,7 @@ func (api *API) httpDeployedVersionRefreshUncreated(w http.ResponseWriter, r *ht
 
 	// Query the DeployedVersionLookup.
 	version, err := dvl.Refresh(
+		r.Context(),
 		&logFrom.Primary,
 		nil, nil)
 	if err != nil {
@@ -247,6 +249,7 @@ func (api *API) httpLatestVersionRefresh(w http.ResponseWriter, r *http.Request)
 
 	// Query the LatestVersion lookup.
 	version, announce, err := latestver.Refresh(
+		r.Context(),
 		api.Config.Service[targetService].LatestVersion,
 		overrides,
 		semanticVersioning)
@@ -336,6 +339,7 @@ func (api *API) httpDeployedVersionRefresh(w http.ResponseWriter, r *http.Reques
 
 	// Query the DeployedVersionLookup.
 	version, err := dvl.Refresh(
+		r.Context(),
 		&targetService,
 		overrides,
 		semanticVersioning)
@@ -509,7 +513,7 @@ func (api *API) httpServiceEdit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Ensure LatestVersion and DeployedVersion (if set) can fetch.
-	if err := newService.CheckFetches(); err != nil {
+	if err := newService.CheckFetches(r.Context()); err != nil {
 		jLog.Error(err, logFrom, true)
 
 		failRequest(&w,
@@ -654,7 +658,7 @@ func (api *API) httpNotifyTest(w http.ResponseWriter, r *http.Request) {
 	testNotify.ServiceStatus.SetLatestVersion(latestVersion, "", false)
 
 	// Send the message.
-	err = testNotify.TestSend(serviceURL)
+	err = testNotify.TestSend(r.Context(), serviceURL)
 	if err != nil {
 		jLog.Error(err, logFrom, true)
 		failRequest(&w, err.Error(), http.StatusBadRequest)
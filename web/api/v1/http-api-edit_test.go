@@ -18,6 +18,7 @@ package v1
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -266,8 +267,8 @@ func TestHTTP_DeployedVersionRefreshUncreated(t *testing.T) {
 func TestHTTP_LatestVersionRefresh(t *testing.T) {
 	testSVC := testService("TestHTTP_LatestVersionRefresh", false)
 	testSVC.LatestVersion.GetStatus().SetLatestVersion("1.0.0", "", false)
-	testSVC.LatestVersion.Query(true, util.LogFrom{})
-	v, _ := testSVC.DeployedVersionLookup.Query(true, util.LogFrom{})
+	testSVC.LatestVersion.Query(context.Background(), true, util.LogFrom{})
+	v, _ := testSVC.DeployedVersionLookup.Query(context.Background(), true, util.LogFrom{})
 	testSVC.Status.SetDeployedVersion(v, "", false)
 	type wants struct {
 		body                           string
@@ -448,8 +449,8 @@ func TestHTTP_LatestVersionRefresh(t *testing.T) {
 func TestHTTP_DeployedVersionRefresh(t *testing.T) {
 	testSVC := testService("TestHTTP_DeployedVersionRefresh", false)
 	testSVC.LatestVersion.GetStatus().SetLatestVersion("1.0.0", "", false)
-	testSVC.LatestVersion.Query(true, util.LogFrom{})
-	v, _ := testSVC.DeployedVersionLookup.Query(true, util.LogFrom{})
+	testSVC.LatestVersion.Query(context.Background(), true, util.LogFrom{})
+	v, _ := testSVC.DeployedVersionLookup.Query(context.Background(), true, util.LogFrom{})
 	testSVC.Status.SetDeployedVersion(v, "", false)
 	type wants struct {
 		body                           string
@@ -806,8 +807,8 @@ func TestHTTP_OtherServiceDetails(t *testing.T) {
 func TestHTTP_ServiceEdit(t *testing.T) {
 	testSVC := testService("TestHTTP_ServiceEdit", true)
 	testSVC.LatestVersion.GetStatus().SetLatestVersion("1.0.0", "", false)
-	testSVC.LatestVersion.Query(true, util.LogFrom{})
-	v, _ := testSVC.DeployedVersionLookup.Query(true, util.LogFrom{})
+	testSVC.LatestVersion.Query(context.Background(), true, util.LogFrom{})
+	v, _ := testSVC.DeployedVersionLookup.Query(context.Background(), true, util.LogFrom{})
 	testSVC.Status.SetDeployedVersion(v, "", false)
 	type wants struct {
 		body                           string
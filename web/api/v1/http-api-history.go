@@ -0,0 +1,169 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v1 provides the API for the webserver.
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/release-argus/Argus/history"
+	"github.com/release-argus/Argus/util"
+	apitype "github.com/release-argus/Argus/web/api/types"
+)
+
+// httpHistory lists the notify/webhook/command attempts, newest first.
+//
+// # GET
+//
+// Query Parameters:
+//
+//	service_id: Only attempts of this Service.
+//	kind: Only attempts of this kind (notify/webhook/command).
+//	target: Only attempts of this Notify/WebHook ID/Command index.
+//	success: Only successful (true) or failed (false) attempts.
+//	since: Only attempts at/after this RFC3339 time.
+//	until: Only attempts before this RFC3339 time.
+//	limit: Maximum attempts to return (default: 50, max: 500).
+//	offset: Attempts to skip.
+//
+// Response:
+//
+//	On success: HTTP 200 OK with the page of attempts and the total matching.
+//	On error: HTTP 400 Bad Request for invalid parameters,
+//	  or HTTP 503 Service Unavailable if there's no database.
+func (api *API) httpHistory(w http.ResponseWriter, r *http.Request) {
+	logFrom := util.LogFrom{Primary: "httpHistory", Secondary: getIP(r)}
+
+	filter, err := parseHistoryFilter(r.URL.Query())
+	if err != nil {
+		failRequest(&w,
+			err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	records, total, err := history.Query(filter)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, history.ErrUnavailable) {
+			statusCode = http.StatusServiceUnavailable
+		}
+		failRequest(&w,
+			fmt.Sprintf("history query failed, %s",
+				err),
+			statusCode)
+		return
+	}
+
+	response := apitype.History{
+		Records: make([]apitype.HistoryRecord, len(records)),
+		Total:   total,
+		Limit:   filter.Limit,
+		Offset:  filter.Offset}
+	if response.Limit <= 0 {
+		response.Limit = history.DefaultLimit
+	} else if response.Limit > history.MaxLimit {
+		response.Limit = history.MaxLimit
+	}
+	for i := range records {
+		response.Records[i] = convertHistoryRecord(&records[i])
+	}
+
+	api.writeJSON(w, response, logFrom)
+}
+
+// parseHistoryFilter returns the history.Filter of the `query` parameters.
+func parseHistoryFilter(query url.Values) (history.Filter, error) {
+	filter := history.Filter{
+		ServiceID: query.Get("service_id"),
+		Kind:      query.Get("kind"),
+		Target:    query.Get("target")}
+
+	switch filter.Kind {
+	case "", history.KindNotify, history.KindWebHook, history.KindCommand:
+	default:
+		return filter, fmt.Errorf("kind: %q <invalid> (expected one of notify/webhook/command)",
+			filter.Kind)
+	}
+
+	if success := query.Get("success"); success != "" {
+		value, err := strconv.ParseBool(success)
+		if err != nil {
+			return filter, fmt.Errorf("success: %q <invalid> (expected true/false)",
+				success)
+		}
+		filter.Success = &value
+	}
+
+	for _, param := range []struct {
+		name   string
+		target *time.Time
+	}{
+		{"since", &filter.Since},
+		{"until", &filter.Until},
+	} {
+		value := query.Get(param.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("%s: %q <invalid> (expected an RFC3339 time)",
+				param.name, value)
+		}
+		*param.target = parsed
+	}
+
+	for _, param := range []struct {
+		name   string
+		target *int
+	}{
+		{"limit", &filter.Limit},
+		{"offset", &filter.Offset},
+	} {
+		value := query.Get(param.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return filter, fmt.Errorf("%s: %q <invalid> (expected a non-negative integer)",
+				param.name, value)
+		}
+		*param.target = parsed
+	}
+
+	return filter, nil
+}
+
+// convertHistoryRecord converts a history.Record to an apitype.HistoryRecord.
+func convertHistoryRecord(record *history.Record) apitype.HistoryRecord {
+	return apitype.HistoryRecord{
+		ID:             record.ID,
+		Time:           record.Time,
+		Kind:           record.Kind,
+		ServiceID:      record.ServiceID,
+		Target:         record.Target,
+		Version:        record.Version,
+		Payload:        record.Payload,
+		ResponseStatus: record.ResponseStatus,
+		DurationMS:     record.Duration.Milliseconds(),
+		Error:          record.Error}
+}
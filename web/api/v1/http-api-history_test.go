@@ -0,0 +1,120 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//go:build unit
+
+package v1
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/release-argus/Argus/history"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestParseHistoryFilter(t *testing.T) {
+	// GIVEN query parameters for the history.
+	tests := map[string]struct {
+		query    string
+		want     history.Filter
+		success  *bool
+		errRegex string
+	}{
+		"none": {
+			query:    "",
+			want:     history.Filter{},
+			errRegex: `^$`},
+		"all": {
+			query: "service_id=svc&kind=webhook&target=wh&success=false" +
+				"&since=2025-01-01T00:00:00Z&until=2025-01-02T00:00:00%2B01:00&limit=10&offset=20",
+			want: history.Filter{
+				ServiceID: "svc", Kind: history.KindWebHook, Target: "wh",
+				Since:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				Until:  time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC),
+				Limit:  10,
+				Offset: 20},
+			success:  new(bool),
+			errRegex: `^$`},
+		"invalid kind": {
+			query:    "kind=email",
+			errRegex: `^kind: "email" <invalid>`},
+		"invalid success": {
+			query:    "success=maybe",
+			errRegex: `^success: "maybe" <invalid>`},
+		"invalid since": {
+			query:    "since=yesterday",
+			errRegex: `^since: "yesterday" <invalid>`},
+		"invalid limit": {
+			query:    "limit=ten",
+			errRegex: `^limit: "ten" <invalid>`},
+		"negative offset": {
+			query:    "offset=-1",
+			errRegex: `^offset: "-1" <invalid>`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			query, _ := url.ParseQuery(tc.query)
+
+			// WHEN parseHistoryFilter is called.
+			got, err := parseHistoryFilter(query)
+
+			// THEN the error is as expected.
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Fatalf("want error matching %q\ngot: %q",
+					tc.errRegex, e)
+			}
+			if err != nil {
+				return
+			}
+			// AND the filter is as expected.
+			if (got.Success == nil) != (tc.success == nil) ||
+				(got.Success != nil && *got.Success != *tc.success) {
+				t.Errorf("success\nwant: %v\ngot:  %v",
+					tc.success, got.Success)
+			}
+			got.Success = nil
+			if got.ServiceID != tc.want.ServiceID || got.Kind != tc.want.Kind || got.Target != tc.want.Target ||
+				!got.Since.Equal(tc.want.Since) || !got.Until.Equal(tc.want.Until) ||
+				got.Limit != tc.want.Limit || got.Offset != tc.want.Offset {
+				t.Errorf("want: %+v\ngot:  %+v",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestConvertHistoryRecord(t *testing.T) {
+	// GIVEN a history.Record.
+	record := history.Record{
+		ID: "id", Time: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Kind: history.KindCommand, ServiceID: "svc", Target: "0", Version: "1.2.3",
+		Payload: "ls -lah", ResponseStatus: 1, Duration: 1500 * time.Millisecond, Error: "exit status 1"}
+
+	// WHEN convertHistoryRecord is called.
+	got := convertHistoryRecord(&record)
+
+	// THEN the fields are converted.
+	if got.ID != record.ID || !got.Time.Equal(record.Time) || got.Kind != record.Kind ||
+		got.ServiceID != record.ServiceID || got.Target != record.Target || got.Version != record.Version ||
+		got.Payload != record.Payload || got.ResponseStatus != record.ResponseStatus ||
+		got.DurationMS != 1500 || got.Error != record.Error {
+		t.Errorf("unexpected conversion of %+v\ngot: %+v",
+			record, got)
+	}
+}
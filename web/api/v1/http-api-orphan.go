@@ -0,0 +1,123 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v1 provides the API for the webserver.
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	"github.com/release-argus/Argus/db"
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/util"
+	apitype "github.com/release-argus/Argus/web/api/types"
+)
+
+// httpOrphans lists the archived status of the Services no longer in the config.
+//
+// # GET
+//
+// Response:
+//
+//	On success: HTTP 200 OK with a list of the orphans, by ID.
+//	On error: HTTP 500 Internal Server Error.
+func (api *API) httpOrphans(w http.ResponseWriter, r *http.Request) {
+	logFrom := util.LogFrom{Primary: "httpOrphans", Secondary: getIP(r)}
+
+	orphans, err := db.Orphans()
+	if err != nil {
+		jLog.Error(err, logFrom, true)
+		failRequest(&w,
+			fmt.Sprintf("listing orphans failed, %s", err),
+			http.StatusInternalServerError)
+		return
+	}
+
+	list := make([]apitype.Orphan, len(orphans))
+	for i := range orphans {
+		list[i] = convertOrphan(&orphans[i])
+	}
+	api.writeJSON(w, list, logFrom)
+}
+
+// httpOrphanRestore restores the archived status (and version history/approvals) of an orphan
+// to a Service in the config, replacing the status that Service has.
+//
+// # POST
+//
+// Path Parameters:
+//
+//	id: The ID of the orphan to restore.
+//
+// Request body (optional):
+//
+//	service_id: The Service to restore to (default = id), renaming the orphan to it.
+//
+// Response:
+//
+//	On success: HTTP 200 OK.
+//	On error: HTTP 404 Not Found if the orphan/Service isn't found.
+func (api *API) httpOrphanRestore(w http.ResponseWriter, r *http.Request) {
+	logFrom := util.LogFrom{Primary: "httpOrphanRestore", Secondary: getIP(r)}
+	id, _ := url.QueryUnescape(mux.Vars(r)["id"])
+
+	var payload apitype.RestoreOrphan
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 2048)).Decode(&payload)
+	if err != nil && !errors.Is(err, io.EOF) {
+		jLog.Error(
+			fmt.Sprintf("Invalid payload - %s", err),
+			logFrom, true)
+		failRequest(&w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	serviceID := payload.ServiceID
+	if serviceID == "" {
+		serviceID = id
+	}
+
+	if err := db.RestoreOrphan(id, serviceID); err != nil {
+		jLog.Error(err, logFrom, true)
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, db.ErrOrphanNotFound) || errors.Is(err, db.ErrServiceNotFound) {
+			statusCode = http.StatusNotFound
+		}
+		failRequest(&w,
+			fmt.Sprintf("restore %q failed, %s",
+				id, err),
+			statusCode)
+		return
+	}
+
+	api.writeJSON(w, apitype.Response{
+		Message: fmt.Sprintf("restored %q to %q", id, serviceID)},
+		logFrom)
+}
+
+// convertOrphan converts a dbtype.Orphan to an apitype.Orphan.
+func convertOrphan(orphan *dbtype.Orphan) apitype.Orphan {
+	return apitype.Orphan{
+		ID:                       orphan.ID,
+		LatestVersion:            orphan.LatestVersion,
+		LatestVersionTimestamp:   orphan.LatestVersionTimestamp,
+		DeployedVersion:          orphan.DeployedVersion,
+		DeployedVersionTimestamp: orphan.DeployedVersionTimestamp,
+		ApprovedVersion:          orphan.ApprovedVersion,
+		Archived:                 orphan.Archived}
+}
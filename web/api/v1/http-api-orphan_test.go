@@ -0,0 +1,99 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestConvertOrphan(t *testing.T) {
+	// GIVEN a dbtype.Orphan.
+	orphan := dbtype.Orphan{
+		ID:                     "svc",
+		LatestVersion:          "1.2.3",
+		LatestVersionTimestamp: "2025-01-01T00:00:00Z",
+		ApprovedVersion:        "1.2.3",
+		Archived:               time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)}
+
+	// WHEN convertOrphan is called.
+	got := convertOrphan(&orphan)
+
+	// THEN the orphan is converted.
+	want := `{"id":"svc",` +
+		`"latest_version":"1.2.3","latest_version_timestamp":"2025-01-01T00:00:00Z",` +
+		`"approved_version":"1.2.3",` +
+		`"archived":"2025-01-02T00:00:00Z"}`
+	if gotStr := util.ToJSONString(got); gotStr != want {
+		t.Errorf("want\n%s\ngot\n%s",
+			want, gotStr)
+	}
+}
+
+func TestHTTP_httpOrphanRestore(t *testing.T) {
+	// GIVEN the database isn't running, and a restore payload.
+	api := &API{}
+	tests := map[string]struct {
+		body           string
+		wantStatusCode int
+		wantBody       string
+	}{
+		"no payload": {
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       `{"message":"restore \"svc\" failed, database not running"}`},
+		"service_id": {
+			body:           `{"service_id":"other"}`,
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       `{"message":"restore \"svc\" failed, database not running"}`},
+		"invalid payload": {
+			body:           `{`,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"message":"invalid payload"}`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/orphans/svc/restore", strings.NewReader(tc.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "svc"})
+			w := httptest.NewRecorder()
+
+			// WHEN httpOrphanRestore is called.
+			api.httpOrphanRestore(w, req)
+
+			// THEN the response is as expected.
+			res := w.Result()
+			defer res.Body.Close()
+			if res.StatusCode != tc.wantStatusCode {
+				t.Errorf("want status code %d, got %d",
+					tc.wantStatusCode, res.StatusCode)
+			}
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("want body %q, got %q",
+					tc.wantBody, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v1 provides the API for the webserver.
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/release-argus/Argus/outbox"
+	"github.com/release-argus/Argus/util"
+	apitype "github.com/release-argus/Argus/web/api/types"
+)
+
+// httpOutbox lists the deliveries in the outbox.
+//
+// # GET
+//
+// Response:
+//
+//	On success: HTTP 200 OK with a list of the deliveries, oldest first.
+func (api *API) httpOutbox(w http.ResponseWriter, r *http.Request) {
+	logFrom := util.LogFrom{Primary: "httpOutbox", Secondary: getIP(r)}
+
+	entries := outbox.List()
+	list := make([]apitype.OutboxEntry, len(entries))
+	for i := range entries {
+		list[i] = convertOutboxEntry(&entries[i])
+	}
+
+	api.writeJSON(w, list, logFrom)
+}
+
+// httpOutboxRetry retries a delivery in the outbox now (reviving it if dead).
+//
+// # POST
+//
+// Path Parameters:
+//
+//	id: The ID of the delivery to retry.
+//
+// Response:
+//
+//	On success: HTTP 200 OK (the retry runs in the background).
+//	On error: HTTP 404 Not Found, or HTTP 409 Conflict if it is being delivered.
+func (api *API) httpOutboxRetry(w http.ResponseWriter, r *http.Request) {
+	logFrom := util.LogFrom{Primary: "httpOutboxRetry", Secondary: getIP(r)}
+	id := mux.Vars(r)["id"]
+
+	if err := outbox.Retry(id); err != nil {
+		failRequest(&w,
+			fmt.Sprintf("retry %q failed, %s",
+				id, err),
+			outboxErrorStatusCode(err))
+		return
+	}
+
+	api.writeJSON(w, apitype.Response{
+		Message: fmt.Sprintf("retrying %q", id)},
+		logFrom)
+}
+
+// httpOutboxDiscard removes a delivery from the outbox.
+//
+// # DELETE
+//
+// Path Parameters:
+//
+//	id: The ID of the delivery to discard.
+//
+// Response:
+//
+//	On success: HTTP 200 OK.
+//	On error: HTTP 404 Not Found.
+func (api *API) httpOutboxDiscard(w http.ResponseWriter, r *http.Request) {
+	logFrom := util.LogFrom{Primary: "httpOutboxDiscard", Secondary: getIP(r)}
+	id := mux.Vars(r)["id"]
+
+	if err := outbox.Discard(id); err != nil {
+		failRequest(&w,
+			fmt.Sprintf("discard %q failed, %s",
+				id, err),
+			outboxErrorStatusCode(err))
+		return
+	}
+
+	api.writeJSON(w, apitype.Response{
+		Message: fmt.Sprintf("discarded %q", id)},
+		logFrom)
+}
+
+// outboxErrorStatusCode returns the HTTP status code for an outbox error.
+func outboxErrorStatusCode(err error) int {
+	if errors.Is(err, outbox.ErrDelivering) {
+		return http.StatusConflict
+	}
+	return http.StatusNotFound
+}
+
+// convertOutboxEntry converts an outbox.Entry to an apitype.OutboxEntry.
+func convertOutboxEntry(entry *outbox.Entry) apitype.OutboxEntry {
+	converted := apitype.OutboxEntry{
+		ID:        entry.ID,
+		Kind:      entry.Kind,
+		ServiceID: entry.ServiceID,
+		Target:    entry.Target,
+		Title:     entry.Title,
+		Version:   entry.ServiceInfo.LatestVersion,
+		Attempts:  entry.Attempts,
+		LastError: entry.LastError,
+		State:     entry.State,
+		Created:   entry.Created}
+	if !entry.NextAttempt.IsZero() {
+		nextAttempt := entry.NextAttempt
+		converted.NextAttempt = &nextAttempt
+	}
+	return converted
+}
@@ -0,0 +1,104 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package v1
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/release-argus/Argus/outbox"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestConvertOutboxEntry(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	nextAttempt := created.Add(time.Minute)
+	// GIVEN an outbox.Entry.
+	tests := map[string]struct {
+		entry           outbox.Entry
+		wantNextAttempt *time.Time
+	}{
+		"pending": {
+			entry: outbox.Entry{
+				ID: "id", Kind: outbox.KindNotify, ServiceID: "svc", Target: "notify",
+				Title:       "title",
+				ServiceInfo: util.ServiceInfo{LatestVersion: "1.2.3"},
+				Attempts:    2, NextAttempt: nextAttempt, LastError: "err",
+				State: outbox.StatePending, Created: created},
+			wantNextAttempt: &nextAttempt},
+		"dead": {
+			entry: outbox.Entry{
+				ID: "id", Kind: outbox.KindNotify, ServiceID: "svc", Target: "notify",
+				Title:       "title",
+				ServiceInfo: util.ServiceInfo{LatestVersion: "1.2.3"},
+				Attempts:    2, LastError: "err",
+				State: outbox.StateDead, Created: created},
+			wantNextAttempt: nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN convertOutboxEntry is called.
+			got := convertOutboxEntry(&tc.entry)
+
+			// THEN the fields are converted.
+			if got.ID != tc.entry.ID || got.Kind != tc.entry.Kind || got.ServiceID != tc.entry.ServiceID ||
+				got.Target != tc.entry.Target || got.Title != tc.entry.Title ||
+				got.Version != tc.entry.ServiceInfo.LatestVersion ||
+				got.Attempts != tc.entry.Attempts || got.LastError != tc.entry.LastError ||
+				got.State != tc.entry.State || !got.Created.Equal(tc.entry.Created) {
+				t.Errorf("unexpected conversion of %+v\ngot: %+v",
+					tc.entry, got)
+			}
+			// AND the NextAttempt is only set when scheduled.
+			if (got.NextAttempt == nil) != (tc.wantNextAttempt == nil) ||
+				(got.NextAttempt != nil && !got.NextAttempt.Equal(*tc.wantNextAttempt)) {
+				t.Errorf("want NextAttempt %v, got %v",
+					tc.wantNextAttempt, got.NextAttempt)
+			}
+		})
+	}
+}
+
+func TestOutboxErrorStatusCode(t *testing.T) {
+	// GIVEN errors from the outbox.
+	tests := map[string]struct {
+		err  error
+		want int
+	}{
+		"not found":  {err: outbox.ErrNotFound, want: http.StatusNotFound},
+		"delivering": {err: outbox.ErrDelivering, want: http.StatusConflict},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN outboxErrorStatusCode is called.
+			got := outboxErrorStatusCode(tc.err)
+
+			// THEN the status code is as expected.
+			if got != tc.want {
+				t.Errorf("want: %d\ngot:  %d",
+					tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v1 provides the API for the webserver.
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	"github.com/release-argus/Argus/history"
+	"github.com/release-argus/Argus/util"
+	apitype "github.com/release-argus/Argus/web/api/types"
+)
+
+// httpServiceTimeline returns the version timeline of a Service.
+//
+// # GET
+//
+// Path Parameters:
+//
+//	service_id: The ID of the Service.
+//
+// Response:
+//
+//	On success: HTTP 200 OK with when each version was seen/approved/skipped/deployed
+//	  (and the lead time from seen to deployed), and the events they came from (oldest first).
+//	On error: HTTP 404 Not Found if the Service doesn't exist,
+//	  or HTTP 503 Service Unavailable if there's no database.
+func (api *API) httpServiceTimeline(w http.ResponseWriter, r *http.Request) {
+	logFrom := util.LogFrom{Primary: "httpServiceTimeline", Secondary: getIP(r)}
+	targetService, _ := url.QueryUnescape(mux.Vars(r)["service_id"])
+
+	// Check the Service exists.
+	api.Config.OrderMutex.RLock()
+	svc := api.Config.Service[targetService]
+	api.Config.OrderMutex.RUnlock()
+	if svc == nil {
+		err := fmt.Sprintf("service %q not found", targetService)
+		jLog.Error(err, logFrom, true)
+		failRequest(&w, err, http.StatusNotFound)
+		return
+	}
+
+	events, err := history.Events(targetService)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, history.ErrUnavailable) {
+			statusCode = http.StatusServiceUnavailable
+		}
+		failRequest(&w,
+			fmt.Sprintf("timeline query failed, %s",
+				err),
+			statusCode)
+		return
+	}
+
+	api.writeJSON(w, convertServiceTimeline(targetService, events), logFrom)
+}
+
+// convertServiceTimeline converts the VersionEvents of a Service to an apitype.ServiceTimeline.
+func convertServiceTimeline(serviceID string, events []history.VersionEvent) apitype.ServiceTimeline {
+	timeline := history.Timeline(events)
+	response := apitype.ServiceTimeline{
+		ServiceID: serviceID,
+		Versions:  make([]apitype.VersionTimeline, len(timeline)),
+		Events:    make([]apitype.VersionEvent, len(events))}
+
+	for i := range timeline {
+		version := &timeline[i]
+		response.Versions[i] = apitype.VersionTimeline{
+			Version:    version.Version,
+			Seen:       version.Seen,
+			Approved:   version.Approved,
+			ApprovedBy: version.ApprovedBy,
+			Skipped:    version.Skipped,
+			SkippedBy:  version.SkippedBy,
+			Deployed:   version.Deployed}
+		if leadTime, ok := version.LeadTime(); ok {
+			leadTimeMS := leadTime.Milliseconds()
+			response.Versions[i].LeadTimeMS = &leadTimeMS
+		}
+	}
+	for i := range events {
+		response.Events[i] = apitype.VersionEvent{
+			ID:      events[i].ID,
+			Time:    events[i].Time,
+			Type:    events[i].Type,
+			Version: events[i].Version,
+			Actor:   events[i].Actor}
+	}
+
+	return response
+}
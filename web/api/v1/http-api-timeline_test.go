@@ -0,0 +1,74 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/release-argus/Argus/history"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestConvertServiceTimeline(t *testing.T) {
+	// GIVEN VersionEvents of a Service.
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		events []history.VersionEvent
+		want   string
+	}{
+		"no events": {
+			events: []history.VersionEvent{},
+			want:   `{"service_id":"svc","versions":[],"events":[]}`},
+		"seen, approved and deployed": {
+			events: []history.VersionEvent{
+				{ID: "a", Time: start, ServiceID: "svc", Type: history.EventSeen, Version: "2.4.1"},
+				{ID: "b", Time: start.Add(time.Hour), ServiceID: "svc", Type: history.EventApproved, Version: "2.4.1", Actor: "alice"},
+				{ID: "c", Time: start.Add(90 * time.Minute), ServiceID: "svc", Type: history.EventDeployed, Version: "2.4.1"}},
+			want: `{"service_id":"svc",` +
+				`"versions":[{"version":"2.4.1",` +
+				`"seen":"2025-01-01T00:00:00Z",` +
+				`"approved":"2025-01-01T01:00:00Z","approved_by":"alice",` +
+				`"deployed":"2025-01-01T01:30:00Z",` +
+				`"lead_time_ms":5400000}],` +
+				`"events":[` +
+				`{"id":"a","time":"2025-01-01T00:00:00Z","type":"seen","version":"2.4.1"},` +
+				`{"id":"b","time":"2025-01-01T01:00:00Z","type":"approved","version":"2.4.1","actor":"alice"},` +
+				`{"id":"c","time":"2025-01-01T01:30:00Z","type":"deployed","version":"2.4.1"}]}`},
+		"skipped": {
+			events: []history.VersionEvent{
+				{ID: "a", Time: start, ServiceID: "svc", Type: history.EventSkipped, Version: "2.5.0", Actor: "bob"}},
+			want: `{"service_id":"svc",` +
+				`"versions":[{"version":"2.5.0","skipped":"2025-01-01T00:00:00Z","skipped_by":"bob"}],` +
+				`"events":[{"id":"a","time":"2025-01-01T00:00:00Z","type":"skipped","version":"2.5.0","actor":"bob"}]}`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN convertServiceTimeline is called.
+			got := convertServiceTimeline("svc", tc.events)
+
+			// THEN the timeline is as expected.
+			if gotStr := util.ToJSONString(got); gotStr != tc.want {
+				t.Errorf("want\n%s\ngot\n%s",
+					tc.want, gotStr)
+			}
+		})
+	}
+}
@@ -61,6 +61,8 @@ func (api *API) SetupRoutesAPI() {
 	v1Router.HandleFunc("/service/actions/{service_id:.+}", api.httpServiceGetActions).Methods("GET")
 	//   POST, service actions (disable=service_actions).
 	v1Router.HandleFunc("/service/actions/{service_id:.+}", api.httpServiceRunActions).Methods("POST")
+	//   GET, service version timeline.
+	v1Router.HandleFunc("/service/timeline/{service_id:.+}", api.httpServiceTimeline).Methods("GET")
 	//   GET, service-edit - get details.
 	v1Router.HandleFunc("/service/update", api.httpOtherServiceDetails).Methods("GET")
 	v1Router.HandleFunc("/service/update/{service_id:.+}", api.httpServiceDetail).Methods("GET")
@@ -78,8 +80,30 @@ func (api *API) SetupRoutesAPI() {
 	v1Router.HandleFunc("/service/new", api.httpServiceEdit).Methods("PUT")
 	//   DELETE, service-edit - delete service (disable=service_delete).
 	v1Router.HandleFunc("/service/delete/{service_id:.+}", api.httpServiceDelete).Methods("DELETE")
+	// /outbox
+	//   GET, pending/dead deliveries.
+	v1Router.HandleFunc("/outbox", api.httpOutbox).Methods("GET")
+	//   POST, retry a delivery (disable=outbox_retry).
+	v1Router.HandleFunc("/outbox/{id}/retry", api.httpOutboxRetry).Methods("POST")
+	//   DELETE, discard a delivery (disable=outbox_discard).
+	v1Router.HandleFunc("/outbox/{id}", api.httpOutboxDiscard).Methods("DELETE")
+	// /history
+	//   GET, notify/webhook/command attempts.
+	v1Router.HandleFunc("/history", api.httpHistory).Methods("GET")
 	//   GET, counts for Heimdall.
 	v1Router.HandleFunc("/counts", api.httpCounts).Methods("GET")
+	// /orphans
+	//   GET, archived status of Services no longer in the config.
+	v1Router.HandleFunc("/orphans", api.httpOrphans).Methods("GET")
+	//   POST, restore an orphan to a Service (disable=orphan_restore).
+	v1Router.HandleFunc("/orphans/{id:.+}/restore", api.httpOrphanRestore).Methods("POST")
+	// /db
+	//   GET, backup of the database (disable=db_backup).
+	v1Router.HandleFunc("/db/backup", api.httpDatabaseBackup).Methods("GET")
+	//   GET, JSON export of the database (disable=db_export).
+	v1Router.HandleFunc("/db/export", api.httpDatabaseExport).Methods("GET")
+	//   POST, import a JSON export into the database (disable=db_import).
+	v1Router.HandleFunc("/db/import", api.httpDatabaseImport).Methods("POST")
 
 	// Disable specified routes.
 	api.DisableRoutesAPI()
@@ -104,6 +128,12 @@ func (api *API) DisableRoutesAPI() {
 		webRoutePrefix + "/api/v1/latest_version/refresh":                   {name: "lv_refresh_new", method: "GET"},
 		webRoutePrefix + "/api/v1/deployed_version/refresh":                 {name: "dv_refresh_new", method: "GET"},
 		webRoutePrefix + "/api/v1/service/actions/{service_id:.+}":          {name: "service_actions", method: "POST"},
+		webRoutePrefix + "/api/v1/outbox/{id}/retry":                        {name: "outbox_retry", method: "POST"},
+		webRoutePrefix + "/api/v1/outbox/{id}":                              {name: "outbox_discard", method: "DELETE"},
+		webRoutePrefix + "/api/v1/orphans/{id:.+}/restore":                  {name: "orphan_restore", method: "POST"},
+		webRoutePrefix + "/api/v1/db/backup":                                {name: "db_backup", method: "GET"},
+		webRoutePrefix + "/api/v1/db/export":                                {name: "db_export", method: "GET"},
+		webRoutePrefix + "/api/v1/db/import":                                {name: "db_import", method: "POST"},
 	}
 	for _, r := range routes {
 		r.disabled = util.Contains(api.Config.Settings.Web.DisabledRoutes, r.name)
@@ -17,6 +17,7 @@
 package web
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -66,7 +67,7 @@ func TestMain(m *testing.M) {
 
 	// WHEN the Router is fetched for this Config.
 	router = newWebUI(mainCfg)
-	go Run(mainCfg, jLog)
+	go Run(context.Background(), mainCfg, jLog)
 	time.Sleep(250 * time.Millisecond)
 
 	// THEN Web UI is accessible for the tests.
@@ -60,6 +60,14 @@ var (
 			"id",
 			"result",
 		})
+	// QueryBackoffSeconds holds how long the next latest/deployed version query is being backed off for.
+	QueryBackoffSeconds = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "query_backoff_seconds",
+		Help: "Seconds this service's next latest/deployed version query was backed off for after consecutive failures (0=not backing off)."},
+		[]string{
+			"id",
+			"type",
+		})
 	// LatestVersionIsDeployed tracks the deployment state of the latest version.
 	LatestVersionIsDeployed = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "latest_version_is_deployed",
@@ -92,6 +100,27 @@ var (
 			"service_id",
 			"type",
 		})
+	// DatabaseQueueLength holds the amount of messages waiting to be written to the database.
+	DatabaseQueueLength = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "database_queue_length",
+		Help: "Number of messages waiting to be written to the database."})
+	// DatabaseBatchSize tracks the amount of messages written to the database in each transaction.
+	DatabaseBatchSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "database_batch_size",
+		Help:    "Number of messages written to the database in each transaction.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 8)})
+	// DatabaseWriteSeconds tracks how long it takes to write a batch of messages to the database.
+	DatabaseWriteSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "database_write_seconds",
+		Help:    "Time taken to write a batch of messages to the database (including retries).",
+		Buckets: prometheus.DefBuckets})
+	// DatabaseWriteResultTotal counts the amount of times a database write has passed, been retried or failed.
+	DatabaseWriteResultTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "database_write_result_total",
+		Help: "Number of times a database write has passed/been retried/failed."},
+		[]string{
+			"result",
+		})
 	// WebHookResultTotal counts the amount of times a WebHook has passed or failed.
 	WebHookResultTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "webhook_result_total",
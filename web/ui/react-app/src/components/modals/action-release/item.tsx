@@ -26,6 +26,7 @@ interface Props {
 	failed?: boolean;
 	sending: boolean;
 	next_runnable: string;
+	outputs?: Record<string, string>;
 	ack: (target: string, isWebHook: boolean) => void;
 }
 
@@ -67,6 +68,7 @@ const sendableTimeout = (
  * @param failed - Whether the item failed.
  * @param sending - Whether the item is being sent.
  * @param next_runnable - The time the item can next be sent.
+ * @param outputs - The values captured from the item's response.
  * @returns A component that displays the item's information with buttons based on the modal type.
  */
 export const Item: FC<Props> = ({
@@ -76,6 +78,7 @@ export const Item: FC<Props> = ({
 	failed,
 	sending,
 	next_runnable,
+	outputs,
 	ack,
 }) => {
 	const nextRunnable = new Date(next_runnable);
@@ -202,6 +205,15 @@ export const Item: FC<Props> = ({
 					</OverlayTrigger>
 				)}
 			</Card.Title>
+			{outputs && Object.keys(outputs).length > 0 && (
+				<Card.Body className="modal-item-outputs">
+					{Object.entries(outputs).map(([name, value]) => (
+						<div key={name}>
+							<strong>{name}</strong>: {value}
+						</div>
+					))}
+				</Card.Body>
+			)}
 		</Card>
 	);
 };
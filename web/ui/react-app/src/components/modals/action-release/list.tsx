@@ -69,6 +69,7 @@ export const ModalList: FC<Props> = ({
 						failed={item.failed}
 						sending={sending}
 						next_runnable={item.next_runnable ?? ''}
+						outputs={'outputs' in item ? item.outputs : undefined}
 						ack={onClickAcknowledge}
 					/>
 				);
@@ -69,6 +69,8 @@ export interface WebHookSummaryType {
 	// undefined = unsent/sending.
 	failed?: boolean;
 	next_runnable?: string;
+	// Values captured from the response.
+	outputs?: Record<string, string>;
 }
 
 export interface WebHookSummaryListType {
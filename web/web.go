@@ -16,6 +16,8 @@
 package web
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -68,8 +70,9 @@ func newWebUI(cfg *config.Config) *mux.Router {
 	return router
 }
 
-// Run the web server.
-func Run(cfg *config.Config, log *util.JLog) {
+// Run the web server until `ctx` is done,
+// then wait up to the shutdown timeout for the requests in progress.
+func Run(ctx context.Context, cfg *config.Config, log *util.JLog) {
 	// Only set if unset (avoid RACE condition in tests).
 	if log != nil && jLog == nil {
 		jLog = log
@@ -87,13 +90,29 @@ func Run(cfg *config.Config, log *util.JLog) {
 		WriteTimeout: 10 * time.Second, // Max time to write response.
 	}
 
+	// Stop the server on shutdown.
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Settings.ShutdownTimeout())
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			jLog.Error(
+				fmt.Sprintf("web server shutdown: %s", err),
+				util.LogFrom{}, true)
+		}
+	}()
+
+	var err error
 	if cfg.Settings.WebCertFile() != "" && cfg.Settings.WebKeyFile() != "" {
-		jLog.Fatal(
-			srv.ListenAndServeTLS(cfg.Settings.WebCertFile(), cfg.Settings.WebKeyFile()),
-			util.LogFrom{}, true)
+		err = srv.ListenAndServeTLS(cfg.Settings.WebCertFile(), cfg.Settings.WebKeyFile())
 	} else {
-		jLog.Fatal(
-			srv.ListenAndServe(),
-			util.LogFrom{}, true)
+		err = srv.ListenAndServe()
 	}
+	if !errors.Is(err, http.ErrServerClosed) {
+		jLog.Fatal(err, util.LogFrom{}, true)
+	}
+	<-stopped
 }
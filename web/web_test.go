@@ -17,6 +17,7 @@
 package web
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -36,7 +37,7 @@ func TestMainWithRoutePrefix(t *testing.T) {
 	cfg.Settings.Web.RoutePrefix = "/test"
 
 	// WHEN the Web UI is started with this Config
-	go Run(cfg, nil)
+	go Run(context.Background(), cfg, nil)
 	time.Sleep(500 * time.Millisecond)
 
 	// THEN Web UI is accessible
@@ -54,6 +55,36 @@ func TestMainWithRoutePrefix(t *testing.T) {
 	}
 }
 
+func TestRun_Shutdown(t *testing.T) {
+	// GIVEN the Web UI is running
+	cfg := testConfig("TestRun_Shutdown.yml", nil, t)
+	ctx, cancel := context.WithCancel(context.Background())
+	didFinish := make(chan bool, 1)
+	go func() {
+		Run(ctx, cfg, nil)
+		didFinish <- true
+	}()
+	time.Sleep(500 * time.Millisecond)
+
+	// WHEN the context is cancelled
+	cancel()
+
+	// THEN Run returns
+	select {
+	case <-didFinish:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run didn't return on shutdown")
+	}
+	// AND the Web UI is no longer accessible
+	url := fmt.Sprintf("http://localhost:%s/api/v1/healthcheck",
+		cfg.Settings.Web.ListenPort)
+	if resp, err := http.Get(url); err == nil {
+		resp.Body.Close()
+		t.Errorf("Should not have been able to reach %s after shutdown",
+			url)
+	}
+}
+
 func TestWebAccessible(t *testing.T) {
 	// GIVEN we have the Web UI Router from TestMain()
 	tests := map[string]struct {
@@ -128,7 +159,7 @@ func TestAccessibleHTTPS(t *testing.T) {
 	})
 
 	router = newWebUI(cfg)
-	go Run(cfg, nil)
+	go Run(context.Background(), cfg, nil)
 	time.Sleep(250 * time.Millisecond)
 	address := fmt.Sprintf("https://localhost:%s", cfg.Settings.Web.ListenPort)
 
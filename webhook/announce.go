@@ -29,7 +29,8 @@ func (w *WebHook) AnnounceSend() {
 	webhookSummary := make(map[string]*apitype.WebHookSummary)
 	webhookSummary[w.ID] = &apitype.WebHookSummary{
 		Failed:       w.Failed.Get(w.ID),
-		NextRunnable: w.NextRunnable()}
+		NextRunnable: w.NextRunnable(),
+		Outputs:      w.ServiceStatus.WebHookOutputs(w.ID)}
 
 	// WebHook pass/fail.
 	payloadData, _ := json.Marshal(apitype.WebSocketMessage{
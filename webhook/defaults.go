@@ -15,6 +15,8 @@
 // Package webhook provides WebHook functionality to services.
 package webhook
 
+import "net/http"
+
 // Default sets this Defaults to the default values.
 func (d *Defaults) Default() {
 	// type
@@ -27,10 +29,20 @@ func (d *Defaults) Default() {
 	// desired_status_code
 	webhookDesiredStatusCode := uint16(0)
 	d.DesiredStatusCode = &webhookDesiredStatusCode
+	// timeout
+	d.Timeout = "5s"
 	// max_tries
 	webhookMaxTries := uint8(3)
 	d.MaxTries = &webhookMaxTries
 	// silent_fails
 	webhookSilentFails := false
 	d.SilentFails = &webhookSilentFails
+	// method
+	d.Method = http.MethodPost
+	// content_type
+	d.ContentType = "json"
+	// signing
+	d.Signing = SigningNone
+	d.SigningHeader = "X-Signature"
+	d.SigningFormat = "sha256=" + signaturePlaceholder
 }
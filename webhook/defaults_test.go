@@ -33,6 +33,11 @@ func TestDefaults_Default(t *testing.T) {
 			DesiredStatusCode: test.UInt16Ptr(0),
 			MaxTries:          test.UInt8Ptr(3),
 			SilentFails:       test.BoolPtr(false),
+			Method:            "POST",
+			ContentType:       "json",
+			Signing:           SigningNone,
+			SigningHeader:     "X-Signature",
+			SigningFormat:     "sha256={signature}",
 		}}
 
 	// WHEN Default is called
@@ -63,4 +68,19 @@ func TestDefaults_Default(t *testing.T) {
 		t.Errorf("SilentFails not set correctly, got %q, want %q",
 			test.StringifyPtr(defaults.SilentFails), test.StringifyPtr(expected.SilentFails))
 	}
+	for _, field := range []struct {
+		name      string
+		got, want string
+	}{
+		{"Method", defaults.Method, expected.Method},
+		{"ContentType", defaults.ContentType, expected.ContentType},
+		{"Signing", defaults.Signing, expected.Signing},
+		{"SigningHeader", defaults.SigningHeader, expected.SigningHeader},
+		{"SigningFormat", defaults.SigningFormat, expected.SigningFormat},
+	} {
+		if field.got != field.want {
+			t.Errorf("%s not set correctly, got %q, want %q",
+				field.name, field.got, field.want)
+		}
+	}
 }
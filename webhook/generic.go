@@ -0,0 +1,170 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook provides WebHook functionality to services.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/release-argus/Argus/util"
+)
+
+// Signing types of a generic WebHook.
+const (
+	SigningNone       = "none"
+	SigningHMACSHA256 = "hmac-sha256"
+	SigningBearer     = "bearer"
+)
+
+// signaturePlaceholder is replaced with the hex-encoded HMAC in the signing_format.
+const signaturePlaceholder = "{signature}"
+
+var (
+	supportedMethods = []string{
+		http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	// contentTypes maps the content_type values to their MIME type.
+	contentTypes = map[string]string{
+		"json": "application/json",
+		"form": "application/x-www-form-urlencoded",
+		"text": "text/plain",
+		"xml":  "application/xml"}
+	supportedSigning = []string{
+		SigningNone, SigningHMACSHA256, SigningBearer}
+)
+
+// GetMethod of the generic WebHook.
+func (w *WebHook) GetMethod() string {
+	return strings.ToUpper(
+		util.FirstNonDefault(
+			w.Method,
+			w.Main.Method,
+			w.Defaults.Method,
+			w.HardDefaults.Method))
+}
+
+// GetContentType of the generic WebHook (as a MIME type).
+func (w *WebHook) GetContentType() string {
+	return contentTypes[util.FirstNonDefault(
+		w.ContentType,
+		w.Main.ContentType,
+		w.Defaults.ContentType,
+		w.HardDefaults.ContentType)]
+}
+
+// GetBody of the generic WebHook, templated with the Service info.
+func (w *WebHook) GetBody() string {
+	return w.templateBody(w.ServiceStatus.ServiceInfo())
+}
+
+// templateBody returns the body of the generic WebHook, templated with `serviceInfo`.
+func (w *WebHook) templateBody(serviceInfo util.ServiceInfo) string {
+	return util.TemplateString(
+		util.FirstNonDefault(
+			w.Body,
+			w.Main.Body,
+			w.Defaults.Body,
+			w.HardDefaults.Body),
+		serviceInfo)
+}
+
+// GetQueryParams of the generic WebHook.
+func (w *WebHook) GetQueryParams() *Headers {
+	switch {
+	case w.QueryParams != nil:
+		return w.QueryParams
+	case w.Main.QueryParams != nil:
+		return w.Main.QueryParams
+	case w.Defaults.QueryParams != nil:
+		return w.Defaults.QueryParams
+	default:
+		return w.HardDefaults.QueryParams
+	}
+}
+
+// GetSigning type of the generic WebHook.
+func (w *WebHook) GetSigning() string {
+	return util.FirstNonDefault(
+		w.Signing,
+		w.Main.Signing,
+		w.Defaults.Signing,
+		w.HardDefaults.Signing)
+}
+
+// GetSigningHeader of the generic WebHook to put the HMAC signature in.
+func (w *WebHook) GetSigningHeader() string {
+	return util.FirstNonDefault(
+		w.SigningHeader,
+		w.Main.SigningHeader,
+		w.Defaults.SigningHeader,
+		w.HardDefaults.SigningHeader)
+}
+
+// GetSigningFormat of the HMAC signature header value.
+func (w *WebHook) GetSigningFormat() string {
+	return util.FirstNonDefault(
+		w.SigningFormat,
+		w.Main.SigningFormat,
+		w.Defaults.SigningFormat,
+		w.HardDefaults.SigningFormat)
+}
+
+// buildGenericRequest returns the http.Request of a generic WebHook,
+// with the query parameters/body templated with `serviceInfo`, and signed with the Secret.
+func (w *WebHook) buildGenericRequest(serviceInfo util.ServiceInfo) (*http.Request, error) {
+	// URL, with the query parameters.
+	reqURL, err := url.Parse(w.templateURL(serviceInfo))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse url: %w", err)
+	}
+	if queryParams := w.GetQueryParams(); queryParams != nil {
+		query := reqURL.Query()
+		for _, param := range *queryParams {
+			query.Set(param.Key, util.TemplateString(param.Value, serviceInfo))
+		}
+		reqURL.RawQuery = query.Encode()
+	}
+
+	body := w.templateBody(serviceInfo)
+	req, err := http.NewRequest(w.GetMethod(), reqURL.String(), strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", w.GetContentType())
+	}
+
+	w.signGenericRequest(req, []byte(body))
+	return req, nil
+}
+
+// signGenericRequest adds the signature/token of the Secret to the req.
+func (w *WebHook) signGenericRequest(req *http.Request, body []byte) {
+	switch w.GetSigning() {
+	case SigningHMACSHA256:
+		hash := hmac.New(sha256.New, []byte(w.GetSecret()))
+		hash.Write(body)
+		req.Header.Set(
+			w.GetSigningHeader(),
+			strings.ReplaceAll(w.GetSigningFormat(), signaturePlaceholder, hex.EncodeToString(hash.Sum(nil))))
+	case SigningBearer:
+		req.Header.Set("Authorization", "Bearer "+w.GetSecret())
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//go:build unit
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestWebHook_BuildRequest_Generic(t *testing.T) {
+	// GIVEN a generic WebHook.
+	tests := map[string]struct {
+		base            Base
+		wantMethod      string
+		wantURL         string
+		wantBody        string
+		wantContentType string
+		wantHeaders     map[string]string
+	}{
+		"defaults": {
+			base:            Base{},
+			wantMethod:      http.MethodPost,
+			wantURL:         "https://example.com/hook",
+			wantBody:        "",
+			wantContentType: ""},
+		"templated body and query params": {
+			base: Base{
+				Method: "put",
+				QueryParams: &Headers{
+					{Key: "service", Value: "{{ service_id }}"},
+					{Key: "version", Value: "{{ version }}"}},
+				ContentType: "text",
+				Body:        "deploy {{ service_id }} {{ version }}"},
+			wantMethod:      http.MethodPut,
+			wantURL:         "https://example.com/hook?service=testServiceID&version=1.2.3",
+			wantBody:        "deploy testServiceID 1.2.3",
+			wantContentType: "text/plain"},
+		"hmac-sha256 signing": {
+			base: Base{
+				Body:          `{"version":"{{ version }}"}`,
+				Signing:       SigningHMACSHA256,
+				SigningHeader: "X-Hub-Sig",
+				SigningFormat: "v1={signature}"},
+			wantMethod:      http.MethodPost,
+			wantURL:         "https://example.com/hook",
+			wantBody:        `{"version":"1.2.3"}`,
+			wantContentType: "application/json",
+			wantHeaders: map[string]string{
+				"X-Hub-Sig": "v1=" + testHMAC(`{"version":"1.2.3"}`, "argus")}},
+		"bearer signing": {
+			base: Base{
+				Method:  "get",
+				Signing: SigningBearer},
+			wantMethod:      http.MethodGet,
+			wantURL:         "https://example.com/hook",
+			wantContentType: "",
+			wantHeaders: map[string]string{
+				"Authorization": "Bearer argus"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			webhook := testWebHook(false, false, false)
+			hardDefaults := &Defaults{}
+			hardDefaults.Default()
+			webhook.HardDefaults = hardDefaults
+			webhook.Base = tc.base
+			webhook.Type = "generic"
+			webhook.URL = "https://example.com/hook"
+			webhook.Secret = "argus"
+			webhook.ServiceStatus.SetLatestVersion("1.2.3", "", false)
+
+			// WHEN BuildRequest is called.
+			req := webhook.BuildRequest(webhook.ServiceStatus.ServiceInfo())
+
+			// THEN the request is built as expected.
+			if req == nil {
+				t.Fatal("BuildRequest returned nil")
+			}
+			if req.Method != tc.wantMethod {
+				t.Errorf("method\nwant: %q\ngot:  %q",
+					tc.wantMethod, req.Method)
+			}
+			if got := req.URL.String(); got != tc.wantURL {
+				t.Errorf("url\nwant: %q\ngot:  %q",
+					tc.wantURL, got)
+			}
+			body, _ := io.ReadAll(req.Body)
+			if string(body) != tc.wantBody {
+				t.Errorf("body\nwant: %q\ngot:  %q",
+					tc.wantBody, string(body))
+			}
+			if got := req.Header.Get("Content-Type"); got != tc.wantContentType {
+				t.Errorf("Content-Type\nwant: %q\ngot:  %q",
+					tc.wantContentType, got)
+			}
+			for key, want := range tc.wantHeaders {
+				if got := req.Header.Get(key); got != want {
+					t.Errorf("%s\nwant: %q\ngot:  %q",
+						key, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestWebHook_BuildRequest_ServiceInfo(t *testing.T) {
+	// GIVEN a generic WebHook for a Service that has since found a newer version.
+	webhook := testWebHook(false, false, false)
+	hardDefaults := &Defaults{}
+	hardDefaults.Default()
+	webhook.HardDefaults = hardDefaults
+	webhook.Type = "generic"
+	webhook.URL = "https://example.com/{{ version }}"
+	webhook.Body = "deploy {{ version }}"
+	webhook.ServiceStatus.SetLatestVersion("1.0.0", "", false)
+	serviceInfo := webhook.ServiceStatus.ServiceInfo()
+	webhook.ServiceStatus.SetLatestVersion("2.0.0", "", false)
+
+	// WHEN BuildRequest is called with the Service info of the older version.
+	req := webhook.BuildRequest(serviceInfo)
+
+	// THEN the request is templated with that version, not the current one.
+	if req == nil {
+		t.Fatal("BuildRequest returned nil")
+	}
+	if got, want := req.URL.String(), "https://example.com/1.0.0"; got != want {
+		t.Errorf("url\nwant: %q\ngot:  %q",
+			want, got)
+	}
+	body, _ := io.ReadAll(req.Body)
+	if got, want := string(body), "deploy 1.0.0"; got != want {
+		t.Errorf("body\nwant: %q\ngot:  %q",
+			want, got)
+	}
+}
+
+// testHMAC returns the hex-encoded HMAC-SHA256 of the body with the secret.
+func testHMAC(body, secret string) string {
+	hash := hmac.New(sha256.New, []byte(secret))
+	hash.Write([]byte(body))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func TestBase_CheckValuesGeneric(t *testing.T) {
+	// GIVEN a Base with generic fields.
+	tests := map[string]struct {
+		base       Base
+		wantMethod string
+		errRegex   string
+	}{
+		"empty": {
+			base:     Base{},
+			errRegex: `^$`},
+		"valid": {
+			base: Base{
+				Method:        "patch",
+				QueryParams:   &Headers{{Key: "v", Value: "{{ version }}"}},
+				ContentType:   "form",
+				Body:          "version={{ version }}",
+				Signing:       SigningHMACSHA256,
+				SigningFormat: "{signature}"},
+			wantMethod: http.MethodPatch,
+			errRegex:   `^$`},
+		"invalid method": {
+			base:     Base{Method: "fetch"},
+			errRegex: `^method: "FETCH" <invalid>`},
+		"invalid query_params": {
+			base: Base{QueryParams: &Headers{{Key: "v", Value: "{{ version }"}}},
+			errRegex: test.TrimYAML(`
+				^query_params:
+					v: "[^"]+" <invalid>.*$`)},
+		"invalid content_type": {
+			base:     Base{ContentType: "yaml"},
+			errRegex: `^content_type: "yaml" <invalid> \(supported types = \[form,json,text,xml\]\)$`},
+		"invalid body": {
+			base:     Base{Body: "{{ version }"},
+			errRegex: `^body: "[^"]+" <invalid>`},
+		"invalid signing": {
+			base:     Base{Signing: "md5"},
+			errRegex: `^signing: "md5" <invalid>`},
+		"signing_format without placeholder": {
+			base:     Base{SigningFormat: "sha256="},
+			errRegex: `^signing_format: "sha256=" <invalid>`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN checkValuesGeneric is called.
+			err := tc.base.checkValuesGeneric("")
+
+			// THEN it errors when expected.
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for:\n%q\ngot:\n%q",
+					tc.errRegex, e)
+			}
+			// AND the method is upper-cased.
+			if tc.wantMethod != "" && tc.base.Method != tc.wantMethod {
+				t.Errorf("method\nwant: %q\ngot:  %q",
+					tc.wantMethod, tc.base.Method)
+			}
+		})
+	}
+}
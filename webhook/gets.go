@@ -123,8 +123,9 @@ func (w *WebHook) IsRunnable() bool {
 	return time.Now().UTC().After(w.nextRunnable)
 }
 
-// BuildRequest will return the WebHook http.request ready to be sent.
-func (w *WebHook) BuildRequest() (req *http.Request) {
+// BuildRequest will return the WebHook http.request ready to be sent,
+// templated with `serviceInfo`.
+func (w *WebHook) BuildRequest(serviceInfo util.ServiceInfo) (req *http.Request) {
 	w.mutex.RLock()
 	defer w.mutex.RUnlock()
 
@@ -139,7 +140,7 @@ func (w *WebHook) BuildRequest() (req *http.Request) {
 			After:  util.RandAlphaNumericLower(40),
 		})
 
-		req, err = http.NewRequest(http.MethodPost, w.GetURL(), bytes.NewReader(payload))
+		req, err = http.NewRequest(http.MethodPost, w.templateURL(serviceInfo), bytes.NewReader(payload))
 		if err != nil {
 			return nil
 		}
@@ -147,16 +148,23 @@ func (w *WebHook) BuildRequest() (req *http.Request) {
 
 		SetGitHubHeaders(req, payload, w.GetSecret())
 	case "gitlab":
-		req, err = http.NewRequest(http.MethodPost, w.GetURL(), nil)
+		req, err = http.NewRequest(http.MethodPost, w.templateURL(serviceInfo), nil)
 		if err != nil {
 			return nil
 		}
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 		SetGitLabParameter(req, w.GetSecret())
+	case "generic":
+		req, err = w.buildGenericRequest(serviceInfo)
+		if err != nil {
+			return nil
+		}
+	default:
+		return nil
 	}
 	req.Header.Set("Connection", "close")
-	w.setCustomHeaders(req)
+	w.setCustomHeaders(req, serviceInfo)
 	return
 }
 
@@ -188,16 +196,20 @@ func (w *WebHook) GetType() string {
 }
 
 // GetURL of the WebHook.
-func (w *WebHook) GetURL() (url string) {
-	url = strings.Clone(
+func (w *WebHook) GetURL() string {
+	return w.templateURL(w.ServiceStatus.ServiceInfo())
+}
+
+// templateURL returns the URL of the WebHook, templated with `serviceInfo`.
+func (w *WebHook) templateURL(serviceInfo util.ServiceInfo) string {
+	url := strings.Clone(
 		util.FirstNonDefaultWithEnv(
 			w.URL,
 			w.Main.URL,
 			w.Defaults.URL,
 			w.HardDefaults.URL))
 
-	url = util.TemplateString(
+	return util.TemplateString(
 		url,
-		util.ServiceInfo{LatestVersion: w.ServiceStatus.LatestVersion()})
-	return
+		serviceInfo)
 }
@@ -336,7 +336,7 @@ func TestWebHook_BuildRequest(t *testing.T) {
 			webhook.CustomHeaders = &tc.customHeaders
 
 			// WHEN BuildRequest is called
-			req := webhook.BuildRequest()
+			req := webhook.BuildRequest(webhook.ServiceStatus.ServiceInfo())
 
 			// THEN the function returns the correct result
 			if tc.wantNil {
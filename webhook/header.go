@@ -28,8 +28,8 @@ type GitHub struct {
 	After  string `json:"after"`  // "RandAlphaNumericLower(40)".
 }
 
-// setCustomHeaders of the req.
-func (w *WebHook) setCustomHeaders(req *http.Request) {
+// setCustomHeaders of the req, templated with `serviceInfo`.
+func (w *WebHook) setCustomHeaders(req *http.Request, serviceInfo util.ServiceInfo) {
 	var customHeaders *Headers
 	switch {
 	case w.CustomHeaders != nil:
@@ -44,9 +44,6 @@ func (w *WebHook) setCustomHeaders(req *http.Request) {
 		return
 	}
 
-	serviceInfo := util.ServiceInfo{
-		ID:            *w.ServiceStatus.ServiceID,
-		LatestVersion: w.ServiceStatus.LatestVersion()}
 	for _, header := range *customHeaders {
 		key := util.EvalEnvVars(header.Key)
 		value := util.TemplateString(util.EvalEnvVars(header.Value), serviceInfo)
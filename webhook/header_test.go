@@ -163,7 +163,7 @@ func TestWebHook_SetCustomHeaders(t *testing.T) {
 			webhook.HardDefaults.CustomHeaders = tc.hardDefaultValue
 
 			// WHEN setCustomHeaders is called on this request.
-			webhook.setCustomHeaders(req)
+			webhook.setCustomHeaders(req, webhook.ServiceStatus.ServiceInfo())
 
 			// THEN the function returns the correct result.
 			if tc.rootValue == nil && tc.mainValue == nil && tc.defaultValue == nil && tc.hardDefaultValue == nil {
@@ -0,0 +1,83 @@
+// Copyright [2025] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook provides WebHook functionality to services.
+package webhook
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/release-argus/Argus/history"
+	"github.com/release-argus/Argus/util"
+)
+
+// historySafeHeaders are the request headers recorded in the history as-is,
+// all others (custom headers/signatures) are redacted.
+var historySafeHeaders = map[string]bool{
+	"Connection":                             true,
+	"Content-Type":                           true,
+	"X-Github-Delivery":                      true,
+	"X-Github-Event":                         true,
+	"X-Github-Hook-Id":                       true,
+	"X-Github-Hook-Installation-Target-Id":   true,
+	"X-Github-Hook-Installation-Target-Type": true,
+}
+
+// historyPayload is the payload of a WebHook request recorded in the history.
+type historyPayload struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// historyRecord returns the history.Record of sending `req` (for `serviceInfo`), with its secrets redacted.
+func (w *WebHook) historyRecord(req *http.Request, serviceInfo util.ServiceInfo) history.Record {
+	payload := historyPayload{
+		Method:  req.Method,
+		Headers: make(map[string]string, len(req.Header))}
+
+	// URL, without the password, or the values of the query parameters
+	// (GitLab token/templated generic params may hold secrets).
+	redactedURL := *req.URL
+	if query := redactedURL.Query(); len(query) != 0 {
+		for key := range query {
+			query.Set(key, util.SecretValue)
+		}
+		redactedURL.RawQuery = query.Encode()
+	}
+	payload.URL = redactedURL.Redacted()
+
+	// Headers.
+	for key, values := range req.Header {
+		value := util.SecretValue
+		if historySafeHeaders[http.CanonicalHeaderKey(key)] {
+			value = strings.Join(values, ", ")
+		}
+		payload.Headers[key] = value
+	}
+
+	// Body, redacted as a templated generic body may hold secrets.
+	if req.ContentLength != 0 {
+		payload.Body = util.SecretValue
+	}
+
+	return history.Record{
+		Kind:      history.KindWebHook,
+		ServiceID: util.DereferenceOrDefault(w.ServiceStatus.ServiceID),
+		Target:    w.ID,
+		Version:   serviceInfo.LatestVersion,
+		Payload:   util.ToJSONString(payload)}
+}
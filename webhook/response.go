@@ -0,0 +1,244 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook provides WebHook functionality to services.
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/release-argus/Argus/util"
+)
+
+// ResponseCheck is an assertion on the response to a WebHook.
+//
+// It targets the value at JSONPath in the body, the Header, or else the whole body.
+type ResponseCheck struct {
+	JSONPath string `yaml:"json_path,omitempty" json:"json_path,omitempty"` // Key of the value in the JSON body, e.g. "job.state".
+	Header   string `yaml:"header,omitempty" json:"header,omitempty"`       // Header that must be present.
+	Equals   string `yaml:"equals,omitempty" json:"equals,omitempty"`       // Value the target must equal.
+	Regex    string `yaml:"regex,omitempty" json:"regex,omitempty"`         // RegEx the target must match.
+}
+
+// ResponseChecks is a list of ResponseCheck.
+type ResponseChecks []ResponseCheck
+
+// ResponseCapture is a value to capture from the response to a WebHook.
+//
+// It captures the value at JSONPath in the body, the Header, or else the whole body,
+// narrowed to the first capture group of Regex (if given).
+type ResponseCapture struct {
+	Name     string `yaml:"name" json:"name"`                               // Name to store the value as.
+	JSONPath string `yaml:"json_path,omitempty" json:"json_path,omitempty"` // Key of the value in the JSON body, e.g. "job.id".
+	Header   string `yaml:"header,omitempty" json:"header,omitempty"`       // Header to capture.
+	Regex    string `yaml:"regex,omitempty" json:"regex,omitempty"`         // RegEx with a capture group to extract.
+}
+
+// ResponseCaptures is a list of ResponseCapture.
+type ResponseCaptures []ResponseCapture
+
+// responseTarget returns the value of the response that a check/capture targets.
+func responseTarget(jsonPath, header string, headers http.Header, body []byte) (string, error) {
+	switch {
+	case jsonPath != "":
+		//nolint:wrapcheck
+		return util.GetValueByKey(body, jsonPath, "WebHook response")
+	case header != "":
+		values, ok := headers[http.CanonicalHeaderKey(header)]
+		if !ok {
+			return "", fmt.Errorf("header %q missing", header)
+		}
+		if len(values) == 0 {
+			return "", nil
+		}
+		return values[0], nil
+	default:
+		return string(body), nil
+	}
+}
+
+// GetResponseChecks of the WebHook.
+func (w *WebHook) GetResponseChecks() *ResponseChecks {
+	switch {
+	case w.ResponseChecks != nil:
+		return w.ResponseChecks
+	case w.Main.ResponseChecks != nil:
+		return w.Main.ResponseChecks
+	case w.Defaults.ResponseChecks != nil:
+		return w.Defaults.ResponseChecks
+	default:
+		return w.HardDefaults.ResponseChecks
+	}
+}
+
+// GetResponseCaptures of the WebHook.
+func (w *WebHook) GetResponseCaptures() *ResponseCaptures {
+	switch {
+	case w.ResponseCaptures != nil:
+		return w.ResponseCaptures
+	case w.Main.ResponseCaptures != nil:
+		return w.Main.ResponseCaptures
+	case w.Defaults.ResponseCaptures != nil:
+		return w.Defaults.ResponseCaptures
+	default:
+		return w.HardDefaults.ResponseCaptures
+	}
+}
+
+// Check the response `headers`/`body` passes every ResponseCheck.
+func (c *ResponseChecks) Check(headers http.Header, body []byte) error {
+	if c == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, check := range *c {
+		target, err := responseTarget(check.JSONPath, check.Header, headers, body)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		name := "body"
+		switch {
+		case check.JSONPath != "":
+			name = fmt.Sprintf("json_path %q", check.JSONPath)
+		case check.Header != "":
+			name = fmt.Sprintf("header %q", check.Header)
+		}
+		if check.Equals != "" && target != check.Equals {
+			errs = append(errs, fmt.Errorf("%s is %q, not %q",
+				name, util.TruncateMessage(target, 100), check.Equals))
+		}
+		if check.Regex != "" && !util.RegexCheck(check.Regex, target) {
+			errs = append(errs, fmt.Errorf("%s %q doesn't match %q",
+				name, util.TruncateMessage(target, 100), check.Regex))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("WebHook response checks failed:\n%w", errors.Join(errs...))
+}
+
+// Capture the ResponseCapture values of the response `headers`/`body`.
+func (c *ResponseCaptures) Capture(headers http.Header, body []byte) (map[string]string, error) {
+	if c == nil || len(*c) == 0 {
+		return nil, nil
+	}
+
+	outputs := make(map[string]string, len(*c))
+	var errs []error
+	for _, capture := range *c {
+		value, err := responseTarget(capture.JSONPath, capture.Header, headers, body)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", capture.Name, err))
+			continue
+		}
+
+		if capture.Regex != "" {
+			re := regexp.MustCompile(capture.Regex)
+			match := re.FindStringSubmatch(value)
+			switch {
+			case match == nil:
+				errs = append(errs, fmt.Errorf("%s: no match for %q",
+					capture.Name, capture.Regex))
+				continue
+			case len(match) > 1:
+				value = match[1]
+			default:
+				value = match[0]
+			}
+		}
+		outputs[capture.Name] = value
+	}
+
+	if len(errs) != 0 {
+		return outputs, fmt.Errorf("WebHook response captures failed:\n%w", errors.Join(errs...))
+	}
+	return outputs, nil
+}
+
+// CheckValues validates the fields of the ResponseChecks.
+func (c *ResponseChecks) CheckValues(prefix string) error {
+	if c == nil {
+		return nil
+	}
+
+	var errs []error
+	for i, check := range *c {
+		var checkErrs []error
+		if check.JSONPath != "" && check.Header != "" {
+			checkErrs = append(checkErrs, fmt.Errorf("%s  json_path/header: <invalid> (only one can be given)",
+				prefix))
+		}
+		if check.JSONPath == "" && check.Header == "" && check.Equals == "" && check.Regex == "" {
+			checkErrs = append(checkErrs, fmt.Errorf("%s  equals/regex: <required> (to check the body)",
+				prefix))
+		}
+		if check.Regex != "" {
+			if _, err := regexp.Compile(check.Regex); err != nil {
+				checkErrs = append(checkErrs, fmt.Errorf("%s  regex: %q <invalid> (Invalid RegEx)",
+					prefix, check.Regex))
+			}
+		}
+		util.AppendCheckError(&errs, prefix, fmt.Sprintf("- item_%d", i), errors.Join(checkErrs...))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// CheckValues validates the fields of the ResponseCaptures.
+func (c *ResponseCaptures) CheckValues(prefix string) error {
+	if c == nil {
+		return nil
+	}
+
+	var errs []error
+	names := make(map[string]bool, len(*c))
+	for i, capture := range *c {
+		var captureErrs []error
+		if capture.Name == "" {
+			captureErrs = append(captureErrs, fmt.Errorf("%s  name: <required>",
+				prefix))
+		} else if names[capture.Name] {
+			captureErrs = append(captureErrs, fmt.Errorf("%s  name: %q <invalid> (duplicate)",
+				prefix, capture.Name))
+		}
+		names[capture.Name] = true
+		if capture.JSONPath != "" && capture.Header != "" {
+			captureErrs = append(captureErrs, fmt.Errorf("%s  json_path/header: <invalid> (only one can be given)",
+				prefix))
+		}
+		if capture.Regex != "" {
+			if _, err := regexp.Compile(capture.Regex); err != nil {
+				captureErrs = append(captureErrs, fmt.Errorf("%s  regex: %q <invalid> (Invalid RegEx)",
+					prefix, capture.Regex))
+			}
+		}
+		util.AppendCheckError(&errs, prefix, fmt.Sprintf("- item_%d", i), errors.Join(captureErrs...))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,248 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//go:build unit
+
+package webhook
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestResponseChecks_Check(t *testing.T) {
+	headers := http.Header{"Location": {"https://example.com/job/42"}}
+	body := []byte(`{"job":{"id":42,"state":"queued"}}`)
+	// GIVEN ResponseChecks.
+	tests := map[string]struct {
+		checks   *ResponseChecks
+		errRegex string
+	}{
+		"nil": {
+			checks:   nil,
+			errRegex: `^$`},
+		"json_path equals": {
+			checks: &ResponseChecks{
+				{JSONPath: "job.state", Equals: "queued"}},
+			errRegex: `^$`},
+		"json_path equals fail": {
+			checks: &ResponseChecks{
+				{JSONPath: "job.state", Equals: "running"}},
+			errRegex: `json_path "job.state" is "queued", not "running"`},
+		"json_path missing": {
+			checks: &ResponseChecks{
+				{JSONPath: "job.url"}},
+			errRegex: `failed to find value for "job.url"`},
+		"json_path regex": {
+			checks: &ResponseChecks{
+				{JSONPath: "job.id", Regex: `^[0-9]+$`}},
+			errRegex: `^$`},
+		"header present": {
+			checks: &ResponseChecks{
+				{Header: "location"}},
+			errRegex: `^$`},
+		"header missing": {
+			checks: &ResponseChecks{
+				{Header: "X-Job"}},
+			errRegex: `header "X-Job" missing`},
+		"header regex fail": {
+			checks: &ResponseChecks{
+				{Header: "Location", Regex: `/build/`}},
+			errRegex: `header "Location" "[^"]+" doesn't match`},
+		"body regex": {
+			checks: &ResponseChecks{
+				{Regex: `"state":"queued"`}},
+			errRegex: `^$`},
+		"multiple failures": {
+			checks: &ResponseChecks{
+				{JSONPath: "job.state", Equals: "running"},
+				{Regex: `error`}},
+			errRegex: test.TrimYAML(`
+				^WebHook response checks failed:
+				json_path "job.state" is "queued", not "running"
+				body "[^\n]+" doesn't match "error"$`)},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN Check is called on a response.
+			err := tc.checks.Check(headers, body)
+
+			// THEN it errors when expected.
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for:\n%q\ngot:\n%q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestResponseCaptures_Capture(t *testing.T) {
+	headers := http.Header{"Location": {"https://example.com/job/42"}}
+	body := []byte(`{"job":{"id":42,"url":"https://example.com/job/42"}}`)
+	// GIVEN ResponseCaptures.
+	tests := map[string]struct {
+		captures *ResponseCaptures
+		want     map[string]string
+		errRegex string
+	}{
+		"nil": {
+			captures: nil,
+			want:     nil,
+			errRegex: `^$`},
+		"json_path": {
+			captures: &ResponseCaptures{
+				{Name: "job_id", JSONPath: "job.id"}},
+			want:     map[string]string{"job_id": "42"},
+			errRegex: `^$`},
+		"header with regex group": {
+			captures: &ResponseCaptures{
+				{Name: "job_id", Header: "Location", Regex: `/job/([0-9]+)$`}},
+			want:     map[string]string{"job_id": "42"},
+			errRegex: `^$`},
+		"body with regex, no group": {
+			captures: &ResponseCaptures{
+				{Name: "url", Regex: `https://[^"]+`}},
+			want:     map[string]string{"url": "https://example.com/job/42"},
+			errRegex: `^$`},
+		"regex no match": {
+			captures: &ResponseCaptures{
+				{Name: "job_id", JSONPath: "job.id"},
+				{Name: "build", Regex: `build/([0-9]+)`}},
+			want:     map[string]string{"job_id": "42"},
+			errRegex: `build: no match for`},
+		"header missing": {
+			captures: &ResponseCaptures{
+				{Name: "job", Header: "X-Job"}},
+			want:     map[string]string{},
+			errRegex: `job: header "X-Job" missing`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN Capture is called on a response.
+			got, err := tc.captures.Capture(headers, body)
+
+			// THEN it errors when expected.
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for:\n%q\ngot:\n%q",
+					tc.errRegex, e)
+			}
+			// AND the values are captured.
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("want: %v\ngot:  %v",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestResponseChecks_CheckValues(t *testing.T) {
+	// GIVEN ResponseChecks.
+	tests := map[string]struct {
+		checks   *ResponseChecks
+		errRegex string
+	}{
+		"nil": {
+			checks:   nil,
+			errRegex: `^$`},
+		"valid": {
+			checks: &ResponseChecks{
+				{JSONPath: "job.state", Equals: "queued"},
+				{Header: "Location"},
+				{Regex: `ok`}},
+			errRegex: `^$`},
+		"invalid": {
+			checks: &ResponseChecks{
+				{JSONPath: "job.state", Header: "Location", Regex: `[0-`},
+				{}},
+			errRegex: test.TrimYAML(`
+				^- item_0:
+					json_path/header: <invalid>.*
+					regex: "\[0-" <invalid>.*
+				- item_1:
+					equals/regex: <required>.*$`)},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN CheckValues is called.
+			err := tc.checks.CheckValues("")
+
+			// THEN it errors when expected.
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for:\n%q\ngot:\n%q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestResponseCaptures_CheckValues(t *testing.T) {
+	// GIVEN ResponseCaptures.
+	tests := map[string]struct {
+		captures *ResponseCaptures
+		errRegex string
+	}{
+		"nil": {
+			captures: nil,
+			errRegex: `^$`},
+		"valid": {
+			captures: &ResponseCaptures{
+				{Name: "job_id", JSONPath: "job.id"},
+				{Name: "url", Header: "Location", Regex: `(.*)`}},
+			errRegex: `^$`},
+		"invalid": {
+			captures: &ResponseCaptures{
+				{JSONPath: "job.id", Header: "Location"},
+				{Name: "url", Regex: `[0-`},
+				{Name: "url"}},
+			errRegex: test.TrimYAML(`
+				^- item_0:
+					name: <required>
+					json_path/header: <invalid>.*
+				- item_1:
+					regex: "\[0-" <invalid>.*
+				- item_2:
+					name: "url" <invalid> \(duplicate\)$`)},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN CheckValues is called.
+			err := tc.captures.CheckValues("")
+
+			// THEN it errors when expected.
+			e := util.ErrorToString(err)
+			if !util.RegexCheck(tc.errRegex, e) {
+				t.Errorf("want match for:\n%q\ngot:\n%q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
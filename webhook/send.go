@@ -17,21 +17,21 @@ package webhook
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
 	"math/rand"
-	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/release-argus/Argus/history"
+	"github.com/release-argus/Argus/outbox"
 	"github.com/release-argus/Argus/util"
 	"github.com/release-argus/Argus/web/metric"
 )
 
 // Send every WebHook in this Slice with a delay between each webhook.
-func (s *Slice) Send(serviceInfo util.ServiceInfo, useDelay bool) error {
+func (s *Slice) Send(ctx context.Context, serviceInfo util.ServiceInfo, useDelay bool) error {
 	if s == nil {
 		return nil
 	}
@@ -39,7 +39,21 @@ func (s *Slice) Send(serviceInfo util.ServiceInfo, useDelay bool) error {
 	errChan := make(chan error, len(*s))
 	for _, wh := range *s {
 		go func(webhook *WebHook) {
-			errChan <- webhook.Send(serviceInfo, useDelay)
+			var sendAt time.Time
+			if useDelay {
+				sendAt = time.Now().UTC().Add(webhook.GetDelayDuration())
+			}
+			errChan <- outbox.Deliver(
+				outbox.Entry{
+					Kind:        outbox.KindWebHook,
+					ServiceID:   serviceInfo.ID,
+					Target:      webhook.ID,
+					ServiceInfo: serviceInfo,
+					NextAttempt: sendAt},
+				outbox.Delivery{
+					Attempt: func() error { return webhook.Attempt(ctx, serviceInfo, useDelay) },
+					Retry:   func() error { return webhook.Attempt(ctx, serviceInfo, false) },
+					Dead:    func(error) { webhook.NotifyFailure(ctx, serviceInfo) }})
 		}(wh)
 
 		// Space out WebHook send starts.
@@ -61,8 +75,22 @@ func (s *Slice) Send(serviceInfo util.ServiceInfo, useDelay bool) error {
 	return errors.Join(errs...)
 }
 
-// Send the WebHook up to MaxTries times until a success.
-func (w *WebHook) Send(serviceInfo util.ServiceInfo, useDelay bool) error {
+// Send the WebHook up to MaxTries times until a success,
+// notifying of the failure if they all fail.
+//
+// The delay and sends are cut short when `ctx` is cancelled (edit/delete of the Service, or shutdown),
+// returning the error of the context without recording a failure.
+func (w *WebHook) Send(ctx context.Context, serviceInfo util.ServiceInfo, useDelay bool) error {
+	err := w.Attempt(ctx, serviceInfo, useDelay)
+	if err != nil && ctx.Err() == nil {
+		w.NotifyFailure(ctx, serviceInfo)
+	}
+	return err
+}
+
+// Attempt to send the WebHook up to MaxTries times until a success,
+// without notifying of the failure (for the outbox to retry).
+func (w *WebHook) Attempt(ctx context.Context, serviceInfo util.ServiceInfo, useDelay bool) error {
 	logFrom := util.LogFrom{Primary: w.ID, Secondary: serviceInfo.ID}
 
 	if useDelay && w.GetDelay() != "0s" {
@@ -70,14 +98,20 @@ func (w *WebHook) Send(serviceInfo util.ServiceInfo, useDelay bool) error {
 		msg := fmt.Sprintf("Sleeping for %s before sending the WebHook", w.GetDelay())
 		jLog.Info(msg, logFrom, true)
 		w.SetExecuting(true, true) // disable sending of auto_approved w/ delay.
-		time.Sleep(w.GetDelayDuration())
+		if !util.Sleep(ctx, w.GetDelayDuration()) {
+			return ctx.Err() //nolint:wrapcheck
+		}
 	} else {
 		w.SetExecuting(false, true)
 	}
 
 	sendErrs := util.RetryWithBackoff(
+		ctx,
 		func() error {
-			err := w.try(logFrom)
+			err := w.try(ctx, serviceInfo, logFrom)
+			if ctx.Err() != nil {
+				return err
+			}
 			w.parseTry(err, *w.ServiceStatus.ServiceID, logFrom)
 			if err == nil {
 				return nil
@@ -92,49 +126,69 @@ func (w *WebHook) Send(serviceInfo util.ServiceInfo, useDelay bool) error {
 	if sendErrs == nil {
 		return nil
 	}
+	if err := ctx.Err(); err != nil {
+		return err //nolint:wrapcheck
+	}
 
-	err := fmt.Errorf("failed %d times to send the WebHook for %s to %q",
-		w.GetMaxTries(), *w.ServiceStatus.ServiceID, w.ID)
+	err := w.failure()
 	jLog.Error(err, logFrom, true)
 	failed := true
 	w.Failed.Set(w.ID, &failed)
 	w.AnnounceSend()
-	if !w.GetSilentFails() {
-		//#nosec G104 -- Errors are logged to CLI
-		//nolint:errcheck // ^
-		w.Notifiers.Send("WebHook fail", err.Error(), serviceInfo)
-	}
 	return errors.Join(sendErrs, err)
 }
 
-// try sends a WebHook to its URL with the body hashed using SHA1 and SHA256,
+// NotifyFailure sends the "WebHook fail" message to the Notifiers (unless silent_fails).
+func (w *WebHook) NotifyFailure(ctx context.Context, serviceInfo util.ServiceInfo) {
+	if w.GetSilentFails() {
+		return
+	}
+
+	err := w.failure()
+	serviceInfo.Failure = err.Error()
+	//#nosec G104 -- Errors are logged to CLI
+	//nolint:errcheck // ^
+	w.Notifiers.Send(ctx, "WebHook fail", err.Error(), serviceInfo)
+}
+
+// failure returns the error of the WebHook failing to send.
+func (w *WebHook) failure() error {
+	return fmt.Errorf("failed %d times to send the WebHook for %s to %q",
+		w.GetMaxTries(), *w.ServiceStatus.ServiceID, w.ID)
+}
+
+// try sends a WebHook (templated with `serviceInfo`) to its URL with the body hashed using SHA1 and SHA256,
 // encrypted with its Secret, and includes simulated GitHub headers.
-func (w *WebHook) try(logFrom util.LogFrom) error {
-	req := w.BuildRequest()
+//
+// The attempt is recorded in the history.
+func (w *WebHook) try(ctx context.Context, serviceInfo util.ServiceInfo, logFrom util.LogFrom) (err error) {
+	req := w.BuildRequest(serviceInfo)
 	if req == nil {
 		err := fmt.Errorf("failed to get *http.request for WebHook")
 		jLog.Error(err, logFrom, true)
 		return err
 	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	req = req.WithContext(ctx)
-	defer cancel()
+	record := w.historyRecord(req, serviceInfo)
+	record.Time = time.Now()
+	defer func() {
+		history.Add(record, err)
+	}()
 
-	// HTTPS insecure skip verify.
-	customTransport := &http.Transport{}
-	if w.GetAllowInvalidCerts() {
-		customTransport = http.DefaultTransport.(*http.Transport).Clone()
-		//#nosec G402 -- explicitly wanted InsecureSkipVerify.
-		customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	client, err := util.NewHTTPClient(w.GetAllowInvalidCerts(),
+		&w.HTTPClientOptions,
+		&w.Main.HTTPClientOptions,
+		&w.Defaults.HTTPClientOptions,
+		&w.HardDefaults.HTTPClientOptions)
+	if err != nil {
+		return err //nolint:wrapcheck
 	}
-
-	client := &http.Client{Transport: customTransport}
 	resp, err := client.Do(req)
 	if err != nil {
 		return err //nolint:wrapcheck
 	}
 	defer resp.Body.Close()
+	record.ResponseStatus = resp.StatusCode
 
 	body, _ := io.ReadAll(resp.Body)
 	bodyOkay := checkWebHookBody(string(body))
@@ -142,6 +196,15 @@ func (w *WebHook) try(logFrom util.LogFrom) error {
 	// SUCCESS!
 	desiredStatusCode := w.GetDesiredStatusCode()
 	if bodyOkay && (resp.StatusCode == int(desiredStatusCode) || (desiredStatusCode == 0 && (strconv.Itoa(resp.StatusCode)[:1] == "2"))) {
+		if err := w.GetResponseChecks().Check(resp.Header, body); err != nil {
+			return err
+		}
+		outputs, err := w.GetResponseCaptures().Capture(resp.Header, body)
+		if err != nil {
+			return err
+		}
+		w.ServiceStatus.SetWebHookOutputs(w.ID, outputs)
+
 		msg := fmt.Sprintf("(%d) WebHook received", resp.StatusCode)
 		jLog.Info(msg, logFrom, true)
 		return nil
@@ -164,13 +227,13 @@ func (w *WebHook) try(logFrom util.LogFrom) error {
 }
 
 // Send a message to the Notifiers (if available).
-func (n *Notifiers) Send(title, message string, serviceInfo util.ServiceInfo) error {
+func (n *Notifiers) Send(ctx context.Context, title, message string, serviceInfo util.ServiceInfo) error {
 	if n == nil || n.Shoutrrr == nil {
 		return nil
 	}
 
 	//nolint:wrapcheck
-	return (*n.Shoutrrr).Send(title, message, serviceInfo, false)
+	return (*n.Shoutrrr).Send(ctx, title, message, serviceInfo, false)
 }
 
 func checkWebHookBody(body string) (okay bool) {
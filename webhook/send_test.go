@@ -17,6 +17,8 @@
 package webhook
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -84,7 +86,7 @@ func TestWebHook_Try(t *testing.T) {
 				webhook.DesiredStatusCode = &tc.desiredStatusCode
 
 				// WHEN try is called on it.
-				err := webhook.try(util.LogFrom{})
+				err := webhook.try(context.Background(), webhook.ServiceStatus.ServiceInfo(), util.LogFrom{})
 
 				// THEN any err is expected.
 				e := util.ErrorToString(err)
@@ -201,7 +203,7 @@ func TestWebHook_Send(t *testing.T) {
 
 				// WHEN try is called on it.
 				startAt := time.Now()
-				webhook.Send(serviceInfo, tc.useDelay)
+				webhook.Send(context.Background(), serviceInfo, tc.useDelay)
 
 				// THEN the logs are expected.
 				completedAt := time.Now()
@@ -233,6 +235,34 @@ func TestWebHook_Send(t *testing.T) {
 	}
 }
 
+func TestWebHook_Send_Cancelled(t *testing.T) {
+	// GIVEN a WebHook with a delay, and a context cancelled during it (edit/delete/shutdown).
+	webhook := testWebHook(false, false, false)
+	webhook.Delay = "1h"
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	// WHEN Send is called with the delay.
+	start := time.Now()
+	err := webhook.Send(ctx, util.ServiceInfo{ID: "TestWebHook_Send_Cancelled"}, true)
+
+	// THEN the delay is cut short.
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("delay not cut short, took %s",
+			took)
+	}
+	// AND the cancellation is returned.
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("want %v, got %v",
+			context.Canceled, err)
+	}
+	// AND no failure is recorded.
+	if failed := webhook.Failed.Get(webhook.ID); failed != nil && *failed {
+		t.Errorf("want no failure recorded, got %t",
+			*failed)
+	}
+}
+
 func TestSlice_Send(t *testing.T) {
 	// GIVEN a Slice.
 	tests := map[string]struct {
@@ -292,7 +322,7 @@ func TestSlice_Send(t *testing.T) {
 					}
 
 					// WHEN try is called on it.
-					tc.slice.Send(util.ServiceInfo{ID: name}, tc.useDelay)
+					tc.slice.Send(context.Background(), util.ServiceInfo{ID: name}, tc.useDelay)
 
 					// THEN the logs are expected.
 					stdout := releaseStdout()
@@ -347,7 +377,7 @@ func TestNotifiers_SendWithNotifier(t *testing.T) {
 			notifiers := Notifiers{Shoutrrr: tc.shoutrrrNotifiers}
 
 			// WHEN Send is called with them.
-			err := notifiers.Send("TestNotifiersSendWithNotifier", name, util.ServiceInfo{ID: name})
+			err := notifiers.Send(context.Background(), "TestNotifiersSendWithNotifier", name, util.ServiceInfo{ID: name})
 
 			// THEN err is as expected.
 			e := util.ErrorToString(err)
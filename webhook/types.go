@@ -30,7 +30,7 @@ import (
 var (
 	jLog           *util.JLog
 	supportedTypes = []string{
-		"github", "gitlab"}
+		"github", "gitlab", "generic"}
 )
 
 // Slice mapping of WebHook.
@@ -49,15 +49,26 @@ type Headers []Header
 
 // Base is the base struct for WebHook.
 type Base struct {
-	Type              string   `yaml:"type,omitempty" json:"type,omitempty"`                               // "github"/"url".
-	URL               string   `yaml:"url,omitempty" json:"url,omitempty"`                                 // "https://example.com".
-	AllowInvalidCerts *bool    `yaml:"allow_invalid_certs,omitempty" json:"allow_invalid_certs,omitempty"` // Default - false = Disallows invalid HTTPS certificates.
-	CustomHeaders     *Headers `yaml:"custom_headers,omitempty" json:"custom_headers,omitempty"`           // Custom Headers for the WebHook.
-	Secret            string   `yaml:"secret,omitempty" json:"secret,omitempty"`                           // 'SECRET'.
-	DesiredStatusCode *uint16  `yaml:"desired_status_code,omitempty" json:"desired_status_code,omitempty"` // e.g. 202.
-	Delay             string   `yaml:"delay,omitempty" json:"delay,omitempty"`                             // The delay before sending the WebHook.
-	MaxTries          *uint8   `yaml:"max_tries,omitempty" json:"max_tries,omitempty"`                     // Amount of times to attempt sending the WebHook until we receive the desired status code.
-	SilentFails       *bool    `yaml:"silent_fails,omitempty" json:"silent_fails,omitempty"`               // Whether to notify if this WebHook fails MaxTries times.
+	Type              string            `yaml:"type,omitempty" json:"type,omitempty"`                               // "github"/"gitlab"/"generic".
+	URL               string            `yaml:"url,omitempty" json:"url,omitempty"`                                 // "https://example.com".
+	AllowInvalidCerts *bool             `yaml:"allow_invalid_certs,omitempty" json:"allow_invalid_certs,omitempty"` // Default - false = Disallows invalid HTTPS certificates.
+	CustomHeaders     *Headers          `yaml:"custom_headers,omitempty" json:"custom_headers,omitempty"`           // Custom Headers for the WebHook.
+	Secret            string            `yaml:"secret,omitempty" json:"secret,omitempty"`                           // 'SECRET'.
+	DesiredStatusCode *uint16           `yaml:"desired_status_code,omitempty" json:"desired_status_code,omitempty"` // e.g. 202.
+	Delay             string            `yaml:"delay,omitempty" json:"delay,omitempty"`                             // The delay before sending the WebHook.
+	MaxTries          *uint8            `yaml:"max_tries,omitempty" json:"max_tries,omitempty"`                     // Amount of times to attempt sending the WebHook until we receive the desired status code.
+	SilentFails       *bool             `yaml:"silent_fails,omitempty" json:"silent_fails,omitempty"`               // Whether to notify if this WebHook fails MaxTries times.
+	Method            string            `yaml:"method,omitempty" json:"method,omitempty"`                           // generic - HTTP method, e.g. POST.
+	QueryParams       *Headers          `yaml:"query_params,omitempty" json:"query_params,omitempty"`               // generic - Templated query parameters to add to the URL.
+	ContentType       string            `yaml:"content_type,omitempty" json:"content_type,omitempty"`               // generic - "json"/"form"/"text"/"xml".
+	Body              string            `yaml:"body,omitempty" json:"body,omitempty"`                               // generic - Templated request body.
+	Signing           string            `yaml:"signing,omitempty" json:"signing,omitempty"`                         // generic - "none"/"hmac-sha256"/"bearer", using the Secret.
+	SigningHeader     string            `yaml:"signing_header,omitempty" json:"signing_header,omitempty"`           // generic - Header to put the HMAC signature in.
+	SigningFormat     string            `yaml:"signing_format,omitempty" json:"signing_format,omitempty"`           // generic - Format of the HMAC header value, e.g. "sha256={signature}".
+	ResponseChecks    *ResponseChecks   `yaml:"response_checks,omitempty" json:"response_checks,omitempty"`         // Assertions the response must pass.
+	ResponseCaptures  *ResponseCaptures `yaml:"response_captures,omitempty" json:"response_captures,omitempty"`     // Values to capture from the response.
+
+	util.HTTPClientOptions `yaml:",inline" json:",inline"` // Timeout, CA bundle and client certificate.
 }
 
 // Defaults are the default values for WebHook.
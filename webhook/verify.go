@@ -94,6 +94,18 @@ func (b *Base) CheckValues(prefix string) error {
 					prefix, b.Delay))
 		}
 	}
+	// timeout/ca_file/cert_file/key_file
+	if httpErrs := b.HTTPClientOptions.CheckValues(prefix); httpErrs != nil {
+		errs = append(errs, httpErrs)
+	}
+	// response_checks
+	util.AppendCheckError(&errs, prefix, "response_checks", b.ResponseChecks.CheckValues(prefix+"  "))
+	// response_captures
+	util.AppendCheckError(&errs, prefix, "response_captures", b.ResponseCaptures.CheckValues(prefix+"  "))
+	// generic
+	if genericErrs := b.checkValuesGeneric(prefix); genericErrs != nil {
+		errs = append(errs, genericErrs)
+	}
 
 	if len(errs) == 0 {
 		return nil
@@ -117,6 +129,62 @@ func (b *Base) checkValuesCustomHeaders(prefix string) error {
 	return errors.Join(errs...)
 }
 
+// checkValuesGeneric validates the fields used by generic WebHooks.
+func (b *Base) checkValuesGeneric(prefix string) error {
+	var errs []error
+	// method
+	if b.Method != "" {
+		b.Method = strings.ToUpper(b.Method)
+		if !util.Contains(supportedMethods, b.Method) {
+			errs = append(errs,
+				fmt.Errorf("%smethod: %q <invalid> (supported methods = [%s])",
+					prefix, b.Method, strings.Join(supportedMethods, ",")))
+		}
+	}
+	// query_params
+	if b.QueryParams != nil {
+		var paramErrs []error
+		for _, param := range *b.QueryParams {
+			if !util.CheckTemplate(param.Value) {
+				paramErrs = append(paramErrs, fmt.Errorf("%s  %s: %q <invalid> (didn't pass templating)",
+					prefix, param.Key, param.Value))
+			}
+		}
+		util.AppendCheckError(&errs, prefix, "query_params", errors.Join(paramErrs...))
+	}
+	// content_type
+	if b.ContentType != "" {
+		if _, ok := contentTypes[b.ContentType]; !ok {
+			errs = append(errs,
+				fmt.Errorf("%scontent_type: %q <invalid> (supported types = [%s])",
+					prefix, b.ContentType, strings.Join(util.SortedKeys(contentTypes), ",")))
+		}
+	}
+	// body
+	if !util.CheckTemplate(b.Body) {
+		errs = append(errs,
+			fmt.Errorf("%sbody: %q <invalid> (didn't pass templating)",
+				prefix, b.Body))
+	}
+	// signing
+	if b.Signing != "" && !util.Contains(supportedSigning, b.Signing) {
+		errs = append(errs,
+			fmt.Errorf("%ssigning: %q <invalid> (supported types = [%s])",
+				prefix, b.Signing, strings.Join(supportedSigning, ",")))
+	}
+	// signing_format
+	if b.SigningFormat != "" && !strings.Contains(b.SigningFormat, signaturePlaceholder) {
+		errs = append(errs,
+			fmt.Errorf("%ssigning_format: %q <invalid> (must contain %q)",
+				prefix, b.SigningFormat, signaturePlaceholder))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
 // CheckValues validates the fields of the WebHook struct.
 func (w *WebHook) CheckValues(prefix string) error {
 	var errs []error
@@ -145,8 +213,9 @@ func (w *WebHook) CheckValues(prefix string) error {
 		errs = append(errs, fmt.Errorf("%surl: <required> (here, in the root webhook.%s, or in defaults)",
 			prefix, w.ID))
 	}
-	// secret
-	if w.GetSecret() == "" {
+	// secret (generic WebHooks only need one to sign with).
+	signing := w.GetSigning()
+	if w.GetSecret() == "" && (whType != "generic" || (signing != "" && signing != SigningNone)) {
 		errs = append(errs, fmt.Errorf("%ssecret: <required> (here, in the root webhook.%s, or in defaults)",
 			prefix, w.ID))
 	}
@@ -218,10 +218,19 @@ func TestWebHook_CheckValues(t *testing.T) {
 		whType           *string
 		whMainType       string
 		url, secret      *string
+		signing          string
+		timeout          string
 		customHeaders    Headers
 		errRegex         string
 	}{
 		"valid WebHook": {},
+		"valid timeout": {
+			timeout: "30s",
+		},
+		"invalid timeout": {
+			errRegex: `^timeout: "5x" <invalid>`,
+			timeout:  "5x",
+		},
 		"invalid delay": {
 			errRegex: `^delay: .* <invalid>`,
 			delay:    "5x",
@@ -260,6 +269,16 @@ func TestWebHook_CheckValues(t *testing.T) {
 			errRegex: `^secret: <required>.*$`,
 			secret:   test.StringPtr(""),
 		},
+		"generic without secret or signing": {
+			whType: test.StringPtr("generic"),
+			secret: test.StringPtr(""),
+		},
+		"generic without secret, but signing": {
+			errRegex: `^secret: <required>.*$`,
+			whType:   test.StringPtr("generic"),
+			secret:   test.StringPtr(""),
+			signing:  SigningBearer,
+		},
 		"valid custom headers": {
 			customHeaders: Headers{
 				{Key: "foo", Value: "bar"}},
@@ -305,6 +324,8 @@ func TestWebHook_CheckValues(t *testing.T) {
 			if tc.secret != nil {
 				webhook.Secret = *tc.secret
 			}
+			webhook.Signing = tc.signing
+			webhook.Timeout = tc.timeout
 			webhook.CustomHeaders = &tc.customHeaders
 
 			// WHEN CheckValues is called